@@ -24,6 +24,41 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/reload": {
+            "post": {
+                "description": "Reload the configuration file and apply the settings that can change at runtime. Settings that need a restart are reported but not applied. On error the previous configuration stays in effect.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reload configuration",
+                "operationId": "reloadConfig",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReloadResult"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1": {
             "get": {
                 "description": "Get API version information",
@@ -31,40 +66,71 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "api"
                 ],
                 "summary": "API root",
+                "operationId": "getAPIInfo",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.APIInfo"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/cluster/services": {
+        "/api/v1/automations": {
             "get": {
-                "description": "Returns a list of Kubernetes cluster services, optionally filtered by name",
+                "description": "Returns a page of the automations, from the API and the automations file, ordered by ID unless sort is given",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "cluster"
+                    "automations"
                 ],
-                "summary": "List cluster services",
+                "summary": "List automations",
+                "operationId": "listAutomations",
                 "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Filter services by name (case-insensitive substring match)",
-                        "name": "name",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
@@ -72,43 +138,241 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/cluster.ServiceInfo"
+                            "$ref": "#/definitions/models.AutomationListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates an automation: when one of its triggers fires (a device state change, a service going up or down, a firing alert or a cron expression) and all of its conditions on device state hold, its actions (device commands, webhooks and notifications) run in order. Without an id, the automation's ID is derived from its name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "automations"
+                ],
+                "summary": "Create an automation",
+                "operationId": "createAutomation",
+                "parameters": [
+                    {
+                        "description": "Automation to create",
+                        "name": "automation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AutomationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Automation"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new automation"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/homeassistant/devices/{id}/command": {
+        "/api/v1/automations/alerts": {
             "post": {
-                "description": "Execute a control command on a HomeAssistant device",
+                "description": "Receives an Alertmanager webhook notification. Each firing alert triggers the automations with an alert trigger on its alertname label whose labels it includes; resolved alerts are ignored. The automations run after the response.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "automations"
+                ],
+                "summary": "Receive alerts",
+                "operationId": "receiveAutomationAlerts",
+                "parameters": [
+                    {
+                        "description": "Alertmanager webhook payload",
+                        "name": "alerts",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/automations/{id}": {
+            "get": {
+                "description": "Returns an automation with the number and time of its runs",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "automations"
+                ],
+                "summary": "Get an automation",
+                "operationId": "getAutomation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Automation"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the triggers, conditions and actions of an automation created through the API; its runs are kept. Automations from the automations file cannot be changed.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "tags": [
-                    "homeassistant"
+                    "automations"
                 ],
-                "summary": "Execute a device command",
+                "summary": "Replace an automation",
+                "operationId": "updateAutomation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device ID",
+                        "description": "Automation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Command to execute",
-                        "name": "command",
+                        "description": "New automation; id may be omitted",
+                        "name": "automation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/homeassistant.Command"
+                            "$ref": "#/definitions/models.AutomationRequest"
                         }
                     }
                 ],
@@ -116,8 +380,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Automation"
                         }
                     },
                     "400": {
@@ -132,106 +395,3640 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "405": {
-                        "description": "Method Not Allowed",
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/services": {
-            "get": {
-                "description": "Returns list of all services in the homelab",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Deletes an automation created through the API, with its runs. Automations from the automations file cannot be deleted.",
                 "tags": [
-                    "services"
+                    "automations"
+                ],
+                "summary": "Delete an automation",
+                "operationId": "deleteAutomation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List available services",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/models.ServicesResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Get the health status of the API",
+        "/api/v1/automations/{id}/dry-run": {
+            "post": {
+                "description": "Evaluates an automation as if event had happened: whether the event matches one of its triggers and whether its conditions hold on the current device state. No action runs and the run is not recorded. Without an event, only the conditions are evaluated.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "health"
+                    "automations"
+                ],
+                "summary": "Dry-run an automation",
+                "operationId": "dryRunAutomation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event to evaluate the automation against",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.DryRunRequest"
+                        }
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.AutomationRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "cluster.ServiceInfo": {
-            "type": "object",
-            "properties": {
-                "endpoints": {
-                    "type": "array",
-                    "items": {
+        },
+        "/api/v1/automations/{id}/runs": {
+            "get": {
+                "description": "Returns a page of the latest runs of an automation, oldest first (run IDs are ordered by time) unless sort is given, each with the event that triggered it and the outcome of its conditions and actions. The last 50 runs are kept.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "automations"
+                ],
+                "summary": "List the runs of an automation",
+                "operationId": "listAutomationRuns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AutomationRunListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cluster/services": {
+            "get": {
+                "description": "Returns a page of Kubernetes cluster services, optionally filtered by name and namespace.\nServices are ordered by namespace and name unless sort is given; the next page is linked in the Link header.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "List cluster services",
+                "operationId": "listClusterServices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter services by name (case-insensitive substring match)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return services in this namespace (exact match)",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ServiceInfo"
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events": {
+            "get": {
+                "description": "Streams device changes, executed commands, service status changes and cluster service changes as server-sent events. The event name is the topic and the data is the event as JSON. A client that falls more than 64 events behind is disconnected rather than missing events; it should reconnect and re-read the state it depends on.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream events",
+                "operationId": "streamEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated topics to stream: homeassistant.device_changed, homeassistant.command_executed, services.status_changed, cluster.service_changed (default: all)",
+                        "name": "topics",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A stream of server-sent events",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/areas": {
+            "get": {
+                "description": "Returns a page of the areas of the home, ordered by ID unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List areas",
+                "operationId": "listAreas",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AreaListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates an area and moves the listed devices into it. Without an id, the area's ID is derived from its name, as Home Assistant does.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Create an area",
+                "operationId": "createArea",
+                "parameters": [
+                    {
+                        "description": "Area to create",
+                        "name": "area",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AreaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Area"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new area"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/areas/{id}": {
+            "get": {
+                "description": "Returns an area with the IDs of its devices",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Get an area",
+                "operationId": "getArea",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Area"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Renames an area and replaces its devices. Devices that leave the area are in no area afterwards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Replace an area",
+                "operationId": "updateArea",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name and devices; id may be omitted",
+                        "name": "area",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AreaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Area"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an area. Its devices are in no area afterwards.",
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Delete an area",
+                "operationId": "deleteArea",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/areas/{id}/command": {
+            "post": {
+                "description": "Executes a command on every controllable device in an area, as a batch, and returns the result of each. Devices that are not controllable are left out. The response is 200 if every command succeeded and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Execute a command on the devices in an area",
+                "operationId": "executeAreaCommand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of this command; retries with the same key are not executed again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Command to execute",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Command"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true if the response is a replay of an earlier request with the same Idempotency-Key"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/areas/{id}/devices": {
+            "get": {
+                "description": "Returns a page of the devices in an area, ordered by ID unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List the devices in an area",
+                "operationId": "listAreaDevices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/areas/{id}/devices/{device_id}": {
+            "put": {
+                "description": "Moves a device into an area, taking it out of the area it was in",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Move a device into an area",
+                "operationId": "addAreaDevice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Area"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Takes a device out of an area, leaving it in no area. Removing a device that is not in the area succeeds.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Take a device out of an area",
+                "operationId": "removeAreaDevice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Area"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/commands:batch": {
+            "post": {
+                "description": "Executes up to 100 device commands concurrently and returns the result of each, with the status code it would have got on its own. The response is 200 if every command succeeded and 207 otherwise. In atomic mode the first failure skips the commands that have not started (424) and restores the devices of the ones that succeeded to the state and attributes they had before, where an action sets that state.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Execute device commands in a batch",
+                "operationId": "executeBatch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unique key of this batch; retries with the same key are not executed again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Commands to execute",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true if the response is a replay of an earlier request with the same Idempotency-Key"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/devices": {
+            "get": {
+                "description": "Returns a page of HomeAssistant devices, ordered by ID unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List devices",
+                "operationId": "listDevices",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/devices/{id}": {
+            "get": {
+                "description": "Returns a single HomeAssistant device with its state and attributes",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Get a device",
+                "operationId": "getDevice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Device"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/devices/{id}/command": {
+            "post": {
+                "description": "Execute a control command on a HomeAssistant device. With an Idempotency-Key, retries with the same key and body get the first response again instead of repeating the command.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Execute a device command",
+                "operationId": "executeCommand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of this command; retries with the same key are not executed again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Command to execute",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Command"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CommandResult"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true if the response is a replay of an earlier request with the same Idempotency-Key"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "405": {
+                        "description": "Method Not Allowed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/groups": {
+            "get": {
+                "description": "Returns a page of the device groups, ordered by ID unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List groups",
+                "operationId": "listGroups",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a group of devices. Without an id, the group's ID is derived from its name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Create a group",
+                "operationId": "createGroup",
+                "parameters": [
+                    {
+                        "description": "Group to create",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Group"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new group"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/groups/{id}": {
+            "get": {
+                "description": "Returns a group with the IDs of its devices",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Get a group",
+                "operationId": "getGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Group"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Renames a group and replaces its devices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Replace a group",
+                "operationId": "updateGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name and devices; id may be omitted",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a group. Its devices are not affected.",
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Delete a group",
+                "operationId": "deleteGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/groups/{id}/command": {
+            "post": {
+                "description": "Executes a command on every controllable device in a group, as a batch, and returns the result of each. Devices that are not controllable are left out. The response is 200 if every command succeeded and 207 otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Execute a command on the devices in a group",
+                "operationId": "executeGroupCommand",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of this command; retries with the same key are not executed again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Command to execute",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Command"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true if the response is a replay of an earlier request with the same Idempotency-Key"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/groups/{id}/devices": {
+            "get": {
+                "description": "Returns a page of the devices in a group, ordered by ID unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List the devices in a group",
+                "operationId": "listGroupDevices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/homeassistant/groups/{id}/devices/{device_id}": {
+            "put": {
+                "description": "Adds a device to a group. Adding a device that is already in the group succeeds.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Add a device to a group",
+                "operationId": "addGroupDevice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Group"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a device from a group. Removing a device that is not in the group succeeds.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Remove a device from a group",
+                "operationId": "removeGroupDevice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Group"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/scenes": {
+            "get": {
+                "description": "Returns a page of the scenes, ordered by ID unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List scenes",
+                "operationId": "listScenes",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SceneListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Captures the current state and attributes of the listed devices as a scene. The devices must be controllable. Without an id, the scene's ID is derived from its name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Create a scene",
+                "operationId": "createScene",
+                "parameters": [
+                    {
+                        "description": "Scene to capture",
+                        "name": "scene",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SceneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Scene"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new scene"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/scenes/{id}": {
+            "get": {
+                "description": "Returns a scene with the captured state of its devices",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Get a scene",
+                "operationId": "getScene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Scene"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Renames a scene and captures it again from the current state of the listed devices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Recapture a scene",
+                "operationId": "updateScene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name and devices; id may be omitted",
+                        "name": "scene",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SceneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Scene"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a scene. Its devices are not affected.",
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Delete a scene",
+                "operationId": "deleteScene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/scenes/{id}/activate": {
+            "post": {
+                "description": "Restores the devices of a scene to their captured state. Each device that differs gets one command; devices that already match get none. The response is 200 if every device was restored or unchanged and 207 if any failed.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Activate a scene",
+                "operationId": "activateScene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of this activation; retries with the same key are not executed again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SceneActivation"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true if the response is a replay of an earlier request with the same Idempotency-Key"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "$ref": "#/definitions/models.SceneActivation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schedules": {
+            "get": {
+                "description": "Returns a page of the scheduled commands, including completed one-time schedules, ordered by ID (creation order) unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "List schedules",
+                "operationId": "listSchedules",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleListResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedules a device command to run once at a time (at) or after a delay (delay), or repeatedly on a cron expression (cron) evaluated in timezone. Exactly one of at, delay and cron must be given. Runs go through the same path as POST /api/v1/devices/{id}/command and appear in the command history. A run that starts more than the configured grace period late, e.g. because the server was down, is skipped or run once according to missed_run_policy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Schedule a device command",
+                "operationId": "createSchedule",
+                "parameters": [
+                    {
+                        "description": "Command and when to run it",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of this request; retries with the same key do not create another schedule",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Schedule"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true if the response is a replay of an earlier request with the same Idempotency-Key"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new schedule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schedules/{id}": {
+            "get": {
+                "description": "Returns a scheduled command with its next run and the outcome of its last run",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Get a schedule",
+                "operationId": "getSchedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Schedule"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancels a schedule so that it does not run again. A run in progress is not interrupted. Completed schedules can be deleted the same way.",
+                "tags": [
+                    "homeassistant"
+                ],
+                "summary": "Cancel a schedule",
+                "operationId": "cancelSchedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services": {
+            "get": {
+                "description": "Returns a page of the services in the homelab, ordered by name unless sort is given",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/csv",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List available services",
+                "operationId": "listServices",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by; prefix a field with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include in each item",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ServicesResponse"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching directives"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "URL of the next page, with rel=\\\"next\\"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get the health status of the API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "operationId": "health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIInfo": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "API v1"
+                }
+            }
+        },
+        "models.Alert": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is firing or resolved; only firing alerts trigger automations.",
+                    "type": "string",
+                    "example": "firing"
+                }
+            }
+        },
+        "models.AlertWebhook": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Alert"
+                    }
+                },
+                "status": {
+                    "description": "Status is firing if any alert of the group is firing.",
+                    "type": "string",
+                    "example": "firing"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "4"
+                }
+            }
+        },
+        "models.AlertsResult": {
+            "type": "object",
+            "properties": {
+                "firing": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Area": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "description": "DeviceIDs lists the devices in the area, ordered by ID.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "ID is the area's slug, as in Home Assistant's area registry.",
+                    "type": "string",
+                    "example": "kitchen"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kitchen"
+                }
+            }
+        },
+        "models.AreaListResponse": {
+            "type": "object",
+            "properties": {
+                "areas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Area"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AreaRequest": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "description": "DeviceIDs are moved into the area from any area they are in.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "ID is derived from Name if it is omitted when creating the area.",
+                    "type": "string",
+                    "example": "kitchen"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kitchen"
+                }
+            }
+        },
+        "models.Automation": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationAction"
+                    }
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationCondition"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Enabled rules are evaluated; disabled ones are kept but never run.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "porch_light_at_dusk"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Porch light at dusk"
+                },
+                "runs": {
+                    "description": "Runs counts the runs of the rule, including those whose conditions did\nnot hold.",
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "Source is api for rules created through the API and file for rules\nloaded from the automations file, which cannot be changed through the\nAPI.",
+                    "type": "string",
+                    "example": "api"
+                },
+                "triggers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationTrigger"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AutomationAction": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "turn_on"
+                },
+                "device_id": {
+                    "description": "DeviceID, Action and Parameters are the device command of a command\naction.",
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "The porch light was turned on"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "title": {
+                    "description": "Title and Message are the notification of a notify action.",
+                    "type": "string",
+                    "example": "Porch light"
+                },
+                "type": {
+                    "description": "Type is command, webhook or notify.",
+                    "type": "string",
+                    "example": "command"
+                },
+                "url": {
+                    "description": "URL receives a webhook action: a JSON POST of the automation ID, the\nrun ID and the event.",
+                    "type": "string",
+                    "example": "https://hooks.example.com/homelab"
+                }
+            }
+        },
+        "models.AutomationActionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "device is not controllable"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status is success, failed, skipped (an earlier action failed) or, in a\ndry run, dry_run.",
+                    "type": "string",
+                    "example": "success"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "command"
+                }
+            }
+        },
+        "models.AutomationCondition": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "description": "Attribute is compared instead of the state when it is given.",
+                    "type": "string",
+                    "example": "brightness"
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "readonly-sensor-001"
+                },
+                "operator": {
+                    "description": "Operator is eq, ne, gt, gte, lt or lte. eq and ne compare numbers as\nnumbers and anything else as JSON; the others only compare numbers,\nincluding numeric states such as \"72\".",
+                    "type": "string",
+                    "example": "gt"
+                },
+                "value": {}
+            }
+        },
+        "models.AutomationConditionResult": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "description": "Actual is the state or attribute the condition compared."
+                },
+                "error": {
+                    "type": "string",
+                    "example": "device not found: device-404"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "passed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.AutomationEvent": {
+            "type": "object",
+            "properties": {
+                "alert_name": {
+                    "type": "string",
+                    "example": "HighTemperature"
+                },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "off"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "request_id": {
+                    "description": "RequestID is the request ID of the command that changed the device.",
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "grafana"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "down"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "on"
+                },
+                "type": {
+                    "description": "Type is device_state, service_status, alert or time, or manual for a\ndry run without an event.",
+                    "type": "string",
+                    "example": "device_state"
+                }
+            }
+        },
+        "models.AutomationListResponse": {
+            "type": "object",
+            "properties": {
+                "automations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Automation"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AutomationRequest": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationAction"
+                    }
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationCondition"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Enabled defaults to true.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "ID is derived from Name if it is omitted when creating the automation.",
+                    "type": "string",
+                    "example": "porch_light_at_dusk"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Porch light at dusk"
+                },
+                "triggers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationTrigger"
+                    }
+                }
+            }
+        },
+        "models.AutomationRun": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationActionResult"
+                    }
+                },
+                "automation_id": {
+                    "type": "string",
+                    "example": "porch_light_at_dusk"
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationConditionResult"
+                    }
+                },
+                "depth": {
+                    "description": "Depth is the position of the run in a chain of automations triggered\nby the device changes of each other's commands, starting at 1.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "dry_run": {
+                    "description": "DryRun runs evaluate the triggers and conditions but run no action.",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.AutomationEvent"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is also the request ID of the commands and webhooks of the run, so\nit finds them in the command history and the logs.",
+                    "type": "string",
+                    "example": "0192f3c4-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is success, failed (an action failed), conditions_not_met or\nloop_blocked (the chain was too deep). Dry runs end in not_triggered,\nif the event matches no trigger, conditions_not_met or would_run.",
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "models.AutomationRunListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
+                    "type": "string"
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutomationRun"
+                    }
+                }
+            }
+        },
+        "models.AutomationTrigger": {
+            "type": "object",
+            "properties": {
+                "alert_name": {
+                    "description": "AlertName and Labels match alert triggers: a firing alert received on\nPOST /api/v1/automations/alerts, whose labels include Labels.",
+                    "type": "string",
+                    "example": "HighTemperature"
+                },
+                "cron": {
+                    "description": "Cron and Timezone fire time triggers, as for schedules.",
+                    "type": "string",
+                    "example": "0 22 * * *"
+                },
+                "device_id": {
+                    "description": "DeviceID, From and To match device_state triggers: the state of the\ndevice changing from From to To. An empty From or To matches any\nstate; with both empty, any change of the device's state or attributes\nfires.",
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "off"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "service": {
+                    "description": "Service and Status match service_status triggers: a probed service\ngoing up or down. An empty Service matches every service and an empty\nStatus both directions.",
+                    "type": "string",
+                    "example": "grafana"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "down"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "on"
+                },
+                "type": {
+                    "description": "Type is device_state, service_status, alert or time.",
+                    "type": "string",
+                    "example": "device_state"
+                }
+            }
+        },
+        "models.BatchCommand": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "turn_off"
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "index": {
+                    "description": "Index is the position of the command in the request.",
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "device not found: device-404"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.CommandResult"
+                },
+                "rollback_error": {
+                    "description": "RollbackError explains why a command of a failed atomic batch is still\napplied.",
+                    "type": "string"
+                },
+                "rolled_back": {
+                    "description": "RolledBack is set on commands of a failed atomic batch that were undone.",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status is the HTTP status the command would have got on its own, or\n424 Failed Dependency if it was skipped because an atomic batch failed.",
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "description": "Atomic stops the batch at the first failed command and restores the\ndevices of the commands that succeeded to their previous state, where\nan action sets that state.",
+                    "type": "boolean"
+                },
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchCommand"
+                    }
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchItemResult"
+                    }
+                },
+                "rolled_back": {
+                    "description": "RolledBack is set when an atomic batch failed and was rolled back.",
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "description": "Skipped counts commands not executed because an atomic batch failed.",
+                    "type": "integer"
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Command": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.CommandResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Device": {
+            "type": "object",
+            "properties": {
+                "area_id": {
+                    "description": "AreaID is the area the device is in; it is omitted if it is in none.",
+                    "type": "string"
+                },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "controllable": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeviceListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of devices in this page.",
+                    "type": "integer"
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Device"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DryRunRequest": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "description": "Event is checked against the triggers. Without it, the triggers are\nnot checked and only the conditions are evaluated.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AutomationEvent"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Group": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "description": "DeviceIDs lists the devices in the group, ordered by ID.",
+                    "type": "array",
+                    "items": {
                         "type": "string"
                     }
                 },
+                "id": {
+                    "type": "string",
+                    "example": "downstairs_lights"
+                },
                 "name": {
+                    "type": "string",
+                    "example": "Downstairs lights"
+                }
+            }
+        },
+        "models.GroupListResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Group"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
                     "type": "string"
+                }
+            }
+        },
+        "models.GroupRequest": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "namespace": {
+                "id": {
+                    "description": "ID is derived from Name if it is omitted when creating the group.",
+                    "type": "string",
+                    "example": "downstairs_lights"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Downstairs lights"
+                }
+            }
+        },
+        "models.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.ReloadResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "Applied are the changed settings that took effect immediately.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "restart_required": {
+                    "description": "RestartRequired are the changed settings that only take effect after a\nrestart. They are kept at their running values until then.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Scene": {
+            "type": "object",
+            "properties": {
+                "captured_at": {
+                    "description": "CapturedAt is when the device states were captured.",
                     "type": "string"
                 },
+                "devices": {
+                    "description": "Devices holds the captured state of each device, ordered by device ID.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SceneDevice"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "movie_night"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Movie night"
+                }
+            }
+        },
+        "models.SceneActivation": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "restored": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SceneDeviceResult"
+                    }
+                },
+                "scene_id": {
+                    "type": "string",
+                    "example": "movie_night"
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SceneDevice": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "on"
+                }
+            }
+        },
+        "models.SceneDeviceResult": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "Command is the command sent to restore the device. It is omitted when\nthe device was unchanged.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Command"
+                        }
+                    ]
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "device not found: device-404"
+                },
                 "status": {
+                    "description": "Status is restored, unchanged (the device already matched the scene)\nor failed.",
+                    "type": "string",
+                    "example": "restored"
+                }
+            }
+        },
+        "models.SceneListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
                     "type": "string"
+                },
+                "scenes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Scene"
+                    }
+                }
+            }
+        },
+        "models.SceneRequest": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "description": "DeviceIDs are the devices whose state is captured; they must be\ncontrollable, or the scene could not restore them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "device-001"
+                    ]
+                },
+                "id": {
+                    "description": "ID is derived from Name if it is omitted when creating the scene.",
+                    "type": "string",
+                    "example": "movie_night"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Movie night"
                 }
             }
         },
-        "homeassistant.Command": {
+        "models.Schedule": {
             "type": "object",
             "properties": {
                 "action": {
+                    "type": "string",
+                    "example": "turn_off"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron is the cron expression of a cron schedule.",
+                    "type": "string",
+                    "example": "30 22 * * 1-5"
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0192f3c4-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+                },
+                "kind": {
+                    "description": "Kind is once, for a schedule created with at or delay, or cron.",
+                    "type": "string",
+                    "example": "cron"
+                },
+                "last_run": {
+                    "description": "LastRun is the outcome of the most recent run.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScheduleRun"
+                        }
+                    ]
+                },
+                "missed_run_policy": {
+                    "description": "MissedRunPolicy is what happens to a run that was missed, e.g. because\nthe server was down: skip it, or run_once as soon as possible.",
+                    "type": "string",
+                    "example": "run_once"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Porch light off"
+                },
+                "next_run": {
+                    "description": "NextRun is omitted once the schedule is completed.",
                     "type": "string"
                 },
                 "parameters": {
                     "type": "object",
                     "additionalProperties": true
+                },
+                "runs": {
+                    "description": "Runs counts the runs that executed the command.",
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "State is scheduled while runs are pending and completed once a one-time\nschedule has run.",
+                    "type": "string",
+                    "example": "scheduled"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA time zone the cron expression is evaluated in.",
+                    "type": "string",
+                    "example": "Europe/Berlin"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.ScheduleListResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
+                    "type": "string"
+                },
+                "schedules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Schedule"
+                    }
+                }
+            }
+        },
+        "models.ScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "turn_off"
+                },
+                "at": {
+                    "description": "At runs the command once at this time (RFC 3339).",
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron runs the command on a five-field cron expression, e.g. \"0 22 * * *\".",
+                    "type": "string",
+                    "example": "0 22 * * *"
+                },
+                "delay": {
+                    "description": "Delay runs the command once after this Go duration, e.g. 30m or 1h30m.",
+                    "type": "string",
+                    "example": "30m"
+                },
+                "device_id": {
+                    "type": "string",
+                    "example": "device-001"
+                },
+                "missed_run_policy": {
+                    "description": "MissedRunPolicy is skip or run_once (the default).",
+                    "type": "string",
+                    "example": "run_once"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Porch light off"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
+                "timezone": {
+                    "description": "Timezone is the IANA time zone of Cron; the server's time zone is used\nif it is omitted.",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "models.ScheduleRun": {
+            "type": "object",
+            "properties": {
                 "error": {
+                    "type": "string",
+                    "example": "device not found"
+                },
+                "request_id": {
+                    "description": "RequestID identifies the command in the command history.",
                     "type": "string"
                 },
-                "message": {
+                "scheduled_for": {
+                    "description": "ScheduledFor is when the run was due.",
+                    "type": "string"
+                },
+                "started_at": {
+                    "description": "StartedAt is when it started; it is later than ScheduledFor for a\nmissed run that was run late.",
                     "type": "string"
+                },
+                "status": {
+                    "description": "Status is success, failed, or skipped for a missed run that the\npolicy skipped.",
+                    "type": "string",
+                    "example": "success"
                 }
             }
         },
@@ -252,9 +4049,33 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ServiceInfo": {
+            "type": "object",
+            "properties": {
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "models.ServicesResponse": {
             "type": "object",
             "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; it is omitted\non the last page.",
+                    "type": "string"
+                },
                 "services": {
                     "type": "array",
                     "items": {
@@ -0,0 +1,11 @@
+package api
+
+import _ "embed"
+
+//go:generate go run ../internal/openapi/internal/openapigen -in swagger.json -out openapi.json
+
+// OpenAPI is the OpenAPI 3.1 document of the HTTP API, generated from
+// swagger.json. It is served at /api/openapi.json.
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"go-github/internal/config"
+)
+
+const configUsage = `Usage: homelab-api config validate [flags]
+
+Loads the configuration exactly as serve would (defaults, config file,
+environment and flags), reports every problem and exits 1 if it is invalid.
+Use -print to also show the effective configuration, with secrets redacted.
+`
+
+// configCommand runs the "config" subcommands.
+func configCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprint(stderr, configUsage)
+		if len(args) > 0 && isHelp(args[0]) {
+			return 0
+		}
+		return 2
+	}
+	return validateConfig(args[1:], stdout, stderr)
+}
+
+// validateConfig loads and validates the configuration. -print is consumed
+// here; the remaining args are the configuration flags.
+func validateConfig(args []string, stdout, stderr io.Writer) int {
+	show := false
+	loadArgs := make([]string, 0, len(args))
+	for _, a := range args {
+		if a == "-print" || a == "--print" {
+			show = true
+			continue
+		}
+		loadArgs = append(loadArgs, a)
+	}
+
+	cfg, err := config.Load(loadArgs)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	source := "defaults and environment"
+	if cfg.File != "" {
+		source = cfg.File
+	}
+	fmt.Fprintf(stdout, "configuration is valid (%s)\n", source)
+	if show {
+		fmt.Fprint(stdout, cfg.String())
+	}
+	return 0
+}
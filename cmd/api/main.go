@@ -1,18 +1,13 @@
+// Command homelab-api serves the homelab HTTP API and the MCP stdio server
+// from a single binary. Run "homelab-api help" for the available commands.
 package main
 
 import (
-	"context"
-	"log/slog"
+	"fmt"
+	"io"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	_ "go-github/api" // Import generated docs
-	internalmcp "go-github/internal/mcp"
-	"go-github/internal/server"
-
-	"golang.org/x/sync/errgroup"
 )
 
 // @title Home Lab API
@@ -31,60 +26,56 @@ import (
 // @BasePath /
 // @schemes http
 
-func main() {
-	// MCP-only mode: ./bin/homelab-api mcp
-	// Starts the MCP stdio server without binding the HTTP port.
-	// Default (no args): starts both HTTP API and MCP stdio concurrently.
-	mcpOnly := len(os.Args) > 1 && os.Args[1] == "mcp"
-
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	// Create a shared context that is cancelled on SIGINT/SIGTERM.
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-
-	g, gctx := errgroup.WithContext(ctx)
+const usage = `Usage: homelab-api [command] [flags]
 
-	if !mcpOnly {
-		srv := server.New()
+Commands:
+  serve            Start the HTTP API and the MCP stdio server (default)
+  mcp              Start only the MCP stdio server
+  healthcheck      Check the /health endpoint of a running server; exits 1 if unhealthy
+  version          Print version information
+  config validate  Load and validate the configuration, then exit
 
-		// Launch HTTP server goroutine.
-		g.Go(func() error {
-			slog.Info("http server started", "port", port)
-			if err := srv.Run(port); err != nil {
-				return err
-			}
-			return nil
-		})
+Run "homelab-api <command> -h" for the flags of a command. serve, mcp and
+config validate accept the configuration flags; see deployments/README.md.
+`
 
-		// Goroutine to trigger graceful HTTP shutdown when context is done.
-		g.Go(func() error {
-			<-gctx.Done()
-
-			slog.Info("shutting down http server...")
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
 
-			if err := srv.GracefulShutdown(shutdownCtx); err != nil {
-				slog.Error("http shutdown error", "error", err)
-			}
-			return nil
-		})
+// run dispatches args to a command and returns the process exit code:
+// 0 on success, 1 on failure and 2 on usage errors. Without a command, or
+// when args start with a flag, the server is started, as before commands
+// existed.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (len(args[0]) > 1 && args[0][0] == '-' && !isHelp(args[0])) {
+		return serve(args, false)
 	}
 
-	// Launch MCP stdio server goroutine (always runs).
-	g.Go(func() error {
-		return internalmcp.Run(gctx)
-	})
-
-	if err := g.Wait(); err != nil {
-		slog.Error("server error", "error", err)
-		os.Exit(1)
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "serve":
+		return serve(rest, false)
+	case "mcp":
+		return serve(rest, true)
+	case "healthcheck":
+		return healthcheck(rest, stdout, stderr)
+	case "version":
+		return printVersion(rest, stdout, stderr)
+	case "config":
+		return configCommand(rest, stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "homelab-api: unknown command %q\n\n%s", cmd, usage)
+		return 2
 	}
+}
 
-	slog.Info("all servers stopped gracefully")
+func isHelp(arg string) bool {
+	switch arg {
+	case "-h", "-help", "--help":
+		return true
+	}
+	return false
 }
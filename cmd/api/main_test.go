@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-github/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs the CLI with args and returns the exit code and output.
+func runCommand(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// writeConfig writes a YAML config file and points CONFIG_FILE at it, so the
+// host environment cannot leak into a test.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	for _, key := range []string{"PORT", "METRICS_PORT", "TLS_CERT_FILE", "TLS_KEY_FILE", "RATE_LIMIT"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("CONFIG_FILE", path)
+	return path
+}
+
+func TestRun_Help(t *testing.T) {
+	for _, arg := range []string{"help", "-h", "--help"} {
+		code, stdout, _ := runCommand(t, arg)
+		assert.Equal(t, 0, code, arg)
+		assert.Contains(t, stdout, "healthcheck", arg)
+		assert.Contains(t, stdout, "config validate", arg)
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	code, _, stderr := runCommand(t, "frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+	assert.Contains(t, stderr, "Usage: homelab-api")
+}
+
+func TestRun_Version(t *testing.T) {
+	code, stdout, _ := runCommand(t, "version")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "homelab-api dev")
+
+	code, stdout, _ = runCommand(t, "version", "-json")
+	assert.Equal(t, 0, code)
+	var info map[string]string
+	require.NoError(t, json.Unmarshal([]byte(stdout), &info))
+	assert.Equal(t, "dev", info["version"])
+	assert.NotEmpty(t, info["go_version"])
+}
+
+func TestRun_ConfigValidate(t *testing.T) {
+	path := writeConfig(t, "server:\n  port: \"9090\"\n")
+
+	code, stdout, _ := runCommand(t, "config", "validate")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "configuration is valid ("+path+")\n", stdout)
+
+	code, stdout, _ = runCommand(t, "config", "validate", "-print", "-rate-limit", "20")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, `port: "9090"`)
+	assert.Contains(t, stdout, "requests: 20")
+}
+
+func TestRun_ConfigValidateInvalid(t *testing.T) {
+	writeConfig(t, "server:\n  port: \"99999\"\nrate_limit:\n  requests: 0\n")
+
+	code, stdout, stderr := runCommand(t, "config", "validate")
+	assert.Equal(t, 1, code)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "server.port")
+	assert.Contains(t, stderr, "rate_limit.requests", "every problem is reported")
+}
+
+func TestRun_ConfigUsage(t *testing.T) {
+	code, _, stderr := runCommand(t, "config")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "homelab-api config validate")
+
+	code, _, _ = runCommand(t, "config", "lint")
+	assert.Equal(t, 2, code)
+}
+
+func TestRun_Healthcheck(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	code, stdout, _ := runCommand(t, "healthcheck", "-url", healthy.URL+"/health")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "healthy")
+
+	draining := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer draining.Close()
+
+	code, _, stderr := runCommand(t, "healthcheck", "-url", draining.URL+"/health")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "503 Service Unavailable")
+
+	draining.Close()
+	code, _, _ = runCommand(t, "healthcheck", "-url", draining.URL+"/health", "-timeout", "100ms")
+	assert.Equal(t, 1, code, "an unreachable server is unhealthy")
+}
+
+func TestRun_HealthcheckTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	code, _, stderr := runCommand(t, "healthcheck", "-url", ts.URL+"/health")
+	assert.Equal(t, 0, code, stderr)
+}
+
+func TestRun_HealthcheckInvalidConfig(t *testing.T) {
+	writeConfig(t, "server:\n  port: \"nope\"\n")
+
+	code, _, stderr := runCommand(t, "healthcheck")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "server.port")
+}
+
+func TestHealthURL(t *testing.T) {
+	cfg := config.Default()
+	assert.Equal(t, "http://127.0.0.1:8080/health", healthURL(cfg))
+
+	cfg.Server.TLS = config.TLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}
+	assert.Equal(t, "https://127.0.0.1:8080/health", healthURL(cfg))
+
+	cfg.Server.MetricsPort = "9100"
+	assert.Equal(t, "http://127.0.0.1:9100/health", healthURL(cfg), "the plain HTTP admin listener is preferred")
+}
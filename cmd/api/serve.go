@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-github/internal/automations"
+	"go-github/internal/cluster"
+	"go-github/internal/config"
+	"go-github/internal/logging"
+	internalmcp "go-github/internal/mcp"
+	"go-github/internal/scheduler"
+	"go-github/internal/server"
+	"go-github/internal/services"
+	"go-github/internal/tracing"
+	"go-github/internal/version"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// configWatchInterval is how often the config file is checked for changes.
+const configWatchInterval = 10 * time.Second
+
+// clusterWatchInterval is how often the cluster services are listed for
+// changes to publish on the event bus.
+const clusterWatchInterval = 30 * time.Second
+
+// serve runs the server until SIGINT or SIGTERM. By default it starts both
+// the HTTP API and the MCP stdio server; with mcpOnly ("homelab-api mcp") it
+// starts the MCP stdio server without binding the HTTP port. args are the
+// configuration flags.
+func serve(args []string, mcpOnly bool) int {
+	// Configuration layers defaults, the -config/CONFIG_FILE file, environment
+	// variables and flags, in increasing order of precedence.
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		return 2
+	}
+
+	// Log to stderr (stdout is reserved for the MCP stdio transport).
+	if err := logging.Setup(os.Stderr, cfg.Log.Level, cfg.Log.Format); err != nil {
+		slog.Error("invalid logging configuration", "error", err)
+		return 1
+	}
+	slog.Info("starting homelab-api", "version", version.Get().Version, "mcp_only", mcpOnly)
+	slog.Debug("configuration loaded", "config", cfg.String())
+
+	// Create a shared context that is cancelled on SIGINT/SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Export traces over OTLP/HTTP when a tracing endpoint is configured;
+	// otherwise spans are created against a no-op provider.
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		slog.Error("tracing setup failed", "error", err)
+		return 1
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("tracing shutdown error", "error", err)
+		}
+	}()
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	// Reload the configuration when the file changes, on SIGHUP, or through
+	// POST /admin/reload. Only live settings are applied; the rest are
+	// reported as requiring a restart.
+	reloader := config.NewReloader(cfg, func() (*config.Config, error) {
+		return config.Load(args)
+	})
+	reloader.OnReload(func(c *config.Config) {
+		if lvl, err := logging.ParseLevel(c.Log.Level); err == nil {
+			logging.SetLevel(lvl)
+		}
+	})
+	g.Go(func() error {
+		return reloader.Watch(gctx, configWatchInterval)
+	})
+	// Register for SIGHUP before serving so that an early signal reloads
+	// instead of terminating the process.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	g.Go(func() error {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-gctx.Done():
+				return nil
+			case <-hup:
+				slog.Info("received SIGHUP, reloading configuration")
+				_, _ = reloader.Reload()
+			}
+		}
+	})
+
+	// Run scheduled device commands, restoring the schedules persisted by
+	// the previous run. Both the HTTP API and MCP tools create schedules.
+	sched := scheduler.New(
+		scheduler.WithFile(cfg.Scheduler.File),
+		scheduler.WithMissedRunGrace(cfg.Scheduler.MissedRunGrace.Duration),
+	)
+	if err := sched.Load(); err != nil {
+		slog.Error("loading schedules failed", "error", err)
+		return 1
+	}
+	scheduler.SetDefault(sched)
+	g.Go(func() error {
+		return sched.Run(gctx)
+	})
+
+	// Run the automations on device, service and alert events. Automations
+	// from the file are loaded at startup; others are created through the API.
+	engine := automations.New(
+		automations.WithFile(cfg.Automations.File),
+		automations.WithNotifyURL(cfg.Automations.NotifyURL),
+		automations.WithMaxChainDepth(cfg.Automations.MaxChainDepth),
+	)
+	if err := engine.Load(); err != nil {
+		slog.Error("loading automations failed", "error", err)
+		return 1
+	}
+	automations.SetDefault(engine)
+	g.Go(func() error {
+		return engine.Run(gctx)
+	})
+
+	// Publish cluster services appearing, changing and disappearing on the
+	// event bus, next to the device and service changes, for the event stream
+	// and the MCP resource update notifications.
+	g.Go(func() error {
+		return cluster.NewService().Watch(gctx, clusterWatchInterval)
+	})
+
+	if mcpOnly {
+		reloader.OnReload(func(c *config.Config) {
+			services.SetCatalog(c.Services.Models())
+		})
+	} else {
+		srv := server.New(server.WithConfig(cfg), server.WithReloader(reloader))
+		reloader.OnReload(srv.ApplyConfig)
+
+		// Launch HTTP server goroutine.
+		g.Go(func() error {
+			slog.Info("http server started", "port", cfg.Server.Port, "tls", cfg.Server.TLS.Enabled())
+			if err := srv.Run(cfg.Server.Port); err != nil {
+				return err
+			}
+			return nil
+		})
+
+		// Launch admin server goroutine (no-op unless a metrics port is configured).
+		g.Go(func() error {
+			if cfg.Server.MetricsPort != "" {
+				slog.Info("admin server started", "port", cfg.Server.MetricsPort)
+			}
+			return srv.RunAdmin()
+		})
+
+		// Probe service endpoints periodically when a probe interval is configured.
+		if interval := cfg.Services.ProbeInterval.Duration; interval > 0 {
+			prober := services.NewProber(nil, interval)
+			g.Go(func() error {
+				return prober.Run(gctx)
+			})
+		}
+
+		// Goroutine to trigger graceful HTTP shutdown when context is done.
+		g.Go(func() error {
+			<-gctx.Done()
+
+			slog.Info("shutting down http server...")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			if err := srv.GracefulShutdown(shutdownCtx); err != nil {
+				slog.Error("http shutdown error", "error", err)
+			}
+			return nil
+		})
+	}
+
+	// Launch MCP stdio server goroutine (always runs).
+	g.Go(func() error {
+		return internalmcp.Run(gctx, internalmcp.WithConfig(cfg))
+	})
+
+	if err := g.Wait(); err != nil {
+		slog.Error("server error", "error", err)
+		return 1
+	}
+
+	slog.Info("all servers stopped gracefully")
+	return 0
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go-github/internal/version"
+	"go-github/pkg/client"
+)
+
+// newAPIClient returns an API client for opts, with the CA and client
+// certificate loaded for (mutual) TLS if given.
+func newAPIClient(opts options) (*client.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.CAFile != "" || opts.CertFile != "" {
+		tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if opts.CAFile != "" {
+			pem, err := os.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsCfg.RootCAs = x509.NewCertPool()
+			if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("%s contains no PEM certificates", opts.CAFile)
+			}
+		}
+		if opts.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+		transport.TLSClientConfig = tlsCfg
+	}
+
+	return client.New(opts.Server,
+		client.WithHTTPClient(&http.Client{Timeout: opts.Timeout, Transport: transport}),
+		client.WithAPIKey(opts.APIKey),
+		client.WithUserAgent("homelabctl/"+version.Get().Version),
+	)
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-github/pkg/client"
+)
+
+// cli is what commands run against.
+type cli struct {
+	client *client.Client
+	out    *printer
+	// warn receives warnings, which are kept out of the command's output.
+	warn io.Writer
+}
+
+// runFunc runs a command with its positional arguments.
+type runFunc func(ctx context.Context, c *cli, args []string) error
+
+// command is a leaf command. setup registers the command's own flags and
+// returns the function that runs it.
+type command struct {
+	name    string
+	args    string
+	summary string
+	help    string
+	minArgs int
+	maxArgs int // -1 for no limit
+	setup   func(fs *flag.FlagSet) runFunc
+}
+
+var commands = []*command{
+	{
+		name:    "devices list",
+		summary: "List HomeAssistant devices",
+		help:    "Lists all devices with their state.",
+		setup:   func(*flag.FlagSet) runFunc { return devicesList },
+	},
+	{
+		name:    "devices get",
+		args:    "<id>",
+		summary: "Show a device and its attributes",
+		help:    "Shows one device with its state and attributes.",
+		minArgs: 1, maxArgs: 1,
+		setup: func(*flag.FlagSet) runFunc { return devicesGet },
+	},
+	{
+		name:    "devices exec",
+		args:    "<id> <action> [key=value ...]",
+		summary: "Execute a command on a device",
+		help: "Executes action on a controllable device. Parameters are given as key=value;\n" +
+			"values that parse as JSON (numbers, booleans, objects) are sent as such,\n" +
+			"anything else as a string, e.g. homelabctl devices exec device-001 turn_on brightness=80",
+		minArgs: 2, maxArgs: -1,
+		setup: func(*flag.FlagSet) runFunc { return devicesExec },
+	},
+	{
+		name:    "services list",
+		summary: "List homelab services",
+		help:    "Lists the services in the homelab service catalog.",
+		setup:   func(*flag.FlagSet) runFunc { return servicesList },
+	},
+	{
+		name:    "cluster services",
+		args:    "[-namespace <ns>] [-name <substr>]",
+		summary: "List Kubernetes cluster services",
+		help:    "Lists Kubernetes cluster services, optionally filtered by namespace and name.",
+		setup: func(fs *flag.FlagSet) runFunc {
+			namespace := fs.String("namespace", "", "only list services in this namespace")
+			name := fs.String("name", "", "only list services whose name contains this (case-insensitive)")
+			return func(ctx context.Context, c *cli, _ []string) error {
+				return clusterServices(ctx, c, *namespace, *name)
+			}
+		},
+	},
+	{
+		name:    "health",
+		summary: "Check the API health",
+		help:    "Reports the health status of the API. Fails if the server is unhealthy.",
+		setup:   func(*flag.FlagSet) runFunc { return health },
+	},
+}
+
+// maxPageSize is the largest page the list endpoints return.
+const maxPageSize = 1000
+
+// lookupCommand finds the command named by the leading words of args and
+// returns it with the remaining args, or nil if there is none.
+func lookupCommand(args []string) (*command, []string) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):]
+		}
+	}
+	return nil, args
+}
+
+func devicesList(ctx context.Context, c *cli, _ []string) error {
+	// Fetch every page so the table and the JSON output list all devices.
+	resp := &client.DeviceListResponse{}
+	opts := &client.ListDevicesOptions{Limit: maxPageSize}
+	for {
+		page, err := c.client.ListDevices(ctx, opts)
+		if err != nil {
+			return err
+		}
+		resp.Devices = append(resp.Devices, page.Devices...)
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	resp.Count = len(resp.Devices)
+	return c.out.print(resp, func(tw *tabwriter.Writer) {
+		row(tw, "ID", "NAME", "TYPE", "STATE", "CONTROLLABLE", "LAST UPDATED")
+		for _, d := range resp.Devices {
+			row(tw, d.ID, d.Name, d.Type, d.State, d.Controllable, d.LastUpdated.Format(time.RFC3339))
+		}
+	})
+}
+
+func devicesGet(ctx context.Context, c *cli, args []string) error {
+	device, err := c.client.GetDevice(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.out.print(device, func(tw *tabwriter.Writer) {
+		row(tw, "ID:", device.ID)
+		row(tw, "Name:", device.Name)
+		row(tw, "Type:", device.Type)
+		row(tw, "State:", device.State)
+		row(tw, "Controllable:", device.Controllable)
+		row(tw, "Last updated:", device.LastUpdated.Format(time.RFC3339))
+		if len(device.Attributes) > 0 {
+			row(tw, "Attributes:")
+			keys := make([]string, 0, len(device.Attributes))
+			for k := range device.Attributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				row(tw, "  "+k+":", device.Attributes[k])
+			}
+		}
+	})
+}
+
+func devicesExec(ctx context.Context, c *cli, args []string) error {
+	cmd := client.Command{Action: args[1], Parameters: map[string]interface{}{}}
+	for _, p := range args[2:] {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid parameter %q: want key=value", p)
+		}
+		cmd.Parameters[key] = parseValue(value)
+	}
+
+	result, err := c.client.ExecuteCommand(ctx, args[0], cmd, nil)
+	if err != nil {
+		return err
+	}
+	return c.out.print(result, func(tw *tabwriter.Writer) {
+		row(tw, "DEVICE", "ACTION", "STATUS")
+		row(tw, result.DeviceID, result.Action, result.Status)
+	})
+}
+
+// parseValue returns value decoded as JSON, or as a string if it isn't JSON.
+func parseValue(value string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return value
+	}
+	return v
+}
+
+func servicesList(ctx context.Context, c *cli, _ []string) error {
+	resp := &client.ServicesResponse{}
+	opts := &client.ListServicesOptions{Limit: maxPageSize}
+	for {
+		page, err := c.client.ListServices(ctx, opts)
+		if err != nil {
+			return err
+		}
+		resp.Services = append(resp.Services, page.Services...)
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	return c.out.print(resp, func(tw *tabwriter.Writer) {
+		row(tw, "NAME", "TYPE", "STATUS", "ENDPOINT")
+		for _, s := range resp.Services {
+			row(tw, s.Name, s.Type, s.Status, s.Endpoint)
+		}
+	})
+}
+
+func clusterServices(ctx context.Context, c *cli, namespace, name string) error {
+	// Cluster services are a bare list without next_cursor, so ask for the
+	// largest page the server allows and warn when it may not be all of them.
+	opts := &client.ListClusterServicesOptions{Namespace: namespace, Name: name, Limit: maxPageSize}
+	services, err := c.client.ListClusterServices(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := c.out.print(services, func(tw *tabwriter.Writer) {
+		row(tw, "NAMESPACE", "NAME", "STATUS", "ENDPOINTS")
+		for _, s := range services {
+			row(tw, s.Namespace, s.Name, s.Status, strings.Join(s.Endpoints, ","))
+		}
+	}); err != nil {
+		return err
+	}
+	if len(services) == maxPageSize {
+		fmt.Fprintf(c.warn, "homelabctl: warning: only the first %d cluster services are shown; filter with -namespace or -name\n", maxPageSize)
+	}
+	return nil
+}
+
+func health(ctx context.Context, c *cli, _ []string) error {
+	health, err := c.client.Health(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.out.print(health, func(tw *tabwriter.Writer) {
+		row(tw, "STATUS")
+		row(tw, health.Status)
+	}); err != nil {
+		return err
+	}
+	if health.Status != "ok" {
+		return errors.New("server reported status " + health.Status)
+	}
+	return nil
+}
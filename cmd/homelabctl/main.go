@@ -0,0 +1,138 @@
+// Command homelabctl is a command-line client for the Home Lab API.
+//
+//	homelabctl [global flags] <command> [flags] [args]
+//
+// Run "homelabctl help" for the commands. The server URL and API key are read
+// from a config file, the environment or flags; see options.go.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+const usageHeader = `Usage: homelabctl [global flags] <command> [flags] [args]
+
+Commands:
+`
+
+const usageFooter = `
+Global flags (accepted before or after the command):
+  -server URL      API base URL (env HOMELAB_SERVER, default http://localhost:8080)
+  -api-key KEY     API key (env HOMELAB_API_KEY)
+  -o FORMAT        output format: table, json or yaml (env HOMELAB_OUTPUT)
+  -timeout D       request timeout, e.g. 5s (default 10s)
+  -config PATH     config file (env HOMELAB_CONFIG, default $XDG_CONFIG_HOME/homelab/config.yaml)
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command in args and returns the process exit code: 0 on
+// success, 1 when the request fails and 2 on usage errors.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	var g globalFlags
+	fs := flag.NewFlagSet("homelabctl", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	g.register(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printUsage(stdout)
+			return 0
+		}
+		fmt.Fprintf(stderr, "homelabctl: %v\n\n", err)
+		printUsage(stderr)
+		return 2
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 || rest[0] == "help" {
+		printUsage(stdout)
+		if len(rest) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, rest := lookupCommand(rest)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "homelabctl: unknown command %q\n\n", strings.Join(fs.Args(), " "))
+		printUsage(stderr)
+		return 2
+	}
+
+	cfs := flag.NewFlagSet("homelabctl "+cmd.name, flag.ContinueOnError)
+	cfs.SetOutput(stderr)
+	g.register(cfs)
+	runCmd := cmd.setup(cfs)
+	cfs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: homelabctl %s [flags] %s\n\n%s\n\nFlags:\n", cmd.name, cmd.args, cmd.help)
+		cfs.PrintDefaults()
+	}
+	positional, err := parseInterspersed(cfs, rest)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(positional) < cmd.minArgs || (cmd.maxArgs >= 0 && len(positional) > cmd.maxArgs) {
+		fmt.Fprintf(stderr, "homelabctl %s: wrong number of arguments\n", cmd.name)
+		cfs.Usage()
+		return 2
+	}
+
+	opts, err := resolveOptions(g)
+	if err != nil {
+		fmt.Fprintf(stderr, "homelabctl: %v\n", err)
+		return 2
+	}
+	client, err := newAPIClient(opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "homelabctl: %v\n", err)
+		return 2
+	}
+
+	c := &cli{client: client, out: newPrinter(opts.Output, stdout), warn: stderr}
+	if err := runCmd(ctx, c, positional); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed parses fs from args, allowing flags after positional
+// arguments, and returns the positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, usageHeader)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-46s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprint(w, usageFooter)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-github/internal/config"
+	"go-github/internal/models"
+	"go-github/internal/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testAPIKey = "homelabctl-test-key-0123456789"
+
+// startAPI serves the real router, with API key authentication enabled, and
+// points the client environment at it.
+func startAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Auth.APIKeys = []config.APIKey{{Name: "homelabctl", Key: config.Secret(testAPIKey)}}
+	ts := httptest.NewServer(server.New(server.WithConfig(cfg)).Router())
+	t.Cleanup(ts.Close)
+
+	t.Setenv("HOMELAB_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOMELAB_SERVER", ts.URL)
+	t.Setenv("HOMELAB_API_KEY", testAPIKey)
+	t.Setenv("HOMELAB_OUTPUT", "")
+	return ts
+}
+
+// runCLI runs homelabctl with args and returns the exit code and output.
+func runCLI(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = run(context.Background(), args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestDevicesList(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, stderr := runCLI(t, "devices", "list")
+	require.Equal(t, 0, code, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Regexp(t, `^ID\s+NAME\s+TYPE\s+STATE\s+CONTROLLABLE\s+LAST UPDATED$`, lines[0])
+	assert.Contains(t, stdout, "device-001")
+	assert.Contains(t, stdout, "Living Room Light")
+
+	code, stdout, _ = runCLI(t, "devices", "list", "-o", "json")
+	require.Equal(t, 0, code)
+	var resp models.DeviceListResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &resp))
+	assert.Equal(t, len(resp.Devices), resp.Count)
+	assert.NotEmpty(t, resp.Devices)
+}
+
+func TestDevicesGet_YAML(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, stderr := runCLI(t, "-o", "yaml", "devices", "get", "readonly-sensor-001")
+	require.Equal(t, 0, code, stderr)
+
+	var device map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(stdout), &device))
+	assert.Equal(t, "readonly-sensor-001", device["id"], "YAML uses the JSON field names")
+	assert.Equal(t, "72", device["state"], "strings that look like numbers stay strings")
+	assert.Equal(t, false, device["controllable"])
+	assert.True(t, strings.HasPrefix(stdout, "id: readonly-sensor-001\n"), "keys keep the JSON order:\n%s", stdout)
+	assert.Contains(t, stdout, `state: "72"`)
+}
+
+func TestWriteYAML_QuotesAmbiguousStrings(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeYAML(&buf, map[string]interface{}{"state": "off", "name": "lamp", "on": true}))
+	assert.Equal(t, "name: lamp\n\"on\": true\nstate: \"off\"\n", buf.String())
+}
+
+func TestDevicesGet_Table(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, _ := runCLI(t, "devices", "get", "device-001")
+	require.Equal(t, 0, code)
+	assert.Regexp(t, `Name:\s+Living Room Light`, stdout)
+	assert.Regexp(t, `brightness:\s+\d+`, stdout)
+}
+
+func TestDevicesGet_NotFound(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, stderr := runCLI(t, "devices", "get", "nope")
+	assert.Equal(t, 1, code)
+	assert.Empty(t, stdout)
+	assert.Equal(t, "Error: device not found: nope (not_found, HTTP 404)\n", stderr)
+}
+
+func TestDevicesExec(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, stderr := runCLI(t, "devices", "exec", "device-001", "turn_on", "brightness=80", "-o", "json")
+	require.Equal(t, 0, code, stderr)
+	var result map[string]string
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, "device-001", result["device_id"])
+	assert.Equal(t, "turn_on", result["action"])
+	assert.NotEmpty(t, result["status"])
+
+	code, _, stderr = runCLI(t, "devices", "exec", "readonly-sensor-001", "turn_on")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "method_not_allowed, HTTP 405")
+
+	code, _, stderr = runCLI(t, "devices", "exec", "device-001", "turn_on", "brightness")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `invalid parameter "brightness"`)
+}
+
+func TestParseValue(t *testing.T) {
+	assert.Equal(t, float64(80), parseValue("80"))
+	assert.Equal(t, true, parseValue("true"))
+	assert.Equal(t, map[string]interface{}{"r": float64(255)}, parseValue(`{"r":255}`))
+	assert.Equal(t, "warm white", parseValue("warm white"))
+}
+
+func TestServicesList(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, _ := runCLI(t, "services", "list")
+	require.Equal(t, 0, code)
+	assert.Regexp(t, `^NAME\s+TYPE\s+STATUS\s+ENDPOINT\n`, stdout)
+
+	code, stdout, _ = runCLI(t, "services", "list", "-o", "json")
+	require.Equal(t, 0, code)
+	var resp models.ServicesResponse
+	require.NoError(t, json.Unmarshal([]byte(stdout), &resp))
+	assert.NotEmpty(t, resp.Services)
+}
+
+func TestClusterServices(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, _ := runCLI(t, "cluster", "services", "-namespace", "default", "-o", "json")
+	require.Equal(t, 0, code)
+	var services []models.ServiceInfo
+	require.NoError(t, json.Unmarshal([]byte(stdout), &services))
+	require.NotEmpty(t, services)
+	for _, s := range services {
+		assert.Equal(t, "default", s.Namespace)
+	}
+
+	code, stdout, _ = runCLI(t, "cluster", "services", "-namespace", "monitoring", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "[]\n", stdout)
+}
+
+func TestClusterServices_WarnsWhenTruncated(t *testing.T) {
+	services := make([]models.ServiceInfo, maxPageSize)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1000", r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(services)
+	}))
+	defer ts.Close()
+
+	t.Setenv("HOMELAB_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOMELAB_OUTPUT", "")
+
+	code, stdout, stderr := runCLI(t, "-server", ts.URL, "cluster", "services", "-o", "json")
+	require.Equal(t, 0, code, stderr)
+	var got []models.ServiceInfo
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Len(t, got, maxPageSize)
+	assert.Contains(t, stderr, "only the first 1000 cluster services are shown")
+}
+
+func TestHealth(t *testing.T) {
+	startAPI(t)
+
+	code, stdout, _ := runCLI(t, "health")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "STATUS\nok\n", stdout)
+}
+
+func TestAuthFailure(t *testing.T) {
+	startAPI(t)
+	t.Setenv("HOMELAB_API_KEY", "")
+
+	code, _, stderr := runCLI(t, "services", "list")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "Error: missing API key (unauthorized, HTTP 401)\n", stderr)
+}
+
+func TestNonJSONError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream connect error", http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	t.Setenv("HOMELAB_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOMELAB_OUTPUT", "")
+
+	code, _, stderr := runCLI(t, "-server", ts.URL, "health")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "Error: upstream connect error (HTTP 502)\n", stderr)
+}
+
+func TestResolveOptions_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server: http://file.example:8080/
+api_key: file-key
+output: yaml
+timeout: 3s
+`), 0o600))
+	t.Setenv("HOMELAB_CONFIG", path)
+	t.Setenv("HOMELAB_SERVER", "")
+	t.Setenv("HOMELAB_API_KEY", "env-key")
+	t.Setenv("HOMELAB_OUTPUT", "")
+
+	opts, err := resolveOptions(globalFlags{output: "json"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://file.example:8080", opts.Server)
+	assert.Equal(t, "env-key", opts.APIKey, "environment overrides the file")
+	assert.Equal(t, "json", opts.Output, "flags override the environment and file")
+	assert.Equal(t, "3s", opts.Timeout.String())
+}
+
+func TestResolveOptions_Errors(t *testing.T) {
+	t.Setenv("HOMELAB_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("HOMELAB_OUTPUT", "")
+
+	_, err := resolveOptions(globalFlags{})
+	assert.ErrorContains(t, err, "config:", "HOMELAB_CONFIG must point at an existing file")
+
+	t.Setenv("HOMELAB_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	_, err = resolveOptions(globalFlags{})
+	assert.NoError(t, err, "the default config file is optional")
+
+	_, err = resolveOptions(globalFlags{output: "xml"})
+	assert.ErrorContains(t, err, `unknown output format "xml"`)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sever: typo\n"), 0o600))
+	_, err = resolveOptions(globalFlags{config: path})
+	assert.ErrorContains(t, err, "field sever not found")
+}
+
+func TestUsage(t *testing.T) {
+	code, stdout, _ := runCLI(t, "help")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "devices exec <id> <action> [key=value ...]")
+
+	code, _, _ = runCLI(t)
+	assert.Equal(t, 2, code)
+
+	code, _, stderr := runCLI(t, "-config", filepath.Join(t.TempDir(), "none.yaml"), "health")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "config:", "an explicitly named config file must exist")
+
+	code, _, stderr = runCLI(t, "devices", "remove")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "devices remove"`)
+
+	code, _, stderr = runCLI(t, "devices", "get")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "wrong number of arguments")
+}
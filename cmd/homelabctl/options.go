@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by -o.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// options are the resolved client settings. They are layered in increasing
+// order of precedence: defaults, the config file, environment variables and
+// flags.
+type options struct {
+	Server  string        `yaml:"server"`
+	APIKey  string        `yaml:"api_key"`
+	Output  string        `yaml:"output"`
+	Timeout time.Duration `yaml:"timeout"`
+	// CAFile verifies the server certificate against this PEM bundle
+	// instead of the system roots.
+	CAFile string `yaml:"ca_file"`
+	// CertFile and KeyFile are presented as a client certificate to servers
+	// that require mutual TLS.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+func defaultOptions() options {
+	return options{
+		Server:  "http://localhost:8080",
+		Output:  outputTable,
+		Timeout: 10 * time.Second,
+	}
+}
+
+// globalFlags are the flags every command accepts. Empty values mean unset,
+// so that they don't mask the config file or environment.
+type globalFlags struct {
+	config  string
+	server  string
+	apiKey  string
+	output  string
+	timeout time.Duration
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.config, "config", g.config, "config file (env HOMELAB_CONFIG)")
+	fs.StringVar(&g.server, "server", g.server, "API base URL (env HOMELAB_SERVER)")
+	fs.StringVar(&g.apiKey, "api-key", g.apiKey, "API key (env HOMELAB_API_KEY)")
+	fs.StringVar(&g.output, "o", g.output, "output format: table, json or yaml (env HOMELAB_OUTPUT)")
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "request timeout")
+}
+
+// resolveOptions layers the config file, environment and flags over the
+// defaults and validates the result.
+func resolveOptions(g globalFlags) (options, error) {
+	opts := defaultOptions()
+
+	path, required := g.config, true
+	if path == "" {
+		path = os.Getenv("HOMELAB_CONFIG")
+	}
+	if path == "" {
+		path, required = defaultConfigPath(), false
+	}
+	if path != "" {
+		if err := opts.loadFile(path, required); err != nil {
+			return options{}, err
+		}
+	}
+
+	for _, env := range []struct {
+		dst *string
+		key string
+	}{
+		{&opts.Server, "HOMELAB_SERVER"},
+		{&opts.APIKey, "HOMELAB_API_KEY"},
+		{&opts.Output, "HOMELAB_OUTPUT"},
+	} {
+		if v := os.Getenv(env.key); v != "" {
+			*env.dst = v
+		}
+	}
+
+	if g.server != "" {
+		opts.Server = g.server
+	}
+	if g.apiKey != "" {
+		opts.APIKey = g.apiKey
+	}
+	if g.output != "" {
+		opts.Output = g.output
+	}
+	if g.timeout != 0 {
+		opts.Timeout = g.timeout
+	}
+
+	opts.Server = strings.TrimRight(opts.Server, "/")
+	switch opts.Output {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return options{}, fmt.Errorf("unknown output format %q (want table, json or yaml)", opts.Output)
+	}
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return options{}, errors.New("cert_file and key_file must be set together")
+	}
+	return opts, nil
+}
+
+// defaultConfigPath returns $XDG_CONFIG_HOME/homelab/config.yaml (or the
+// platform equivalent), or "" when there is no user config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "homelab", "config.yaml")
+}
+
+// loadFile decodes the YAML file at path into o. A missing file is only an
+// error when it was named explicitly.
+func (o *options) loadFile(path string, required bool) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(o); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// printer writes command results in the selected output format.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) *printer {
+	return &printer{format: format, w: w}
+}
+
+// print writes v as JSON or YAML, or calls table to render it as a table.
+// JSON and YAML use the API's JSON field names.
+func (p *printer) print(v any, table func(tw *tabwriter.Writer)) error {
+	switch p.format {
+	case outputJSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		return writeYAML(p.w, v)
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	}
+}
+
+// writeYAML renders v as YAML with the keys and key order of its JSON
+// encoding, so that both formats describe the same document.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle drops the flow and quoting styles yaml.v3 records when reading
+// JSON, so the output uses block YAML. Strings stay quoted where yaml.v3 would
+// quote them itself, e.g. "off" or "72", so that they read back as strings.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		if out, err := yaml.Marshal(n.Value); err == nil && (out[0] == '"' || out[0] == '\'') {
+			n.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// row writes tab-separated cells to tw.
+func row(tw *tabwriter.Writer, cells ...any) {
+	for i, c := range cells {
+		if i > 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, c)
+	}
+	fmt.Fprintln(tw)
+}
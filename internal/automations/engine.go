@@ -0,0 +1,748 @@
+// Package automations runs if-this-then-that rules inside the API. A rule is
+// triggered by a device state change, a service going up or down, a firing
+// alert or a cron expression; if its conditions on device state hold, its
+// actions (device commands, webhooks and notifications) run in order, and
+// the run is kept as an execution trace.
+//
+// Device and service changes come from the event bus (package events) and
+// firing alerts from the Alertmanager webhook receiver. Because a rule's
+// commands can change devices that trigger other rules, or itself, every run
+// knows its depth in such a chain, and runs deeper than the configured limit
+// are blocked as loops.
+package automations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/homeassistant"
+	"go-github/internal/metrics"
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+	"go-github/internal/scheduler"
+	"go-github/internal/services"
+	"go-github/internal/tracing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Statuses of a run.
+const (
+	RunSuccess          = "success"
+	RunFailed           = "failed"
+	RunConditionsNotMet = "conditions_not_met"
+	RunLoopBlocked      = "loop_blocked"
+	RunNotTriggered     = "not_triggered"
+	RunWouldRun         = "would_run"
+)
+
+// Statuses of an action in a run.
+const (
+	ActionSuccess = "success"
+	ActionFailed  = "failed"
+	ActionSkipped = "skipped"
+	ActionDryRun  = "dry_run"
+)
+
+// Sources of an automation.
+const (
+	SourceAPI  = "api"
+	SourceFile = "file"
+)
+
+const (
+	// defaultMaxChainDepth is the chain depth limit unless WithMaxChainDepth
+	// says otherwise.
+	defaultMaxChainDepth = 5
+	// runsKept is how many runs of each automation are kept.
+	runsKept = 50
+	// eventBuffer is how many events of each source may wait for the
+	// engine before new ones are dropped.
+	eventBuffer = 256
+	// originTTL is how long the depth of a run is remembered for the device
+	// changes its commands cause.
+	originTTL = 5 * time.Minute
+	// actionTimeout bounds a webhook or notification.
+	actionTimeout = 10 * time.Second
+)
+
+// Errors returned by the Engine methods.
+var (
+	ErrNotFound = errors.New("automation not found")
+	ErrExists   = errors.New("automation already exists")
+	ErrReadOnly = errors.New("automation is defined in the automations file")
+)
+
+// executeFunc executes a device command; it is
+// homeassistant.ExecuteCommandContext outside tests.
+type executeFunc func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error)
+
+// Engine holds the automations and runs them when they are triggered.
+type Engine struct {
+	file      string
+	notifyURL string
+	maxDepth  int
+	client    *http.Client
+	now       func() time.Time
+	execute   executeFunc
+
+	mu      sync.Mutex
+	rules   map[string]*rule
+	runs    map[string][]models.AutomationRun
+	origins map[string]origin
+
+	events chan models.AutomationEvent
+	// wake interrupts Run's wait when the time triggers change.
+	wake chan struct{}
+}
+
+// rule is an automation with the parsed cron expressions of its time
+// triggers and when each fires next; both are nil for other triggers.
+type rule struct {
+	models.Automation
+	crons []*scheduler.Cron
+	next  []time.Time
+}
+
+// origin is the chain depth of a run, remembered by its request ID.
+type origin struct {
+	depth int
+	at    time.Time
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithFile loads the automations in the YAML file path on Load.
+func WithFile(path string) Option {
+	return func(e *Engine) { e.file = path }
+}
+
+// WithNotifyURL posts notify actions to url. Without it, notifications are
+// only logged.
+func WithNotifyURL(url string) Option {
+	return func(e *Engine) { e.notifyURL = url }
+}
+
+// WithMaxChainDepth sets how many automations may trigger each other in a
+// row. Zero keeps the default of five.
+func WithMaxChainDepth(n int) Option {
+	return func(e *Engine) {
+		if n > 0 {
+			e.maxDepth = n
+		}
+	}
+}
+
+// WithHTTPClient sends webhooks and notifications with client. The default
+// client times out after ten seconds and forwards the trace context and
+// request ID.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Engine) { e.client = client }
+}
+
+// New creates an Engine with no automations; call Load to read the
+// automations file and Run to start running them.
+func New(opts ...Option) *Engine {
+	e := &Engine{
+		maxDepth: defaultMaxChainDepth,
+		client: &http.Client{
+			Timeout:   actionTimeout,
+			Transport: tracing.Transport(requestid.Transport(nil)),
+		},
+		now:     time.Now,
+		execute: homeassistant.ExecuteCommandContext,
+		rules:   map[string]*rule{},
+		runs:    map[string][]models.AutomationRun{},
+		origins: map[string]origin{},
+		events:  make(chan models.AutomationEvent, eventBuffer),
+		wake:    make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+var (
+	defaultMu sync.Mutex
+	defaultE  *Engine
+)
+
+// Default returns the engine used by the HTTP handlers. Until SetDefault is
+// called it is an engine without automations that is not running.
+func Default() *Engine {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultE == nil {
+		defaultE = New()
+	}
+	return defaultE
+}
+
+// SetDefault replaces the engine returned by Default.
+func SetDefault(e *Engine) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultE = e
+}
+
+// fileContents is the layout of the automations file.
+type fileContents struct {
+	Automations []models.AutomationRequest `json:"automations"`
+}
+
+// Load reads the automations file given by WithFile, replacing the
+// automations that came from it. Every rule is validated as for the API, and
+// any invalid rule fails the whole file.
+func (e *Engine) Load() error {
+	if e.file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(e.file)
+	if err != nil {
+		return fmt.Errorf("read automations: %w", err)
+	}
+	// The file has the fields of the API, so it is decoded as JSON.
+	js, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return fmt.Errorf("parse automations %s: %w", e.file, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.DisallowUnknownFields()
+	var contents fileContents
+	if err := dec.Decode(&contents); err != nil {
+		return fmt.Errorf("parse automations %s: %w", e.file, err)
+	}
+
+	now := e.now()
+	loaded := make(map[string]*rule, len(contents.Automations))
+	for i, r := range contents.Automations {
+		if problems := ValidateAutomation(&r); len(problems) > 0 {
+			return fmt.Errorf("automations %s: automations[%d]: %s: %s", e.file, i, problems[0].Pointer, problems[0].Detail)
+		}
+		id := r.ID
+		if id == "" {
+			id = homeassistant.Slugify(r.Name)
+		}
+		if _, ok := loaded[id]; ok {
+			return fmt.Errorf("automations %s: automations[%d]: duplicate id %q", e.file, i, id)
+		}
+		loaded[id] = newRule(id, r, SourceFile, now)
+	}
+
+	e.mu.Lock()
+	for id := range loaded {
+		if existing, ok := e.rules[id]; ok && existing.Source != SourceFile {
+			e.mu.Unlock()
+			return fmt.Errorf("automations %s: id %q: %w", e.file, id, ErrExists)
+		}
+	}
+	for id, r := range e.rules {
+		if r.Source == SourceFile {
+			delete(e.rules, id)
+		}
+	}
+	maps.Copy(e.rules, loaded)
+	e.mu.Unlock()
+	e.notify()
+	return nil
+}
+
+// newRule returns the rule id for r, which must be valid.
+func newRule(id string, r models.AutomationRequest, source string, now time.Time) *rule {
+	enabled := r.Enabled == nil || *r.Enabled
+	conditions := r.Conditions
+	if conditions == nil {
+		conditions = []models.AutomationCondition{}
+	}
+	rl := &rule{
+		Automation: models.Automation{
+			ID:          id,
+			Name:        strings.TrimSpace(r.Name),
+			Description: r.Description,
+			Enabled:     enabled,
+			Triggers:    r.Triggers,
+			Conditions:  conditions,
+			Actions:     r.Actions,
+			Source:      source,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		crons: make([]*scheduler.Cron, len(r.Triggers)),
+		next:  make([]time.Time, len(r.Triggers)),
+	}
+	for i, t := range r.Triggers {
+		if t.Type != TriggerTime {
+			continue
+		}
+		loc, _ := scheduler.LoadLocation(t.Timezone)
+		rl.crons[i], _ = scheduler.ParseCron(t.Cron, loc)
+		rl.next[i] = rl.crons[i].Next(now)
+	}
+	return rl
+}
+
+// Run runs the automations as their triggers fire until ctx is cancelled.
+func (e *Engine) Run(ctx context.Context) error {
+	slog.Info("automation engine started", "automations", len(e.List()), "file", e.file)
+
+	devices := events.Subscribe(events.Default(), homeassistant.DeviceChanged,
+		events.WithName("automations"), events.WithBuffer(eventBuffer))
+	defer devices.Close()
+	statuses := events.Subscribe(events.Default(), services.StatusChanged,
+		events.WithName("automations"), events.WithBuffer(eventBuffer))
+	defer statuses.Close()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		e.fireDue(ctx)
+
+		wait := time.Hour
+		if next, ok := e.nextTime(); ok {
+			wait = max(next.Sub(e.now()), 0)
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case c := <-devices.C():
+			e.Handle(ctx, deviceEvent(c))
+		case c := <-statuses.C():
+			e.Handle(ctx, statusEvent(c))
+		case ev := <-e.events:
+			e.Handle(ctx, ev)
+		case <-e.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// Publish queues ev for Run without waiting. It returns false, and the event
+// is dropped, if too many events are already waiting.
+func (e *Engine) Publish(ev models.AutomationEvent) bool {
+	select {
+	case e.events <- ev:
+		return true
+	default:
+		slog.Warn("automation event dropped, engine is busy", "type", ev.Type, "device_id", ev.DeviceID, "service", ev.Service)
+		return false
+	}
+}
+
+// PublishAlerts queues an alert event for every firing alert of w, named by
+// its alertname label, and reports how many there were.
+func (e *Engine) PublishAlerts(w models.AlertWebhook) models.AlertsResult {
+	var res models.AlertsResult
+	for _, a := range w.Alerts {
+		if a.Status != "firing" {
+			continue
+		}
+		at := a.StartsAt
+		if at.IsZero() {
+			at = e.now()
+		}
+		e.Publish(models.AutomationEvent{
+			Type:      TriggerAlert,
+			AlertName: a.Labels["alertname"],
+			Labels:    maps.Clone(a.Labels),
+			Time:      at,
+		})
+		res.Firing++
+	}
+	return res
+}
+
+// statusEvent returns the event of a service status change.
+func statusEvent(c services.StatusChange) models.AutomationEvent {
+	status := "down"
+	if c.Current.Up {
+		status = "up"
+	}
+	return models.AutomationEvent{Type: TriggerServiceStatus, Service: c.Current.Service, Status: status, Time: c.Current.CheckedAt}
+}
+
+// notify wakes Run to recompute its wait.
+func (e *Engine) notify() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nextTime returns when the next time trigger fires.
+func (e *Engine) nextTime() (time.Time, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var next time.Time
+	for _, r := range e.rules {
+		for _, t := range r.next {
+			if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// fireDue runs the automations whose time triggers are due, and moves the
+// triggers to their next time. Disabled automations do not run, but their
+// triggers move on all the same.
+func (e *Engine) fireDue(ctx context.Context) {
+	now := e.now()
+	type firing struct {
+		a  models.Automation
+		ev models.AutomationEvent
+	}
+	var due []firing
+	e.mu.Lock()
+	for _, r := range e.rules {
+		for i, t := range r.next {
+			if t.IsZero() || t.After(now) {
+				continue
+			}
+			if r.Enabled {
+				due = append(due, firing{r.Automation, models.AutomationEvent{Type: TriggerTime, Time: t}})
+			}
+			r.next[i] = r.crons[i].Next(now)
+		}
+	}
+	e.mu.Unlock()
+
+	slices.SortFunc(due, func(a, b firing) int { return strings.Compare(a.a.ID, b.a.ID) })
+	for _, f := range due {
+		e.run(ctx, f.a, f.ev)
+	}
+}
+
+// Handle runs every enabled automation that ev triggers, in order of ID, and
+// returns their runs. Run calls it for each published event.
+func (e *Engine) Handle(ctx context.Context, ev models.AutomationEvent) []models.AutomationRun {
+	if ev.Type == TriggerTime {
+		return nil
+	}
+	var triggered []models.Automation
+	e.mu.Lock()
+	for _, r := range e.rules {
+		if r.Enabled && slices.ContainsFunc(r.Triggers, func(t models.AutomationTrigger) bool { return matches(t, ev) }) {
+			triggered = append(triggered, r.Automation)
+		}
+	}
+	e.mu.Unlock()
+
+	slices.SortFunc(triggered, func(a, b models.Automation) int { return strings.Compare(a.ID, b.ID) })
+	runs := make([]models.AutomationRun, 0, len(triggered))
+	for _, a := range triggered {
+		runs = append(runs, e.run(ctx, a, ev))
+	}
+	return runs
+}
+
+// run runs a for ev and records the run.
+func (e *Engine) run(ctx context.Context, a models.Automation, ev models.AutomationEvent) models.AutomationRun {
+	depth := 1
+	e.mu.Lock()
+	if o, ok := e.origins[ev.RequestID]; ok && ev.RequestID != "" {
+		depth = o.depth + 1
+	}
+	e.mu.Unlock()
+
+	run := e.newRun(a.ID, ev, depth)
+	ctx = requestid.NewContext(ctx, run.ID)
+	ctx, span := tracing.Tracer().Start(ctx, "automation.run")
+	span.SetAttributes(
+		attribute.String("automation.id", a.ID),
+		attribute.String("automation.trigger", ev.Type),
+		attribute.Int("automation.depth", depth),
+		attribute.String("request_id", run.ID),
+	)
+	defer span.End()
+
+	if depth > e.maxDepth {
+		run.Status = RunLoopBlocked
+		run.Error = fmt.Sprintf("chain of automations is deeper than %d; automations may be triggering each other", e.maxDepth)
+		slog.WarnContext(ctx, "automation blocked as a loop", "automation_id", a.ID, "depth", depth)
+	} else {
+		e.remember(run.ID, depth)
+		if e.check(&run, a) {
+			e.act(ctx, &run, a, false)
+		}
+	}
+	run.FinishedAt = e.now()
+	if run.Status == RunFailed || run.Status == RunLoopBlocked {
+		span.SetStatus(codes.Error, run.Status)
+	}
+	slog.InfoContext(ctx, "automation ran", "automation_id", a.ID, "trigger", ev.Type, "status", run.Status, "depth", depth)
+	metrics.AutomationRunsTotal.WithLabelValues(run.Status).Inc()
+	e.record(run)
+	return run
+}
+
+// newRun starts the trace of a run.
+func (e *Engine) newRun(id string, ev models.AutomationEvent, depth int) models.AutomationRun {
+	return models.AutomationRun{
+		ID:           uuid.Must(uuid.NewV7()).String(),
+		AutomationID: id,
+		Event:        ev,
+		Depth:        depth,
+		Conditions:   []models.AutomationConditionResult{},
+		Actions:      []models.AutomationActionResult{},
+		StartedAt:    e.now(),
+	}
+}
+
+// remember records the depth of the run id, so that the device changes its
+// commands cause carry the chain on, and forgets old runs.
+func (e *Engine) remember(id string, depth int) {
+	now := e.now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for k, o := range e.origins {
+		if now.Sub(o.at) > originTTL {
+			delete(e.origins, k)
+		}
+	}
+	e.origins[id] = origin{depth: depth, at: now}
+}
+
+// check evaluates the conditions of a into run and reports whether they all
+// hold; if not, the run ends as conditions_not_met.
+func (e *Engine) check(run *models.AutomationRun, a models.Automation) bool {
+	ok := true
+	for i, c := range a.Conditions {
+		res := evaluate(i, c)
+		run.Conditions = append(run.Conditions, res)
+		ok = ok && res.Passed
+	}
+	if !ok {
+		run.Status = RunConditionsNotMet
+	}
+	return ok
+}
+
+// act runs the actions of a in order, into run. After a failed action the
+// rest are skipped. In a dry run no action runs.
+func (e *Engine) act(ctx context.Context, run *models.AutomationRun, a models.Automation, dryRun bool) {
+	run.Status = RunSuccess
+	if dryRun {
+		run.Status = RunWouldRun
+	}
+	for i, action := range a.Actions {
+		res := models.AutomationActionResult{Index: i, Type: action.Type}
+		switch {
+		case dryRun:
+			res.Status = ActionDryRun
+		case run.Status == RunFailed:
+			res.Status = ActionSkipped
+		default:
+			if err := e.perform(ctx, *run, action); err != nil {
+				res.Status, res.Error = ActionFailed, err.Error()
+				run.Status = RunFailed
+			} else {
+				res.Status = ActionSuccess
+			}
+		}
+		run.Actions = append(run.Actions, res)
+	}
+}
+
+// perform runs one action of run.
+func (e *Engine) perform(ctx context.Context, run models.AutomationRun, action models.AutomationAction) error {
+	switch action.Type {
+	case ActionCommand:
+		params := maps.Clone(action.Parameters)
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		_, err := e.execute(ctx, action.DeviceID, models.Command{Action: action.Action, Parameters: params})
+		return err
+	case ActionWebhook:
+		return e.post(ctx, action.URL, map[string]interface{}{
+			"automation_id": run.AutomationID,
+			"run_id":        run.ID,
+			"event":         run.Event,
+		})
+	case ActionNotify:
+		slog.InfoContext(ctx, "automation notification", "automation_id", run.AutomationID, "title", action.Title, "message", action.Message)
+		if e.notifyURL == "" {
+			return nil
+		}
+		return e.post(ctx, e.notifyURL, map[string]interface{}{
+			"automation_id": run.AutomationID,
+			"run_id":        run.ID,
+			"title":         action.Title,
+			"message":       action.Message,
+		})
+	}
+	return fmt.Errorf("unknown action type %q", action.Type)
+}
+
+// post sends body to url as JSON and fails unless the answer is 2xx.
+func (e *Engine) post(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// record keeps run as the latest run of its automation.
+func (e *Engine) record(run models.AutomationRun) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.rules[run.AutomationID]
+	if !ok {
+		// Deleted while running.
+		return
+	}
+	r.Runs++
+	started := run.StartedAt
+	r.LastRunAt = &started
+	runs := append(e.runs[run.AutomationID], run)
+	if len(runs) > runsKept {
+		runs = slices.Clone(runs[len(runs)-runsKept:])
+	}
+	e.runs[run.AutomationID] = runs
+}
+
+// DryRun evaluates the automation id as if req.Event had happened: whether
+// the event matches a trigger, and whether the conditions hold on the
+// current device state. No action runs and the run is not recorded.
+func (e *Engine) DryRun(id string, req models.DryRunRequest) (models.AutomationRun, error) {
+	a, err := e.Get(id)
+	if err != nil {
+		return models.AutomationRun{}, err
+	}
+	ev := models.AutomationEvent{Type: "manual", Time: e.now()}
+	if req.Event != nil {
+		ev = *req.Event
+	}
+	run := e.newRun(id, ev, 1)
+	run.DryRun = true
+	switch {
+	case req.Event != nil && !slices.ContainsFunc(a.Triggers, func(t models.AutomationTrigger) bool { return matches(t, ev) }):
+		run.Status = RunNotTriggered
+	case e.check(&run, a):
+		e.act(context.Background(), &run, a, true)
+	}
+	run.FinishedAt = e.now()
+	return run, nil
+}
+
+// List returns every automation, ordered by ID.
+func (e *Engine) List() []models.Automation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]models.Automation, 0, len(e.rules))
+	for _, r := range e.rules {
+		out = append(out, r.Automation)
+	}
+	slices.SortFunc(out, func(a, b models.Automation) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// Get returns the automation id, or ErrNotFound.
+func (e *Engine) Get(id string) (models.Automation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.rules[id]
+	if !ok {
+		return models.Automation{}, ErrNotFound
+	}
+	return r.Automation, nil
+}
+
+// Create adds the automation r, which must be valid. Without an ID, the ID
+// is derived from the name, with a suffix (_2, _3, ...) if it is taken. It
+// returns ErrExists if r names the ID of an existing automation.
+func (e *Engine) Create(r models.AutomationRequest) (models.Automation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := homeassistant.UniqueID(r.ID, r.Name, func(id string) bool { return e.rules[id] != nil })
+	if _, ok := e.rules[id]; ok {
+		return models.Automation{}, ErrExists
+	}
+	rl := newRule(id, r, SourceAPI, e.now())
+	e.rules[id] = rl
+	e.notify()
+	return rl.Automation, nil
+}
+
+// Update replaces the automation id with r, which must be valid; r.ID is
+// ignored. Its runs are kept. Automations from the file are read-only.
+func (e *Engine) Update(id string, r models.AutomationRequest) (models.Automation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	old, ok := e.rules[id]
+	switch {
+	case !ok:
+		return models.Automation{}, ErrNotFound
+	case old.Source == SourceFile:
+		return models.Automation{}, ErrReadOnly
+	}
+	rl := newRule(id, r, SourceAPI, e.now())
+	rl.CreatedAt, rl.Runs, rl.LastRunAt = old.CreatedAt, old.Runs, old.LastRunAt
+	e.rules[id] = rl
+	e.notify()
+	return rl.Automation, nil
+}
+
+// Delete removes the automation id and its runs. Automations from the file
+// are read-only.
+func (e *Engine) Delete(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	r, ok := e.rules[id]
+	switch {
+	case !ok:
+		return ErrNotFound
+	case r.Source == SourceFile:
+		return ErrReadOnly
+	}
+	delete(e.rules, id)
+	delete(e.runs, id)
+	e.notify()
+	return nil
+}
+
+// Runs returns the latest runs of the automation id, oldest first.
+func (e *Engine) Runs(id string) ([]models.AutomationRun, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.rules[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return append([]models.AutomationRun{}, e.runs[id]...), nil
+}
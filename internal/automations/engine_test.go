@@ -0,0 +1,543 @@
+package automations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+	"go-github/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// call is a command executed by a test engine.
+type call struct {
+	deviceID  string
+	action    string
+	requestID string
+}
+
+// testEngine returns an engine whose clock is *now and which records the
+// commands it executes instead of sending them.
+func testEngine(t *testing.T, now *time.Time, opts ...Option) (*Engine, *[]call) {
+	t.Helper()
+	e := New(opts...)
+	e.now = func() time.Time { return *now }
+	var calls []call
+	e.execute = func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+		calls = append(calls, call{deviceID, cmd.Action, requestid.FromContext(ctx)})
+		if cmd.Action == "explode" {
+			return models.CommandResult{}, errors.New("unsupported action: explode")
+		}
+		return models.CommandResult{Status: "success", DeviceID: deviceID, Action: cmd.Action}, nil
+	}
+	return e, &calls
+}
+
+// lightOn is an automation that turns device-001 on when the sensor's state
+// changes.
+func lightOn(id string) models.AutomationRequest {
+	return models.AutomationRequest{
+		ID:       id,
+		Name:     "Light on",
+		Triggers: []models.AutomationTrigger{{Type: TriggerDeviceState, DeviceID: "readonly-sensor-001"}},
+		Actions:  []models.AutomationAction{{Type: ActionCommand, DeviceID: "device-001", Action: "turn_on"}},
+	}
+}
+
+func TestAutomationRequest_Validate(t *testing.T) {
+	command := []models.AutomationAction{{Type: ActionCommand, DeviceID: "device-001", Action: "turn_on"}}
+	alert := []models.AutomationTrigger{{Type: TriggerAlert, AlertName: "HighTemperature"}}
+	tests := []struct {
+		name string
+		req  models.AutomationRequest
+		want []string
+	}{
+		{"valid", lightOn(""), nil},
+		{"every kind", models.AutomationRequest{
+			ID:   "everything",
+			Name: "Everything",
+			Triggers: []models.AutomationTrigger{
+				{Type: TriggerDeviceState, DeviceID: "device-001", To: "on"},
+				{Type: TriggerServiceStatus, Service: "grafana", Status: "down"},
+				{Type: TriggerAlert, AlertName: "HighTemperature", Labels: map[string]string{"room": "attic"}},
+				{Type: TriggerTime, Cron: "0 22 * * *", Timezone: "Europe/Berlin"},
+			},
+			Conditions: []models.AutomationCondition{{DeviceID: "readonly-sensor-001", Operator: "gte", Value: 70}},
+			Actions: []models.AutomationAction{
+				{Type: ActionCommand, DeviceID: "device-001", Action: "turn_on"},
+				{Type: ActionWebhook, URL: "https://hooks.example.com/homelab"},
+				{Type: ActionNotify, Message: "Hot in the attic"},
+			},
+		}, nil},
+		{"missing", models.AutomationRequest{}, []string{
+			"/name: name is required",
+			"/triggers: at least one trigger is required",
+			"/actions: at least one action is required",
+		}},
+		{"id", models.AutomationRequest{ID: "Porch Light", Name: "Porch", Triggers: alert, Actions: command}, []string{
+			"/id: id must be lowercase letters and digits separated by underscores",
+		}},
+		{"triggers", models.AutomationRequest{Name: "Triggers", Triggers: []models.AutomationTrigger{
+			{Type: TriggerDeviceState, DeviceID: "device-404"},
+			{Type: TriggerServiceStatus, Status: "sideways"},
+			{Type: TriggerAlert},
+			{Type: TriggerTime, Cron: "61 * * * *"},
+			{Type: TriggerTime, Cron: "* * * * *", Timezone: "Mars/Olympus"},
+			{Type: "sunset"},
+		}, Actions: command}, []string{
+			"/triggers/0/device_id: device not found: device-404",
+			"/triggers/1/status: status must be up or down",
+			"/triggers/2/alert_name: alert_name is required",
+			"/triggers/3/cron: invalid cron expression: minute: 61 is out of range 0-59",
+			"/triggers/4/timezone: unknown time zone: Mars/Olympus",
+			"/triggers/5/type: type must be device_state, service_status, alert or time",
+		}},
+		{"conditions", models.AutomationRequest{Name: "Conditions", Triggers: alert, Conditions: []models.AutomationCondition{
+			{DeviceID: "device-404", Operator: "eq", Value: "on"},
+			{DeviceID: "device-001", Operator: "like", Value: "on"},
+			{DeviceID: "device-001", Operator: "eq"},
+			{DeviceID: "device-001", Operator: "gt", Value: "bright"},
+		}, Actions: command}, []string{
+			"/conditions/0/device_id: device not found: device-404",
+			"/conditions/1/operator: operator must be eq, ne, gt, gte, lt or lte",
+			"/conditions/2/value: value is required",
+			"/conditions/3/value: gt compares numbers; value must be a number",
+		}},
+		{"actions", models.AutomationRequest{Name: "Actions", Triggers: alert, Actions: []models.AutomationAction{
+			{Type: ActionCommand, DeviceID: "readonly-sensor-001"},
+			{Type: ActionWebhook, URL: "/relative"},
+			{Type: ActionNotify},
+			{Type: "email"},
+		}}, []string{
+			"/actions/0/device_id: device is not controllable: readonly-sensor-001",
+			"/actions/0/action: action is required",
+			"/actions/1/url: url must be an absolute http(s) URL",
+			"/actions/2/message: message is required",
+			"/actions/3/type: type must be command, webhook or notify",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range ValidateAutomation(&tt.req) {
+				got = append(got, f.Pointer+": "+f.Detail)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEngine_Handle(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, calls := testEngine(t, &now)
+
+	a, err := e.Create(lightOn(""))
+	require.NoError(t, err)
+	assert.Equal(t, "light_on", a.ID)
+	assert.True(t, a.Enabled)
+	assert.Equal(t, SourceAPI, a.Source)
+
+	runs := e.Handle(context.Background(), models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "readonly-sensor-001", From: "72", To: "73"})
+	require.Len(t, runs, 1)
+	run := runs[0]
+	assert.Equal(t, RunSuccess, run.Status)
+	assert.Equal(t, 1, run.Depth)
+	assert.Equal(t, []models.AutomationActionResult{{Index: 0, Type: ActionCommand, Status: ActionSuccess}}, run.Actions)
+	require.Len(t, *calls, 1)
+	assert.Equal(t, call{"device-001", "turn_on", run.ID}, (*calls)[0], "the run ID is the request ID of its commands")
+
+	assert.Empty(t, e.Handle(context.Background(), models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "device-001", From: "off", To: "on"}))
+
+	got, err := e.Get("light_on")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Runs)
+	require.NotNil(t, got.LastRunAt)
+	assert.Equal(t, now, *got.LastRunAt)
+	stored, err := e.Runs("light_on")
+	require.NoError(t, err)
+	assert.Equal(t, []models.AutomationRun{run}, stored)
+}
+
+func TestEngine_Conditions(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, calls := testEngine(t, &now)
+
+	req := lightOn("hot")
+	req.Triggers = []models.AutomationTrigger{{Type: TriggerAlert, AlertName: "HighTemperature"}}
+	req.Conditions = []models.AutomationCondition{{DeviceID: "readonly-sensor-001", Operator: "gt", Value: 80}}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+
+	runs := e.Handle(context.Background(), models.AutomationEvent{Type: TriggerAlert, AlertName: "HighTemperature"})
+	require.Len(t, runs, 1)
+	assert.Equal(t, RunConditionsNotMet, runs[0].Status)
+	assert.Equal(t, []models.AutomationConditionResult{{Index: 0, Actual: "72", Passed: false}}, runs[0].Conditions)
+	assert.Empty(t, runs[0].Actions)
+	assert.Empty(t, *calls)
+}
+
+func TestEngine_FailedAction(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, calls := testEngine(t, &now)
+
+	req := lightOn("broken")
+	req.Actions = []models.AutomationAction{
+		{Type: ActionCommand, DeviceID: "device-001", Action: "explode"},
+		{Type: ActionCommand, DeviceID: "device-001", Action: "turn_on"},
+	}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+
+	runs := e.Handle(context.Background(), models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "readonly-sensor-001"})
+	require.Len(t, runs, 1)
+	assert.Equal(t, RunFailed, runs[0].Status)
+	assert.Equal(t, []models.AutomationActionResult{
+		{Index: 0, Type: ActionCommand, Status: ActionFailed, Error: "unsupported action: explode"},
+		{Index: 1, Type: ActionCommand, Status: ActionSkipped},
+	}, runs[0].Actions)
+	assert.Len(t, *calls, 1, "the actions after a failed one are skipped")
+}
+
+func TestEngine_LoopProtection(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, calls := testEngine(t, &now, WithMaxChainDepth(3))
+
+	// An automation that reacts to its own command, as a device change
+	// carrying the request ID of the run that caused it.
+	req := lightOn("flip")
+	req.Triggers = []models.AutomationTrigger{{Type: TriggerDeviceState, DeviceID: "device-001"}}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+
+	ev := models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "device-001", From: "off", To: "on"}
+	var statuses []string
+	for range 4 {
+		runs := e.Handle(context.Background(), ev)
+		require.Len(t, runs, 1)
+		statuses = append(statuses, runs[0].Status)
+		ev.RequestID = runs[0].ID
+	}
+	assert.Equal(t, []string{RunSuccess, RunSuccess, RunSuccess, RunLoopBlocked}, statuses)
+	assert.Len(t, *calls, 3)
+
+	runs, err := e.Runs("flip")
+	require.NoError(t, err)
+	assert.Equal(t, 4, runs[3].Depth)
+	assert.Equal(t, "chain of automations is deeper than 3; automations may be triggering each other", runs[3].Error)
+}
+
+func TestEngine_DryRun(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, calls := testEngine(t, &now)
+
+	req := lightOn("warm")
+	req.Conditions = []models.AutomationCondition{{DeviceID: "readonly-sensor-001", Operator: "gte", Value: 70}}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+
+	run, err := e.DryRun("warm", models.DryRunRequest{})
+	require.NoError(t, err)
+	assert.True(t, run.DryRun)
+	assert.Equal(t, "manual", run.Event.Type)
+	assert.Equal(t, RunWouldRun, run.Status)
+	assert.Equal(t, []models.AutomationActionResult{{Index: 0, Type: ActionCommand, Status: ActionDryRun}}, run.Actions)
+
+	run, err = e.DryRun("warm", models.DryRunRequest{Event: &models.AutomationEvent{Type: TriggerAlert, AlertName: "HighTemperature"}})
+	require.NoError(t, err)
+	assert.Equal(t, RunNotTriggered, run.Status)
+
+	_, err = e.DryRun("cold", models.DryRunRequest{})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Empty(t, *calls)
+	runs, err := e.Runs("warm")
+	require.NoError(t, err)
+	assert.Empty(t, runs, "dry runs are not recorded")
+}
+
+func TestEngine_Webhook(t *testing.T) {
+	type received struct {
+		path  string
+		body  map[string]interface{}
+		reqID string
+	}
+	got := make(chan received, 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got <- received{r.URL.Path, body, r.Header.Get(requestid.Header)}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, _ := testEngine(t, &now, WithNotifyURL(srv.URL+"/notify"))
+	_, err := e.Create(models.AutomationRequest{
+		ID:       "grafana_down",
+		Name:     "Grafana down",
+		Triggers: []models.AutomationTrigger{{Type: TriggerServiceStatus, Service: "grafana", Status: "down"}},
+		Actions: []models.AutomationAction{
+			{Type: ActionWebhook, URL: srv.URL + "/hook"},
+			{Type: ActionNotify, Title: "Grafana", Message: "Grafana is down"},
+			{Type: ActionWebhook, URL: srv.URL + "/fail"},
+		},
+	})
+	require.NoError(t, err)
+
+	runs := e.Handle(context.Background(), models.AutomationEvent{Type: TriggerServiceStatus, Service: "grafana", Status: "down"})
+	require.Len(t, runs, 1)
+	run := runs[0]
+	assert.Equal(t, RunFailed, run.Status)
+	assert.Equal(t, ActionSuccess, run.Actions[0].Status)
+	assert.Equal(t, ActionSuccess, run.Actions[1].Status)
+	assert.Equal(t, ActionFailed, run.Actions[2].Status)
+	assert.Contains(t, run.Actions[2].Error, "502 Bad Gateway")
+
+	hook := <-got
+	assert.Equal(t, "/hook", hook.path)
+	assert.Equal(t, "grafana_down", hook.body["automation_id"])
+	assert.Equal(t, run.ID, hook.body["run_id"])
+	assert.Equal(t, run.ID, hook.reqID)
+	assert.Equal(t, "grafana", hook.body["event"].(map[string]interface{})["service"])
+	notify := <-got
+	assert.Equal(t, "/notify", notify.path)
+	assert.Equal(t, "Grafana is down", notify.body["message"])
+}
+
+func TestEngine_CRUD(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, _ := testEngine(t, &now)
+
+	first, err := e.Create(lightOn(""))
+	require.NoError(t, err)
+	second, err := e.Create(lightOn(""))
+	require.NoError(t, err)
+	assert.Equal(t, "light_on_2", second.ID, "a derived ID is made unique")
+	_, err = e.Create(lightOn("light_on"))
+	assert.ErrorIs(t, err, ErrExists)
+
+	e.Handle(context.Background(), models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "readonly-sensor-001"})
+
+	now = now.Add(time.Minute)
+	disabled := false
+	req := lightOn("")
+	req.Name = "Light on, later"
+	req.Enabled = &disabled
+	updated, err := e.Update("light_on", req)
+	require.NoError(t, err)
+	assert.Equal(t, "Light on, later", updated.Name)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, first.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, now, updated.UpdatedAt)
+	assert.Equal(t, 1, updated.Runs, "runs are kept")
+
+	runs := e.Handle(context.Background(), models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "readonly-sensor-001"})
+	require.Len(t, runs, 1, "disabled automations do not run")
+	assert.Equal(t, "light_on_2", runs[0].AutomationID)
+
+	var ids []string
+	for _, a := range e.List() {
+		ids = append(ids, a.ID)
+	}
+	assert.Equal(t, []string{"light_on", "light_on_2"}, ids)
+
+	require.NoError(t, e.Delete("light_on"))
+	assert.ErrorIs(t, e.Delete("light_on"), ErrNotFound)
+	_, err = e.Runs("light_on")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = e.Update("light_on", req)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEngine_RunsKept(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, _ := testEngine(t, &now)
+	_, err := e.Create(lightOn("busy"))
+	require.NoError(t, err)
+
+	var last models.AutomationRun
+	for range runsKept + 5 {
+		last = e.Handle(context.Background(), models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "readonly-sensor-001"})[0]
+	}
+	runs, err := e.Runs("busy")
+	require.NoError(t, err)
+	assert.Len(t, runs, runsKept)
+	assert.Equal(t, last.ID, runs[len(runs)-1].ID)
+	a, _ := e.Get("busy")
+	assert.Equal(t, runsKept+5, a.Runs)
+}
+
+func TestEngine_TimeTriggers(t *testing.T) {
+	now := time.Date(2026, 5, 1, 21, 59, 0, 0, time.UTC)
+	e, calls := testEngine(t, &now)
+
+	req := lightOn("night")
+	req.Triggers = []models.AutomationTrigger{{Type: TriggerTime, Cron: "0 22 * * *", Timezone: "UTC"}}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+	next, ok := e.nextTime()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC), next)
+
+	e.fireDue(context.Background())
+	assert.Empty(t, *calls)
+
+	now = now.Add(time.Minute)
+	e.fireDue(context.Background())
+	require.Len(t, *calls, 1)
+	e.fireDue(context.Background())
+	assert.Len(t, *calls, 1, "a time trigger fires once per match")
+
+	next, _ = e.nextTime()
+	assert.Equal(t, time.Date(2026, 5, 2, 22, 0, 0, 0, time.UTC), next)
+	runs, _ := e.Runs("night")
+	assert.Equal(t, TriggerTime, runs[0].Event.Type)
+}
+
+func TestEngine_PublishAlerts(t *testing.T) {
+	e := New()
+	res := e.PublishAlerts(models.AlertWebhook{Status: "firing", Alerts: []models.Alert{
+		{Status: "firing", Labels: map[string]string{"alertname": "HighTemperature", "room": "attic"}},
+		{Status: "resolved", Labels: map[string]string{"alertname": "DiskFull"}},
+	}})
+	assert.Equal(t, models.AlertsResult{Firing: 1}, res)
+	ev := <-e.events
+	assert.Equal(t, TriggerAlert, ev.Type)
+	assert.Equal(t, "HighTemperature", ev.AlertName)
+	assert.Equal(t, "attic", ev.Labels["room"])
+	assert.False(t, ev.Time.IsZero())
+}
+
+func TestEngine_Load(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "automations.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+automations:
+  - name: Hot attic
+    triggers:
+      - type: alert
+        alert_name: HighTemperature
+        labels:
+          room: attic
+    conditions:
+      - device_id: readonly-sensor-001
+        operator: gt
+        value: 70
+    actions:
+      - type: command
+        device_id: device-001
+        action: turn_on
+      - type: notify
+        message: The attic is hot
+`), 0o600))
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, _ := testEngine(t, &now, WithFile(file))
+	require.NoError(t, e.Load())
+	a, err := e.Get("hot_attic")
+	require.NoError(t, err)
+	assert.Equal(t, SourceFile, a.Source)
+	assert.Equal(t, "attic", a.Triggers[0].Labels["room"])
+	assert.Equal(t, float64(70), a.Conditions[0].Value)
+
+	_, err = e.Update("hot_attic", lightOn(""))
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, e.Delete("hot_attic"), ErrReadOnly)
+
+	// Loading again replaces the automations from the file.
+	require.NoError(t, e.Load())
+	assert.Len(t, e.List(), 1)
+}
+
+func TestEngine_LoadErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "automations.yaml")
+	assert.ErrorContains(t, New(WithFile(file)).Load(), "read automations")
+
+	require.NoError(t, os.WriteFile(file, []byte("automations:\n  - name: x\n    colour: red\n"), 0o600))
+	assert.ErrorContains(t, New(WithFile(file)).Load(), `unknown field "colour"`)
+
+	require.NoError(t, os.WriteFile(file, []byte("automations:\n  - name: Nothing\n"), 0o600))
+	assert.EqualError(t, New(WithFile(file)).Load(), "automations "+file+": automations[0]: /triggers: at least one trigger is required")
+
+	require.NoError(t, os.WriteFile(file, []byte(`automations:
+  - {name: Twice, triggers: [{type: alert, alert_name: A}], actions: [{type: notify, message: m}]}
+  - {name: Twice, triggers: [{type: alert, alert_name: B}], actions: [{type: notify, message: m}]}
+`), 0o600))
+	assert.EqualError(t, New(WithFile(file)).Load(), "automations "+file+`: automations[1]: duplicate id "twice"`)
+}
+
+func TestEngine_Run(t *testing.T) {
+	e := New()
+	done := make(chan string, 1)
+	e.execute = func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+		done <- deviceID
+		return models.CommandResult{Status: "success"}, nil
+	}
+	req := lightOn("hot")
+	req.Triggers = []models.AutomationTrigger{{Type: TriggerAlert, AlertName: "HighTemperature"}}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- e.Run(ctx) }()
+
+	e.PublishAlerts(models.AlertWebhook{Alerts: []models.Alert{{Status: "firing", Labels: map[string]string{"alertname": "HighTemperature"}}}})
+	select {
+	case id := <-done:
+		assert.Equal(t, "device-001", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("automation did not run")
+	}
+
+	cancel()
+	assert.NoError(t, <-stopped)
+}
+
+func TestEngine_Run_Bus(t *testing.T) {
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	e, _ := testEngine(t, &now)
+	req := lightOn("grafana_down")
+	req.Triggers = []models.AutomationTrigger{{Type: TriggerServiceStatus, Service: "grafana", Status: "down"}}
+	req.Actions = []models.AutomationAction{{Type: ActionNotify, Message: "Grafana is down"}}
+	_, err := e.Create(req)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- e.Run(ctx) }()
+
+	// Run subscribes once it has started, so the change is published until
+	// it arrives.
+	change := services.StatusChange{
+		Previous: services.ProbeResult{Service: "grafana", Up: true},
+		Current:  services.ProbeResult{Service: "grafana", Up: false, CheckedAt: now},
+	}
+	require.Eventually(t, func() bool {
+		events.Publish(bus, services.StatusChanged, change)
+		a, err := e.Get("grafana_down")
+		return err == nil && a.Runs > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-stopped)
+	runs, err := e.Runs("grafana_down")
+	require.NoError(t, err)
+	assert.Equal(t, models.AutomationEvent{Type: TriggerServiceStatus, Service: "grafana", Status: "down", Time: now}, runs[0].Event)
+}
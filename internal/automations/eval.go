@@ -0,0 +1,129 @@
+package automations
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+)
+
+// matches reports whether ev fires t. Time events are not matched here: the
+// engine fires them for the trigger whose cron expression is due, and a dry
+// run matches them with any time trigger.
+func matches(t models.AutomationTrigger, ev models.AutomationEvent) bool {
+	if t.Type != ev.Type {
+		return false
+	}
+	switch t.Type {
+	case TriggerDeviceState:
+		if t.DeviceID != ev.DeviceID {
+			return false
+		}
+		if t.From == "" && t.To == "" {
+			return true
+		}
+		return ev.From != ev.To && (t.From == "" || t.From == ev.From) && (t.To == "" || t.To == ev.To)
+	case TriggerServiceStatus:
+		return (t.Service == "" || t.Service == ev.Service) && (t.Status == "" || t.Status == ev.Status)
+	case TriggerAlert:
+		if t.AlertName != ev.AlertName {
+			return false
+		}
+		for k, v := range t.Labels {
+			if ev.Labels[k] != v {
+				return false
+			}
+		}
+		return true
+	case TriggerTime:
+		return true
+	}
+	return false
+}
+
+// evaluate checks the condition c against the current state of its device.
+func evaluate(i int, c models.AutomationCondition) models.AutomationConditionResult {
+	res := models.AutomationConditionResult{Index: i}
+	d, ok := homeassistant.GetDevice(c.DeviceID)
+	if !ok {
+		res.Error = "device not found: " + c.DeviceID
+		return res
+	}
+	actual := interface{}(d.State)
+	if c.Attribute != "" {
+		v, ok := d.Attributes[c.Attribute]
+		if !ok {
+			res.Error = fmt.Sprintf("device %s has no attribute %s", c.DeviceID, c.Attribute)
+			return res
+		}
+		actual = v
+	}
+	res.Actual = actual
+
+	switch c.Operator {
+	case "eq", "ne":
+		equal := homeassistant.SameValue(actual, c.Value)
+		if a, ok := number(actual); ok {
+			if b, ok := number(c.Value); ok {
+				equal = a == b
+			}
+		}
+		res.Passed = equal == (c.Operator == "eq")
+	default:
+		a, okA := number(actual)
+		b, okB := number(c.Value)
+		if !okA || !okB {
+			res.Error = fmt.Sprintf("%s compares numbers; %v is not a number", c.Operator, actual)
+			return res
+		}
+		switch c.Operator {
+		case "gt":
+			res.Passed = a > b
+		case "gte":
+			res.Passed = a >= b
+		case "lt":
+			res.Passed = a < b
+		case "lte":
+			res.Passed = a <= b
+		}
+	}
+	return res
+}
+
+// number returns v as a float64 if it is a number or a string holding one,
+// as sensor states are.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// deviceEvent returns the event of a device change.
+func deviceEvent(c homeassistant.DeviceChange) models.AutomationEvent {
+	return models.AutomationEvent{
+		Type:       TriggerDeviceState,
+		DeviceID:   c.DeviceID,
+		From:       c.OldState,
+		To:         c.NewState,
+		Attributes: maps.Clone(c.NewAttributes),
+		Time:       c.ChangedAt,
+		RequestID:  c.RequestID,
+	}
+}
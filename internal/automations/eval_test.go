@@ -0,0 +1,71 @@
+package automations
+
+import (
+	"testing"
+
+	"go-github/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatches(t *testing.T) {
+	change := models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "device-001", From: "off", To: "on"}
+	dim := models.AutomationEvent{Type: TriggerDeviceState, DeviceID: "device-001", From: "on", To: "on"}
+	down := models.AutomationEvent{Type: TriggerServiceStatus, Service: "grafana", Status: "down"}
+	alert := models.AutomationEvent{Type: TriggerAlert, AlertName: "HighTemperature", Labels: map[string]string{"alertname": "HighTemperature", "room": "attic", "severity": "warning"}}
+	tests := []struct {
+		name    string
+		trigger models.AutomationTrigger
+		event   models.AutomationEvent
+		want    bool
+	}{
+		{"any change", models.AutomationTrigger{Type: TriggerDeviceState, DeviceID: "device-001"}, dim, true},
+		{"to", models.AutomationTrigger{Type: TriggerDeviceState, DeviceID: "device-001", To: "on"}, change, true},
+		{"from and to", models.AutomationTrigger{Type: TriggerDeviceState, DeviceID: "device-001", From: "off", To: "on"}, change, true},
+		{"wrong from", models.AutomationTrigger{Type: TriggerDeviceState, DeviceID: "device-001", From: "on"}, change, false},
+		{"attributes only", models.AutomationTrigger{Type: TriggerDeviceState, DeviceID: "device-001", To: "on"}, dim, false},
+		{"other device", models.AutomationTrigger{Type: TriggerDeviceState, DeviceID: "device-002"}, change, false},
+		{"other type", models.AutomationTrigger{Type: TriggerServiceStatus}, change, false},
+		{"any service", models.AutomationTrigger{Type: TriggerServiceStatus}, down, true},
+		{"service down", models.AutomationTrigger{Type: TriggerServiceStatus, Service: "grafana", Status: "down"}, down, true},
+		{"service up", models.AutomationTrigger{Type: TriggerServiceStatus, Service: "grafana", Status: "up"}, down, false},
+		{"alert", models.AutomationTrigger{Type: TriggerAlert, AlertName: "HighTemperature"}, alert, true},
+		{"alert labels", models.AutomationTrigger{Type: TriggerAlert, AlertName: "HighTemperature", Labels: map[string]string{"room": "attic"}}, alert, true},
+		{"alert other labels", models.AutomationTrigger{Type: TriggerAlert, AlertName: "HighTemperature", Labels: map[string]string{"room": "cellar"}}, alert, false},
+		{"other alert", models.AutomationTrigger{Type: TriggerAlert, AlertName: "DiskFull"}, alert, false},
+		{"time", models.AutomationTrigger{Type: TriggerTime, Cron: "0 22 * * *"}, models.AutomationEvent{Type: TriggerTime}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matches(tt.trigger, tt.event))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	// The sensor's state is "72" and its unit attribute "°F".
+	tests := []struct {
+		name   string
+		cond   models.AutomationCondition
+		passed bool
+		err    string
+	}{
+		{"numeric state", models.AutomationCondition{DeviceID: "readonly-sensor-001", Operator: "gt", Value: 70}, true, ""},
+		{"lte", models.AutomationCondition{DeviceID: "readonly-sensor-001", Operator: "lte", Value: 71.5}, false, ""},
+		{"eq number", models.AutomationCondition{DeviceID: "readonly-sensor-001", Operator: "eq", Value: 72}, true, ""},
+		{"eq string", models.AutomationCondition{DeviceID: "readonly-sensor-001", Operator: "eq", Value: "72"}, true, ""},
+		{"attribute", models.AutomationCondition{DeviceID: "readonly-sensor-001", Attribute: "unit", Operator: "eq", Value: "°F"}, true, ""},
+		{"ne", models.AutomationCondition{DeviceID: "readonly-sensor-001", Attribute: "unit", Operator: "ne", Value: "°C"}, true, ""},
+		{"not a number", models.AutomationCondition{DeviceID: "readonly-sensor-001", Attribute: "unit", Operator: "gt", Value: 1}, false, "gt compares numbers; °F is not a number"},
+		{"missing attribute", models.AutomationCondition{DeviceID: "readonly-sensor-001", Attribute: "humidity", Operator: "eq", Value: 1}, false, "device readonly-sensor-001 has no attribute humidity"},
+		{"missing device", models.AutomationCondition{DeviceID: "device-404", Operator: "eq", Value: "on"}, false, "device not found: device-404"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluate(i, tt.cond)
+			assert.Equal(t, i, got.Index)
+			assert.Equal(t, tt.passed, got.Passed)
+			assert.Equal(t, tt.err, got.Error)
+		})
+	}
+}
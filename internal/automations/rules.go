@@ -0,0 +1,124 @@
+package automations
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+	"go-github/internal/scheduler"
+)
+
+// Trigger, action and operator types.
+const (
+	TriggerDeviceState   = "device_state"
+	TriggerServiceStatus = "service_status"
+	TriggerAlert         = "alert"
+	TriggerTime          = "time"
+
+	ActionCommand = "command"
+	ActionWebhook = "webhook"
+	ActionNotify  = "notify"
+)
+
+// operators are the comparisons a condition can make.
+var operators = map[string]bool{"eq": true, "ne": true, "gt": true, "gte": true, "lt": true, "lte": true}
+
+// ValidateAutomation checks r and returns every invalid field, located by JSON
+// pointer.
+func ValidateAutomation(r *models.AutomationRequest) []models.FieldError {
+	var errs []models.FieldError
+	add := func(pointer, format string, args ...any) {
+		errs = append(errs, models.FieldError{Pointer: pointer, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	if r.ID != "" && homeassistant.Slugify(r.ID) != r.ID {
+		add("/id", "id must be lowercase letters and digits separated by underscores")
+	}
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		add("/name", "name is required")
+	case r.ID == "" && homeassistant.Slugify(r.Name) == "":
+		add("/name", "name must contain a letter or digit")
+	}
+
+	if len(r.Triggers) == 0 {
+		add("/triggers", "at least one trigger is required")
+	}
+	for i, t := range r.Triggers {
+		p := fmt.Sprintf("/triggers/%d", i)
+		switch t.Type {
+		case TriggerDeviceState:
+			if _, ok := homeassistant.GetDevice(t.DeviceID); !ok {
+				add(p+"/device_id", "device not found: %s", t.DeviceID)
+			}
+		case TriggerServiceStatus:
+			if t.Status != "" && t.Status != "up" && t.Status != "down" {
+				add(p+"/status", "status must be up or down")
+			}
+		case TriggerAlert:
+			if strings.TrimSpace(t.AlertName) == "" {
+				add(p+"/alert_name", "alert_name is required")
+			}
+		case TriggerTime:
+			if loc, err := scheduler.LoadLocation(t.Timezone); err != nil {
+				add(p+"/timezone", "unknown time zone: %s", t.Timezone)
+			} else if c, err := scheduler.ParseCron(t.Cron, loc); err != nil {
+				add(p+"/cron", "invalid cron expression: %v", err)
+			} else if c.Next(time.Now()).IsZero() {
+				add(p+"/cron", "the cron expression never matches")
+			}
+		default:
+			add(p+"/type", "type must be device_state, service_status, alert or time")
+		}
+	}
+
+	for i, c := range r.Conditions {
+		p := fmt.Sprintf("/conditions/%d", i)
+		if _, ok := homeassistant.GetDevice(c.DeviceID); !ok {
+			add(p+"/device_id", "device not found: %s", c.DeviceID)
+		}
+		switch {
+		case !operators[c.Operator]:
+			add(p+"/operator", "operator must be eq, ne, gt, gte, lt or lte")
+		case c.Value == nil:
+			add(p+"/value", "value is required")
+		case c.Operator != "eq" && c.Operator != "ne":
+			if _, ok := number(c.Value); !ok {
+				add(p+"/value", "%s compares numbers; value must be a number", c.Operator)
+			}
+		}
+	}
+
+	if len(r.Actions) == 0 {
+		add("/actions", "at least one action is required")
+	}
+	for i, a := range r.Actions {
+		p := fmt.Sprintf("/actions/%d", i)
+		switch a.Type {
+		case ActionCommand:
+			switch d, ok := homeassistant.GetDevice(a.DeviceID); {
+			case !ok:
+				add(p+"/device_id", "device not found: %s", a.DeviceID)
+			case !d.Controllable:
+				add(p+"/device_id", "device is not controllable: %s", a.DeviceID)
+			}
+			if strings.TrimSpace(a.Action) == "" {
+				add(p+"/action", "action is required")
+			}
+		case ActionWebhook:
+			if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				add(p+"/url", "url must be an absolute http(s) URL")
+			}
+		case ActionNotify:
+			if strings.TrimSpace(a.Message) == "" {
+				add(p+"/message", "message is required")
+			}
+		default:
+			add(p+"/type", "type must be command, webhook or notify")
+		}
+	}
+	return errs
+}
@@ -1,7 +1,15 @@
 package cluster
 
 import (
+	"context"
 	"strings"
+
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+	"go-github/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // Service provides access to cluster service information
@@ -15,11 +23,30 @@ func NewService() *Service {
 // ListServices returns a list of cluster services, optionally filtered by name.
 // Filter is case-insensitive substring matching; whitespace is trimmed.
 // An empty or whitespace-only filter returns all services.
-func (s *Service) ListServices(filter string) ([]ServiceInfo, error) {
+func (s *Service) ListServices(filter string) ([]models.ServiceInfo, error) {
+	return s.ListServicesContext(context.Background(), filter)
+}
+
+// ListServicesContext is like ListServices but runs within ctx, recording the
+// call to the Kubernetes API as a client span of the caller's trace. It
+// returns ctx's error if ctx is done before the call is made.
+func (s *Service) ListServicesContext(ctx context.Context, filter string) ([]models.ServiceInfo, error) {
+	_, span := tracing.StartClientSpan(ctx, "kubernetes", "ListServices")
+	defer span.End()
+
 	filter = strings.TrimSpace(filter)
+	span.SetAttributes(
+		attribute.String("kubernetes.service.filter", filter),
+		attribute.String("request_id", requestid.FromContext(ctx)),
+	)
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
 
 	// Mock data representing running cluster services
-	services := []ServiceInfo{
+	services := []models.ServiceInfo{
 		{
 			Name:      "api-service",
 			Namespace: "default",
@@ -46,7 +73,7 @@ func (s *Service) ListServices(filter string) ([]ServiceInfo, error) {
 
 	// Filter by name using case-insensitive substring matching
 	lowerFilter := strings.ToLower(filter)
-	filtered := make([]ServiceInfo, 0)
+	filtered := make([]models.ServiceInfo, 0)
 	for _, svc := range services {
 		if strings.Contains(strings.ToLower(svc.Name), lowerFilter) {
 			filtered = append(filtered, svc)
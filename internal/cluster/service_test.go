@@ -1,6 +1,8 @@
 package cluster
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -346,3 +348,17 @@ func TestServiceInfo_Structure(t *testing.T) {
 		})
 	}
 }
+
+// TestListServicesContext_DeadlineExceeded tests that an expired context is reported instead of listing services
+func TestListServicesContext_DeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	services, err := NewService().ListServicesContext(ctx, "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if services != nil {
+		t.Errorf("expected no services, got %v", services)
+	}
+}
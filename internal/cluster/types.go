@@ -1,9 +1,8 @@
 package cluster
 
-// ServiceInfo represents information about a Kubernetes cluster service
-type ServiceInfo struct {
-	Name      string   `json:"name"`
-	Namespace string   `json:"namespace"`
-	Status    string   `json:"status"`
-	Endpoints []string `json:"endpoints"`
-}
+import "go-github/internal/models"
+
+// ServiceInfo describes a Kubernetes cluster service. It is declared in
+// package models, which the Go client shares; this name is kept for existing
+// importers.
+type ServiceInfo = models.ServiceInfo
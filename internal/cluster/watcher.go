@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+)
+
+// ServiceChanged is the topic on events.Default() that carries every cluster
+// service that Watch sees appear, change or disappear.
+var ServiceChanged = events.NewTopic[ServiceEvent]("cluster.service_changed")
+
+// Types of a ServiceEvent.
+const (
+	ServiceAdded   = "added"
+	ServiceUpdated = "updated"
+	ServiceRemoved = "removed"
+)
+
+// ServiceEvent is a change of a cluster service between two listings.
+type ServiceEvent struct {
+	// Type is added, updated (its status or endpoints changed) or removed.
+	Type string `json:"type"`
+	// Service is the service as listed, or as last listed if it was
+	// removed.
+	Service    models.ServiceInfo `json:"service"`
+	ObservedAt time.Time          `json:"observed_at"`
+}
+
+// Watch lists the cluster services immediately and then every interval until
+// ctx is cancelled, publishing the differences from the previous listing on
+// ServiceChanged. Every service of the first listing is published as added.
+// A failed listing is logged and skipped.
+func (s *Service) Watch(ctx context.Context, interval time.Duration) error {
+	slog.Info("cluster watcher started", "interval", interval.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var known []models.ServiceInfo
+	for {
+		listCtx := requestid.NewContext(ctx, requestid.New())
+		current, err := s.ListServicesContext(listCtx, "")
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.WarnContext(listCtx, "listing cluster services failed", "error", err)
+		case err == nil:
+			now := time.Now()
+			for _, ev := range diffServices(known, current) {
+				ev.ObservedAt = now
+				events.Publish(events.Default(), ServiceChanged, ev)
+			}
+			known = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// diffServices returns the events that turn the listing old into current:
+// additions and updates in the order of current, then removals in the order
+// of old. Services are identified by namespace and name.
+func diffServices(old, current []models.ServiceInfo) []ServiceEvent {
+	key := func(svc models.ServiceInfo) string { return svc.Namespace + "/" + svc.Name }
+	before := make(map[string]models.ServiceInfo, len(old))
+	for _, svc := range old {
+		before[key(svc)] = svc
+	}
+
+	var out []ServiceEvent
+	seen := make(map[string]bool, len(current))
+	for _, svc := range current {
+		seen[key(svc)] = true
+		prev, ok := before[key(svc)]
+		switch {
+		case !ok:
+			out = append(out, ServiceEvent{Type: ServiceAdded, Service: svc})
+		case prev.Status != svc.Status || !slices.Equal(prev.Endpoints, svc.Endpoints):
+			out = append(out, ServiceEvent{Type: ServiceUpdated, Service: svc})
+		}
+	}
+	for _, svc := range old {
+		if !seen[key(svc)] {
+			out = append(out, ServiceEvent{Type: ServiceRemoved, Service: svc})
+		}
+	}
+	return out
+}
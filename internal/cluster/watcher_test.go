@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/models"
+)
+
+// TestDiffServices tests that additions, updates and removals are found between two listings
+func TestDiffServices(t *testing.T) {
+	api := models.ServiceInfo{Name: "api-service", Namespace: "default", Status: "Running", Endpoints: []string{"10.0.0.1:8080"}}
+	db := models.ServiceInfo{Name: "database-service", Namespace: "default", Status: "Running", Endpoints: []string{"10.0.0.2:5432"}}
+	dbPending := models.ServiceInfo{Name: "database-service", Namespace: "default", Status: "Pending", Endpoints: []string{"10.0.0.2:5432"}}
+	apiMoved := models.ServiceInfo{Name: "api-service", Namespace: "default", Status: "Running", Endpoints: []string{"10.0.0.9:8080"}}
+	apiStaging := models.ServiceInfo{Name: "api-service", Namespace: "staging", Status: "Running"}
+
+	tests := []struct {
+		name     string
+		old, cur []models.ServiceInfo
+		want     []ServiceEvent
+	}{
+		{"first listing", nil, []models.ServiceInfo{api, db}, []ServiceEvent{
+			{Type: ServiceAdded, Service: api},
+			{Type: ServiceAdded, Service: db},
+		}},
+		{"unchanged", []models.ServiceInfo{api, db}, []models.ServiceInfo{db, api}, nil},
+		{"status", []models.ServiceInfo{api, db}, []models.ServiceInfo{api, dbPending}, []ServiceEvent{
+			{Type: ServiceUpdated, Service: dbPending},
+		}},
+		{"endpoints", []models.ServiceInfo{api}, []models.ServiceInfo{apiMoved}, []ServiceEvent{
+			{Type: ServiceUpdated, Service: apiMoved},
+		}},
+		{"removed", []models.ServiceInfo{api, db}, []models.ServiceInfo{api}, []ServiceEvent{
+			{Type: ServiceRemoved, Service: db},
+		}},
+		{"namespaces", []models.ServiceInfo{api}, []models.ServiceInfo{apiStaging}, []ServiceEvent{
+			{Type: ServiceAdded, Service: apiStaging},
+			{Type: ServiceRemoved, Service: api},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffServices(tt.old, tt.cur); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffServices() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWatch_PublishesServices tests that the first listing is published as added services
+func TestWatch_PublishesServices(t *testing.T) {
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+	sub := events.Subscribe(bus, ServiceChanged)
+	defer sub.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- NewService().Watch(ctx, time.Hour) }()
+
+	var names []string
+	for len(names) < 3 {
+		select {
+		case ev := <-sub.C():
+			if ev.Type != ServiceAdded || ev.ObservedAt.IsZero() {
+				t.Errorf("unexpected event %+v", ev)
+			}
+			names = append(names, ev.Service.Name)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %v, want the three mocked services", names)
+		}
+	}
+	if want := []string{"api-service", "database-service", "cache-service"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Errorf("Watch() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,758 @@
+// Package config loads the typed configuration of the homelab API. Values are
+// layered in increasing order of precedence: built-in defaults, a YAML or TOML
+// config file, environment variables, and command-line flags. The result is
+// validated before it is returned, and secrets are redacted whenever the
+// configuration is printed or logged.
+package config
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in printed configuration.
+const redacted = "[REDACTED]"
+
+// Config is the complete runtime configuration.
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	CORS        CORSConfig        `yaml:"cors" toml:"cors"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	Services    ServicesConfig    `yaml:"services" toml:"services"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler" toml:"scheduler"`
+	Automations AutomationsConfig `yaml:"automations" toml:"automations"`
+
+	// File is the config file the configuration was loaded from, if any.
+	File string `yaml:"-" toml:"-"`
+}
+
+// ServerConfig controls the HTTP listeners.
+type ServerConfig struct {
+	// Port is the public HTTP port.
+	Port string `yaml:"port" toml:"port"`
+	// MetricsPort serves /metrics on a separate admin listener when set.
+	MetricsPort string `yaml:"metrics_port" toml:"metrics_port"`
+	// TLS serves the public listener over HTTPS when configured.
+	TLS TLSConfig `yaml:"tls" toml:"tls"`
+	// Timeouts bounds how long clients and handlers may hold a connection.
+	Timeouts TimeoutsConfig `yaml:"timeouts" toml:"timeouts"`
+	// MaxBodyBytes is the largest request body accepted. Larger bodies are
+	// rejected with 413. Zero disables the limit.
+	MaxBodyBytes int64 `yaml:"max_body_bytes" toml:"max_body_bytes"`
+	// Validation checks traffic against the OpenAPI document.
+	Validation ValidationConfig `yaml:"validation" toml:"validation"`
+	// Compression compresses responses for clients that accept it.
+	Compression CompressionConfig `yaml:"compression" toml:"compression"`
+	// IdempotencyTTL is how long responses to requests with an
+	// Idempotency-Key are kept for replay.
+	IdempotencyTTL Duration `yaml:"idempotency_ttl" toml:"idempotency_ttl"`
+}
+
+// CompressionConfig controls response compression. The encoding (zstd,
+// brotli or gzip) is negotiated from the Accept-Encoding header.
+type CompressionConfig struct {
+	// Enabled turns compression on.
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// MinBytes is the smallest response body that is compressed.
+	MinBytes int `yaml:"min_bytes" toml:"min_bytes"`
+}
+
+// ValidationConfig controls validation against the OpenAPI document served
+// at /api/openapi.json.
+type ValidationConfig struct {
+	// Requests rejects requests whose parameters or body do not match the
+	// document with 400 (or 415 for an unsupported media type).
+	Requests bool `yaml:"requests" toml:"requests"`
+	// Responses logs responses that do not match the document.
+	Responses bool `yaml:"responses" toml:"responses"`
+}
+
+// TimeoutsConfig controls the http.Server timeouts and per-request handler
+// deadlines. A zero value disables the corresponding timeout.
+type TimeoutsConfig struct {
+	// ReadHeader bounds reading the request headers.
+	ReadHeader Duration `yaml:"read_header" toml:"read_header"`
+	// Read bounds reading the whole request, including the body.
+	Read Duration `yaml:"read" toml:"read"`
+	// Write bounds the time from the end of the request headers to the end
+	// of the response. It must exceed the handler deadlines so that a 504
+	// can still be written.
+	Write Duration `yaml:"write" toml:"write"`
+	// Idle bounds how long a keep-alive connection waits for the next request.
+	Idle Duration `yaml:"idle" toml:"idle"`
+	// Handler is the deadline of the request context. Requests that exceed it
+	// are answered with 504.
+	Handler Duration `yaml:"handler" toml:"handler"`
+	// Routes overrides Handler per route template, e.g.
+	// "/api/v1/homeassistant/devices/:id/command".
+	Routes map[string]Duration `yaml:"routes" toml:"routes"`
+}
+
+// Client certificate policies accepted in TLSConfig.ClientAuth.
+const (
+	ClientAuthRequire  = "require"
+	ClientAuthOptional = "optional"
+)
+
+// TLSConfig controls HTTPS and mutual TLS on the public listener. The files
+// are re-read when they change, so rotated certificates are picked up
+// without a restart.
+type TLSConfig struct {
+	// CertFile and KeyFile are the PEM certificate chain and private key.
+	// TLS is enabled when both are set.
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file"`
+	// ClientCAFile enables mutual TLS: client certificates are verified
+	// against the PEM CA bundle and their subject becomes the principal.
+	ClientCAFile string `yaml:"client_ca_file" toml:"client_ca_file"`
+	// ClientAuth is require (the default) or optional, which accepts clients
+	// without a certificate so they can authenticate with an API key instead.
+	ClientAuth string `yaml:"client_auth" toml:"client_auth"`
+	// MinVersion is the minimum TLS version, 1.2 (default) or 1.3.
+	MinVersion string `yaml:"min_version" toml:"min_version"`
+	// CipherSuites restricts the TLS 1.2 cipher suites, by standard name
+	// (e.g. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256). TLS 1.3 suites are not
+	// configurable.
+	CipherSuites []string `yaml:"cipher_suites" toml:"cipher_suites"`
+}
+
+// Enabled reports whether TLS is configured.
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// LogConfig controls the process-wide logger.
+type LogConfig struct {
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+	// Format is text or json.
+	Format string `yaml:"format" toml:"format"`
+}
+
+// CORSConfig controls cross-origin access to the API.
+type CORSConfig struct {
+	// Origins lists the exact origins allowed to call the API.
+	Origins []string `yaml:"origins" toml:"origins"`
+}
+
+// RateLimitConfig controls per-client rate limiting of /api/v1.
+type RateLimitConfig struct {
+	// Requests is the number of requests allowed per window.
+	Requests int `yaml:"requests" toml:"requests"`
+	// PerMinutes is the window length in minutes.
+	PerMinutes int `yaml:"per_minutes" toml:"per_minutes"`
+	// RedisAddr shares limits across replicas through Redis when set.
+	RedisAddr string `yaml:"redis_addr" toml:"redis_addr"`
+	// RedisPassword is sent with AUTH to RedisAddr.
+	RedisPassword Secret `yaml:"redis_password" toml:"redis_password"`
+}
+
+// TracingConfig controls OpenTelemetry trace export.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector URL. Export is disabled when empty.
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	// ServiceName is reported as service.name on exported spans.
+	ServiceName string `yaml:"service_name" toml:"service_name"`
+	// SampleRatio is the fraction of new root traces to sample (0 means all).
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// ServicesConfig controls the homelab service catalog.
+type ServicesConfig struct {
+	// ProbeInterval enables periodic endpoint probes when non-zero.
+	ProbeInterval Duration `yaml:"probe_interval" toml:"probe_interval"`
+	// Catalog replaces the built-in service list when non-empty.
+	Catalog []ServiceEntry `yaml:"catalog" toml:"catalog"`
+}
+
+// ServiceEntry describes one service in the catalog.
+type ServiceEntry struct {
+	Name     string `yaml:"name" toml:"name"`
+	Type     string `yaml:"type" toml:"type"`
+	Status   string `yaml:"status" toml:"status"`
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+}
+
+// Models converts the catalog to the API representation. It returns nil when
+// the catalog is empty.
+func (s ServicesConfig) Models() []models.Service {
+	if len(s.Catalog) == 0 {
+		return nil
+	}
+	out := make([]models.Service, len(s.Catalog))
+	for i, e := range s.Catalog {
+		status := e.Status
+		if status == "" {
+			status = "running"
+		}
+		out[i] = models.Service{Name: e.Name, Type: e.Type, Status: status, Endpoint: e.Endpoint}
+	}
+	return out
+}
+
+// AuthConfig controls API key authentication of /api/v1.
+type AuthConfig struct {
+	// APIKeys lists the accepted keys. Authentication is disabled when empty.
+	APIKeys []APIKey `yaml:"api_keys" toml:"api_keys"`
+}
+
+// SchedulerConfig controls scheduled device commands.
+type SchedulerConfig struct {
+	// File keeps the schedules across restarts. Schedules are kept in memory
+	// only when it is empty.
+	File string `yaml:"file" toml:"file"`
+	// MissedRunGrace is how late a run may start and still count as on time.
+	// Later runs, such as those due while the server was down, are missed
+	// and handled by the schedule's missed-run policy.
+	MissedRunGrace Duration `yaml:"missed_run_grace" toml:"missed_run_grace"`
+}
+
+// AutomationsConfig controls the automation rules engine.
+type AutomationsConfig struct {
+	// File is a YAML file of automation rules loaded at startup. Its rules
+	// are read-only through the API.
+	File string `yaml:"file" toml:"file"`
+	// NotifyURL receives notify actions as a JSON POST. Without it,
+	// notifications are only logged.
+	NotifyURL string `yaml:"notify_url" toml:"notify_url"`
+	// MaxChainDepth is how many automations may trigger each other in a row,
+	// through the device changes their commands make, before the chain is
+	// stopped as a loop.
+	MaxChainDepth int `yaml:"max_chain_depth" toml:"max_chain_depth"`
+}
+
+// APIKey is a named API key. The name identifies the caller in logs.
+type APIKey struct {
+	Name string `yaml:"name" toml:"name"`
+	Key  Secret `yaml:"key" toml:"key"`
+}
+
+// minAPIKeyLength is the shortest API key accepted by Validate.
+const minAPIKeyLength = 16
+
+// Secret is a string that is redacted when printed, logged or marshalled.
+type Secret string
+
+// String implements fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// Value returns the secret in clear text.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// Duration is a time.Duration written as a Go duration string ("30s", "5m")
+// in config files.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port: "8080",
+			Timeouts: TimeoutsConfig{
+				ReadHeader: Duration{5 * time.Second},
+				Read:       Duration{10 * time.Second},
+				Write:      Duration{15 * time.Second},
+				Idle:       Duration{60 * time.Second},
+				Handler:    Duration{10 * time.Second},
+			},
+			MaxBodyBytes:   1 << 20,
+			Compression:    CompressionConfig{Enabled: true, MinBytes: 1024},
+			IdempotencyTTL: Duration{24 * time.Hour},
+		},
+		Log:  LogConfig{Level: "info", Format: "text"},
+		CORS: CORSConfig{Origins: []string{"http://localhost:3000"}},
+		RateLimit: RateLimitConfig{
+			Requests:   500,
+			PerMinutes: 1,
+		},
+		Tracing:     TracingConfig{ServiceName: "homelab-api"},
+		Scheduler:   SchedulerConfig{MissedRunGrace: Duration{time.Minute}},
+		Automations: AutomationsConfig{MaxChainDepth: 5},
+	}
+}
+
+// Load builds the configuration from defaults, the config file, environment
+// variables and the command-line flags in args, in that order of precedence.
+// The config file is taken from the -config flag or CONFIG_FILE. Load returns
+// flag.ErrHelp when args contains -h or -help.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("homelab-api", flag.ContinueOnError)
+	var f flagValues
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	path := f.configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+		cfg.File = path
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := f.apply(fs, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile decodes the YAML or TOML file at path into c. Unknown keys are
+// rejected so that typos do not silently fall back to defaults.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config: parse %s: %w", path, err)
+		}
+	case ".toml":
+		dec := toml.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(c); err != nil {
+			var missing *toml.StrictMissingError
+			if errors.As(err, &missing) {
+				var errs []error
+				for _, e := range missing.Errors {
+					row, _ := e.Position()
+					errs = append(errs, fmt.Errorf("line %d: unknown key %q", row, strings.Join(e.Key(), ".")))
+				}
+				return fmt.Errorf("config: parse %s: %w", path, errors.Join(errs...))
+			}
+			var derr *toml.DecodeError
+			if errors.As(err, &derr) {
+				row, col := derr.Position()
+				return fmt.Errorf("config: parse %s:%d:%d: %s", path, row, col, derr.Error())
+			}
+			return fmt.Errorf("config: parse %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("config: %s: unsupported file extension %q (want .yaml, .yml or .toml)", path, ext)
+	}
+	return nil
+}
+
+// applyEnv overrides c with the environment variables that are set.
+func (c *Config) applyEnv() error {
+	setString := func(dst *string, key string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+
+	setString(&c.Server.Port, "PORT")
+	setString(&c.Server.MetricsPort, "METRICS_PORT")
+	setString(&c.Server.TLS.CertFile, "TLS_CERT_FILE")
+	setString(&c.Server.TLS.KeyFile, "TLS_KEY_FILE")
+	setString(&c.Server.TLS.ClientCAFile, "TLS_CLIENT_CA_FILE")
+	setString(&c.Server.TLS.ClientAuth, "TLS_CLIENT_AUTH")
+	setString(&c.Server.TLS.MinVersion, "TLS_MIN_VERSION")
+	if v := os.Getenv("TLS_CIPHER_SUITES"); v != "" {
+		c.Server.TLS.CipherSuites = splitList(v)
+	}
+	setString(&c.Log.Level, "LOG_LEVEL")
+	setString(&c.Log.Format, "LOG_FORMAT")
+	if v := os.Getenv("CORS_ORIGINS"); v != "" {
+		c.CORS.Origins = splitList(v)
+	}
+	setString(&c.RateLimit.RedisAddr, "RATE_LIMIT_REDIS_ADDR")
+	if v := os.Getenv("RATE_LIMIT_REDIS_PASSWORD"); v != "" {
+		c.RateLimit.RedisPassword = Secret(v)
+	}
+	setString(&c.Tracing.Endpoint, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	setString(&c.Tracing.ServiceName, "OTEL_SERVICE_NAME")
+	setString(&c.Scheduler.File, "SCHEDULER_FILE")
+	setString(&c.Automations.File, "AUTOMATIONS_FILE")
+	setString(&c.Automations.NotifyURL, "AUTOMATIONS_NOTIFY_URL")
+	if v := os.Getenv("API_KEYS"); v != "" {
+		c.Auth.APIKeys = nil
+		for _, entry := range splitList(v) {
+			name, key, _ := strings.Cut(entry, "=")
+			c.Auth.APIKeys = append(c.Auth.APIKeys, APIKey{Name: strings.TrimSpace(name), Key: Secret(strings.TrimSpace(key))})
+		}
+	}
+
+	var errs []error
+	if v := os.Getenv("RATE_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("RATE_LIMIT: %q is not an integer", v))
+		}
+		c.RateLimit.Requests = n
+	}
+	if v := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: %q is not a number", v))
+		}
+		c.Tracing.SampleRatio = r
+	}
+	setDuration := func(dst *Duration, key string) {
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration", key, v))
+			}
+			*dst = Duration{d}
+		}
+	}
+	setBool := func(dst *bool, key string) {
+		if v := os.Getenv(key); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a boolean", key, v))
+			}
+			*dst = b
+		}
+	}
+	setBool(&c.Server.Validation.Requests, "HTTP_VALIDATE_REQUESTS")
+	setBool(&c.Server.Validation.Responses, "HTTP_VALIDATE_RESPONSES")
+	setBool(&c.Server.Compression.Enabled, "HTTP_COMPRESSION")
+	setDuration(&c.Services.ProbeInterval, "SERVICE_PROBE_INTERVAL")
+	setDuration(&c.Server.Timeouts.ReadHeader, "HTTP_READ_HEADER_TIMEOUT")
+	setDuration(&c.Server.Timeouts.Read, "HTTP_READ_TIMEOUT")
+	setDuration(&c.Server.Timeouts.Write, "HTTP_WRITE_TIMEOUT")
+	setDuration(&c.Server.Timeouts.Idle, "HTTP_IDLE_TIMEOUT")
+	setDuration(&c.Server.Timeouts.Handler, "HTTP_HANDLER_TIMEOUT")
+	setDuration(&c.Server.IdempotencyTTL, "HTTP_IDEMPOTENCY_TTL")
+	setDuration(&c.Scheduler.MissedRunGrace, "SCHEDULER_MISSED_RUN_GRACE")
+	if v := os.Getenv("HTTP_MAX_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("HTTP_MAX_BODY_BYTES: %q is not an integer", v))
+		}
+		c.Server.MaxBodyBytes = n
+	}
+	if v := os.Getenv("HTTP_COMPRESSION_MIN_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("HTTP_COMPRESSION_MIN_BYTES: %q is not an integer", v))
+		}
+		c.Server.Compression.MinBytes = n
+	}
+	if v := os.Getenv("AUTOMATIONS_MAX_CHAIN_DEPTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("AUTOMATIONS_MAX_CHAIN_DEPTH: %q is not an integer", v))
+		}
+		c.Automations.MaxChainDepth = n
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("config: invalid environment: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate checks every field and reports all problems at once.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if !validPort(c.Server.Port) {
+		add("server.port", "must be a port number between 1 and 65535, got %q", c.Server.Port)
+	}
+	if c.Server.MetricsPort != "" {
+		if !validPort(c.Server.MetricsPort) {
+			add("server.metrics_port", "must be a port number between 1 and 65535, got %q", c.Server.MetricsPort)
+		} else if c.Server.MetricsPort == c.Server.Port {
+			add("server.metrics_port", "must differ from server.port (%s)", c.Server.Port)
+		}
+	}
+
+	c.Server.TLS.validate(add)
+	c.Server.Timeouts.validate(add)
+	if c.Server.MaxBodyBytes < 0 {
+		add("server.max_body_bytes", "must not be negative, got %d", c.Server.MaxBodyBytes)
+	}
+	if c.Server.IdempotencyTTL.Duration <= 0 {
+		add("server.idempotency_ttl", "must be greater than 0, got %s", c.Server.IdempotencyTTL)
+	}
+	if c.Server.Compression.MinBytes < 0 {
+		add("server.compression.min_bytes", "must not be negative, got %d", c.Server.Compression.MinBytes)
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		add("log.level", "must be one of debug, info, warn, error, got %q", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		add("log.format", "must be text or json, got %q", c.Log.Format)
+	}
+
+	if len(c.CORS.Origins) == 0 {
+		add("cors.origins", "must list at least one origin")
+	}
+	for _, o := range c.CORS.Origins {
+		if !validURL(o) {
+			add("cors.origins", "%q is not an absolute http(s) origin", o)
+		}
+	}
+
+	if c.RateLimit.Requests <= 0 {
+		add("rate_limit.requests", "must be greater than 0, got %d", c.RateLimit.Requests)
+	}
+	if c.RateLimit.PerMinutes <= 0 {
+		add("rate_limit.per_minutes", "must be greater than 0, got %d", c.RateLimit.PerMinutes)
+	}
+
+	if c.Tracing.Endpoint != "" && !validURL(c.Tracing.Endpoint) {
+		add("tracing.endpoint", "%q is not an absolute http(s) URL", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	if c.Services.ProbeInterval.Duration < 0 {
+		add("services.probe_interval", "must not be negative, got %s", c.Services.ProbeInterval)
+	}
+	seen := make(map[string]bool, len(c.Services.Catalog))
+	for i, e := range c.Services.Catalog {
+		field := fmt.Sprintf("services.catalog[%d]", i)
+		switch {
+		case e.Name == "":
+			add(field+".name", "must not be empty")
+		case seen[e.Name]:
+			add(field+".name", "duplicate service %q", e.Name)
+		}
+		seen[e.Name] = true
+		if !validURL(e.Endpoint) {
+			add(field+".endpoint", "%q is not an absolute http(s) URL", e.Endpoint)
+		}
+	}
+
+	if c.Scheduler.MissedRunGrace.Duration < 0 {
+		add("scheduler.missed_run_grace", "must not be negative, got %s", c.Scheduler.MissedRunGrace)
+	}
+	if c.Automations.NotifyURL != "" && !validURL(c.Automations.NotifyURL) {
+		add("automations.notify_url", "%q is not an absolute http(s) URL", c.Automations.NotifyURL)
+	}
+	if c.Automations.MaxChainDepth < 1 {
+		add("automations.max_chain_depth", "must be at least 1, got %d", c.Automations.MaxChainDepth)
+	}
+
+	names := make(map[string]bool, len(c.Auth.APIKeys))
+	keys := make(map[Secret]bool, len(c.Auth.APIKeys))
+	for i, k := range c.Auth.APIKeys {
+		field := fmt.Sprintf("auth.api_keys[%d]", i)
+		switch {
+		case k.Name == "":
+			add(field+".name", "must not be empty")
+		case names[k.Name]:
+			add(field+".name", "duplicate key name %q", k.Name)
+		}
+		names[k.Name] = true
+		switch {
+		case len(k.Key) < minAPIKeyLength:
+			add(field+".key", "must be at least %d characters", minAPIKeyLength)
+		case keys[k.Key]:
+			add(field+".key", "duplicates another key")
+		}
+		keys[k.Key] = true
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("config: invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validate reports timeout problems through add.
+func (t TimeoutsConfig) validate(add func(field, format string, args ...any)) {
+	for _, f := range []struct {
+		field string
+		d     Duration
+	}{
+		{"server.timeouts.read_header", t.ReadHeader},
+		{"server.timeouts.read", t.Read},
+		{"server.timeouts.write", t.Write},
+		{"server.timeouts.idle", t.Idle},
+	} {
+		if f.d.Duration < 0 {
+			add(f.field, "must not be negative, got %s", f.d)
+		}
+	}
+
+	check := func(field string, d Duration) {
+		switch {
+		case d.Duration < 0:
+			add(field, "must not be negative, got %s", d)
+		case t.Write.Duration > 0 && d.Duration >= t.Write.Duration:
+			add(field, "must be shorter than server.timeouts.write (%s) so the timeout response can be written, got %s", t.Write, d)
+		}
+	}
+	check("server.timeouts.handler", t.Handler)
+	routes := make([]string, 0, len(t.Routes))
+	for route := range t.Routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		field := fmt.Sprintf("server.timeouts.routes[%q]", route)
+		if !strings.HasPrefix(route, "/") {
+			add(field, "must be a route template such as \"/api/v1/services\"")
+		}
+		check(field, t.Routes[route])
+	}
+}
+
+// validate reports TLS problems through add.
+func (t TLSConfig) validate(add func(field, format string, args ...any)) {
+	if !t.Enabled() {
+		if t.ClientCAFile != "" {
+			add("server.tls.client_ca_file", "requires server.tls.cert_file and server.tls.key_file")
+		}
+		return
+	}
+	if t.CertFile == "" {
+		add("server.tls.cert_file", "must be set together with server.tls.key_file")
+	}
+	if t.KeyFile == "" {
+		add("server.tls.key_file", "must be set together with server.tls.cert_file")
+	}
+	for _, f := range []struct{ field, path string }{
+		{"server.tls.cert_file", t.CertFile},
+		{"server.tls.key_file", t.KeyFile},
+		{"server.tls.client_ca_file", t.ClientCAFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			add(f.field, "%v", err)
+		}
+	}
+	switch t.ClientAuth {
+	case "", ClientAuthRequire, ClientAuthOptional:
+	default:
+		add("server.tls.client_auth", "must be require or optional, got %q", t.ClientAuth)
+	}
+	switch t.MinVersion {
+	case "", "1.2", "1.3":
+	default:
+		add("server.tls.min_version", "must be 1.2 or 1.3, got %q", t.MinVersion)
+	}
+	for _, name := range t.CipherSuites {
+		if !secureCipherSuite(name) {
+			add("server.tls.cipher_suites", "%q is not a supported secure TLS 1.2 cipher suite", name)
+		}
+	}
+}
+
+// secureCipherSuite reports whether name is one of Go's secure cipher suites
+// that can be used with TLS 1.2. TLS 1.3 suites are not configurable in Go.
+func secureCipherSuite(name string) bool {
+	for _, cs := range tls.CipherSuites() {
+		if cs.Name == name {
+			return slices.Contains(cs.SupportedVersions, tls.VersionTLS12)
+		}
+	}
+	return false
+}
+
+// String renders the configuration as YAML with secrets redacted.
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("config: %v", err)
+	}
+	return string(out)
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func validPort(p string) bool {
+	n, err := strconv.Atoi(p)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+func validURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,560 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv unsets every variable Load reads so the host environment cannot
+// leak into a test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"CONFIG_FILE", "PORT", "METRICS_PORT", "LOG_LEVEL", "LOG_FORMAT",
+		"CORS_ORIGINS", "RATE_LIMIT", "RATE_LIMIT_REDIS_ADDR", "RATE_LIMIT_REDIS_PASSWORD",
+		"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_SERVICE_NAME", "OTEL_TRACES_SAMPLER_ARG",
+		"SERVICE_PROBE_INTERVAL", "API_KEYS",
+		"TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CLIENT_CA_FILE", "TLS_CLIENT_AUTH", "TLS_MIN_VERSION", "TLS_CIPHER_SUITES",
+		"HTTP_READ_HEADER_TIMEOUT", "HTTP_READ_TIMEOUT", "HTTP_WRITE_TIMEOUT", "HTTP_IDLE_TIMEOUT",
+		"HTTP_HANDLER_TIMEOUT", "HTTP_MAX_BODY_BYTES", "HTTP_VALIDATE_REQUESTS", "HTTP_VALIDATE_RESPONSES",
+		"HTTP_COMPRESSION", "HTTP_COMPRESSION_MIN_BYTES", "HTTP_IDEMPOTENCY_TTL",
+		"SCHEDULER_FILE", "SCHEDULER_MISSED_RUN_GRACE",
+		"AUTOMATIONS_FILE", "AUTOMATIONS_NOTIFY_URL", "AUTOMATIONS_MAX_CHAIN_DEPTH",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 500, cfg.RateLimit.Requests)
+	assert.Equal(t, []string{"http://localhost:3000"}, cfg.CORS.Origins)
+}
+
+func TestLoad_YAMLFile(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+server:
+  port: "9090"
+  metrics_port: "9100"
+log:
+  level: debug
+  format: json
+cors:
+  origins: [https://dash.example.com]
+rate_limit:
+  requests: 50
+  per_minutes: 2
+  redis_password: hunter2
+services:
+  probe_interval: 30s
+  catalog:
+    - name: jellyfin
+      type: media
+      endpoint: http://jellyfin.local:8096
+`)
+
+	cfg, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Server.Port)
+	assert.Equal(t, "9100", cfg.Server.MetricsPort)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, "json", cfg.Log.Format)
+	assert.Equal(t, []string{"https://dash.example.com"}, cfg.CORS.Origins)
+	assert.Equal(t, 50, cfg.RateLimit.Requests)
+	assert.Equal(t, 2, cfg.RateLimit.PerMinutes)
+	assert.Equal(t, "hunter2", cfg.RateLimit.RedisPassword.Value())
+	assert.Equal(t, 30*time.Second, cfg.Services.ProbeInterval.Duration)
+	assert.Equal(t, []models.Service{
+		{Name: "jellyfin", Type: "media", Status: "running", Endpoint: "http://jellyfin.local:8096"},
+	}, cfg.Services.Models())
+	assert.Equal(t, "homelab-api", cfg.Tracing.ServiceName, "unset keys keep their defaults")
+}
+
+func TestLoad_TOMLFile(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+[server]
+port = "9090"
+
+[rate_limit]
+requests = 42
+
+[services]
+probe_interval = "1m"
+`)
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Server.Port)
+	assert.Equal(t, 42, cfg.RateLimit.Requests)
+	assert.Equal(t, time.Minute, cfg.Services.ProbeInterval.Duration)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", "server:\n  port: \"7000\"\nlog:\n  level: warn\nrate_limit:\n  requests: 10\n")
+	t.Setenv("PORT", "7001")
+	t.Setenv("LOG_LEVEL", "error")
+
+	cfg, err := Load([]string{"-config", path, "-port", "7002"})
+	require.NoError(t, err)
+	assert.Equal(t, "7002", cfg.Server.Port, "flags override env")
+	assert.Equal(t, "error", cfg.Log.Level, "env overrides the file")
+	assert.Equal(t, 10, cfg.RateLimit.Requests, "the file overrides defaults")
+}
+
+func TestLoad_Env(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CORS_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("RATE_LIMIT", "75")
+	t.Setenv("RATE_LIMIT_REDIS_ADDR", "redis:6379")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0.25")
+	t.Setenv("SERVICE_PROBE_INTERVAL", "15s")
+	t.Setenv("SCHEDULER_FILE", "/var/lib/homelab-api/schedules.json")
+	t.Setenv("SCHEDULER_MISSED_RUN_GRACE", "5m")
+	t.Setenv("AUTOMATIONS_FILE", "/etc/homelab-api/automations.yaml")
+	t.Setenv("AUTOMATIONS_MAX_CHAIN_DEPTH", "3")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORS.Origins)
+	assert.Equal(t, 75, cfg.RateLimit.Requests)
+	assert.Equal(t, "redis:6379", cfg.RateLimit.RedisAddr)
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+	assert.Equal(t, 15*time.Second, cfg.Services.ProbeInterval.Duration)
+	assert.Equal(t, "/var/lib/homelab-api/schedules.json", cfg.Scheduler.File)
+	assert.Equal(t, 5*time.Minute, cfg.Scheduler.MissedRunGrace.Duration)
+	assert.Equal(t, "/etc/homelab-api/automations.yaml", cfg.Automations.File)
+	assert.Equal(t, 3, cfg.Automations.MaxChainDepth)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		content string
+		wantErr []string
+	}{
+		{
+			name:    "unknown yaml key",
+			file:    "config.yaml",
+			content: "server:\n  prot: \"80\"\n",
+			wantErr: []string{"field prot not found"},
+		},
+		{
+			name:    "unknown toml key",
+			file:    "config.toml",
+			content: "[server]\nprot = \"80\"\n",
+			wantErr: []string{`line 2: unknown key "server.prot"`},
+		},
+		{
+			name:    "unsupported extension",
+			file:    "config.json",
+			content: "{}",
+			wantErr: []string{"unsupported file extension"},
+		},
+		{
+			name:    "bad env number",
+			env:     map[string]string{"RATE_LIMIT": "lots"},
+			wantErr: []string{`RATE_LIMIT: "lots" is not an integer`},
+		},
+		{
+			name:    "bad flag",
+			args:    []string{"-rate-limit", "many"},
+			wantErr: []string{"invalid value"},
+		},
+		{
+			name: "validation reports every problem",
+			env: map[string]string{
+				"PORT":         "http",
+				"LOG_LEVEL":    "loud",
+				"CORS_ORIGINS": "example.com",
+			},
+			wantErr: []string{"server.port", "log.level", `cors.origins: "example.com"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "-config", writeFile(t, tt.file, tt.content))
+			}
+
+			_, err := Load(args)
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	clearEnv(t)
+	_, err := Load([]string{"-h"})
+	assert.ErrorIs(t, err, flag.ErrHelp)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{name: "defaults are valid", mutate: func(*Config) {}},
+		{
+			name:    "metrics port clashes",
+			mutate:  func(c *Config) { c.Server.MetricsPort = c.Server.Port },
+			wantErr: "server.metrics_port: must differ",
+		},
+		{
+			name:    "zero rate limit",
+			mutate:  func(c *Config) { c.RateLimit.Requests = 0 },
+			wantErr: "rate_limit.requests",
+		},
+		{
+			name:    "negative missed run grace",
+			mutate:  func(c *Config) { c.Scheduler.MissedRunGrace = Duration{-time.Second} },
+			wantErr: "scheduler.missed_run_grace: must not be negative",
+		},
+		{
+			name:    "invalid notify URL",
+			mutate:  func(c *Config) { c.Automations.NotifyURL = "ntfy.sh/homelab" },
+			wantErr: "automations.notify_url",
+		},
+		{
+			name:    "zero chain depth",
+			mutate:  func(c *Config) { c.Automations.MaxChainDepth = 0 },
+			wantErr: "automations.max_chain_depth: must be at least 1",
+		},
+		{
+			name:    "sample ratio out of range",
+			mutate:  func(c *Config) { c.Tracing.SampleRatio = 1.5 },
+			wantErr: "tracing.sample_ratio",
+		},
+		{
+			name: "duplicate catalog entry",
+			mutate: func(c *Config) {
+				c.Services.Catalog = []ServiceEntry{
+					{Name: "grafana", Endpoint: "http://grafana.local:3000"},
+					{Name: "grafana", Endpoint: "http://grafana2.local:3000"},
+				}
+			},
+			wantErr: `services.catalog[1].name: duplicate service "grafana"`,
+		},
+		{
+			name: "catalog endpoint must be a URL",
+			mutate: func(c *Config) {
+				c.Services.Catalog = []ServiceEntry{{Name: "grafana", Endpoint: "grafana.local"}}
+			},
+			wantErr: "services.catalog[0].endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_StringRedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.RateLimit.RedisAddr = "redis:6379"
+	cfg.RateLimit.RedisPassword = "hunter2"
+	cfg.Services.ProbeInterval = Duration{30 * time.Second}
+
+	out := cfg.String()
+	assert.NotContains(t, out, "hunter2")
+	assert.Contains(t, out, "redis_password: '[REDACTED]'")
+	assert.Contains(t, out, "probe_interval: 30s")
+	assert.Contains(t, out, "redis_addr: redis:6379")
+}
+
+func TestSecret(t *testing.T) {
+	s := Secret("hunter2")
+	assert.Equal(t, "[REDACTED]", s.String())
+	assert.Equal(t, "[REDACTED]", s.LogValue().String())
+	assert.Equal(t, "hunter2", s.Value())
+	assert.Equal(t, "", Secret("").String(), "an unset secret prints as empty")
+}
+
+func TestLoad_ExampleFile(t *testing.T) {
+	clearEnv(t)
+	cfg, err := Load([]string{"-config", "../../deployments/config.example.yaml"})
+	require.NoError(t, err, "the documented example must stay loadable")
+	assert.Len(t, cfg.Services.Catalog, 2)
+}
+
+func TestLoad_APIKeys(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("API_KEYS", "dashboard=dashboard-key-0123456789, ci=ci-key-0123456789abcdef")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	require.Len(t, cfg.Auth.APIKeys, 2)
+	assert.Equal(t, "dashboard", cfg.Auth.APIKeys[0].Name)
+	assert.Equal(t, "ci-key-0123456789abcdef", cfg.Auth.APIKeys[1].Key.Value())
+	assert.NotContains(t, cfg.String(), "ci-key-0123456789abcdef")
+}
+
+func TestValidate_APIKeys(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.APIKeys = []APIKey{
+		{Name: "ci", Key: "short"},
+		{Name: "ci", Key: "0123456789abcdef"},
+		{Name: "", Key: "0123456789abcdef"},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "auth.api_keys[0].key: must be at least 16 characters")
+	assert.Contains(t, err.Error(), `auth.api_keys[1].name: duplicate key name "ci"`)
+	assert.Contains(t, err.Error(), "auth.api_keys[2].name: must not be empty")
+	assert.Contains(t, err.Error(), "auth.api_keys[2].key: duplicates another key")
+}
+
+func TestValidate_TLS(t *testing.T) {
+	dir := t.TempDir()
+	cert := writeFile(t, "tls.crt", "cert")
+	key := writeFile(t, "tls.key", "key")
+
+	tests := []struct {
+		name    string
+		tls     TLSConfig
+		wantErr []string
+	}{
+		{name: "disabled", tls: TLSConfig{}},
+		{name: "cert and key", tls: TLSConfig{CertFile: cert, KeyFile: key, MinVersion: "1.3"}},
+		{
+			name:    "key without cert",
+			tls:     TLSConfig{KeyFile: key},
+			wantErr: []string{"server.tls.cert_file: must be set together with server.tls.key_file"},
+		},
+		{
+			name:    "client CA without TLS",
+			tls:     TLSConfig{ClientCAFile: cert},
+			wantErr: []string{"server.tls.client_ca_file: requires"},
+		},
+		{
+			name:    "missing file",
+			tls:     TLSConfig{CertFile: filepath.Join(dir, "nope.crt"), KeyFile: key},
+			wantErr: []string{"server.tls.cert_file:", "nope.crt"},
+		},
+		{
+			name: "bad options",
+			tls: TLSConfig{
+				CertFile:     cert,
+				KeyFile:      key,
+				ClientAuth:   "sometimes",
+				MinVersion:   "1.0",
+				CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+			},
+			wantErr: []string{"server.tls.client_auth", "server.tls.min_version", `"TLS_RSA_WITH_RC4_128_SHA" is not a supported`},
+		},
+		{
+			name:    "TLS 1.3 cipher suite",
+			tls:     TLSConfig{CertFile: cert, KeyFile: key, CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+			wantErr: []string{`"TLS_AES_128_GCM_SHA256" is not a supported secure TLS 1.2 cipher suite`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Server.TLS = tt.tls
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestLoad_TLSEnv(t *testing.T) {
+	clearEnv(t)
+	cert := writeFile(t, "tls.crt", "cert")
+	key := writeFile(t, "tls.key", "key")
+	t.Setenv("TLS_CERT_FILE", cert)
+	t.Setenv("TLS_KEY_FILE", key)
+	t.Setenv("TLS_MIN_VERSION", "1.3")
+	t.Setenv("TLS_CIPHER_SUITES", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.Server.TLS.Enabled())
+	assert.Equal(t, "1.3", cfg.Server.TLS.MinVersion)
+	assert.Len(t, cfg.Server.TLS.CipherSuites, 2)
+}
+
+func TestLoad_Timeouts(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+server:
+  max_body_bytes: 65536
+  idempotency_ttl: 1h
+  compression:
+    min_bytes: 512
+  timeouts:
+    read_header: 2s
+    write: 45s
+    handler: 5s
+    routes:
+      /api/v1/homeassistant/devices/:id/command: 30s
+`)
+	t.Setenv("HTTP_IDLE_TIMEOUT", "2m")
+	t.Setenv("HTTP_COMPRESSION", "false")
+
+	cfg, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+
+	timeouts := cfg.Server.Timeouts
+	assert.Equal(t, 2*time.Second, timeouts.ReadHeader.Duration)
+	assert.Equal(t, 10*time.Second, timeouts.Read.Duration, "unset keys keep their defaults")
+	assert.Equal(t, 45*time.Second, timeouts.Write.Duration)
+	assert.Equal(t, 2*time.Minute, timeouts.Idle.Duration)
+	assert.Equal(t, 5*time.Second, timeouts.Handler.Duration)
+	assert.Equal(t, map[string]Duration{
+		"/api/v1/homeassistant/devices/:id/command": {30 * time.Second},
+	}, timeouts.Routes)
+	assert.Equal(t, int64(65536), cfg.Server.MaxBodyBytes)
+	assert.Equal(t, CompressionConfig{Enabled: false, MinBytes: 512}, cfg.Server.Compression)
+	assert.Equal(t, time.Hour, cfg.Server.IdempotencyTTL.Duration)
+}
+
+func TestLoad_TimeoutsEnvErrors(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("HTTP_WRITE_TIMEOUT", "soon")
+	t.Setenv("HTTP_MAX_BODY_BYTES", "1MB")
+
+	_, err := Load(nil)
+	assert.ErrorContains(t, err, `HTTP_WRITE_TIMEOUT: "soon" is not a duration`)
+	assert.ErrorContains(t, err, `HTTP_MAX_BODY_BYTES: "1MB" is not an integer`)
+}
+
+func TestLoad_Validation(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+[server.validation]
+requests = true
+`)
+	t.Setenv("HTTP_VALIDATE_RESPONSES", "true")
+
+	cfg, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+	assert.Equal(t, ValidationConfig{Requests: true, Responses: true}, cfg.Server.Validation)
+
+	t.Setenv("HTTP_VALIDATE_REQUESTS", "sometimes")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, `HTTP_VALIDATE_REQUESTS: "sometimes" is not a boolean`)
+}
+
+func TestValidate_Timeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{
+			name:   "zero disables timeouts",
+			mutate: func(c *Config) { c.Server.Timeouts = TimeoutsConfig{}; c.Server.MaxBodyBytes = 0 },
+		},
+		{
+			name:   "handler deadline without write timeout",
+			mutate: func(c *Config) { c.Server.Timeouts.Write = Duration{} },
+		},
+		{
+			name:    "negative read timeout",
+			mutate:  func(c *Config) { c.Server.Timeouts.Read = Duration{-time.Second} },
+			wantErr: "server.timeouts.read: must not be negative",
+		},
+		{
+			name:    "handler deadline outlives write timeout",
+			mutate:  func(c *Config) { c.Server.Timeouts.Handler = c.Server.Timeouts.Write },
+			wantErr: "server.timeouts.handler: must be shorter than server.timeouts.write",
+		},
+		{
+			name: "route deadline outlives write timeout",
+			mutate: func(c *Config) {
+				c.Server.Timeouts.Routes = map[string]Duration{"/api/v1/services": {time.Minute}}
+			},
+			wantErr: `server.timeouts.routes["/api/v1/services"]: must be shorter`,
+		},
+		{
+			name: "route must be a template",
+			mutate: func(c *Config) {
+				c.Server.Timeouts.Routes = map[string]Duration{"GET /api/v1/services": {time.Second}}
+			},
+			wantErr: "must be a route template",
+		},
+		{
+			name:    "negative body limit",
+			mutate:  func(c *Config) { c.Server.MaxBodyBytes = -1 },
+			wantErr: "server.max_body_bytes",
+		},
+		{
+			name:    "negative compression threshold",
+			mutate:  func(c *Config) { c.Server.Compression.MinBytes = -1 },
+			wantErr: "server.compression.min_bytes",
+		},
+		{
+			name:    "zero idempotency TTL",
+			mutate:  func(c *Config) { c.Server.IdempotencyTTL = Duration{} },
+			wantErr: "server.idempotency_ttl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
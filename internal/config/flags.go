@@ -0,0 +1,58 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// flagValues holds the command-line overrides. Only flags that were set
+// explicitly are applied, so defaults never mask file or environment values.
+type flagValues struct {
+	configFile    string
+	port          string
+	metricsPort   string
+	logLevel      string
+	logFormat     string
+	corsOrigins   string
+	rateLimit     int
+	probeInterval time.Duration
+}
+
+func (f *flagValues) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.configFile, "config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	fs.StringVar(&f.port, "port", "", "HTTP port (env PORT)")
+	fs.StringVar(&f.metricsPort, "metrics-port", "", "serve /metrics on this admin port (env METRICS_PORT)")
+	fs.StringVar(&f.logLevel, "log-level", "", "debug, info, warn or error (env LOG_LEVEL)")
+	fs.StringVar(&f.logFormat, "log-format", "", "text or json (env LOG_FORMAT)")
+	fs.StringVar(&f.corsOrigins, "cors-origins", "", "comma-separated allowed CORS origins (env CORS_ORIGINS)")
+	fs.IntVar(&f.rateLimit, "rate-limit", 0, "requests per rate limit window per client (env RATE_LIMIT)")
+	fs.DurationVar(&f.probeInterval, "probe-interval", 0, "service probe interval, e.g. 30s (env SERVICE_PROBE_INTERVAL)")
+}
+
+// apply copies the explicitly set flags onto c.
+func (f *flagValues) apply(fs *flag.FlagSet, c *Config) error {
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "port":
+			c.Server.Port = f.port
+		case "metrics-port":
+			c.Server.MetricsPort = f.metricsPort
+		case "log-level":
+			c.Log.Level = f.logLevel
+		case "log-format":
+			c.Log.Format = f.logFormat
+		case "cors-origins":
+			c.CORS.Origins = splitList(f.corsOrigins)
+		case "rate-limit":
+			c.RateLimit.Requests = f.rateLimit
+		case "probe-interval":
+			c.Services.ProbeInterval = Duration{f.probeInterval}
+		case "config":
+		default:
+			err = fmt.Errorf("config: unhandled flag -%s", fl.Name)
+		}
+	})
+	return err
+}
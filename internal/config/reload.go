@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-github/internal/metrics"
+	"go-github/internal/models"
+)
+
+// setting describes one top-level setting for change detection.
+type setting struct {
+	name string
+	live bool
+	get  func(*Config) any
+}
+
+// settings lists every setting and whether it can be applied without a
+// restart. Listeners are bound once at startup, the server middleware is built
+// once in server.New, and the log format, tracer provider, prober, scheduler
+// and automation engine are built once, so those require a restart.
+var settings = []setting{
+	{"server.port", false, func(c *Config) any { return c.Server.Port }},
+	{"server.metrics_port", false, func(c *Config) any { return c.Server.MetricsPort }},
+	{"server.tls", false, func(c *Config) any { return c.Server.TLS }},
+	{"server.timeouts", false, func(c *Config) any { return c.Server.Timeouts }},
+	{"server.max_body_bytes", false, func(c *Config) any { return c.Server.MaxBodyBytes }},
+	{"server.validation", false, func(c *Config) any { return c.Server.Validation }},
+	{"server.compression", false, func(c *Config) any { return c.Server.Compression }},
+	{"server.idempotency_ttl", false, func(c *Config) any { return c.Server.IdempotencyTTL }},
+	{"log.level", true, func(c *Config) any { return c.Log.Level }},
+	{"log.format", false, func(c *Config) any { return c.Log.Format }},
+	{"cors.origins", true, func(c *Config) any { return c.CORS.Origins }},
+	{"rate_limit", true, func(c *Config) any { return c.RateLimit }},
+	{"tracing", false, func(c *Config) any { return c.Tracing }},
+	{"services.probe_interval", false, func(c *Config) any { return c.Services.ProbeInterval }},
+	{"services.catalog", true, func(c *Config) any { return c.Services.Catalog }},
+	{"auth.api_keys", true, func(c *Config) any { return c.Auth.APIKeys }},
+	{"scheduler", false, func(c *Config) any { return c.Scheduler }},
+	{"automations", false, func(c *Config) any { return c.Automations }},
+}
+
+// Diff reports which settings differ between old and next.
+func Diff(old, next *Config) models.ReloadResult {
+	r := models.ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	for _, s := range settings {
+		if reflect.DeepEqual(s.get(old), s.get(next)) {
+			continue
+		}
+		if s.live {
+			r.Applied = append(r.Applied, s.name)
+		} else {
+			r.RestartRequired = append(r.RestartRequired, s.name)
+		}
+	}
+	return r
+}
+
+// keepRestartOnly copies the settings that cannot change at runtime from
+// running into next, so that next describes what is actually in effect.
+func keepRestartOnly(next, running *Config) {
+	next.Server = running.Server
+	next.Log.Format = running.Log.Format
+	next.Tracing = running.Tracing
+	next.Services.ProbeInterval = running.Services.ProbeInterval
+	next.Scheduler = running.Scheduler
+	next.Automations = running.Automations
+}
+
+// Reloader holds the live configuration and replaces it on Reload. Reloads
+// are serialized and all-or-nothing: the new configuration is loaded and
+// validated in full before anything is applied, and the previous one stays in
+// effect when that fails.
+type Reloader struct {
+	load    func() (*Config, error)
+	current atomic.Pointer[Config]
+
+	mu        sync.Mutex
+	listeners []func(*Config)
+}
+
+// NewReloader returns a Reloader serving initial. load is called on every
+// reload and should layer the same sources as the initial load.
+func NewReloader(initial *Config, load func() (*Config, error)) *Reloader {
+	r := &Reloader{load: load}
+	r.current.Store(initial)
+	return r
+}
+
+// Current returns the configuration in effect. Callers must not modify it.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload registers fn to be called with the new configuration after every
+// successful reload that changed a live setting.
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload loads and validates the configuration again and applies the live
+// settings that changed. On error the previous configuration is kept.
+func (r *Reloader) Reload() (models.ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		metrics.ConfigReloadsTotal.WithLabelValues(metrics.OutcomeError).Inc()
+		slog.Error("config reload failed, keeping previous configuration", "error", err)
+		return models.ReloadResult{}, err
+	}
+
+	running := r.current.Load()
+	result := Diff(running, next)
+	keepRestartOnly(next, running)
+	r.current.Store(next)
+	metrics.ConfigReloadsTotal.WithLabelValues(metrics.OutcomeSuccess).Inc()
+
+	if len(result.Applied) > 0 {
+		for _, fn := range r.listeners {
+			fn(next)
+		}
+	}
+	slog.Info("config reloaded",
+		"applied", result.Applied,
+		"restart_required", result.RestartRequired,
+	)
+	if len(result.RestartRequired) > 0 {
+		slog.Warn("config changes require a restart to take effect", "settings", result.RestartRequired)
+	}
+	return result, nil
+}
+
+// Watch polls the config file every interval and reloads when its contents
+// change, until ctx is cancelled. Polling rather than inotify also catches
+// Kubernetes ConfigMap updates, which swap a symlink instead of writing the
+// file. Watch returns immediately when no config file is in use.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) error {
+	path := r.Current().File
+	if path == "" {
+		return nil
+	}
+	slog.Info("watching config file", "path", path, "interval", interval.String())
+
+	last := fileSum(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		sum := fileSum(path)
+		if sum == nil || bytes.Equal(sum, last) {
+			continue
+		}
+		last = sum
+		_, _ = r.Reload()
+	}
+}
+
+// fileSum returns the SHA-256 of the file at path, or nil if it can't be read
+// or is empty (for example while an update is being written).
+func fileSum(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
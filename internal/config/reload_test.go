@@ -0,0 +1,214 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	next := Default()
+	next.Server.Port = "9090"
+	next.Server.TLS.MinVersion = "1.3"
+	next.Server.MaxBodyBytes = 1 << 10
+	next.Server.Validation.Requests = !old.Server.Validation.Requests
+	next.Server.Compression.MinBytes = 64
+	next.Server.IdempotencyTTL = Duration{time.Minute}
+	next.Log.Level = "debug"
+	next.CORS.Origins = []string{"https://dash.example.com"}
+	next.RateLimit.Requests = 10
+	next.Tracing.Endpoint = "http://otel:4318"
+	next.Auth.APIKeys = []APIKey{{Name: "ci", Key: "0123456789abcdef"}}
+	next.Scheduler.File = "/tmp/schedules.json"
+	next.Automations.MaxChainDepth = 2
+
+	r := Diff(old, next)
+	assert.Equal(t, []string{"log.level", "cors.origins", "rate_limit", "auth.api_keys"}, r.Applied)
+	assert.Equal(t, []string{"server.port", "server.tls", "server.max_body_bytes", "server.validation", "server.compression", "server.idempotency_ttl", "tracing", "scheduler", "automations"}, r.RestartRequired)
+
+	assert.Empty(t, Diff(old, Default()).Applied)
+}
+
+// TestSettings_CoverConfig changes every configuration field in turn and checks
+// that Diff reports it, and that keepRestartOnly keeps it at its running value
+// exactly when it is reported as requiring a restart.
+func TestSettings_CoverConfig(t *testing.T) {
+	for _, path := range leafFields(reflect.TypeFor[Config](), nil) {
+		name := fieldName(path)
+		t.Run(name, func(t *testing.T) {
+			old := Default()
+			next := Default()
+			changeValue(reflect.ValueOf(next).Elem().FieldByIndex(path))
+
+			r := Diff(old, next)
+			require.Len(t, append(r.Applied, r.RestartRequired...), 1,
+				"%s is covered by no entry of settings", name)
+
+			keepRestartOnly(next, old)
+			kept := reflect.DeepEqual(
+				reflect.ValueOf(old).Elem().FieldByIndex(path).Interface(),
+				reflect.ValueOf(next).Elem().FieldByIndex(path).Interface(),
+			)
+			if len(r.RestartRequired) > 0 {
+				assert.True(t, kept, "%s requires a restart but keepRestartOnly does not keep it", name)
+			} else {
+				assert.False(t, kept, "%s is live but keepRestartOnly reverts it", name)
+			}
+		})
+	}
+}
+
+// leafFields returns the index paths of every configuration field below t
+// that is not itself a struct. Fields without a yaml name are skipped.
+func leafFields(t reflect.Type, prefix []int) [][]int {
+	var out [][]int
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Tag.Get("yaml") == "-" {
+			continue
+		}
+		path := append(slices.Clone(prefix), i)
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeFor[Duration]() {
+			out = append(out, leafFields(f.Type, path)...)
+			continue
+		}
+		out = append(out, path)
+	}
+	return out
+}
+
+// fieldName returns the dotted yaml name of the field at path.
+func fieldName(path []int) string {
+	t := reflect.TypeFor[Config]()
+	var parts []string
+	for _, i := range path {
+		f := t.Field(i)
+		parts = append(parts, f.Tag.Get("yaml"))
+		t = f.Type
+	}
+	return strings.Join(parts, ".")
+}
+
+// changeValue sets v to a value different from its current one.
+func changeValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(v.String() + "x")
+	case reflect.Bool:
+		v.SetBool(!v.Bool())
+	case reflect.Int, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Float64:
+		v.SetFloat(v.Float() + 0.5)
+	case reflect.Slice:
+		v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		m.SetMapIndex(reflect.ValueOf("changed"), reflect.New(v.Type().Elem()).Elem())
+		v.Set(m)
+	case reflect.Struct:
+		changeValue(v.Field(0))
+	default:
+		panic("changeValue: unsupported kind " + v.Kind().String())
+	}
+}
+
+func TestReloader_Reload(t *testing.T) {
+	initial := Default()
+	next := Default()
+	next.Server.Port = "9090"
+	next.CORS.Origins = []string{"https://dash.example.com"}
+
+	var applied []*Config
+	r := NewReloader(initial, func() (*Config, error) { return next, nil })
+	r.OnReload(func(c *Config) { applied = append(applied, c) })
+
+	result, err := r.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cors.origins"}, result.Applied)
+	assert.Equal(t, []string{"server.port"}, result.RestartRequired)
+
+	cur := r.Current()
+	assert.Equal(t, []string{"https://dash.example.com"}, cur.CORS.Origins)
+	assert.Equal(t, "8080", cur.Server.Port, "restart-only settings keep their running value")
+	require.Len(t, applied, 1)
+	assert.Same(t, cur, applied[0])
+}
+
+func TestReloader_ReloadWithoutLiveChangesSkipsListeners(t *testing.T) {
+	next := Default()
+	next.Server.Port = "9090"
+	r := NewReloader(Default(), func() (*Config, error) { return next, nil })
+
+	called := false
+	r.OnReload(func(*Config) { called = true })
+
+	_, err := r.Reload()
+	require.NoError(t, err)
+	assert.False(t, called)
+}
+
+func TestReloader_FailedReloadKeepsPrevious(t *testing.T) {
+	initial := Default()
+	r := NewReloader(initial, func() (*Config, error) {
+		return nil, errors.New("config: invalid configuration")
+	})
+	r.OnReload(func(*Config) { t.Fatal("listeners must not run on a failed reload") })
+
+	_, err := r.Reload()
+	assert.Error(t, err)
+	assert.Same(t, initial, r.Current())
+}
+
+// replaceFile atomically replaces path, the way editors and ConfigMap
+// updates do.
+func replaceFile(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestReloader_WatchReloadsOnFileChange(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", "cors:\n  origins: [https://a.example.com]\n")
+	initial, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+
+	r := NewReloader(initial, func() (*Config, error) {
+		return Load([]string{"-config", path})
+	})
+	var reloads atomic.Int32
+	r.OnReload(func(*Config) { reloads.Add(1) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Watch(ctx, 10*time.Millisecond) }()
+
+	// An invalid edit is rejected and the previous configuration kept.
+	replaceFile(t, path, "cors:\n  origins: [not-a-url]\n")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"https://a.example.com"}, r.Current().CORS.Origins)
+
+	replaceFile(t, path, "cors:\n  origins: [https://b.example.com]\n")
+	assert.Eventually(t, func() bool { return reloads.Load() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"https://b.example.com"}, r.Current().CORS.Origins)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestReloader_WatchWithoutFile(t *testing.T) {
+	r := NewReloader(Default(), func() (*Config, error) { return Default(), nil })
+	assert.NoError(t, r.Watch(context.Background(), time.Millisecond), "Watch returns immediately when no file is in use")
+}
@@ -0,0 +1,298 @@
+// Package events is an in-process publish/subscribe bus for state and
+// lifecycle events, such as device changes, executed commands, services
+// going up or down and cluster services appearing.
+//
+// Events are published on typed topics, declared with NewTopic by the package
+// that publishes them. Each subscription has its own bounded buffer, so a
+// slow subscriber never blocks a publisher or the other subscribers: when its
+// buffer is full, the subscription's Policy decides whether the new event or
+// the oldest buffered one is dropped, or whether the subscriber is cut off.
+// Published, dropped and subscriber counts are exported as Prometheus
+// metrics.
+package events
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+
+	"go-github/internal/metrics"
+)
+
+// DefaultBuffer is the buffer of a subscription unless WithBuffer says
+// otherwise.
+const DefaultBuffer = 64
+
+// Topic is a named stream of events of type T.
+type Topic[T any] struct {
+	name string
+}
+
+// topicTypes records the event type of every topic name, so that two
+// packages cannot declare the same topic with different types.
+var topicTypes = struct {
+	mu    sync.Mutex
+	types map[string]reflect.Type
+}{types: map[string]reflect.Type{}}
+
+// NewTopic declares the topic name, whose events are of type T. Declaring a
+// name again with the same type returns the same topic; with another type it
+// panics.
+func NewTopic[T any](name string) Topic[T] {
+	typ := reflect.TypeFor[T]()
+	topicTypes.mu.Lock()
+	defer topicTypes.mu.Unlock()
+	if existing, ok := topicTypes.types[name]; ok && existing != typ {
+		panic(fmt.Sprintf("events: topic %q declared with %v and %v", name, existing, typ))
+	}
+	topicTypes.types[name] = typ
+	return Topic[T]{name: name}
+}
+
+// Name returns the name of the topic.
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Policy says what happens to an event published to a subscription whose
+// buffer is full.
+type Policy int
+
+const (
+	// DropNewest drops the new event and keeps the buffered ones.
+	DropNewest Policy = iota
+	// DropOldest drops the oldest buffered event to make room for the new
+	// one, for subscribers that only care about recent events.
+	DropOldest
+	// Disconnect closes the subscription, for subscribers that cannot
+	// tolerate gaps and would rather start over, such as a stream to a
+	// client.
+	Disconnect
+)
+
+// String returns the name of p as used in logs.
+func (p Policy) String() string {
+	switch p {
+	case DropNewest:
+		return "drop_newest"
+	case DropOldest:
+		return "drop_oldest"
+	case Disconnect:
+		return "disconnect"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Bus delivers published events to the subscriptions of their topic.
+type Bus struct {
+	mu   sync.RWMutex
+	next uint64
+	subs map[string]map[uint64]subscriber
+}
+
+// subscriber is a Subscription of any event type.
+type subscriber interface {
+	// deliver queues ev and reports whether the subscription was
+	// disconnected because its buffer was full.
+	deliver(ev any) (disconnected bool)
+}
+
+// New creates a Bus without subscriptions.
+func New() *Bus {
+	return &Bus{subs: map[string]map[uint64]subscriber{}}
+}
+
+var (
+	defaultMu  sync.Mutex
+	defaultBus *Bus
+)
+
+// Default returns the bus that the stores, the service prober and the
+// cluster watcher publish on.
+func Default() *Bus {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultBus == nil {
+		defaultBus = New()
+	}
+	return defaultBus
+}
+
+// SetDefault replaces the bus returned by Default. Subscriptions to the
+// previous bus receive no more events.
+func SetDefault(b *Bus) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultBus = b
+}
+
+// Publish delivers ev to every subscription of topic without waiting for
+// them. A subscription whose buffer is full handles ev by its policy.
+func Publish[T any](b *Bus, topic Topic[T], ev T) {
+	metrics.EventsPublishedTotal.WithLabelValues(topic.name).Inc()
+
+	var disconnected []uint64
+	b.mu.RLock()
+	for id, s := range b.subs[topic.name] {
+		if s.deliver(ev) {
+			disconnected = append(disconnected, id)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, id := range disconnected {
+		b.remove(topic.name, id)
+	}
+}
+
+// remove unregisters the subscription id of topic.
+func (b *Bus) remove(topic string, id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[topic][id]; !ok {
+		return
+	}
+	delete(b.subs[topic], id)
+	if len(b.subs[topic]) == 0 {
+		delete(b.subs, topic)
+	}
+	metrics.EventSubscribers.WithLabelValues(topic).Dec()
+}
+
+// SubscribeOption configures a Subscription.
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	name   string
+	buffer int
+	policy Policy
+}
+
+// WithName names the subscriber in logs and in the dropped events metric.
+// It should be a constant, such as the name of the subscribing package.
+func WithName(name string) SubscribeOption {
+	return func(o *subscribeOptions) { o.name = name }
+}
+
+// WithBuffer sets how many events may wait for the subscriber. Values below
+// one keep DefaultBuffer.
+func WithBuffer(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		if n > 0 {
+			o.buffer = n
+		}
+	}
+}
+
+// WithPolicy sets what happens when the buffer is full. The default is
+// DropNewest.
+func WithPolicy(p Policy) SubscribeOption {
+	return func(o *subscribeOptions) { o.policy = p }
+}
+
+// Subscription receives the events of one topic published after it was
+// created. Close it when done.
+type Subscription[T any] struct {
+	bus    *Bus
+	topic  string
+	id     uint64
+	name   string
+	policy Policy
+
+	// mu serializes deliveries with Close, so that nothing is sent on a
+	// closed channel.
+	mu      sync.Mutex
+	ch      chan T
+	closed  bool
+	dropped uint64
+}
+
+// Subscribe subscribes to topic on b.
+func Subscribe[T any](b *Bus, topic Topic[T], opts ...SubscribeOption) *Subscription[T] {
+	o := subscribeOptions{name: "unnamed", buffer: DefaultBuffer, policy: DropNewest}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := &Subscription[T]{
+		bus:    b,
+		topic:  topic.name,
+		name:   o.name,
+		policy: o.policy,
+		ch:     make(chan T, o.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s.id = b.next
+	b.next++
+	if b.subs[topic.name] == nil {
+		b.subs[topic.name] = map[uint64]subscriber{}
+	}
+	b.subs[topic.name][s.id] = s
+	metrics.EventSubscribers.WithLabelValues(topic.name).Inc()
+	return s
+}
+
+// C returns the channel the events are received on. It is closed when the
+// subscription is closed, including by the Disconnect policy.
+func (s *Subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+// Dropped returns how many events the subscription has dropped because its
+// buffer was full.
+func (s *Subscription[T]) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close unsubscribes and closes the channel. Events still buffered can be
+// received until the channel is drained. Close may be called more than once.
+func (s *Subscription[T]) Close() {
+	s.bus.remove(s.topic, s.id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+func (s *Subscription[T]) deliver(ev any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	v := ev.(T)
+	select {
+	case s.ch <- v:
+		return false
+	default:
+	}
+
+	s.dropped++
+	metrics.EventsDroppedTotal.WithLabelValues(s.topic, s.name).Inc()
+	switch s.policy {
+	case Disconnect:
+		slog.Warn("event subscriber disconnected, its buffer is full", "topic", s.topic, "subscriber", s.name, "buffer", cap(s.ch))
+		s.closed = true
+		close(s.ch)
+		return true
+	case DropOldest:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- v:
+		default:
+		}
+	}
+	if s.dropped == 1 || s.dropped%100 == 0 {
+		slog.Warn("event dropped, subscriber is slow", "topic", s.topic, "subscriber", s.name, "policy", s.policy.String(), "dropped", s.dropped)
+	}
+	return false
+}
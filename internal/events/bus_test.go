@@ -0,0 +1,154 @@
+package events
+
+import (
+	"sync"
+	"testing"
+
+	"go-github/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	numbers = NewTopic[int]("test.numbers")
+	words   = NewTopic[string]("test.words")
+)
+
+// drain closes s and returns the events it still holds.
+func drain[T any](s *Subscription[T]) []T {
+	s.Close()
+	var out []T
+	for ev := range s.C() {
+		out = append(out, ev)
+	}
+	return out
+}
+
+func TestPublish(t *testing.T) {
+	b := New()
+	first := Subscribe(b, numbers)
+	second := Subscribe(b, numbers)
+	other := Subscribe(b, words)
+
+	Publish(b, numbers, 1)
+	Publish(b, numbers, 2)
+	Publish(b, words, "three")
+
+	assert.Equal(t, []int{1, 2}, drain(first))
+	assert.Equal(t, []int{1, 2}, drain(second))
+	assert.Equal(t, []string{"three"}, drain(other))
+}
+
+func TestSubscription_Close(t *testing.T) {
+	b := New()
+	before := testutil.ToFloat64(metrics.EventSubscribers.WithLabelValues(numbers.Name()))
+	s := Subscribe(b, numbers)
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.EventSubscribers.WithLabelValues(numbers.Name())))
+
+	Publish(b, numbers, 1)
+	s.Close()
+	s.Close()
+	Publish(b, numbers, 2)
+
+	assert.Equal(t, []int{1}, drain(s), "buffered events survive Close; later ones are not delivered")
+	assert.Equal(t, before, testutil.ToFloat64(metrics.EventSubscribers.WithLabelValues(numbers.Name())))
+}
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		want   []int
+	}{
+		{DropNewest, []int{1, 2}},
+		{DropOldest, []int{3, 4}},
+		{Disconnect, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			b := New()
+			name := "test-" + tt.policy.String()
+			s := Subscribe(b, numbers, WithName(name), WithBuffer(2), WithPolicy(tt.policy))
+			for i := 1; i <= 4; i++ {
+				Publish(b, numbers, i)
+			}
+
+			var got []int
+			for len(got) < 2 {
+				got = append(got, <-s.C())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Positive(t, testutil.ToFloat64(metrics.EventsDroppedTotal.WithLabelValues(numbers.Name(), name)))
+
+			if tt.policy == Disconnect {
+				_, open := <-s.C()
+				assert.False(t, open, "a disconnected subscription is closed")
+				assert.Equal(t, uint64(1), s.Dropped(), "nothing is dropped after the disconnect")
+				b.mu.RLock()
+				assert.Empty(t, b.subs[numbers.Name()])
+				b.mu.RUnlock()
+				return
+			}
+			assert.Equal(t, uint64(2), s.Dropped())
+			s.Close()
+		})
+	}
+}
+
+func TestWithBuffer(t *testing.T) {
+	b := New()
+	assert.Equal(t, DefaultBuffer, cap(Subscribe(b, numbers, WithBuffer(0)).C()))
+	assert.Equal(t, 3, cap(Subscribe(b, numbers, WithBuffer(3)).C()))
+}
+
+func TestNewTopic(t *testing.T) {
+	assert.Equal(t, numbers, NewTopic[int]("test.numbers"), "declaring a topic again returns it")
+	assert.PanicsWithValue(t, `events: topic "test.numbers" declared with int and string`, func() {
+		NewTopic[string]("test.numbers")
+	})
+}
+
+func TestDefault(t *testing.T) {
+	b := New()
+	SetDefault(b)
+	t.Cleanup(func() { SetDefault(nil) })
+	assert.Same(t, b, Default())
+
+	SetDefault(nil)
+	require.NotNil(t, Default())
+	assert.NotSame(t, b, Default())
+}
+
+// TestConcurrent runs publishers, subscribers and Close together under the
+// race detector.
+func TestConcurrent(t *testing.T) {
+	b := New()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				Publish(b, numbers, i)
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				s := Subscribe(b, numbers, WithBuffer(4), WithPolicy(Disconnect))
+				for range 2 {
+					select {
+					case <-s.C():
+					default:
+					}
+				}
+				s.Close()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAreasHandler godoc
+// @Summary List areas
+// @ID listAreas
+// @Description Returns a page of the areas of the home, ordered by ID unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.AreaListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas [get]
+func ListAreasHandler(c *gin.Context) {
+	page, p, next, ok := paginate(c, homeassistant.ListAreas(), "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.AreaListResponse{Areas: page, NextCursor: next})
+}
+
+// CreateAreaHandler godoc
+// @Summary Create an area
+// @ID createArea
+// @Description Creates an area and moves the listed devices into it. Without an id, the area's ID is derived from its name, as Home Assistant does.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param area body models.AreaRequest true "Area to create"
+// @Success 201 {object} models.Area
+// @Header 201 {string} Location "URL of the new area"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas [post]
+func CreateAreaHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Area{}) {
+		return
+	}
+	var req models.AreaRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "area", homeassistant.ValidateArea(&req)) {
+		return
+	}
+	area, err := homeassistant.CreateArea(req)
+	if err != nil {
+		areaError(c, err, req.ID, "")
+		return
+	}
+	c.Header("Location", "/api/v1/homeassistant/areas/"+area.ID)
+	Respond(c, http.StatusCreated, area)
+}
+
+// GetAreaHandler godoc
+// @Summary Get an area
+// @ID getArea
+// @Description Returns an area with the IDs of its devices
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Area ID"
+// @Success 200 {object} models.Area
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id} [get]
+func GetAreaHandler(c *gin.Context) {
+	area, err := homeassistant.GetArea(c.Param("id"))
+	if err != nil {
+		areaError(c, err, c.Param("id"), "")
+		return
+	}
+	Respond(c, http.StatusOK, area)
+}
+
+// UpdateAreaHandler godoc
+// @Summary Replace an area
+// @ID updateArea
+// @Description Renames an area and replaces its devices. Devices that leave the area are in no area afterwards.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Area ID"
+// @Param area body models.AreaRequest true "New name and devices; id may be omitted"
+// @Success 200 {object} models.Area
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id} [put]
+func UpdateAreaHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Area{}) {
+		return
+	}
+	id := c.Param("id")
+	var req models.AreaRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "area", append(homeassistant.ValidateArea(&req), idMismatch(req.ID, id)...)) {
+		return
+	}
+	area, err := homeassistant.UpdateArea(id, req)
+	if err != nil {
+		areaError(c, err, id, "")
+		return
+	}
+	Respond(c, http.StatusOK, area)
+}
+
+// DeleteAreaHandler godoc
+// @Summary Delete an area
+// @ID deleteArea
+// @Description Deletes an area. Its devices are in no area afterwards.
+// @Tags homeassistant
+// @Param id path string true "Area ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id} [delete]
+func DeleteAreaHandler(c *gin.Context) {
+	if err := homeassistant.DeleteArea(c.Param("id")); err != nil {
+		areaError(c, err, c.Param("id"), "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListAreaDevicesHandler godoc
+// @Summary List the devices in an area
+// @ID listAreaDevices
+// @Description Returns a page of the devices in an area, ordered by ID unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param id path string true "Area ID"
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.DeviceListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id}/devices [get]
+func ListAreaDevicesHandler(c *gin.Context) {
+	devices, err := homeassistant.AreaDevices(c.Param("id"))
+	if err != nil {
+		areaError(c, err, c.Param("id"), "")
+		return
+	}
+	respondDevices(c, devices)
+}
+
+// AddAreaDeviceHandler godoc
+// @Summary Move a device into an area
+// @ID addAreaDevice
+// @Description Moves a device into an area, taking it out of the area it was in
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Area ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} models.Area
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id}/devices/{device_id} [put]
+func AddAreaDeviceHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Area{}) {
+		return
+	}
+	area, err := homeassistant.AddAreaDevice(c.Param("id"), c.Param("device_id"))
+	if err != nil {
+		areaError(c, err, c.Param("id"), c.Param("device_id"))
+		return
+	}
+	Respond(c, http.StatusOK, area)
+}
+
+// RemoveAreaDeviceHandler godoc
+// @Summary Take a device out of an area
+// @ID removeAreaDevice
+// @Description Takes a device out of an area, leaving it in no area. Removing a device that is not in the area succeeds.
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Area ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} models.Area
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id}/devices/{device_id} [delete]
+func RemoveAreaDeviceHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Area{}) {
+		return
+	}
+	area, err := homeassistant.RemoveAreaDevice(c.Param("id"), c.Param("device_id"))
+	if err != nil {
+		areaError(c, err, c.Param("id"), c.Param("device_id"))
+		return
+	}
+	Respond(c, http.StatusOK, area)
+}
+
+// ExecuteAreaCommandHandler godoc
+// @Summary Execute a command on the devices in an area
+// @ID executeAreaCommand
+// @Description Executes a command on every controllable device in an area, as a batch, and returns the result of each. Devices that are not controllable are left out. The response is 200 if every command succeeded and 207 otherwise.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param id path string true "Area ID"
+// @Param Idempotency-Key header string false "Unique key of this command; retries with the same key are not executed again"
+// @Param command body models.Command true "Command to execute"
+// @Success 200 {object} models.BatchResult
+// @Success 207 {object} models.BatchResult
+// @Header 200 {string} Idempotent-Replayed "true if the response is a replay of an earlier request with the same Idempotency-Key"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/areas/{id}/command [post]
+func ExecuteAreaCommandHandler(c *gin.Context) {
+	cmd, ok := bindTargetCommand(c)
+	if !ok {
+		return
+	}
+	result, err := homeassistant.ExecuteAreaCommand(c.Request.Context(), c.Param("id"), cmd)
+	if err != nil {
+		areaError(c, err, c.Param("id"), "")
+		return
+	}
+	respondBatch(c, result)
+}
+
+// areaError answers err from the area functions of package homeassistant.
+func areaError(c *gin.Context, err error, id, deviceID string) {
+	switch {
+	case errors.Is(err, homeassistant.ErrAreaNotFound):
+		NotFound(c, "area not found: "+id)
+	case errors.Is(err, homeassistant.ErrDeviceNotFound):
+		NotFound(c, "device not found: "+deviceID)
+	case errors.Is(err, homeassistant.ErrAreaExists):
+		JSONError(c, http.StatusConflict, "conflict", "area already exists: "+id,
+			models.FieldError{Pointer: "/id", Detail: "an area with this id already exists"})
+	default:
+		InternalError(c, err.Error())
+	}
+}
+
+// validateTarget answers 400 if fields, the problems of an area or group
+// request, is not empty, and reports whether the request is valid.
+func validateTarget(c *gin.Context, kind string, fields []models.FieldError) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	JSONError(c, http.StatusBadRequest, "validation_failed",
+		fmt.Sprintf("invalid %s: %s: %s", kind, fields[0].Pointer, fields[0].Detail), fields...)
+	return false
+}
+
+// idMismatch reports the id of a replacement body that names another
+// resource than the path.
+func idMismatch(bodyID, pathID string) []models.FieldError {
+	if bodyID == "" || bodyID == pathID {
+		return nil
+	}
+	return []models.FieldError{{Pointer: "/id", Detail: "id does not match the path"}}
+}
+
+// bindTargetCommand decodes and validates the command of a request to an area
+// or group. It answers the request and returns false if the command is
+// invalid.
+func bindTargetCommand(c *gin.Context) (models.Command, bool) {
+	var cmd models.Command
+	if !checkAcceptable(c, models.BatchResult{}) || !bindJSON(c, &cmd) {
+		return cmd, false
+	}
+	if err := cmd.Validate(); err != nil {
+		JSONError(c, http.StatusBadRequest, "bad_request", err.Error())
+		return cmd, false
+	}
+	return cmd, true
+}
+
+// respondDevices sends a page of devices.
+func respondDevices(c *gin.Context, devices []models.Device) {
+	page, p, next, ok := paginate(c, devices, "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.DeviceListResponse{Devices: page, Count: len(page), NextCursor: next})
+}
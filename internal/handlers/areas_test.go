@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// targetRouter routes the area and group endpoints like the server does.
+func targetRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	for _, kind := range []struct {
+		path                                      string
+		list, create, get, update, remove         gin.HandlerFunc
+		devices, addDevice, removeDevice, command gin.HandlerFunc
+	}{
+		{"/areas", ListAreasHandler, CreateAreaHandler, GetAreaHandler, UpdateAreaHandler, DeleteAreaHandler,
+			ListAreaDevicesHandler, AddAreaDeviceHandler, RemoveAreaDeviceHandler, ExecuteAreaCommandHandler},
+		{"/groups", ListGroupsHandler, CreateGroupHandler, GetGroupHandler, UpdateGroupHandler, DeleteGroupHandler,
+			ListGroupDevicesHandler, AddGroupDeviceHandler, RemoveGroupDeviceHandler, ExecuteGroupCommandHandler},
+	} {
+		r.GET(kind.path, kind.list)
+		r.POST(kind.path, kind.create)
+		r.GET(kind.path+"/:id", kind.get)
+		r.PUT(kind.path+"/:id", kind.update)
+		r.DELETE(kind.path+"/:id", kind.remove)
+		r.GET(kind.path+"/:id/devices", kind.devices)
+		r.PUT(kind.path+"/:id/devices/:device_id", kind.addDevice)
+		r.DELETE(kind.path+"/:id/devices/:device_id", kind.removeDevice)
+		r.POST(kind.path+"/:id/command", kind.command)
+	}
+	return r
+}
+
+func serveTarget(t *testing.T, r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, path, nil)
+	} else {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAreaHandlers(t *testing.T) {
+	r := targetRouter()
+	t.Cleanup(func() { _ = homeassistant.DeleteArea("handler_test_garage") })
+
+	w := serveTarget(t, r, http.MethodPost, "/areas", `{"name":"Handler Test Garage","device_ids":["readonly-sensor-001"]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v1/homeassistant/areas/handler_test_garage", w.Header().Get("Location"))
+	var area models.Area
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &area))
+	assert.Equal(t, []string{"readonly-sensor-001"}, area.DeviceIDs)
+
+	w = serveTarget(t, r, http.MethodPost, "/areas", `{"id":"handler_test_garage","name":"Garage"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serveTarget(t, r, http.MethodGet, "/areas/handler_test_garage/devices", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var devices models.DeviceListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &devices))
+	require.Equal(t, 1, devices.Count)
+	assert.Equal(t, "handler_test_garage", devices.Devices[0].AreaID)
+
+	w = serveTarget(t, r, http.MethodGet, "/areas?fields=id", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `{"id":"handler_test_garage"}`)
+
+	w = serveTarget(t, r, http.MethodPut, "/areas/handler_test_garage", `{"id":"elsewhere","name":"Garage","device_ids":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid area: /id: id does not match the path")
+
+	w = serveTarget(t, r, http.MethodPut, "/areas/handler_test_garage", `{"name":"Garage","device_ids":["device-001"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &area))
+	assert.Equal(t, []string{"device-001"}, area.DeviceIDs)
+
+	w = serveTarget(t, r, http.MethodPost, "/areas/handler_test_garage/command", `{"action":"turn_off","parameters":{}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result models.BatchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.Succeeded)
+
+	w = serveTarget(t, r, http.MethodPut, "/areas/handler_test_garage/devices/no-such-device", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "device not found: no-such-device")
+
+	// Put device-001 back where it started.
+	w = serveTarget(t, r, http.MethodPut, "/areas/living_room/devices/device-001", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serveTarget(t, r, http.MethodDelete, "/areas/handler_test_garage", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+	w = serveTarget(t, r, http.MethodGet, "/areas/handler_test_garage", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "area not found: handler_test_garage")
+}
+
+func TestAreaHandlers_Invalid(t *testing.T) {
+	r := targetRouter()
+	tests := []struct {
+		name, method, path, body string
+		code                     int
+		message                  string
+	}{
+		{"missing name", http.MethodPost, "/areas", `{"device_ids":[]}`, http.StatusBadRequest, "invalid area: /name: name is required"},
+		{"unknown device", http.MethodPost, "/groups", `{"name":"G","device_ids":["x"]}`, http.StatusBadRequest, "invalid group: /device_ids/0: device not found: x"},
+		{"malformed JSON", http.MethodPost, "/groups", `{`, http.StatusBadRequest, ""},
+		{"unknown area", http.MethodPost, "/areas/attic/command", `{"action":"turn_on","parameters":{}}`, http.StatusNotFound, "area not found: attic"},
+		{"invalid command", http.MethodPost, "/groups/any/command", `{"parameters":{}}`, http.StatusBadRequest, "action is required"},
+		{"unknown group", http.MethodDelete, "/groups/nope", "", http.StatusNotFound, "group not found: nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveTarget(t, r, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+			if tt.message != "" {
+				var resp models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.message, resp.Message)
+			}
+		})
+	}
+}
+
+func TestGroupHandlers(t *testing.T) {
+	r := targetRouter()
+	t.Cleanup(func() { _ = homeassistant.DeleteGroup("handler_test_all") })
+
+	w := serveTarget(t, r, http.MethodPost, "/groups", `{"id":"handler_test_all","name":"All","device_ids":["device-001","readonly-sensor-001"]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v1/homeassistant/groups/handler_test_all", w.Header().Get("Location"))
+
+	w = serveTarget(t, r, http.MethodDelete, "/groups/handler_test_all/devices/device-001", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var group models.Group
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &group))
+	assert.Equal(t, []string{"readonly-sensor-001"}, group.DeviceIDs)
+
+	// Only controllable members get the command; none are left.
+	w = serveTarget(t, r, http.MethodPost, "/groups/handler_test_all/command", `{"action":"turn_on","parameters":{}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"results":[]`)
+
+	w = serveTarget(t, r, http.MethodPut, "/groups/handler_test_all/devices/device-001", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	w = serveTarget(t, r, http.MethodGet, "/groups/handler_test_all/devices?sort=-id", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var devices models.DeviceListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &devices))
+	require.Len(t, devices.Devices, 2)
+	assert.Equal(t, "readonly-sensor-001", devices.Devices[0].ID)
+
+	w = serveTarget(t, r, http.MethodGet, "/groups", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"handler_test_all"`)
+}
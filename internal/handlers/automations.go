@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-github/internal/automations"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAutomationsHandler godoc
+// @Summary List automations
+// @ID listAutomations
+// @Description Returns a page of the automations, from the API and the automations file, ordered by ID unless sort is given
+// @Tags automations
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.AutomationListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/automations [get]
+func ListAutomationsHandler(c *gin.Context) {
+	page, p, next, ok := paginate(c, automations.Default().List(), "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.AutomationListResponse{Automations: page, NextCursor: next})
+}
+
+// CreateAutomationHandler godoc
+// @Summary Create an automation
+// @ID createAutomation
+// @Description Creates an automation: when one of its triggers fires (a device state change, a service going up or down, a firing alert or a cron expression) and all of its conditions on device state hold, its actions (device commands, webhooks and notifications) run in order. Without an id, the automation's ID is derived from its name.
+// @Tags automations
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param automation body models.AutomationRequest true "Automation to create"
+// @Success 201 {object} models.Automation
+// @Header 201 {string} Location "URL of the new automation"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/automations [post]
+func CreateAutomationHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Automation{}) {
+		return
+	}
+	var req models.AutomationRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "automation", automations.ValidateAutomation(&req)) {
+		return
+	}
+	a, err := automations.Default().Create(req)
+	if err != nil {
+		automationError(c, err, req.ID)
+		return
+	}
+	c.Header("Location", "/api/v1/automations/"+a.ID)
+	Respond(c, http.StatusCreated, a)
+}
+
+// GetAutomationHandler godoc
+// @Summary Get an automation
+// @ID getAutomation
+// @Description Returns an automation with the number and time of its runs
+// @Tags automations
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Automation ID"
+// @Success 200 {object} models.Automation
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/automations/{id} [get]
+func GetAutomationHandler(c *gin.Context) {
+	a, err := automations.Default().Get(c.Param("id"))
+	if err != nil {
+		automationError(c, err, c.Param("id"))
+		return
+	}
+	Respond(c, http.StatusOK, a)
+}
+
+// UpdateAutomationHandler godoc
+// @Summary Replace an automation
+// @ID updateAutomation
+// @Description Replaces the triggers, conditions and actions of an automation created through the API; its runs are kept. Automations from the automations file cannot be changed.
+// @Tags automations
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Automation ID"
+// @Param automation body models.AutomationRequest true "New automation; id may be omitted"
+// @Success 200 {object} models.Automation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/automations/{id} [put]
+func UpdateAutomationHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Automation{}) {
+		return
+	}
+	id := c.Param("id")
+	var req models.AutomationRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "automation", append(automations.ValidateAutomation(&req), idMismatch(req.ID, id)...)) {
+		return
+	}
+	a, err := automations.Default().Update(id, req)
+	if err != nil {
+		automationError(c, err, id)
+		return
+	}
+	Respond(c, http.StatusOK, a)
+}
+
+// DeleteAutomationHandler godoc
+// @Summary Delete an automation
+// @ID deleteAutomation
+// @Description Deletes an automation created through the API, with its runs. Automations from the automations file cannot be deleted.
+// @Tags automations
+// @Param id path string true "Automation ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /api/v1/automations/{id} [delete]
+func DeleteAutomationHandler(c *gin.Context) {
+	if err := automations.Default().Delete(c.Param("id")); err != nil {
+		automationError(c, err, c.Param("id"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListAutomationRunsHandler godoc
+// @Summary List the runs of an automation
+// @ID listAutomationRuns
+// @Description Returns a page of the latest runs of an automation, oldest first (run IDs are ordered by time) unless sort is given, each with the event that triggered it and the outcome of its conditions and actions. The last 50 runs are kept.
+// @Tags automations
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Automation ID"
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.AutomationRunListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/automations/{id}/runs [get]
+func ListAutomationRunsHandler(c *gin.Context) {
+	runs, err := automations.Default().Runs(c.Param("id"))
+	if err != nil {
+		automationError(c, err, c.Param("id"))
+		return
+	}
+	page, p, next, ok := paginate(c, runs, "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.AutomationRunListResponse{Runs: page, NextCursor: next})
+}
+
+// DryRunAutomationHandler godoc
+// @Summary Dry-run an automation
+// @ID dryRunAutomation
+// @Description Evaluates an automation as if event had happened: whether the event matches one of its triggers and whether its conditions hold on the current device state. No action runs and the run is not recorded. Without an event, only the conditions are evaluated.
+// @Tags automations
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Automation ID"
+// @Param request body models.DryRunRequest false "Event to evaluate the automation against"
+// @Success 200 {object} models.AutomationRun
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/automations/{id}/dry-run [post]
+func DryRunAutomationHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.AutomationRun{}) {
+		return
+	}
+	var req models.DryRunRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+	run, err := automations.Default().DryRun(c.Param("id"), req)
+	if err != nil {
+		automationError(c, err, c.Param("id"))
+		return
+	}
+	Respond(c, http.StatusOK, run)
+}
+
+// AutomationAlertsHandler godoc
+// @Summary Receive alerts
+// @ID receiveAutomationAlerts
+// @Description Receives an Alertmanager webhook notification. Each firing alert triggers the automations with an alert trigger on its alertname label whose labels it includes; resolved alerts are ignored. The automations run after the response.
+// @Tags automations
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param alerts body models.AlertWebhook true "Alertmanager webhook payload"
+// @Success 202 {object} models.AlertsResult
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/automations/alerts [post]
+func AutomationAlertsHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.AlertsResult{}) {
+		return
+	}
+	var req models.AlertWebhook
+	if !bindJSON(c, &req) {
+		return
+	}
+	Respond(c, http.StatusAccepted, automations.Default().PublishAlerts(req))
+}
+
+// automationError answers err from the methods of automations.Engine.
+func automationError(c *gin.Context, err error, id string) {
+	switch {
+	case errors.Is(err, automations.ErrNotFound):
+		NotFound(c, "automation not found: "+id)
+	case errors.Is(err, automations.ErrExists):
+		JSONError(c, http.StatusConflict, "conflict", "automation already exists: "+id,
+			models.FieldError{Pointer: "/id", Detail: "an automation with this id already exists"})
+	case errors.Is(err, automations.ErrReadOnly):
+		JSONError(c, http.StatusConflict, "conflict", "automation is defined in the automations file: "+id)
+	default:
+		InternalError(c, err.Error())
+	}
+}
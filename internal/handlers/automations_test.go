@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-github/internal/automations"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hotAttic is an automation that turns device-001 on when the attic is hot.
+const hotAttic = `{"name":"Hot attic",
+	"triggers":[{"type":"alert","alert_name":"HighTemperature","labels":{"room":"attic"}}],
+	"conditions":[{"device_id":"readonly-sensor-001","operator":"gt","value":70}],
+	"actions":[{"type":"command","device_id":"device-001","action":"turn_on"}]}`
+
+func automationRouter(t *testing.T) (*gin.Engine, *automations.Engine) {
+	t.Helper()
+	// An engine that is not running, so events are only queued.
+	e := automations.New()
+	automations.SetDefault(e)
+	t.Cleanup(func() { automations.SetDefault(nil) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/automations", ListAutomationsHandler)
+	r.POST("/automations", CreateAutomationHandler)
+	r.POST("/automations/alerts", AutomationAlertsHandler)
+	r.GET("/automations/:id", GetAutomationHandler)
+	r.PUT("/automations/:id", UpdateAutomationHandler)
+	r.DELETE("/automations/:id", DeleteAutomationHandler)
+	r.GET("/automations/:id/runs", ListAutomationRunsHandler)
+	r.POST("/automations/:id/dry-run", DryRunAutomationHandler)
+	return r, e
+}
+
+func TestAutomationHandlers(t *testing.T) {
+	r, e := automationRouter(t)
+
+	w := serveTarget(t, r, http.MethodPost, "/automations", hotAttic)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var a models.Automation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &a))
+	assert.Equal(t, "hot_attic", a.ID)
+	assert.Equal(t, "/api/v1/automations/hot_attic", w.Header().Get("Location"))
+	assert.True(t, a.Enabled)
+	assert.Equal(t, automations.SourceAPI, a.Source)
+
+	w = serveTarget(t, r, http.MethodPost, "/automations", hotAttic)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"hot_attic_2"`)
+
+	w = serveTarget(t, r, http.MethodGet, "/automations?fields=id", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"automations":[{"id":"hot_attic"},{"id":"hot_attic_2"}]}`, w.Body.String())
+
+	w = serveTarget(t, r, http.MethodPost, "/automations/hot_attic/dry-run", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var run models.AutomationRun
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+	assert.True(t, run.DryRun)
+	assert.Equal(t, automations.RunWouldRun, run.Status)
+
+	w = serveTarget(t, r, http.MethodPost, "/automations/hot_attic/dry-run", `{"event":{"type":"alert","alert_name":"HighTemperature","labels":{"room":"cellar"}}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"not_triggered"`)
+
+	w = serveTarget(t, r, http.MethodPost, "/automations/alerts", `{"version":"4","status":"firing","alerts":[
+		{"status":"firing","labels":{"alertname":"HighTemperature","room":"attic"},"startsAt":"2026-05-01T20:00:00Z"},
+		{"status":"resolved","labels":{"alertname":"HighTemperature","room":"cellar"}}]}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"firing":1}`, w.Body.String())
+
+	// The engine is not running; run one automation as it would.
+	e.Handle(context.Background(), models.AutomationEvent{Type: automations.TriggerAlert, AlertName: "HighTemperature", Labels: map[string]string{"room": "cellar"}})
+	w = serveTarget(t, r, http.MethodGet, "/automations/hot_attic/runs", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"runs":[]}`, w.Body.String(), "labels that do not match do not run the automation")
+
+	w = serveTarget(t, r, http.MethodPut, "/automations/hot_attic", `{"name":"Hot attic","enabled":false,
+		"triggers":[{"type":"alert","alert_name":"HighTemperature"}],
+		"actions":[{"type":"notify","message":"The attic is hot"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"enabled":false`)
+
+	w = serveTarget(t, r, http.MethodGet, "/automations/hot_attic", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"type":"notify"`)
+
+	w = serveTarget(t, r, http.MethodDelete, "/automations/hot_attic", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serveTarget(t, r, http.MethodGet, "/automations/hot_attic", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "automation not found: hot_attic")
+}
+
+func TestAutomationHandlers_Invalid(t *testing.T) {
+	r, _ := automationRouter(t)
+	require.Equal(t, http.StatusCreated, serveTarget(t, r, http.MethodPost, "/automations", hotAttic).Code)
+
+	tests := []struct {
+		name, method, path, body string
+		code                     int
+		message                  string
+	}{
+		{"no triggers", http.MethodPost, "/automations", `{"name":"Nothing","actions":[{"type":"notify","message":"m"}]}`, http.StatusBadRequest,
+			"invalid automation: /triggers: at least one trigger is required"},
+		{"bad condition", http.MethodPost, "/automations", `{"name":"Bad","triggers":[{"type":"alert","alert_name":"A"}],
+			"conditions":[{"device_id":"device-001","operator":"about","value":1}],"actions":[{"type":"notify","message":"m"}]}`, http.StatusBadRequest,
+			"invalid automation: /conditions/0/operator: operator must be eq, ne, gt, gte, lt or lte"},
+		{"exists", http.MethodPost, "/automations", `{"id":"hot_attic","name":"Hot attic","triggers":[{"type":"alert","alert_name":"A"}],
+			"actions":[{"type":"notify","message":"m"}]}`, http.StatusConflict, "automation already exists: hot_attic"},
+		{"id mismatch", http.MethodPut, "/automations/hot_attic", `{"id":"cold_attic","name":"Cold attic","triggers":[{"type":"alert","alert_name":"A"}],
+			"actions":[{"type":"notify","message":"m"}]}`, http.StatusBadRequest, "invalid automation: /id: id does not match the path"},
+		{"unknown automation", http.MethodGet, "/automations/nope/runs", "", http.StatusNotFound, "automation not found: nope"},
+		{"unknown dry run", http.MethodPost, "/automations/nope/dry-run", "", http.StatusNotFound, "automation not found: nope"},
+		{"bad alerts", http.MethodPost, "/automations/alerts", `{"alerts":"none"}`, http.StatusBadRequest,
+			"invalid request body: json: cannot unmarshal string into Go struct field AlertWebhook.alerts of type []models.Alert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveTarget(t, r, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
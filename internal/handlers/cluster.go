@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"go-github/internal/cluster"
+	"go-github/internal/models"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,21 +12,52 @@ import (
 
 // ListClusterServicesHandler godoc
 // @Summary List cluster services
-// @Description Returns a list of Kubernetes cluster services, optionally filtered by name
+// @ID listClusterServices
+// @Description Returns a page of Kubernetes cluster services, optionally filtered by name and namespace.
+// @Description Services are ordered by namespace and name unless sort is given; the next page is linked in the Link header.
 // @Tags cluster
-// @Produce json
+// @Produce json,application/yaml,text/csv,application/msgpack
 // @Param name query string false "Filter services by name (case-insensitive substring match)"
-// @Success 200 {array} cluster.ServiceInfo
+// @Param namespace query string false "Only return services in this namespace (exact match)"
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {array} models.ServiceInfo
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
 // @Router /api/v1/cluster/services [get]
 func ListClusterServicesHandler(c *gin.Context) {
 	nameFilter := c.Query("name")
 
 	svc := cluster.NewService()
-	services, err := svc.ListServices(nameFilter)
+	services, err := svc.ListServicesContext(c.Request.Context(), nameFilter)
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			GatewayTimeout(c, "cluster did not respond in time")
+			return
+		}
 		JSONError(c, http.StatusInternalServerError, "internal_error", err.Error())
 		return
 	}
 
-	JSONSuccess(c, http.StatusOK, services)
+	if namespace := c.Query("namespace"); namespace != "" {
+		filtered := make([]models.ServiceInfo, 0, len(services))
+		for _, svc := range services {
+			if svc.Namespace == namespace {
+				filtered = append(filtered, svc)
+			}
+		}
+		services = filtered
+	}
+
+	page, p, next, ok := paginate(c, services, "namespace", "name")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, page)
 }
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"go-github/internal/cluster"
+	"go-github/internal/models"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -54,3 +56,66 @@ func TestListClusterServicesHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestListClusterServicesHandler_DeadlineExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	c.Request = httptest.NewRequestWithContext(ctx, http.MethodGet, "/api/v1/cluster/services", nil)
+
+	ListClusterServicesHandler(c)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status code %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error != "gateway_timeout" {
+		t.Errorf("expected error gateway_timeout, got %q", resp.Error)
+	}
+}
+
+func TestListClusterServicesHandler_Namespace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		namespace string
+		wantCount int
+	}{
+		{name: "matching namespace", namespace: "default", wantCount: 3},
+		{name: "other namespace", namespace: "monitoring", wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/cluster/services?namespace="+tt.namespace, nil)
+
+			ListClusterServicesHandler(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+			var response []cluster.ServiceInfo
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(response) != tt.wantCount {
+				t.Errorf("expected %d services, got %d", tt.wantCount, len(response))
+			}
+			for _, svc := range response {
+				if svc.Namespace != tt.namespace {
+					t.Errorf("expected namespace %q, got %q", tt.namespace, svc.Namespace)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Data sends an already encoded success response, like gin's c.Data. A 200
+// response to a GET or HEAD request gets a strong ETag derived from the body,
+// so each format and each page has its own, and is answered with 304 Not
+// Modified and no body if the request's If-None-Match lists that ETag.
+func Data(c *gin.Context, code int, contentType string, body []byte) {
+	if code == http.StatusOK && c.Request != nil &&
+		(c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead) {
+		etag := entityTag(body)
+		c.Header("ETag", etag)
+		if noneMatch(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
+	c.Data(code, contentType, body)
+}
+
+// entityTag returns the strong entity tag of a response body: a quoted,
+// truncated SHA-256 of its bytes.
+func entityTag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// noneMatch reports whether an If-None-Match header matches etag: it is "*"
+// or lists etag. As RFC 9110 requires for If-None-Match, the comparison is
+// weak, so W/-prefixed tags match too.
+func noneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
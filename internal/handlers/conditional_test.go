@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conditional runs Respond for testServices on a request with the given
+// method, target and If-None-Match header.
+func conditional(method, target, ifNoneMatch string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, nil)
+	if ifNoneMatch != "" {
+		c.Request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	Respond(c, http.StatusOK, testServices)
+	return w
+}
+
+func TestRespond_ETag(t *testing.T) {
+	w := conditional(http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, etag, conditional(http.MethodGet, "/", "").Header().Get("ETag"), "ETags are stable")
+
+	yaml := conditional(http.MethodGet, "/?format=yaml", "").Header().Get("ETag")
+	assert.NotEqual(t, etag, yaml, "each representation has its own ETag")
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		ifNoneMatch string
+		want        int
+	}{
+		{"matching tag", http.MethodGet, "/", etag, http.StatusNotModified},
+		{"weak tag", http.MethodGet, "/", "W/" + etag, http.StatusNotModified},
+		{"one of a list", http.MethodGet, "/", `"other", ` + etag, http.StatusNotModified},
+		{"any", http.MethodGet, "/", "*", http.StatusNotModified},
+		{"stale tag", http.MethodGet, "/", `"other"`, http.StatusOK},
+		{"tag of another format", http.MethodGet, "/?format=yaml", etag, http.StatusOK},
+		{"not a GET", http.MethodPost, "/", etag, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := conditional(tt.method, tt.target, tt.ifNoneMatch)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+				assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			} else {
+				assert.NotEmpty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONError_DropsCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Header("Cache-Control", "private, no-cache")
+	NotFound(c, "gone")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("ETag"))
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go-github/internal/cluster"
+	"go-github/internal/events"
+	"go-github/internal/homeassistant"
+	"go-github/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamBuffer is how many events may wait for an event stream client
+// before it is disconnected.
+const eventStreamBuffer = 64
+
+// eventStreamKeepAlive is how often an idle event stream gets a comment line,
+// so that proxies do not close it.
+var eventStreamKeepAlive = 15 * time.Second
+
+// streamEvent is an event on its way to an event stream client. The zero
+// value means a subscription was disconnected for falling behind.
+type streamEvent struct {
+	topic string
+	data  any
+}
+
+// eventTopics subscribes out to each topic that GET /api/v1/events streams,
+// by name.
+var eventTopics = map[string]func(ctx context.Context, out chan<- streamEvent){
+	homeassistant.DeviceChanged.Name():   func(ctx context.Context, out chan<- streamEvent) { forward(ctx, homeassistant.DeviceChanged, out) },
+	homeassistant.CommandExecuted.Name(): func(ctx context.Context, out chan<- streamEvent) { forward(ctx, homeassistant.CommandExecuted, out) },
+	services.StatusChanged.Name():        func(ctx context.Context, out chan<- streamEvent) { forward(ctx, services.StatusChanged, out) },
+	cluster.ServiceChanged.Name():        func(ctx context.Context, out chan<- streamEvent) { forward(ctx, cluster.ServiceChanged, out) },
+}
+
+// forward subscribes to topic and passes its events to out until ctx is
+// cancelled. The subscription is disconnected rather than dropping events,
+// so that a client never silently misses one.
+func forward[T any](ctx context.Context, topic events.Topic[T], out chan<- streamEvent) {
+	sub := events.Subscribe(events.Default(), topic,
+		events.WithName("event_stream"),
+		events.WithBuffer(eventStreamBuffer),
+		events.WithPolicy(events.Disconnect),
+	)
+	go func() {
+		defer sub.Close()
+		for {
+			var ev streamEvent
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-sub.C():
+				if ok {
+					ev = streamEvent{topic: topic.Name(), data: data}
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- ev:
+			}
+			if ev.topic == "" {
+				return
+			}
+		}
+	}()
+}
+
+// StreamEventsHandler godoc
+// @Summary Stream events
+// @ID streamEvents
+// @Description Streams device changes, executed commands, service status changes and cluster service changes as server-sent events. The event name is the topic and the data is the event as JSON. A client that falls more than 64 events behind is disconnected rather than missing events; it should reconnect and re-read the state it depends on.
+// @Tags events
+// @Produce text/event-stream
+// @Param topics query string false "Comma-separated topics to stream: homeassistant.device_changed, homeassistant.command_executed, services.status_changed, cluster.service_changed (default: all)"
+// @Success 200 {string} string "A stream of server-sent events"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/events [get]
+func StreamEventsHandler(c *gin.Context) {
+	names := slices.Sorted(maps.Keys(eventTopics))
+	if topics := c.Query("topics"); topics != "" {
+		names = names[:0]
+		for _, name := range strings.Split(topics, ",") {
+			name = strings.TrimSpace(name)
+			if _, ok := eventTopics[name]; !ok {
+				BadRequest(c, fmt.Sprintf("unknown topic %q", name))
+				return
+			}
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		names = slices.Compact(names)
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	out := make(chan streamEvent)
+	for _, name := range names {
+		eventTopics[name](ctx, out)
+	}
+
+	// The stream outlives the write timeout of ordinary responses.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			_, _ = fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		case ev := <-out:
+			if ev.topic == "" {
+				return
+			}
+			data, err := json.Marshal(ev.data)
+			if err != nil {
+				continue
+			}
+			_, _ = fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", ev.topic, data)
+		}
+		c.Writer.Flush()
+	}
+}
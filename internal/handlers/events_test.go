@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/homeassistant"
+	"go-github/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openEventStream serves StreamEventsHandler on a fresh bus and returns the
+// bus and a reader of the stream opened with query.
+func openEventStream(t *testing.T, query string) (*events.Bus, *bufio.Reader) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+
+	r := gin.New()
+	r.GET("/api/v1/events", StreamEventsHandler)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/events"+query, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return bus, bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the next event from the stream, skipping comments.
+func readEvent(t *testing.T, r *bufio.Reader) (name, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && name != "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestStreamEventsHandler(t *testing.T) {
+	bus, stream := openEventStream(t, "")
+
+	events.Publish(bus, homeassistant.DeviceChanged, homeassistant.DeviceChange{
+		DeviceID: "device-001",
+		OldState: "off",
+		NewState: "on",
+	})
+	name, data := readEvent(t, stream)
+	assert.Equal(t, "homeassistant.device_changed", name)
+	assert.Contains(t, data, `"device_id":"device-001"`)
+	assert.Contains(t, data, `"new_state":"on"`)
+}
+
+func TestStreamEventsHandler_Topics(t *testing.T) {
+	bus, stream := openEventStream(t, "?topics=services.status_changed")
+
+	events.Publish(bus, homeassistant.DeviceChanged, homeassistant.DeviceChange{DeviceID: "device-001"})
+	events.Publish(bus, services.StatusChanged, services.StatusChange{})
+	name, _ := readEvent(t, stream)
+	assert.Equal(t, "services.status_changed", name)
+}
+
+func TestForward_Disconnect(t *testing.T) {
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	out := make(chan streamEvent)
+	forward(ctx, homeassistant.DeviceChanged, out)
+	// Nothing reads out while the subscription's buffer overflows.
+	for range eventStreamBuffer + 2 {
+		events.Publish(bus, homeassistant.DeviceChanged, homeassistant.DeviceChange{DeviceID: "device-001"})
+	}
+
+	var got int
+	for {
+		select {
+		case ev := <-out:
+			if ev.topic == "" {
+				assert.Positive(t, got)
+				return
+			}
+			got++
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription was not disconnected after falling behind")
+		}
+	}
+}
+
+func TestStreamEventsHandler_UnknownTopic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/events?topics=homeassistant.device_changed,nope", nil)
+
+	StreamEventsHandler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown topic \"nope\"`)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postBatch runs ExecuteBatchHandler with body.
+func postBatch(t *testing.T, body string, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/homeassistant/commands:batch", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	ExecuteBatchHandler(c)
+	return w
+}
+
+func TestExecuteBatchHandler(t *testing.T) {
+	w := postBatch(t, `{"commands":[
+		{"device_id":"device-001","action":"turn_off","parameters":{}},
+		{"device_id":"device-001","action":"turn_on","parameters":{"brightness":20}}
+	]}`, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result models.BatchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Succeeded)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "turn_on", result.Results[1].Action)
+
+	w = postBatch(t, `{"commands":[
+		{"device_id":"device-001","action":"turn_off","parameters":{}},
+		{"device_id":"readonly-sensor-001","action":"turn_off","parameters":{}}
+	]}`, "")
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, http.StatusMethodNotAllowed, result.Results[1].Status)
+}
+
+func TestExecuteBatchHandler_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		code    int
+		message string
+	}{
+		{"malformed JSON", `{"commands":`, http.StatusBadRequest, ""},
+		{"no commands", `{"commands":[]}`, http.StatusBadRequest, "invalid batch: /commands: at least one command is required"},
+		{"missing device", `{"commands":[{"action":"turn_on","parameters":{}}]}`, http.StatusBadRequest, "invalid batch: /commands/0/device_id: device_id is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postBatch(t, tt.body, "")
+			assert.Equal(t, tt.code, w.Code)
+			if tt.message != "" {
+				var resp models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "validation_failed", resp.Error)
+				assert.Equal(t, tt.message, resp.Message)
+			}
+		})
+	}
+
+	w := postBatch(t, `{"commands":[{"device_id":"","action":"","parameters":{}}]}`, "application/json, "+models.ProblemContentType)
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 2)
+	assert.Equal(t, "/commands/0/device_id", problem.Errors[0].Pointer)
+	assert.Equal(t, "/commands/0/action", problem.Errors[1].Pointer)
+}
+
+func TestExecuteBatchHandler_CSV(t *testing.T) {
+	w := postBatch(t, `{"commands":[{"device_id":"no-such-device","action":"turn_on","parameters":{}}]}`, "text/csv")
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "index,device_id,action,status"), lines[0])
+	assert.Contains(t, lines[1], "no-such-device,turn_on,404")
+}
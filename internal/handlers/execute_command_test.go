@@ -2,12 +2,18 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"go-github/internal/models"
+
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExecuteCommandHandler(t *testing.T) {
@@ -99,3 +105,46 @@ func TestExecuteCommandHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteCommandHandler_BodyTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body := `{"action":"turn_on","parameters":{"note":"` + strings.Repeat("x", 256) + `"}}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/homeassistant/devices/device-001/command", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Body = http.MaxBytesReader(w, c.Request.Body, 64)
+	c.Params = gin.Params{{Key: "id", Value: "device-001"}}
+
+	ExecuteCommandHandler(c)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "payload_too_large", resp.Error)
+	assert.Equal(t, "request body exceeds 64 bytes", resp.Message)
+}
+
+func TestExecuteCommandHandler_DeadlineExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	body := `{"action":"turn_on","parameters":{}}`
+	c.Request = httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/v1/homeassistant/devices/device-001/command", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "device-001"}}
+
+	ExecuteCommandHandler(c)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "gateway_timeout", resp.Error)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.Code)
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListGroupsHandler godoc
+// @Summary List groups
+// @ID listGroups
+// @Description Returns a page of the device groups, ordered by ID unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.GroupListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups [get]
+func ListGroupsHandler(c *gin.Context) {
+	page, p, next, ok := paginate(c, homeassistant.ListGroups(), "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.GroupListResponse{Groups: page, NextCursor: next})
+}
+
+// CreateGroupHandler godoc
+// @Summary Create a group
+// @ID createGroup
+// @Description Creates a group of devices. Without an id, the group's ID is derived from its name.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param group body models.GroupRequest true "Group to create"
+// @Success 201 {object} models.Group
+// @Header 201 {string} Location "URL of the new group"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups [post]
+func CreateGroupHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Group{}) {
+		return
+	}
+	var req models.GroupRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "group", homeassistant.ValidateGroup(&req)) {
+		return
+	}
+	group, err := homeassistant.CreateGroup(req)
+	if err != nil {
+		groupError(c, err, req.ID, "")
+		return
+	}
+	c.Header("Location", "/api/v1/homeassistant/groups/"+group.ID)
+	Respond(c, http.StatusCreated, group)
+}
+
+// GetGroupHandler godoc
+// @Summary Get a group
+// @ID getGroup
+// @Description Returns a group with the IDs of its devices
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Group ID"
+// @Success 200 {object} models.Group
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id} [get]
+func GetGroupHandler(c *gin.Context) {
+	group, err := homeassistant.GetGroup(c.Param("id"))
+	if err != nil {
+		groupError(c, err, c.Param("id"), "")
+		return
+	}
+	Respond(c, http.StatusOK, group)
+}
+
+// UpdateGroupHandler godoc
+// @Summary Replace a group
+// @ID updateGroup
+// @Description Renames a group and replaces its devices
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Group ID"
+// @Param group body models.GroupRequest true "New name and devices; id may be omitted"
+// @Success 200 {object} models.Group
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id} [put]
+func UpdateGroupHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Group{}) {
+		return
+	}
+	id := c.Param("id")
+	var req models.GroupRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "group", append(homeassistant.ValidateGroup(&req), idMismatch(req.ID, id)...)) {
+		return
+	}
+	group, err := homeassistant.UpdateGroup(id, req)
+	if err != nil {
+		groupError(c, err, id, "")
+		return
+	}
+	Respond(c, http.StatusOK, group)
+}
+
+// DeleteGroupHandler godoc
+// @Summary Delete a group
+// @ID deleteGroup
+// @Description Deletes a group. Its devices are not affected.
+// @Tags homeassistant
+// @Param id path string true "Group ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id} [delete]
+func DeleteGroupHandler(c *gin.Context) {
+	if err := homeassistant.DeleteGroup(c.Param("id")); err != nil {
+		groupError(c, err, c.Param("id"), "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroupDevicesHandler godoc
+// @Summary List the devices in a group
+// @ID listGroupDevices
+// @Description Returns a page of the devices in a group, ordered by ID unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param id path string true "Group ID"
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.DeviceListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id}/devices [get]
+func ListGroupDevicesHandler(c *gin.Context) {
+	devices, err := homeassistant.GroupDevices(c.Param("id"))
+	if err != nil {
+		groupError(c, err, c.Param("id"), "")
+		return
+	}
+	respondDevices(c, devices)
+}
+
+// AddGroupDeviceHandler godoc
+// @Summary Add a device to a group
+// @ID addGroupDevice
+// @Description Adds a device to a group. Adding a device that is already in the group succeeds.
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Group ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} models.Group
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id}/devices/{device_id} [put]
+func AddGroupDeviceHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Group{}) {
+		return
+	}
+	group, err := homeassistant.AddGroupDevice(c.Param("id"), c.Param("device_id"))
+	if err != nil {
+		groupError(c, err, c.Param("id"), c.Param("device_id"))
+		return
+	}
+	Respond(c, http.StatusOK, group)
+}
+
+// RemoveGroupDeviceHandler godoc
+// @Summary Remove a device from a group
+// @ID removeGroupDevice
+// @Description Removes a device from a group. Removing a device that is not in the group succeeds.
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Group ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} models.Group
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id}/devices/{device_id} [delete]
+func RemoveGroupDeviceHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Group{}) {
+		return
+	}
+	group, err := homeassistant.RemoveGroupDevice(c.Param("id"), c.Param("device_id"))
+	if err != nil {
+		groupError(c, err, c.Param("id"), c.Param("device_id"))
+		return
+	}
+	Respond(c, http.StatusOK, group)
+}
+
+// ExecuteGroupCommandHandler godoc
+// @Summary Execute a command on the devices in a group
+// @ID executeGroupCommand
+// @Description Executes a command on every controllable device in a group, as a batch, and returns the result of each. Devices that are not controllable are left out. The response is 200 if every command succeeded and 207 otherwise.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param id path string true "Group ID"
+// @Param Idempotency-Key header string false "Unique key of this command; retries with the same key are not executed again"
+// @Param command body models.Command true "Command to execute"
+// @Success 200 {object} models.BatchResult
+// @Success 207 {object} models.BatchResult
+// @Header 200 {string} Idempotent-Replayed "true if the response is a replay of an earlier request with the same Idempotency-Key"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/groups/{id}/command [post]
+func ExecuteGroupCommandHandler(c *gin.Context) {
+	cmd, ok := bindTargetCommand(c)
+	if !ok {
+		return
+	}
+	result, err := homeassistant.ExecuteGroupCommand(c.Request.Context(), c.Param("id"), cmd)
+	if err != nil {
+		groupError(c, err, c.Param("id"), "")
+		return
+	}
+	respondBatch(c, result)
+}
+
+// groupError answers err from the group functions of package homeassistant.
+func groupError(c *gin.Context, err error, id, deviceID string) {
+	switch {
+	case errors.Is(err, homeassistant.ErrGroupNotFound):
+		NotFound(c, "group not found: "+id)
+	case errors.Is(err, homeassistant.ErrDeviceNotFound):
+		NotFound(c, "device not found: "+deviceID)
+	case errors.Is(err, homeassistant.ErrGroupExists):
+		JSONError(c, http.StatusConflict, "conflict", "group already exists: "+id,
+			models.FieldError{Pointer: "/id", Detail: "a group with this id already exists"})
+	default:
+		InternalError(c, err.Error())
+	}
+}
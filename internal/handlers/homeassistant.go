@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"go-github/internal/homeassistant"
 	"go-github/internal/models"
 	"net/http"
@@ -11,10 +13,7 @@ import (
 )
 
 // DeviceListResponse represents a response containing a list of devices
-type DeviceListResponse struct {
-	Devices []models.Device `json:"devices"`
-	Count   int             `json:"count"`
-}
+type DeviceListResponse = models.DeviceListResponse
 
 // deviceListResponsePool is a global sync.Pool for DeviceListResponse objects
 // This reduces memory allocations by reusing response objects
@@ -36,13 +35,29 @@ func putResponseInPool(resp *DeviceListResponse) {
 	// Reset the response state before returning to pool
 	resp.Devices = resp.Devices[:0] // Clear slice but keep capacity
 	resp.Count = 0
+	resp.NextCursor = ""
 	deviceListResponsePool.Put(resp)
 }
 
-// DeviceListHandler handles GET requests for the device list
-// It uses sync.Pool to reduce memory allocations
+// DeviceListHandler godoc
+// @Summary List devices
+// @ID listDevices
+// @Description Returns a page of HomeAssistant devices, ordered by ID unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.DeviceListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/devices [get]
 func DeviceListHandler(c *gin.Context) {
-	// Get a response object from the pool
+	// Get a response object from the pool to reduce memory allocations
 	resp := getResponseFromPool()
 	defer putResponseInPool(resp)
 
@@ -50,31 +65,72 @@ func DeviceListHandler(c *gin.Context) {
 	for _, d := range devices {
 		resp.Devices = append(resp.Devices, *d)
 	}
+	page, p, next, ok := paginate(c, resp.Devices, "id")
+	if !ok {
+		return
+	}
+	// Move the page to the front so the pooled slice keeps its capacity.
+	resp.Devices = resp.Devices[:copy(resp.Devices, page)]
 
 	resp.Count = len(resp.Devices)
-	JSONSuccess(c, http.StatusOK, resp)
+	resp.NextCursor = next
+	respondList(c, p, next, resp)
+}
+
+// GetDeviceHandler godoc
+// @Summary Get a device
+// @ID getDevice
+// @Description Returns a single HomeAssistant device with its state and attributes
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Device ID"
+// @Success 200 {object} models.Device
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/devices/{id} [get]
+func GetDeviceHandler(c *gin.Context) {
+	deviceID := c.Param("id")
+
+	device, ok := homeassistant.GetDevice(deviceID)
+	if !ok {
+		NotFound(c, "device not found: "+deviceID)
+		return
+	}
+	Respond(c, http.StatusOK, device)
 }
 
 // ExecuteCommandHandler godoc
 // @Summary Execute a device command
-// @Description Execute a control command on a HomeAssistant device
+// @ID executeCommand
+// @Description Execute a control command on a HomeAssistant device. With an Idempotency-Key, retries with the same key and body get the first response again instead of repeating the command.
 // @Tags homeassistant
 // @Accept json
-// @Produce json
+// @Produce json,application/yaml,application/msgpack
 // @Param id path string true "Device ID"
-// @Param command body homeassistant.Command true "Command to execute"
-// @Success 200 {object} map[string]interface{}
+// @Param Idempotency-Key header string false "Unique key of this command; retries with the same key are not executed again"
+// @Param command body models.Command true "Command to execute"
+// @Success 200 {object} models.CommandResult
+// @Header 200 {string} Idempotent-Replayed "true if the response is a replay of an earlier request with the same Idempotency-Key"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 405 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
 // @Router /api/v1/homeassistant/devices/{id}/command [post]
 func ExecuteCommandHandler(c *gin.Context) {
 	deviceID := c.Param("id")
+	if !checkAcceptable(c, models.CommandResult{}) {
+		return
+	}
 
 	// Parse command from request body
-	var cmd homeassistant.Command
-	if err := c.ShouldBindJSON(&cmd); err != nil {
-		JSONError(c, http.StatusBadRequest, "bad_request", "invalid request body: "+err.Error())
+	var cmd models.Command
+	if !bindJSON(c, &cmd) {
 		return
 	}
 
@@ -85,13 +141,15 @@ func ExecuteCommandHandler(c *gin.Context) {
 	}
 
 	// Execute command via homeassistant package
-	result, err := homeassistant.ExecuteCommand(deviceID, cmd)
+	result, err := homeassistant.ExecuteCommandContext(c.Request.Context(), deviceID, cmd)
 	if err != nil {
 		switch {
 		case errors.Is(err, homeassistant.ErrDeviceNotFound):
 			JSONError(c, http.StatusNotFound, "not_found", "device not found: "+deviceID)
 		case errors.Is(err, homeassistant.ErrDeviceNotControllable):
 			JSONError(c, http.StatusMethodNotAllowed, "method_not_allowed", "device is not controllable: "+deviceID)
+		case errors.Is(err, context.DeadlineExceeded):
+			GatewayTimeout(c, "device did not respond in time: "+deviceID)
 		default:
 			JSONError(c, http.StatusInternalServerError, "internal_error", err.Error())
 		}
@@ -99,9 +157,68 @@ func ExecuteCommandHandler(c *gin.Context) {
 	}
 
 	// Command executed successfully
-	JSONSuccess(c, http.StatusOK, gin.H{
-		"status":    result.Status,
-		"device_id": result.DeviceID,
-		"action":    result.Action,
-	})
+	Respond(c, http.StatusOK, result)
+}
+
+// ExecuteBatchHandler godoc
+// @Summary Execute device commands in a batch
+// @ID executeBatch
+// @Description Executes up to 100 device commands concurrently and returns the result of each, with the status code it would have got on its own. The response is 200 if every command succeeded and 207 otherwise. In atomic mode the first failure skips the commands that have not started (424) and restores the devices of the ones that succeeded to the state and attributes they had before, where an action sets that state.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param Idempotency-Key header string false "Unique key of this batch; retries with the same key are not executed again"
+// @Param batch body models.BatchRequest true "Commands to execute"
+// @Success 200 {object} models.BatchResult
+// @Success 207 {object} models.BatchResult
+// @Header 200 {string} Idempotent-Replayed "true if the response is a replay of an earlier request with the same Idempotency-Key"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /api/v1/homeassistant/commands:batch [post]
+func ExecuteBatchHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.BatchResult{}) {
+		return
+	}
+
+	var req models.BatchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if fields := homeassistant.ValidateBatch(&req); len(fields) > 0 {
+		JSONError(c, http.StatusBadRequest, "validation_failed",
+			fmt.Sprintf("invalid batch: %s: %s", fields[0].Pointer, fields[0].Detail), fields...)
+		return
+	}
+
+	respondBatch(c, homeassistant.ExecuteBatch(c.Request.Context(), req))
+}
+
+// respondBatch sends the result of a batch: 200 if every command succeeded,
+// 207 Multi-Status otherwise.
+func respondBatch(c *gin.Context, result models.BatchResult) {
+	code := http.StatusOK
+	if result.Failed > 0 || result.Skipped > 0 {
+		code = http.StatusMultiStatus
+	}
+	Respond(c, code, result)
+}
+
+// bindJSON decodes the JSON request body into v. It answers 413 for a body
+// over the size limit and 400 for any other decoding error, and returns false
+// in both cases.
+func bindJSON(c *gin.Context, v interface{}) bool {
+	if err := c.ShouldBindJSON(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			JSONError(c, http.StatusRequestEntityTooLarge, "payload_too_large",
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return false
+		}
+		JSONError(c, http.StatusBadRequest, "bad_request", "invalid request body: "+err.Error())
+		return false
+	}
+	return true
 }
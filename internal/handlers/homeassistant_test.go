@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"go-github/internal/models"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDeviceListHandler(t *testing.T) {
@@ -86,3 +88,56 @@ func TestPoolReuse(t *testing.T) {
 	// Cleanup
 	putResponseInPool(resp2)
 }
+
+func TestDeviceListHandler_SortedByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/homeassistant/devices", nil)
+
+	DeviceListHandler(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.DeviceListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, len(resp.Devices), resp.Count)
+	for i := 1; i < len(resp.Devices); i++ {
+		assert.Less(t, resp.Devices[i-1].ID, resp.Devices[i].ID)
+	}
+}
+
+func TestGetDeviceHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		deviceID       string
+		expectedStatus int
+	}{
+		{name: "returns the device", deviceID: "device-001", expectedStatus: http.StatusOK},
+		{name: "returns 404 for unknown device", deviceID: "unknown-device", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/homeassistant/devices/"+tt.deviceID, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.deviceID}}
+
+			GetDeviceHandler(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				var resp models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "not_found", resp.Error)
+				return
+			}
+			var device models.Device
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &device))
+			assert.Equal(t, tt.deviceID, device.ID)
+		})
+	}
+}
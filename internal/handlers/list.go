@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"go-github/internal/listing"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginate applies the list parameters of the request (limit, cursor, sort
+// and fields) to items, which it sorts in place, and returns the selected
+// page with the cursor of the next one. key names the fields that identify
+// an item. Invalid parameters are answered with 400 and ok is false.
+func paginate[T any](c *gin.Context, items []T, key ...string) (page []T, p listing.Params, next string, ok bool) {
+	var query url.Values
+	if c.Request != nil {
+		query = c.Request.URL.Query()
+	}
+	p, err := listing.ParseParams(query)
+	if err == nil {
+		page, next, err = listing.Paginate(items, p, key...)
+	}
+	if err != nil {
+		listError(c, err)
+		return nil, p, "", false
+	}
+	return page, p, next, true
+}
+
+// respondList sends data, a page returned by paginate or a response wrapping
+// one, restricted to the fields p asks for. If there is a next page, its URL
+// is sent in a Link header.
+func respondList(c *gin.Context, p listing.Params, next string, data interface{}) {
+	if next != "" && c.Request != nil {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set("cursor", next)
+		u.RawQuery = query.Encode()
+		c.Header("Link", "<"+u.RequestURI()+`>; rel="next"`)
+	}
+	if len(p.Fields) > 0 {
+		projected, err := listing.Project(data, p.Fields)
+		if err != nil {
+			listError(c, err)
+			return
+		}
+		data = projected
+	}
+	Respond(c, http.StatusOK, data)
+}
+
+func listError(c *gin.Context, err error) {
+	var invalid *listing.ParamError
+	if errors.As(err, &invalid) {
+		JSONError(c, http.StatusBadRequest, "validation_failed", err.Error(),
+			models.FieldError{Parameter: invalid.Param, Detail: invalid.Message})
+		return
+	}
+	InternalError(c, "Failed to list items")
+}
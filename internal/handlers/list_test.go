@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// get runs handler for a GET request to target.
+func get(t *testing.T, handler gin.HandlerFunc, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	handler(c)
+	return w
+}
+
+func serviceNames(services []models.Service) []string {
+	names := make([]string, len(services))
+	for i, s := range services {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func TestListServicesHandler_Pagination(t *testing.T) {
+	var pages [][]string
+	target := "/api/v1/services?limit=2&sort=-type,name"
+	for target != "" {
+		w := get(t, ListServicesHandler, target)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp models.ServicesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		pages = append(pages, serviceNames(resp.Services))
+
+		target = ""
+		if resp.NextCursor != "" {
+			link := w.Header().Get("Link")
+			require.True(t, strings.HasSuffix(link, `>; rel="next"`), link)
+			target = strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+
+			u, err := url.Parse(target)
+			require.NoError(t, err)
+			assert.Equal(t, "/api/v1/services", u.Path)
+			assert.Equal(t, "2", u.Query().Get("limit"))
+			assert.Equal(t, resp.NextCursor, u.Query().Get("cursor"))
+		} else {
+			assert.Empty(t, w.Header().Get("Link"))
+		}
+	}
+	assert.Equal(t, [][]string{
+		{"grafana", "prometheus"},
+		{"node-exporter", "homeassistant"},
+		{"alertmanager"},
+	}, pages)
+}
+
+func TestListServicesHandler_DefaultOrder(t *testing.T) {
+	w := get(t, ListServicesHandler, "/api/v1/services")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.ServicesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"alertmanager", "grafana", "homeassistant", "node-exporter", "prometheus"}, serviceNames(resp.Services))
+	assert.Empty(t, resp.NextCursor)
+}
+
+func TestListHandlers_Fields(t *testing.T) {
+	w := get(t, DeviceListHandler, "/api/v1/homeassistant/devices?limit=1&fields=id,state")
+	require.Equal(t, http.StatusOK, w.Code)
+	var devices map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &devices))
+	assert.EqualValues(t, 1, devices["count"])
+	assert.NotEmpty(t, devices["next_cursor"])
+	require.Len(t, devices["devices"], 1)
+	assert.Equal(t, []string{"id", "state"}, keys(devices["devices"].([]interface{})[0]))
+
+	w = get(t, ListClusterServicesHandler, "/api/v1/cluster/services?fields=name&format=csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "name\n"), w.Body.String())
+}
+
+func keys(v interface{}) []string {
+	var out []string
+	for k := range v.(map[string]interface{}) {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestListHandlers_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		target  string
+		param   string
+		message string
+	}{
+		{"limit", ListServicesHandler, "/?limit=0", "limit", `invalid limit: "0" is not a number between 1 and 1000`},
+		{"unknown sort field", DeviceListHandler, "/?sort=colour", "sort", `invalid sort: unknown field "colour"`},
+		{"unsortable field", DeviceListHandler, "/?sort=attributes", "sort", `invalid sort: cannot sort by "attributes"`},
+		{"unknown fields entry", ListClusterServicesHandler, "/?fields=name,colour", "fields", `invalid fields: unknown field "colour"`},
+		{"malformed cursor", ListServicesHandler, "/?cursor=nope", "cursor", "invalid cursor: malformed cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(t, tt.handler, tt.target)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "validation_failed", resp.Error)
+			assert.Equal(t, tt.message, resp.Message)
+
+			w = httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+			c.Request.Header.Set("Accept", models.ProblemContentType)
+			tt.handler(c)
+			var problem models.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.Len(t, problem.Errors, 1)
+			assert.Equal(t, tt.param, problem.Errors[0].Parameter)
+		})
+	}
+}
+
+func TestListServicesHandler_CursorForAnotherSort(t *testing.T) {
+	w := get(t, ListServicesHandler, "/?limit=1")
+	var resp models.ServicesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.NextCursor)
+
+	w = get(t, ListServicesHandler, "/?limit=1&sort=-name&cursor="+resp.NextCursor)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `cursor was issued for sort \"name\", not \"-name\"`)
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-github/internal/listing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+	"github.com/ugorji/go/codec"
+)
+
+// Media types Respond offers besides application/json.
+const (
+	MIMEYAML    = "application/yaml"
+	MIMECSV     = "text/csv"
+	MIMEMsgPack = "application/msgpack"
+)
+
+// responseFormat is a representation Respond can produce.
+type responseFormat struct {
+	// name selects the format in ?format=.
+	name      string
+	mediaType string
+	// aliases are other media types that select the format in Accept.
+	aliases []string
+	// tabular formats are only offered for lists (see listing.Rows).
+	tabular bool
+}
+
+// formats lists the response formats in order of preference: JSON wins ties
+// and is sent when the request has no Accept header.
+var formats = []responseFormat{
+	{name: "json", mediaType: gin.MIMEJSON},
+	{name: "yaml", mediaType: MIMEYAML, aliases: []string{"application/x-yaml", "text/yaml"}},
+	{name: "csv", mediaType: MIMECSV, tabular: true},
+	{name: "msgpack", mediaType: MIMEMsgPack, aliases: []string{"application/x-msgpack"}},
+}
+
+var msgpackHandle codec.MsgpackHandle
+
+// Respond sends data in the format the client asked for: the ?format=
+// query parameter (json, yaml, csv or msgpack) if present, otherwise the
+// best match for the Accept header. CSV is only offered for lists, such as
+// a slice of structs or a response wrapping one. Requests for a format that
+// is not offered are answered with 406.
+//
+// Field names follow the json struct tags in every format. GET responses
+// carry an ETag and honour If-None-Match (see Data).
+func Respond(c *gin.Context, code int, data interface{}) {
+	c.Header("Vary", "Accept")
+	rows, tabular := listing.Rows(data)
+	f, err := negotiate(c, tabular)
+	if err != nil {
+		JSONError(c, http.StatusNotAcceptable, "not_acceptable", err.Error())
+		return
+	}
+
+	var body []byte
+	switch f.name {
+	case "json":
+		JSONSuccess(c, code, data)
+		return
+	case "yaml":
+		body, err = yaml.Marshal(data)
+	case "csv":
+		body, err = writeCSV(rows)
+	case "msgpack":
+		err = codec.NewEncoderBytes(&body, &msgpackHandle).Encode(data)
+	}
+	if err != nil {
+		InternalError(c, "Failed to encode response")
+		return
+	}
+	contentType := f.mediaType
+	if strings.HasPrefix(contentType, "text/") || contentType == MIMEYAML {
+		contentType += "; charset=utf-8"
+	}
+	Data(c, code, contentType, body)
+}
+
+// checkAcceptable answers 406 and returns false if no format of a response
+// like sample is acceptable. Handlers with side effects call it before doing
+// any work, since Respond only negotiates once the work is done.
+func checkAcceptable(c *gin.Context, sample interface{}) bool {
+	_, tabular := listing.Rows(sample)
+	if _, err := negotiate(c, tabular); err != nil {
+		c.Header("Vary", "Accept")
+		JSONError(c, http.StatusNotAcceptable, "not_acceptable", err.Error())
+		return false
+	}
+	return true
+}
+
+// negotiate picks the response format for the request.
+func negotiate(c *gin.Context, tabular bool) (responseFormat, error) {
+	if c.Request == nil {
+		return formats[0], nil
+	}
+	if name, ok := c.GetQuery("format"); ok {
+		for _, f := range formats {
+			if f.name != name {
+				continue
+			}
+			if f.tabular && !tabular {
+				return responseFormat{}, fmt.Errorf("format %q is only available for lists", name)
+			}
+			return f, nil
+		}
+		return responseFormat{}, fmt.Errorf("unsupported format %q; use json, yaml, csv or msgpack", name)
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return formats[0], nil
+	}
+	ranges := parseAccept(accept)
+	best, bestQ := -1, 0.0
+	var offered []string
+	for i, f := range formats {
+		if f.tabular && !tabular {
+			continue
+		}
+		offered = append(offered, f.mediaType)
+		q, _ := quality(ranges, f.mediaType)
+		for _, alias := range f.aliases {
+			// Aliases only count when named, so that "text/*" means CSV.
+			if aq, s := quality(ranges, alias); s == 2 && aq > q {
+				q = aq
+			}
+		}
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return responseFormat{}, fmt.Errorf("cannot produce any of %q; available: %s", accept, strings.Join(offered, ", "))
+	}
+	return formats[best], nil
+}
+
+// acceptRange is one media range of an Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses an Accept header, skipping malformed ranges.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, r := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// quality returns the q-value ranges give mediaType, taken from the most
+// specific matching range, and that range's specificity: 2 for an exact
+// match, 1 for "type/*", 0 for "*/*" and -1 if nothing matches.
+func quality(ranges []acceptRange, mediaType string) (float64, int) {
+	q, specificity := 0.0, -1
+	typ, _, _ := strings.Cut(mediaType, "/")
+	for _, r := range ranges {
+		s := -1
+		switch r.mediaType {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q, specificity
+}
+
+// writeCSV renders rows with a header of the json field names. Scalars are
+// written as text and times in RFC 3339; nested values such as maps and
+// lists are written as JSON.
+func writeCSV(rows reflect.Value) ([]byte, error) {
+	rowType := rows.Type().Elem()
+	if rowType.Kind() == reflect.Pointer {
+		rowType = rowType.Elem()
+	}
+	var header []string
+	var fields []int
+	for i := 0; i < rowType.NumField(); i++ {
+		f := rowType.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(fields))
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		if row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+		for j, field := range fields {
+			cell, err := csvCell(row.Field(field))
+			if err != nil {
+				return nil, err
+			}
+			record[j] = cell
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func csvCell(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return "", nil
+		}
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	b, err := jsonAPI.Marshal(v.Interface())
+	return string(b), err
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// respond runs Respond for data on a request to target with the given
+// Accept header.
+func respond(t *testing.T, target, accept string, data interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	Respond(c, http.StatusOK, data)
+	return w
+}
+
+var testServices = models.ServicesResponse{Services: []models.Service{
+	{Name: "grafana", Type: "monitoring", Status: "running", Endpoint: "http://grafana:3000"},
+	{Name: "home, sweet home", Type: "automation", Status: "stopped", Endpoint: ""},
+}}
+
+func TestRespond_Negotiation(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		contentType string
+	}{
+		{"no Accept header", "/", "", "application/json; charset=utf-8"},
+		{"any type", "/", "*/*", "application/json; charset=utf-8"},
+		{"json", "/", "application/json", "application/json; charset=utf-8"},
+		{"yaml", "/", "application/yaml", "application/yaml; charset=utf-8"},
+		{"yaml alias", "/", "application/x-yaml", "application/yaml; charset=utf-8"},
+		{"csv", "/", "text/csv", "text/csv; charset=utf-8"},
+		{"text wildcard picks csv", "/", "text/*", "text/csv; charset=utf-8"},
+		{"msgpack", "/", "application/msgpack", "application/msgpack"},
+		{"highest q wins", "/", "application/json;q=0.5, application/yaml;q=0.9", "application/yaml; charset=utf-8"},
+		{"ties go to json", "/", "application/yaml, application/json", "application/json; charset=utf-8"},
+		{"format overrides Accept", "/?format=csv", "application/json", "text/csv; charset=utf-8"},
+		{"format msgpack", "/?format=msgpack", "", "application/msgpack"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := respond(t, tt.target, tt.accept, testServices)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestRespond_NotAcceptable(t *testing.T) {
+	device := models.Device{ID: "light-1"}
+	tests := []struct {
+		name    string
+		target  string
+		accept  string
+		data    interface{}
+		message string
+	}{
+		{"unsupported type", "/", "application/xml", testServices,
+			`cannot produce any of "application/xml"; available: application/json, application/yaml, text/csv, application/msgpack`},
+		{"csv for a single object", "/", "text/csv", device,
+			`cannot produce any of "text/csv"; available: application/json, application/yaml, application/msgpack`},
+		{"json refused", "/", "application/json;q=0", testServices,
+			`cannot produce any of "application/json;q=0"; available: application/json, application/yaml, text/csv, application/msgpack`},
+		{"unknown format", "/?format=xml", "", testServices, `unsupported format "xml"; use json, yaml, csv or msgpack`},
+		{"csv format for a single object", "/?format=csv", "", device, `format "csv" is only available for lists`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := respond(t, tt.target, tt.accept, tt.data)
+			assert.Equal(t, http.StatusNotAcceptable, w.Code)
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "not_acceptable", resp.Error)
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
+
+func TestRespond_Encodings(t *testing.T) {
+	updated := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	devices := &models.DeviceListResponse{
+		Devices: []models.Device{{
+			ID:           "light-1",
+			Name:         "Lamp",
+			Type:         "light",
+			State:        "on",
+			Attributes:   map[string]interface{}{"brightness": 80},
+			LastUpdated:  updated,
+			Controllable: true,
+			AreaID:       "living_room",
+		}},
+		Count: 1,
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		w := respond(t, "/?format=csv", "", devices)
+		assert.Equal(t, "id,name,type,state,attributes,last_updated,controllable,area_id\n"+
+			`light-1,Lamp,light,on,"{""brightness"":80}",2026-03-14T10:00:00Z,true,living_room`+"\n", w.Body.String())
+
+		w = respond(t, "/?format=csv", "", testServices)
+		assert.Equal(t, "name,type,status,endpoint\n"+
+			"grafana,monitoring,running,http://grafana:3000\n"+
+			`"home, sweet home",automation,stopped,`+"\n", w.Body.String())
+
+		w = respond(t, "/?format=csv", "", []models.ServiceInfo{{Name: "api", Namespace: "default", Status: "Running"}})
+		assert.Equal(t, "name,namespace,status,endpoints\napi,default,Running,\n", w.Body.String())
+	})
+
+	t.Run("yaml uses json field names", func(t *testing.T) {
+		w := respond(t, "/", "application/yaml", devices)
+		var got map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &got))
+		assert.EqualValues(t, 1, got["count"])
+		device := got["devices"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "light-1", device["id"])
+		assert.Contains(t, device, "last_updated")
+	})
+
+	t.Run("msgpack round trip", func(t *testing.T) {
+		w := respond(t, "/", "application/msgpack", devices)
+		var got models.DeviceListResponse
+		require.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&got))
+		assert.Equal(t, 1, got.Count)
+		assert.Equal(t, "light-1", got.Devices[0].ID)
+		assert.True(t, got.Devices[0].LastUpdated.Equal(updated))
+	})
+}
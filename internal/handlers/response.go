@@ -1,9 +1,12 @@
 package handlers
 
 import (
-	"go-github/internal/models"
+	"errors"
 	"net/http"
 
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+
 	"github.com/gin-gonic/gin"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -11,34 +14,72 @@ import (
 // jsonAPI is the jsoniter API instance configured for fastest performance
 var jsonAPI = jsoniter.ConfigFastest
 
-// JSONSuccess sends a successful JSON response using jsoniter for improved performance
+// JSONSuccess sends a successful JSON response using jsoniter for improved
+// performance. GET responses carry an ETag and honour If-None-Match.
 func JSONSuccess(c *gin.Context, code int, data interface{}) {
 	bytes, err := jsonAPI.Marshal(data)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "encoding_error",
-			Message: "Failed to encode response",
-			Code:    http.StatusInternalServerError,
-		})
+		InternalError(c, "Failed to encode response")
 		return
 	}
-	c.Data(code, "application/json; charset=utf-8", bytes)
+	Data(c, code, "application/json; charset=utf-8", bytes)
 }
 
-// JSONError sends an error JSON response using the ErrorResponse model with jsoniter
-func JSONError(c *gin.Context, code int, err string, message string) {
+// JSONError sends an error response. It is the single way errors leave the
+// API: handlers call it directly and middleware through abortWithError.
+//
+// Clients that prefer application/problem+json over application/json in
+// their Accept header get an RFC 9457 models.Problem, with the request ID and
+// any field-level errors; everyone else gets the models.ErrorResponse shape.
+// The ErrorResponse is also attached to c.Errors so the access log can report
+// the error reason. Errors are never cached: any Cache-Control set for the
+// route's successful responses is dropped.
+func JSONError(c *gin.Context, code int, err string, message string, fields ...models.FieldError) {
+	c.Writer.Header().Del("Cache-Control")
 	errorResponse := models.ErrorResponse{
 		Error:   err,
 		Message: message,
 		Code:    code,
 	}
-	bytes, marshalErr := jsonAPI.Marshal(errorResponse)
-	if marshalErr != nil {
+	_ = c.Error(errors.New(message)).SetMeta(errorResponse)
+	if c.Request == nil || !prefersProblem(c.GetHeader("Accept")) {
+		writeError(c, code, "application/json; charset=utf-8", errorResponse)
+		return
+	}
+	writeError(c, code, models.ProblemContentType, models.Problem{
+		Type:      models.ProblemTypePrefix + err,
+		Title:     http.StatusText(code),
+		Status:    code,
+		Detail:    message,
+		Instance:  c.Request.URL.Path,
+		RequestID: requestid.FromContext(c.Request.Context()),
+		Errors:    fields,
+	})
+}
+
+func writeError(c *gin.Context, code int, contentType string, body any) {
+	bytes, err := jsonAPI.Marshal(body)
+	if err != nil {
 		// Fallback to gin's JSON if jsoniter fails
-		c.JSON(code, errorResponse)
+		c.Header("Content-Type", contentType)
+		c.JSON(code, body)
 		return
 	}
-	c.Data(code, "application/json; charset=utf-8", bytes)
+	c.Data(code, contentType, bytes)
+}
+
+// prefersProblem reports whether an Accept header ranks
+// application/problem+json at least as high as application/json. Only an
+// explicit application/problem+json counts, so "*/*" and a missing header
+// keep the ErrorResponse shape.
+func prefersProblem(accept string) bool {
+	ranges := parseAccept(accept)
+	problemQ, specificity := quality(ranges, models.ProblemContentType)
+	if specificity < 2 {
+		return false
+	}
+	jsonQ, _ := quality(ranges, gin.MIMEJSON)
+	return problemQ > 0 && problemQ >= jsonQ
 }
 
 // NotFound sends a 404 Not Found error response
@@ -55,3 +96,13 @@ func BadRequest(c *gin.Context, message string) {
 func InternalError(c *gin.Context, message string) {
 	JSONError(c, http.StatusInternalServerError, "internal_error", message)
 }
+
+// GatewayTimeout sends a 504 Gateway Timeout error response
+func GatewayTimeout(c *gin.Context, message string) {
+	JSONError(c, http.StatusGatewayTimeout, "gateway_timeout", message)
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable error response
+func ServiceUnavailable(c *gin.Context, message string) {
+	JSONError(c, http.StatusServiceUnavailable, "service_unavailable", message)
+}
@@ -3,11 +3,14 @@ package handlers
 import (
 	"encoding/json"
 	"go-github/internal/models"
+	"go-github/internal/requestid"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJSONSuccess(t *testing.T) {
@@ -219,3 +222,87 @@ func TestInternalError(t *testing.T) {
 		t.Errorf("expected code %d, got %d", http.StatusInternalServerError, response.Code)
 	}
 }
+
+func TestJSONError_AttachesErrorResponseToContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	JSONError(c, http.StatusNotFound, "not_found", "device not found: x")
+
+	require.Len(t, c.Errors, 1)
+	resp, ok := c.Errors.Last().Meta.(models.ErrorResponse)
+	require.True(t, ok, "the access log reads the reason from the error meta")
+	assert.Equal(t, "not_found", resp.Error)
+	assert.Equal(t, "device not found: x", resp.Message)
+}
+
+func TestJSONError_Problem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/things/1?verbose=true", nil)
+	c.Request.Header.Set("Accept", "application/problem+json, application/json")
+	c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), "req-123"))
+
+	JSONError(c, http.StatusBadRequest, "validation_failed", "invalid request body",
+		models.FieldError{Pointer: "/count", Detail: "expected integer, got string"},
+		models.FieldError{Parameter: "limit", Detail: "is required"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "tag:homelab-api,2026:problems/validation_failed",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "invalid request body",
+		"instance": "/api/v1/things/1",
+		"request_id": "req-123",
+		"errors": [
+			{"pointer": "/count", "detail": "expected integer, got string"},
+			{"parameter": "limit", "detail": "is required"}
+		]
+	}`, w.Body.String())
+
+	require.Len(t, c.Errors, 1)
+	assert.Equal(t, models.ErrorResponse{Error: "validation_failed", Message: "invalid request body", Code: 400}, c.Errors[0].Meta)
+}
+
+func TestJSONError_LegacyByDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/missing", nil)
+	c.Request.Header.Set("Accept", "*/*")
+
+	JSONError(c, http.StatusNotFound, "not_found", "gone", models.FieldError{Parameter: "id", Detail: "unknown"})
+
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"not_found","message":"gone","code":404}`, w.Body.String())
+}
+
+func TestPrefersProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/problem+json, application/json", true},
+		{"application/json, application/problem+json", true},
+		{"application/problem+json;q=0.5, application/json", false},
+		{"application/problem+json;q=0.5, application/*;q=0.4, */*", true},
+		{"application/problem+json;q=0", false},
+		{"application/problem+json;q=oops", false},
+		{"text/html, application/problem+json", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, prefersProblem(tt.accept))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListScenesHandler godoc
+// @Summary List scenes
+// @ID listScenes
+// @Description Returns a page of the scenes, ordered by ID unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.SceneListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/scenes [get]
+func ListScenesHandler(c *gin.Context) {
+	page, p, next, ok := paginate(c, homeassistant.ListScenes(), "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.SceneListResponse{Scenes: page, NextCursor: next})
+}
+
+// CreateSceneHandler godoc
+// @Summary Create a scene
+// @ID createScene
+// @Description Captures the current state and attributes of the listed devices as a scene. The devices must be controllable. Without an id, the scene's ID is derived from its name.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param scene body models.SceneRequest true "Scene to capture"
+// @Success 201 {object} models.Scene
+// @Header 201 {string} Location "URL of the new scene"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/scenes [post]
+func CreateSceneHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Scene{}) {
+		return
+	}
+	var req models.SceneRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "scene", homeassistant.ValidateScene(&req)) {
+		return
+	}
+	scene, err := homeassistant.CreateScene(req)
+	if err != nil {
+		sceneError(c, err, req.ID)
+		return
+	}
+	c.Header("Location", "/api/v1/scenes/"+scene.ID)
+	Respond(c, http.StatusCreated, scene)
+}
+
+// GetSceneHandler godoc
+// @Summary Get a scene
+// @ID getScene
+// @Description Returns a scene with the captured state of its devices
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Scene ID"
+// @Success 200 {object} models.Scene
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/scenes/{id} [get]
+func GetSceneHandler(c *gin.Context) {
+	scene, err := homeassistant.GetScene(c.Param("id"))
+	if err != nil {
+		sceneError(c, err, c.Param("id"))
+		return
+	}
+	Respond(c, http.StatusOK, scene)
+}
+
+// UpdateSceneHandler godoc
+// @Summary Recapture a scene
+// @ID updateScene
+// @Description Renames a scene and captures it again from the current state of the listed devices
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Scene ID"
+// @Param scene body models.SceneRequest true "New name and devices; id may be omitted"
+// @Success 200 {object} models.Scene
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /api/v1/scenes/{id} [put]
+func UpdateSceneHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Scene{}) {
+		return
+	}
+	id := c.Param("id")
+	var req models.SceneRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "scene", append(homeassistant.ValidateScene(&req), idMismatch(req.ID, id)...)) {
+		return
+	}
+	scene, err := homeassistant.UpdateScene(id, req)
+	if err != nil {
+		sceneError(c, err, id)
+		return
+	}
+	Respond(c, http.StatusOK, scene)
+}
+
+// DeleteSceneHandler godoc
+// @Summary Delete a scene
+// @ID deleteScene
+// @Description Deletes a scene. Its devices are not affected.
+// @Tags homeassistant
+// @Param id path string true "Scene ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/scenes/{id} [delete]
+func DeleteSceneHandler(c *gin.Context) {
+	if err := homeassistant.DeleteScene(c.Param("id")); err != nil {
+		sceneError(c, err, c.Param("id"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ActivateSceneHandler godoc
+// @Summary Activate a scene
+// @ID activateScene
+// @Description Restores the devices of a scene to their captured state. Each device that differs gets one command; devices that already match get none. The response is 200 if every device was restored or unchanged and 207 if any failed.
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param id path string true "Scene ID"
+// @Param Idempotency-Key header string false "Unique key of this activation; retries with the same key are not executed again"
+// @Success 200 {object} models.SceneActivation
+// @Success 207 {object} models.SceneActivation
+// @Header 200 {string} Idempotent-Replayed "true if the response is a replay of an earlier request with the same Idempotency-Key"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /api/v1/scenes/{id}/activate [post]
+func ActivateSceneHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.SceneActivation{}) {
+		return
+	}
+	result, err := homeassistant.ActivateScene(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		sceneError(c, err, c.Param("id"))
+		return
+	}
+	code := http.StatusOK
+	if result.Failed > 0 {
+		code = http.StatusMultiStatus
+	}
+	Respond(c, code, result)
+}
+
+// sceneError answers err from the scene functions of package homeassistant.
+func sceneError(c *gin.Context, err error, id string) {
+	switch {
+	case errors.Is(err, homeassistant.ErrSceneNotFound):
+		NotFound(c, "scene not found: "+id)
+	case errors.Is(err, homeassistant.ErrSceneExists):
+		JSONError(c, http.StatusConflict, "conflict", "scene already exists: "+id,
+			models.FieldError{Pointer: "/id", Detail: "a scene with this id already exists"})
+	default:
+		InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sceneRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/scenes", ListScenesHandler)
+	r.POST("/scenes", CreateSceneHandler)
+	r.GET("/scenes/:id", GetSceneHandler)
+	r.PUT("/scenes/:id", UpdateSceneHandler)
+	r.DELETE("/scenes/:id", DeleteSceneHandler)
+	r.POST("/scenes/:id/activate", ActivateSceneHandler)
+	return r
+}
+
+func TestSceneHandlers(t *testing.T) {
+	r := sceneRouter()
+	t.Cleanup(func() { _ = homeassistant.DeleteScene("handler_test_scene") })
+
+	w := serveTarget(t, r, http.MethodPost, "/scenes", `{"name":"Handler Test Scene","device_ids":["device-001"]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v1/scenes/handler_test_scene", w.Header().Get("Location"))
+	var scene models.Scene
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scene))
+	require.Len(t, scene.Devices, 1)
+	assert.Equal(t, "device-001", scene.Devices[0].DeviceID)
+
+	w = serveTarget(t, r, http.MethodPost, "/scenes", `{"id":"handler_test_scene","name":"Again","device_ids":["device-001"]}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serveTarget(t, r, http.MethodGet, "/scenes?fields=id", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `{"id":"handler_test_scene"}`)
+
+	w = serveTarget(t, r, http.MethodPut, "/scenes/handler_test_scene", `{"name":"Renamed","device_ids":["device-001"]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scene))
+	assert.Equal(t, "Renamed", scene.Name)
+
+	w = serveTarget(t, r, http.MethodPost, "/scenes/handler_test_scene/activate", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var activation models.SceneActivation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &activation))
+	assert.Equal(t, 1, activation.Unchanged)
+
+	w = serveTarget(t, r, http.MethodDelete, "/scenes/handler_test_scene", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serveTarget(t, r, http.MethodGet, "/scenes/handler_test_scene", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "scene not found: handler_test_scene")
+}
+
+func TestSceneHandlers_Invalid(t *testing.T) {
+	r := sceneRouter()
+	tests := []struct {
+		name, method, path, body string
+		code                     int
+		message                  string
+	}{
+		{"no devices", http.MethodPost, "/scenes", `{"name":"Empty"}`, http.StatusBadRequest, "invalid scene: /device_ids: at least one device is required"},
+		{"read-only device", http.MethodPost, "/scenes", `{"name":"S","device_ids":["readonly-sensor-001"]}`, http.StatusBadRequest,
+			"invalid scene: /device_ids/0: device is not controllable: readonly-sensor-001"},
+		{"id mismatch", http.MethodPut, "/scenes/a", `{"id":"b","name":"S","device_ids":["device-001"]}`, http.StatusBadRequest, "invalid scene: /id: id does not match the path"},
+		{"unknown scene", http.MethodPut, "/scenes/nope", `{"name":"S","device_ids":["device-001"]}`, http.StatusNotFound, "scene not found: nope"},
+		{"activate unknown scene", http.MethodPost, "/scenes/nope/activate", "", http.StatusNotFound, "scene not found: nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveTarget(t, r, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-github/internal/models"
+	"go-github/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSchedulesHandler godoc
+// @Summary List schedules
+// @ID listSchedules
+// @Description Returns a page of the scheduled commands, including completed one-time schedules, ordered by ID (creation order) unless sort is given
+// @Tags homeassistant
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
+// @Success 200 {object} models.ScheduleListResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/schedules [get]
+func ListSchedulesHandler(c *gin.Context) {
+	page, p, next, ok := paginate(c, scheduler.Default().List(), "id")
+	if !ok {
+		return
+	}
+	respondList(c, p, next, models.ScheduleListResponse{Schedules: page, NextCursor: next})
+}
+
+// CreateScheduleHandler godoc
+// @Summary Schedule a device command
+// @ID createSchedule
+// @Description Schedules a device command to run once at a time (at) or after a delay (delay), or repeatedly on a cron expression (cron) evaluated in timezone. Exactly one of at, delay and cron must be given. Runs go through the same path as POST /api/v1/devices/{id}/command and appear in the command history. A run that starts more than the configured grace period late, e.g. because the server was down, is skipped or run once according to missed_run_policy.
+// @Tags homeassistant
+// @Accept json
+// @Produce json,application/yaml,application/msgpack
+// @Param schedule body models.ScheduleRequest true "Command and when to run it"
+// @Param Idempotency-Key header string false "Unique key of this request; retries with the same key do not create another schedule"
+// @Success 201 {object} models.Schedule
+// @Header 201 {string} Location "URL of the new schedule"
+// @Header 201 {string} Idempotent-Replayed "true if the response is a replay of an earlier request with the same Idempotency-Key"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/schedules [post]
+func CreateScheduleHandler(c *gin.Context) {
+	if !checkAcceptable(c, models.Schedule{}) {
+		return
+	}
+	var req models.ScheduleRequest
+	if !bindJSON(c, &req) || !validateTarget(c, "schedule", scheduler.ValidateSchedule(&req)) {
+		return
+	}
+	sched, err := scheduler.Default().Create(req)
+	if err != nil {
+		scheduleError(c, err, "")
+		return
+	}
+	c.Header("Location", "/api/v1/schedules/"+sched.ID)
+	Respond(c, http.StatusCreated, sched)
+}
+
+// GetScheduleHandler godoc
+// @Summary Get a schedule
+// @ID getSchedule
+// @Description Returns a scheduled command with its next run and the outcome of its last run
+// @Tags homeassistant
+// @Produce json,application/yaml,application/msgpack
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} models.Schedule
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
+// @Router /api/v1/schedules/{id} [get]
+func GetScheduleHandler(c *gin.Context) {
+	sched, err := scheduler.Default().Get(c.Param("id"))
+	if err != nil {
+		scheduleError(c, err, c.Param("id"))
+		return
+	}
+	Respond(c, http.StatusOK, sched)
+}
+
+// CancelScheduleHandler godoc
+// @Summary Cancel a schedule
+// @ID cancelSchedule
+// @Description Cancels a schedule so that it does not run again. A run in progress is not interrupted. Completed schedules can be deleted the same way.
+// @Tags homeassistant
+// @Param id path string true "Schedule ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/schedules/{id} [delete]
+func CancelScheduleHandler(c *gin.Context) {
+	if _, err := scheduler.Default().Cancel(c.Param("id")); err != nil {
+		scheduleError(c, err, c.Param("id"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// scheduleError answers err from the methods of scheduler.Scheduler.
+func scheduleError(c *gin.Context, err error, id string) {
+	if errors.Is(err, scheduler.ErrNotFound) {
+		NotFound(c, "schedule not found: "+id)
+		return
+	}
+	InternalError(c, err.Error())
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-github/internal/models"
+	"go-github/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scheduleRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	// A scheduler that is not running, so nothing is executed.
+	scheduler.SetDefault(scheduler.New())
+	t.Cleanup(func() { scheduler.SetDefault(nil) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/schedules", ListSchedulesHandler)
+	r.POST("/schedules", CreateScheduleHandler)
+	r.GET("/schedules/:id", GetScheduleHandler)
+	r.DELETE("/schedules/:id", CancelScheduleHandler)
+	return r
+}
+
+func TestScheduleHandlers(t *testing.T) {
+	r := scheduleRouter(t)
+
+	w := serveTarget(t, r, http.MethodPost, "/schedules", `{"name":"Porch light off","device_id":"device-001","action":"turn_off","delay":"30m"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var sched models.Schedule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sched))
+	assert.Equal(t, "/api/v1/schedules/"+sched.ID, w.Header().Get("Location"))
+	assert.Equal(t, scheduler.KindOnce, sched.Kind)
+	assert.Equal(t, scheduler.StateScheduled, sched.State)
+	require.NotNil(t, sched.NextRun)
+
+	w = serveTarget(t, r, http.MethodPost, "/schedules", `{"device_id":"device-001","action":"turn_on","cron":"0 7 * * MON-FRI","timezone":"Europe/Berlin"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var cron models.Schedule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cron))
+	assert.Equal(t, scheduler.KindCron, cron.Kind)
+	assert.Equal(t, 7, cron.NextRun.Hour())
+
+	w = serveTarget(t, r, http.MethodGet, "/schedules?fields=id", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"schedules":[{"id":"`+sched.ID+`"},{"id":"`+cron.ID+`"}]}`, w.Body.String())
+
+	w = serveTarget(t, r, http.MethodGet, "/schedules/"+sched.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Porch light off"`)
+
+	w = serveTarget(t, r, http.MethodDelete, "/schedules/"+sched.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serveTarget(t, r, http.MethodGet, "/schedules/"+sched.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "schedule not found: "+sched.ID)
+}
+
+func TestScheduleHandlers_Invalid(t *testing.T) {
+	r := scheduleRouter(t)
+	tests := []struct {
+		name, method, path, body string
+		code                     int
+		message                  string
+	}{
+		{"no time", http.MethodPost, "/schedules", `{"device_id":"device-001","action":"turn_on"}`, http.StatusBadRequest,
+			"invalid schedule: /at: one of at, delay and cron is required"},
+		{"read-only device", http.MethodPost, "/schedules", `{"device_id":"readonly-sensor-001","action":"turn_on","delay":"5m"}`, http.StatusBadRequest,
+			"invalid schedule: /device_id: device is not controllable: readonly-sensor-001"},
+		{"bad cron", http.MethodPost, "/schedules", `{"device_id":"device-001","action":"turn_on","cron":"every day"}`, http.StatusBadRequest,
+			"invalid schedule: /cron: invalid cron expression: expected 5 fields (minute hour day-of-month month day-of-week), got 2"},
+		{"unknown schedule", http.MethodDelete, "/schedules/nope", "", http.StatusNotFound, "schedule not found: nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveTarget(t, r, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
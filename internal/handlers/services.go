@@ -3,22 +3,36 @@ package handlers
 import (
 	"go-github/internal/models"
 	"go-github/internal/services"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ListServicesHandler godoc
 // @Summary List available services
-// @Description Returns list of all services in the homelab
+// @ID listServices
+// @Description Returns a page of the services in the homelab, ordered by name unless sort is given
 // @Tags services
-// @Produce json
+// @Produce json,application/yaml,text/csv,application/msgpack
+// @Param limit query int false "Maximum number of items to return" minimum(1) maximum(1000) default(100)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Comma-separated fields to sort by; prefix a field with - for descending order"
+// @Param fields query string false "Comma-separated fields to include in each item"
 // @Success 200 {object} models.ServicesResponse
+// @Header 200 {string} ETag "Entity tag of the response; send it back in If-None-Match to get 304 Not Modified while it is unchanged"
+// @Header 200 {string} Cache-Control "Caching directives"
+// @Header 200 {string} Link "URL of the next page, with rel=\"next\""
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 406 {object} models.ErrorResponse
 // @Router /api/v1/services [get]
 func ListServicesHandler(c *gin.Context) {
+	page, p, next, ok := paginate(c, services.GetServices(), "name")
+	if !ok {
+		return
+	}
 	response := models.ServicesResponse{
-		Services: services.GetServices(),
+		Services:   page,
+		NextCursor: next,
 	}
 
-	JSONSuccess(c, http.StatusOK, response)
+	respondList(c, p, next, response)
 }
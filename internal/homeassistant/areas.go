@@ -0,0 +1,414 @@
+package homeassistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-github/internal/models"
+)
+
+// Areas and groups follow Home Assistant's registries: an area has a slug ID
+// derived from its name, as area_id is, and a device is in at most one area,
+// as with the device registry's area_id. They only exist for the mocked
+// device store: both are kept in memory in registry and are not read from or
+// written to Home Assistant's area and device registries.
+
+// Errors returned by the area and group functions.
+var (
+	ErrAreaNotFound  = errors.New("area not found")
+	ErrAreaExists    = errors.New("area already exists")
+	ErrGroupNotFound = errors.New("group not found")
+	ErrGroupExists   = errors.New("group already exists")
+)
+
+// slugPattern is the form of area and group IDs.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// ValidateArea checks r and returns every invalid field, located by JSON
+// pointer.
+func ValidateArea(r *models.AreaRequest) []models.FieldError {
+	return validateMembers(r.ID, r.Name, r.DeviceIDs)
+}
+
+// ValidateGroup checks r and returns every invalid field, located by JSON
+// pointer.
+func ValidateGroup(r *models.GroupRequest) []models.FieldError {
+	return validateMembers(r.ID, r.Name, r.DeviceIDs)
+}
+
+func validateMembers(id, name string, deviceIDs []string) []models.FieldError {
+	var errs []models.FieldError
+	if id != "" && !slugPattern.MatchString(id) {
+		errs = append(errs, models.FieldError{Pointer: "/id", Detail: "id must be lowercase letters and digits separated by underscores"})
+	}
+	switch {
+	case strings.TrimSpace(name) == "":
+		errs = append(errs, models.FieldError{Pointer: "/name", Detail: "name is required"})
+	case id == "" && Slugify(name) == "":
+		errs = append(errs, models.FieldError{Pointer: "/name", Detail: "name must contain a letter or digit"})
+	}
+	for i, deviceID := range deviceIDs {
+		if _, ok := mockDevices[deviceID]; !ok {
+			errs = append(errs, models.FieldError{Pointer: fmt.Sprintf("/device_ids/%d", i), Detail: "device not found: " + deviceID})
+		}
+	}
+	return errs
+}
+
+// Slugify returns the ID Home Assistant would give an area named name:
+// lowercase letters and digits, with every other run of characters replaced
+// by an underscore.
+func Slugify(name string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pending && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pending = false
+			b.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+	return b.String()
+}
+
+// membership holds the areas and groups and which devices are in them.
+type membership struct {
+	mu         sync.RWMutex
+	areas      map[string]string // area ID to name
+	deviceArea map[string]string // device ID to area ID
+	groups     map[string]*groupEntry
+}
+
+type groupEntry struct {
+	name    string
+	devices map[string]bool
+}
+
+var registry = newMembership()
+
+func newMembership() *membership {
+	return &membership{
+		areas:      map[string]string{"living_room": "Living Room"},
+		deviceArea: map[string]string{"device-001": "living_room"},
+		groups:     map[string]*groupEntry{},
+	}
+}
+
+// area returns the area id. m.mu must be held.
+func (m *membership) area(id string) models.Area {
+	a := models.Area{ID: id, Name: m.areas[id], DeviceIDs: []string{}}
+	for deviceID, areaID := range m.deviceArea {
+		if areaID == id {
+			a.DeviceIDs = append(a.DeviceIDs, deviceID)
+		}
+	}
+	slices.Sort(a.DeviceIDs)
+	return a
+}
+
+// group returns the group id. m.mu must be held.
+func (m *membership) group(id string) models.Group {
+	e := m.groups[id]
+	g := models.Group{ID: id, Name: e.name, DeviceIDs: []string{}}
+	for deviceID := range e.devices {
+		g.DeviceIDs = append(g.DeviceIDs, deviceID)
+	}
+	slices.Sort(g.DeviceIDs)
+	return g
+}
+
+// UniqueID returns id or, if id is empty, the slug of name followed by the
+// first suffix (_2, _3, ...) that taken does not report, if the slug itself
+// is taken.
+func UniqueID(id, name string, taken func(string) bool) string {
+	if id != "" {
+		return id
+	}
+	base := Slugify(name)
+	id = base
+	for n := 2; taken(id); n++ {
+		id = fmt.Sprintf("%s_%d", base, n)
+	}
+	return id
+}
+
+// ListAreas returns every area, ordered by ID.
+func ListAreas() []models.Area {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	out := make([]models.Area, 0, len(registry.areas))
+	for id := range registry.areas {
+		out = append(out, registry.area(id))
+	}
+	slices.SortFunc(out, func(a, b models.Area) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// GetArea returns the area id, or ErrAreaNotFound.
+func GetArea(id string) (models.Area, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if _, ok := registry.areas[id]; !ok {
+		return models.Area{}, ErrAreaNotFound
+	}
+	return registry.area(id), nil
+}
+
+// CreateArea adds the area described by r, which must be valid. It returns
+// ErrAreaExists if r names the ID of an existing area.
+func CreateArea(r models.AreaRequest) (models.Area, error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	id := UniqueID(r.ID, r.Name, func(id string) bool { _, ok := registry.areas[id]; return ok })
+	if _, ok := registry.areas[id]; ok {
+		return models.Area{}, ErrAreaExists
+	}
+	registry.areas[id] = strings.TrimSpace(r.Name)
+	for _, deviceID := range r.DeviceIDs {
+		registry.deviceArea[deviceID] = id
+	}
+	return registry.area(id), nil
+}
+
+// UpdateArea renames the area id and replaces its devices with those of r,
+// which must be valid; r.ID is ignored. Devices that leave the area are in no
+// area afterwards.
+func UpdateArea(id string, r models.AreaRequest) (models.Area, error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.areas[id]; !ok {
+		return models.Area{}, ErrAreaNotFound
+	}
+	registry.areas[id] = strings.TrimSpace(r.Name)
+	for deviceID, areaID := range registry.deviceArea {
+		if areaID == id {
+			delete(registry.deviceArea, deviceID)
+		}
+	}
+	for _, deviceID := range r.DeviceIDs {
+		registry.deviceArea[deviceID] = id
+	}
+	return registry.area(id), nil
+}
+
+// DeleteArea removes the area id; its devices are in no area afterwards.
+func DeleteArea(id string) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.areas[id]; !ok {
+		return ErrAreaNotFound
+	}
+	delete(registry.areas, id)
+	for deviceID, areaID := range registry.deviceArea {
+		if areaID == id {
+			delete(registry.deviceArea, deviceID)
+		}
+	}
+	return nil
+}
+
+// AddAreaDevice moves the device deviceID into the area id.
+func AddAreaDevice(id, deviceID string) (models.Area, error) {
+	if _, ok := mockDevices[deviceID]; !ok {
+		return models.Area{}, ErrDeviceNotFound
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.areas[id]; !ok {
+		return models.Area{}, ErrAreaNotFound
+	}
+	registry.deviceArea[deviceID] = id
+	return registry.area(id), nil
+}
+
+// RemoveAreaDevice takes the device deviceID out of the area id. Removing a
+// device that is not in the area is not an error.
+func RemoveAreaDevice(id, deviceID string) (models.Area, error) {
+	if _, ok := mockDevices[deviceID]; !ok {
+		return models.Area{}, ErrDeviceNotFound
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.areas[id]; !ok {
+		return models.Area{}, ErrAreaNotFound
+	}
+	if registry.deviceArea[deviceID] == id {
+		delete(registry.deviceArea, deviceID)
+	}
+	return registry.area(id), nil
+}
+
+// AreaDevices returns the devices in the area id, ordered by ID.
+func AreaDevices(id string) ([]models.Device, error) {
+	a, err := GetArea(id)
+	if err != nil {
+		return nil, err
+	}
+	return devicesByID(a.DeviceIDs), nil
+}
+
+// ListGroups returns every group, ordered by ID.
+func ListGroups() []models.Group {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	out := make([]models.Group, 0, len(registry.groups))
+	for id := range registry.groups {
+		out = append(out, registry.group(id))
+	}
+	slices.SortFunc(out, func(a, b models.Group) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// GetGroup returns the group id, or ErrGroupNotFound.
+func GetGroup(id string) (models.Group, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if _, ok := registry.groups[id]; !ok {
+		return models.Group{}, ErrGroupNotFound
+	}
+	return registry.group(id), nil
+}
+
+// CreateGroup adds the group described by r, which must be valid. It returns
+// ErrGroupExists if r names the ID of an existing group.
+func CreateGroup(r models.GroupRequest) (models.Group, error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	id := UniqueID(r.ID, r.Name, func(id string) bool { _, ok := registry.groups[id]; return ok })
+	if _, ok := registry.groups[id]; ok {
+		return models.Group{}, ErrGroupExists
+	}
+	registry.groups[id] = &groupEntry{name: strings.TrimSpace(r.Name), devices: deviceSet(r.DeviceIDs)}
+	return registry.group(id), nil
+}
+
+// UpdateGroup renames the group id and replaces its devices with those of r,
+// which must be valid; r.ID is ignored.
+func UpdateGroup(id string, r models.GroupRequest) (models.Group, error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	e, ok := registry.groups[id]
+	if !ok {
+		return models.Group{}, ErrGroupNotFound
+	}
+	e.name = strings.TrimSpace(r.Name)
+	e.devices = deviceSet(r.DeviceIDs)
+	return registry.group(id), nil
+}
+
+// DeleteGroup removes the group id. Its devices are not affected.
+func DeleteGroup(id string) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.groups[id]; !ok {
+		return ErrGroupNotFound
+	}
+	delete(registry.groups, id)
+	return nil
+}
+
+// AddGroupDevice adds the device deviceID to the group id.
+func AddGroupDevice(id, deviceID string) (models.Group, error) {
+	if _, ok := mockDevices[deviceID]; !ok {
+		return models.Group{}, ErrDeviceNotFound
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	e, ok := registry.groups[id]
+	if !ok {
+		return models.Group{}, ErrGroupNotFound
+	}
+	e.devices[deviceID] = true
+	return registry.group(id), nil
+}
+
+// RemoveGroupDevice takes the device deviceID out of the group id. Removing a
+// device that is not in the group is not an error.
+func RemoveGroupDevice(id, deviceID string) (models.Group, error) {
+	if _, ok := mockDevices[deviceID]; !ok {
+		return models.Group{}, ErrDeviceNotFound
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	e, ok := registry.groups[id]
+	if !ok {
+		return models.Group{}, ErrGroupNotFound
+	}
+	delete(e.devices, deviceID)
+	return registry.group(id), nil
+}
+
+// GroupDevices returns the devices in the group id, ordered by ID.
+func GroupDevices(id string) ([]models.Device, error) {
+	g, err := GetGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	return devicesByID(g.DeviceIDs), nil
+}
+
+// ExecuteAreaCommand sends cmd to every controllable device in the area id,
+// as a batch. Devices that are not controllable are left out.
+func ExecuteAreaCommand(ctx context.Context, id string, cmd models.Command) (models.BatchResult, error) {
+	a, err := GetArea(id)
+	if err != nil {
+		return models.BatchResult{}, err
+	}
+	return fanOut(ctx, a.DeviceIDs, cmd), nil
+}
+
+// ExecuteGroupCommand sends cmd to every controllable device in the group id,
+// as a batch. Devices that are not controllable are left out.
+func ExecuteGroupCommand(ctx context.Context, id string, cmd models.Command) (models.BatchResult, error) {
+	g, err := GetGroup(id)
+	if err != nil {
+		return models.BatchResult{}, err
+	}
+	return fanOut(ctx, g.DeviceIDs, cmd), nil
+}
+
+// fanOut runs cmd on the controllable devices among deviceIDs.
+func fanOut(ctx context.Context, deviceIDs []string, cmd models.Command) models.BatchResult {
+	var r models.BatchRequest
+	for _, deviceID := range deviceIDs {
+		if d, ok := mockDevices[deviceID]; ok && d.Controllable {
+			r.Commands = append(r.Commands, models.BatchCommand{DeviceID: deviceID, Action: cmd.Action, Parameters: cmd.Parameters})
+		}
+	}
+	return ExecuteBatch(ctx, r)
+}
+
+// devicesByID returns the devices with the given IDs, with their areas.
+func devicesByID(ids []string) []models.Device {
+	out := make([]models.Device, 0, len(ids))
+	for _, id := range ids {
+		if d, ok := GetDevice(id); ok {
+			out = append(out, *d)
+		}
+	}
+	return out
+}
+
+func deviceSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// deviceAreaID returns the area the device id is in, or "".
+func deviceAreaID(id string) string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.deviceArea[id]
+}
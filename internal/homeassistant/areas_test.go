@@ -0,0 +1,167 @@
+package homeassistant
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-github/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetRegistry restores the built-in areas and groups after the test.
+func resetRegistry(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() { registry = newMembership() })
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "living_room", Slugify("Living Room"))
+	assert.Equal(t, "kid_s_room_2", Slugify("  Kid's room #2 "))
+	assert.Equal(t, "", Slugify("!!!"))
+}
+
+func TestAreaRequest_Validate(t *testing.T) {
+	r := models.AreaRequest{ID: "Not A Slug", Name: " ", DeviceIDs: []string{"device-001", "no-such-device"}}
+	var pointers []string
+	for _, f := range ValidateArea(&r) {
+		pointers = append(pointers, f.Pointer)
+	}
+	assert.Equal(t, []string{"/id", "/name", "/device_ids/1"}, pointers)
+
+	r = models.AreaRequest{Name: "???"}
+	require.Len(t, ValidateArea(&r), 1)
+	assert.Equal(t, "name must contain a letter or digit", ValidateArea(&r)[0].Detail)
+
+	r = models.AreaRequest{ID: "kitchen", Name: "???"}
+	assert.Empty(t, ValidateArea(&r), "a name without a slug is fine with an explicit id")
+}
+
+func TestAreas(t *testing.T) {
+	resetRegistry(t)
+
+	kitchen, err := CreateArea(models.AreaRequest{Name: "Kitchen", DeviceIDs: []string{"device-001", "readonly-sensor-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "kitchen", kitchen.ID)
+	assert.Equal(t, []string{"device-001", "readonly-sensor-001"}, kitchen.DeviceIDs)
+
+	living, err := GetArea("living_room")
+	require.NoError(t, err)
+	assert.Empty(t, living.DeviceIDs, "device-001 moved to the kitchen")
+	device, _ := GetDevice("device-001")
+	assert.Equal(t, "kitchen", device.AreaID)
+
+	second, err := CreateArea(models.AreaRequest{Name: "kitchen!"})
+	require.NoError(t, err)
+	assert.Equal(t, "kitchen_2", second.ID, "derived IDs are made unique")
+	_, err = CreateArea(models.AreaRequest{ID: "kitchen", Name: "Kitchen"})
+	assert.ErrorIs(t, err, ErrAreaExists)
+
+	var ids []string
+	for _, a := range ListAreas() {
+		ids = append(ids, a.ID)
+	}
+	assert.Equal(t, []string{"kitchen", "kitchen_2", "living_room"}, ids)
+
+	kitchen, err = UpdateArea("kitchen", models.AreaRequest{Name: "Cuisine", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Cuisine", kitchen.Name)
+	sensor, _ := GetDevice("readonly-sensor-001")
+	assert.Empty(t, sensor.AreaID, "devices left out of the update leave the area")
+
+	_, err = AddAreaDevice("living_room", "device-001")
+	require.NoError(t, err)
+	devices, err := AreaDevices("living_room")
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "living_room", devices[0].AreaID)
+
+	living, err = RemoveAreaDevice("living_room", "device-001")
+	require.NoError(t, err)
+	assert.Empty(t, living.DeviceIDs)
+	_, err = RemoveAreaDevice("living_room", "device-001")
+	assert.NoError(t, err, "removing a device twice is not an error")
+	_, err = AddAreaDevice("living_room", "no-such-device")
+	assert.ErrorIs(t, err, ErrDeviceNotFound)
+	_, err = AddAreaDevice("attic", "device-001")
+	assert.ErrorIs(t, err, ErrAreaNotFound)
+
+	_, _ = AddAreaDevice("kitchen", "device-001")
+	require.NoError(t, DeleteArea("kitchen"))
+	device, _ = GetDevice("device-001")
+	assert.Empty(t, device.AreaID)
+	assert.ErrorIs(t, DeleteArea("kitchen"), ErrAreaNotFound)
+	_, err = AreaDevices("kitchen")
+	assert.ErrorIs(t, err, ErrAreaNotFound)
+}
+
+func TestGroups(t *testing.T) {
+	resetRegistry(t)
+
+	g, err := CreateGroup(models.GroupRequest{Name: "Everything", DeviceIDs: []string{"readonly-sensor-001", "device-001", "device-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "everything", g.ID)
+	assert.Equal(t, []string{"device-001", "readonly-sensor-001"}, g.DeviceIDs)
+
+	other, err := CreateGroup(models.GroupRequest{ID: "lights", Name: "Lights", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device-001"}, other.DeviceIDs, "a device can be in several groups")
+	_, err = CreateGroup(models.GroupRequest{ID: "lights", Name: "Lights"})
+	assert.ErrorIs(t, err, ErrGroupExists)
+
+	g, err = RemoveGroupDevice("everything", "readonly-sensor-001")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device-001"}, g.DeviceIDs)
+	g, err = AddGroupDevice("everything", "readonly-sensor-001")
+	require.NoError(t, err)
+	assert.Len(t, g.DeviceIDs, 2)
+
+	g, err = UpdateGroup("lights", models.GroupRequest{Name: "No lights"})
+	require.NoError(t, err)
+	assert.Equal(t, "No lights", g.Name)
+	assert.Empty(t, g.DeviceIDs)
+
+	devices, err := GroupDevices("everything")
+	require.NoError(t, err)
+	assert.Len(t, devices, 2)
+
+	require.NoError(t, DeleteGroup("lights"))
+	assert.Len(t, ListGroups(), 1)
+	_, err = GetGroup("lights")
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+	_, err = UpdateGroup("lights", models.GroupRequest{Name: "Lights"})
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+}
+
+func TestExecuteGroupCommand(t *testing.T) {
+	resetRegistry(t)
+
+	_, err := CreateGroup(models.GroupRequest{ID: "all", Name: "All", DeviceIDs: []string{"device-001", "readonly-sensor-001"}})
+	require.NoError(t, err)
+
+	result, err := ExecuteGroupCommand(context.Background(), "all", models.Command{Action: "turn_off", Parameters: map[string]interface{}{}})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1, "only controllable members get the command")
+	assert.Equal(t, "device-001", result.Results[0].DeviceID)
+	assert.Equal(t, http.StatusOK, result.Results[0].Status)
+
+	_, err = ExecuteGroupCommand(context.Background(), "none", models.Command{Action: "turn_off"})
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+}
+
+func TestExecuteAreaCommand(t *testing.T) {
+	resetRegistry(t)
+
+	result, err := ExecuteAreaCommand(context.Background(), "living_room", models.Command{Action: "turn_on", Parameters: map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+
+	_, err = CreateArea(models.AreaRequest{ID: "hallway", Name: "Hallway", DeviceIDs: []string{"readonly-sensor-001"}})
+	require.NoError(t, err)
+	result, err = ExecuteAreaCommand(context.Background(), "hallway", models.Command{Action: "turn_on", Parameters: map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.Empty(t, result.Results)
+	assert.NotNil(t, result.Results, "an empty batch still lists its results")
+}
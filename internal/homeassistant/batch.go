@@ -0,0 +1,181 @@
+package homeassistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go-github/internal/models"
+)
+
+const (
+	// MaxBatchSize is the largest number of commands in one batch.
+	MaxBatchSize = 100
+	// BatchWorkers is how many commands of a batch run at the same time.
+	BatchWorkers = 4
+)
+
+// ValidateBatch checks r and returns every invalid field, located by JSON
+// pointer (e.g. /commands/2/action).
+func ValidateBatch(r *models.BatchRequest) []models.FieldError {
+	var errs []models.FieldError
+	switch {
+	case len(r.Commands) == 0:
+		errs = append(errs, models.FieldError{Pointer: "/commands", Detail: "at least one command is required"})
+	case len(r.Commands) > MaxBatchSize:
+		errs = append(errs, models.FieldError{Pointer: "/commands", Detail: fmt.Sprintf("at most %d commands are allowed, got %d", MaxBatchSize, len(r.Commands))})
+	}
+	for i, c := range r.Commands {
+		if strings.TrimSpace(c.DeviceID) == "" {
+			errs = append(errs, models.FieldError{Pointer: fmt.Sprintf("/commands/%d/device_id", i), Detail: "device_id is required"})
+		}
+		cmd := c.Command()
+		if err := cmd.Validate(); err != nil {
+			field := "action"
+			if cmd.Action != "" {
+				field = "parameters"
+			}
+			errs = append(errs, models.FieldError{Pointer: fmt.Sprintf("/commands/%d/%s", i, field), Detail: err.Error()})
+		}
+	}
+	return errs
+}
+
+// executeContext runs a single command and lookupDevice reads a device;
+// tests replace them.
+var (
+	executeContext = ExecuteCommandContext
+	lookupDevice   = GetDevice
+)
+
+// ExecuteBatch runs the commands of r on up to BatchWorkers goroutines and
+// returns the result of each. r must be valid.
+//
+// In atomic mode the state and attributes of each device are captured before
+// its command runs, and the first failure stops the batch: commands that have
+// not started are skipped, and the devices of commands that succeeded are
+// restored to what was captured, newest first, the way a scene is activated.
+// Devices that are unchanged get no command. A device whose previous state no
+// action sets stays as it is and says so in RollbackError.
+func ExecuteBatch(ctx context.Context, r models.BatchRequest) models.BatchResult {
+	results := make([]models.BatchItemResult, len(r.Commands))
+	before := make([]*models.Device, len(r.Commands))
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		failed   bool
+		finished []int // indexes of succeeded commands, in completion order
+	)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(BatchWorkers, len(r.Commands)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				c := r.Commands[i]
+				item := models.BatchItemResult{Index: i, DeviceID: c.DeviceID, Action: c.Action}
+
+				mu.Lock()
+				skip := r.Atomic && failed
+				mu.Unlock()
+				var result models.CommandResult
+				var err error
+				if !skip {
+					if r.Atomic {
+						before[i], _ = lookupDevice(c.DeviceID)
+					}
+					result, err = executeContext(runCtx, c.DeviceID, c.Command())
+					skip = r.Atomic && errors.Is(err, context.Canceled) && ctx.Err() == nil
+				}
+
+				mu.Lock()
+				switch {
+				case skip:
+					item.Status = http.StatusFailedDependency
+					item.Error = "skipped"
+					item.Message = "not executed because another command of the atomic batch failed"
+				case err != nil:
+					item.Status, item.Error, item.Message = commandError(c.DeviceID, err)
+					if r.Atomic && !failed {
+						failed = true
+						cancel()
+					}
+				default:
+					item.Status = http.StatusOK
+					item.Result = &result
+					finished = append(finished, i)
+				}
+				results[i] = item
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range r.Commands {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	out := models.BatchResult{Results: results}
+	if r.Atomic && failed {
+		out.RolledBack = true
+		for j := len(finished) - 1; j >= 0; j-- {
+			i := finished[j]
+			rollback(ctx, &results[i], before[i])
+		}
+	}
+	for _, item := range results {
+		switch {
+		case item.Status == http.StatusOK:
+			out.Succeeded++
+		case item.Status == http.StatusFailedDependency:
+			out.Skipped++
+		default:
+			out.Failed++
+		}
+	}
+	return out
+}
+
+// rollback restores the device of item to before, its state and attributes
+// before the command ran.
+func rollback(ctx context.Context, item *models.BatchItemResult, before *models.Device) {
+	current, ok := lookupDevice(item.DeviceID)
+	if before == nil || !ok {
+		item.RollbackError = fmt.Sprintf("device %s cannot be restored: %v", item.DeviceID, ErrDeviceNotFound)
+		return
+	}
+	snap := models.SceneDevice{DeviceID: before.ID, State: before.State, Attributes: before.Attributes}
+	cmd, err := restoreCommand(snap, current)
+	if err != nil {
+		item.RollbackError = err.Error()
+		return
+	}
+	if cmd != nil {
+		if _, err := executeContext(context.WithoutCancel(ctx), item.DeviceID, *cmd); err != nil {
+			item.RollbackError = fmt.Sprintf("%s failed: %v", cmd.Action, err)
+			return
+		}
+	}
+	item.RolledBack = true
+}
+
+// commandError returns the HTTP status, error code and message of a failed
+// command, matching the single-command endpoint.
+func commandError(deviceID string, err error) (int, string, string) {
+	switch {
+	case errors.Is(err, ErrDeviceNotFound):
+		return http.StatusNotFound, "not_found", "device not found: " + deviceID
+	case errors.Is(err, ErrDeviceNotControllable):
+		return http.StatusMethodNotAllowed, "method_not_allowed", "device is not controllable: " + deviceID
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "gateway_timeout", "device did not respond in time: " + deviceID
+	}
+	return http.StatusInternalServerError, "internal_error", err.Error()
+}
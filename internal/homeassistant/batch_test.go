@@ -0,0 +1,211 @@
+package homeassistant
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchRequest_Validate(t *testing.T) {
+	r := models.BatchRequest{Commands: []models.BatchCommand{
+		{DeviceID: "device-001", Action: "turn_on", Parameters: map[string]interface{}{}},
+		{Action: "turn_on", Parameters: map[string]interface{}{}},
+		{DeviceID: "device-001", Parameters: map[string]interface{}{}},
+		{DeviceID: "device-001", Action: "turn_on"},
+	}}
+	var pointers []string
+	for _, f := range ValidateBatch(&r) {
+		pointers = append(pointers, f.Pointer)
+	}
+	assert.Equal(t, []string{"/commands/1/device_id", "/commands/2/action", "/commands/3/parameters"}, pointers)
+
+	empty := models.BatchRequest{}
+	require.Len(t, ValidateBatch(&empty), 1)
+	assert.Equal(t, "/commands", ValidateBatch(&empty)[0].Pointer)
+
+	large := models.BatchRequest{Commands: make([]models.BatchCommand, MaxBatchSize+1)}
+	for i := range large.Commands {
+		large.Commands[i] = models.BatchCommand{DeviceID: "device-001", Action: "turn_off", Parameters: map[string]interface{}{}}
+	}
+	require.Len(t, ValidateBatch(&large), 1)
+	assert.Equal(t, "at most 100 commands are allowed, got 101", ValidateBatch(&large)[0].Detail)
+}
+
+func TestExecuteBatch(t *testing.T) {
+	params := map[string]interface{}{}
+	result := ExecuteBatch(context.Background(), models.BatchRequest{Commands: []models.BatchCommand{
+		{DeviceID: "device-001", Action: "turn_on", Parameters: params},
+		{DeviceID: "readonly-sensor-001", Action: "turn_on", Parameters: params},
+		{DeviceID: "no-such-device", Action: "turn_on", Parameters: params},
+		{DeviceID: "device-001", Action: "turn_off", Parameters: params},
+	}})
+
+	require.Len(t, result.Results, 4)
+	for i, item := range result.Results {
+		assert.Equal(t, i, item.Index)
+	}
+	assert.Equal(t, http.StatusOK, result.Results[0].Status)
+	require.NotNil(t, result.Results[0].Result)
+	assert.Equal(t, "success", result.Results[0].Result.Status)
+	assert.Equal(t, http.StatusMethodNotAllowed, result.Results[1].Status)
+	assert.Equal(t, "method_not_allowed", result.Results[1].Error)
+	assert.Equal(t, http.StatusNotFound, result.Results[2].Status)
+	assert.Equal(t, "device not found: no-such-device", result.Results[2].Message)
+	assert.Equal(t, http.StatusOK, result.Results[3].Status)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.False(t, result.RolledBack, "only atomic batches roll back")
+}
+
+// stubExecute replaces executeContext for the duration of the test.
+func stubExecute(t *testing.T, fn func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error)) {
+	t.Helper()
+	executeContext = fn
+	t.Cleanup(func() { executeContext = ExecuteCommandContext })
+}
+
+func TestExecuteBatch_BoundedConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	stubExecute(t, func(_ context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return models.CommandResult{Status: "success", DeviceID: deviceID, Action: cmd.Action}, nil
+	})
+
+	r := models.BatchRequest{}
+	for i := 0; i < 20; i++ {
+		r.Commands = append(r.Commands, models.BatchCommand{DeviceID: fmt.Sprintf("light-%02d", i), Action: "turn_off"})
+	}
+	result := ExecuteBatch(context.Background(), r)
+	assert.Equal(t, 20, result.Succeeded)
+	assert.Equal(t, "light-07", result.Results[7].DeviceID)
+	assert.LessOrEqual(t, peak.Load(), int32(BatchWorkers))
+	assert.Greater(t, peak.Load(), int32(1), "commands run concurrently")
+}
+
+// stubDevices replaces the device store read by batches with devices, which
+// the returned apply updates as applyCommand would.
+func stubDevices(t *testing.T, devices ...*models.Device) (apply func(deviceID string, cmd models.Command)) {
+	t.Helper()
+	var mu sync.Mutex
+	store := make(map[string]*models.Device, len(devices))
+	for _, d := range devices {
+		store[d.ID] = d
+	}
+	lookupDevice = func(id string) (*models.Device, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		d, ok := store[id]
+		if !ok {
+			return nil, false
+		}
+		cp := *d
+		cp.Attributes = maps.Clone(d.Attributes)
+		return &cp, true
+	}
+	t.Cleanup(func() { lookupDevice = GetDevice })
+	return func(deviceID string, cmd models.Command) {
+		mu.Lock()
+		defer mu.Unlock()
+		if d, ok := store[deviceID]; ok {
+			applyCommand(d, cmd)
+		}
+	}
+}
+
+func TestExecuteBatch_Atomic(t *testing.T) {
+	apply := stubDevices(t,
+		&models.Device{ID: "light", State: "on", Attributes: map[string]interface{}{"brightness": 80}},
+		&models.Device{ID: "door", State: "unlocked"},
+		&models.Device{ID: "fan", State: "off"},
+		&models.Device{ID: "speaker", State: "playing", Attributes: map[string]interface{}{"volume": 10}},
+		&models.Device{ID: "broken", State: "on"},
+	)
+	var mu sync.Mutex
+	var calls []string
+	stubExecute(t, func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+		if strings.HasPrefix(deviceID, "slow-") {
+			// Still running when the batch fails.
+			<-ctx.Done()
+			return models.CommandResult{}, ctx.Err()
+		}
+		mu.Lock()
+		calls = append(calls, fmt.Sprint(deviceID, " ", cmd.Action, " ", cmd.Parameters))
+		mu.Unlock()
+		if deviceID == "broken" {
+			time.Sleep(10 * time.Millisecond) // let the others finish first
+			return models.CommandResult{}, ErrDeviceNotControllable
+		}
+		apply(deviceID, cmd)
+		return models.CommandResult{Status: "success", DeviceID: deviceID, Action: cmd.Action}, nil
+	})
+
+	r := models.BatchRequest{Atomic: true, Commands: []models.BatchCommand{
+		{DeviceID: "light", Action: "turn_off", Parameters: map[string]interface{}{"brightness": 0}},
+		{DeviceID: "door", Action: "lock"},
+		{DeviceID: "fan", Action: "turn_off"},
+		{DeviceID: "speaker", Action: "set_volume", Parameters: map[string]interface{}{"volume": 30}},
+		{DeviceID: "broken", Action: "turn_off"},
+	}}
+	for i := 0; i < 10; i++ {
+		r.Commands = append(r.Commands, models.BatchCommand{DeviceID: fmt.Sprintf("slow-%d", i), Action: "turn_off"})
+	}
+	result := ExecuteBatch(context.Background(), r)
+
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, http.StatusMethodNotAllowed, result.Results[4].Status)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 4, result.Succeeded)
+	assert.Equal(t, len(r.Commands)-5, result.Skipped)
+	for _, item := range result.Results[5:] {
+		assert.Equal(t, http.StatusFailedDependency, item.Status, item.DeviceID)
+		assert.Equal(t, "skipped", item.Error)
+	}
+
+	assert.True(t, result.Results[0].RolledBack)
+	assert.True(t, result.Results[1].RolledBack)
+	assert.True(t, result.Results[2].RolledBack, "an unchanged device needs no command")
+	assert.False(t, result.Results[3].RolledBack)
+	assert.Equal(t, `no action restores state "playing" of device speaker`, result.Results[3].RollbackError)
+
+	light, _ := lookupDevice("light")
+	assert.Equal(t, "on", light.State)
+	assert.Equal(t, map[string]interface{}{"brightness": 80}, light.Attributes)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, calls, "light turn_on map[brightness:80]")
+	assert.Contains(t, calls, "door unlock map[]")
+	assert.NotContains(t, calls, "fan turn_on map[]")
+	for _, call := range calls {
+		assert.NotContains(t, call, "slow-", "skipped commands are not rolled back")
+	}
+}
+
+func TestExecuteBatch_AtomicSuccess(t *testing.T) {
+	result := ExecuteBatch(context.Background(), models.BatchRequest{Atomic: true, Commands: []models.BatchCommand{
+		{DeviceID: "device-001", Action: "turn_on", Parameters: map[string]interface{}{}},
+		{DeviceID: "device-001", Action: "turn_off", Parameters: map[string]interface{}{}},
+	}})
+	assert.Equal(t, 2, result.Succeeded)
+	assert.False(t, result.RolledBack)
+	assert.False(t, result.Results[0].RolledBack)
+}
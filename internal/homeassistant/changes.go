@@ -0,0 +1,31 @@
+package homeassistant
+
+import (
+	"time"
+
+	"go-github/internal/events"
+)
+
+// Topics the device store publishes on events.Default(). Subscribers must
+// not modify the attribute and parameter maps of the events.
+var (
+	// DeviceChanged carries every change a command makes to the state or
+	// attributes of a device.
+	DeviceChanged = events.NewTopic[DeviceChange]("homeassistant.device_changed")
+	// CommandExecuted carries every executed command, including failed ones,
+	// as it is recorded in the command history.
+	CommandExecuted = events.NewTopic[CommandRecord]("homeassistant.command_executed")
+)
+
+// DeviceChange is a change of a device's state or attributes made by a
+// command.
+type DeviceChange struct {
+	DeviceID      string                 `json:"device_id"`
+	OldState      string                 `json:"old_state"`
+	NewState      string                 `json:"new_state"`
+	OldAttributes map[string]interface{} `json:"old_attributes"`
+	NewAttributes map[string]interface{} `json:"new_attributes"`
+	// RequestID is the request ID of the command that made the change.
+	RequestID string    `json:"request_id,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
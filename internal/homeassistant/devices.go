@@ -1,9 +1,20 @@
 package homeassistant
 
 import (
+	"context"
 	"errors"
+	"go-github/internal/events"
+	"go-github/internal/metrics"
 	"go-github/internal/models"
+	"go-github/internal/requestid"
+	"go-github/internal/tracing"
+	"log/slog"
+	"maps"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // ErrDeviceNotFound is returned when the requested device ID does not exist.
@@ -12,13 +23,36 @@ var ErrDeviceNotFound = errors.New("device not found")
 // ErrDeviceNotControllable is returned when the requested device cannot be controlled.
 var ErrDeviceNotControllable = errors.New("device is not controllable")
 
-// CommandResult represents the result of executing a command on a device.
-type CommandResult struct {
-	Status   string `json:"status"`
-	DeviceID string `json:"device_id"`
-	Action   string `json:"action"`
+// CommandResult is the outcome of a device command. It is declared in
+// package models, which the Go client shares; this name is kept for existing
+// importers.
+type CommandResult = models.CommandResult
+
+// actionStates maps the actions that set a device's state to that state.
+// Toggle flips between "on" and "off".
+var actionStates = map[string]string{
+	"turn_on":  "on",
+	"turn_off": "off",
+	"open":     "open",
+	"close":    "closed",
+	"lock":     "locked",
+	"unlock":   "unlocked",
 }
 
+// actionLabel returns the action label for the commands metric: action if it
+// is one the devices understand, otherwise "other", so that the action
+// callers send cannot mint new series.
+func actionLabel(action string) string {
+	if _, ok := actionStates[action]; ok || action == "toggle" {
+		return action
+	}
+	return "other"
+}
+
+// devicesMu guards the state, attributes and last update of mockDevices;
+// commands change them.
+var devicesMu sync.RWMutex
+
 // mockDevices is the in-memory device store shared by the homeassistant package.
 var mockDevices = map[string]*models.Device{
 	"device-001": {
@@ -41,33 +75,146 @@ var mockDevices = map[string]*models.Device{
 	},
 }
 
-// GetDevices returns all devices from the mock device store.
+// GetDevices returns all devices from the mock device store. The devices are
+// copies, with the area each is in.
 func GetDevices() map[string]*models.Device {
-	return mockDevices
+	out := make(map[string]*models.Device, len(mockDevices))
+	for id := range mockDevices {
+		out[id], _ = GetDevice(id)
+	}
+	return out
 }
 
-// GetDevice returns the device with the given ID, or false if not found.
+// GetDevice returns a copy of the device with the given ID, with the area it
+// is in, or false if not found.
 func GetDevice(id string) (*models.Device, bool) {
 	device, ok := mockDevices[id]
-	return device, ok
+	if !ok {
+		return nil, false
+	}
+	devicesMu.RLock()
+	d := *device
+	d.Attributes = maps.Clone(device.Attributes)
+	devicesMu.RUnlock()
+	d.AreaID = deviceAreaID(id)
+	return &d, true
 }
 
 // ExecuteCommand executes a command on the specified device.
 // Returns ErrDeviceNotFound if the device ID is unknown.
 // Returns ErrDeviceNotControllable if the device is read-only.
-func ExecuteCommand(deviceID string, cmd Command) (CommandResult, error) {
+func ExecuteCommand(deviceID string, cmd models.Command) (models.CommandResult, error) {
+	return ExecuteCommandContext(context.Background(), deviceID, cmd)
+}
+
+// ExecuteCommandContext is like ExecuteCommand but runs within ctx, recording
+// the call to Home Assistant as a client span of the caller's trace. The
+// request ID carried by ctx is attached to the span, the log record and the
+// command history entry. If ctx is done, the command is not sent and ctx's
+// error is returned.
+func ExecuteCommandContext(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+	ctx, span := tracing.StartClientSpan(ctx, "homeassistant", "ExecuteCommand")
+	defer span.End()
+
+	reqID := requestid.FromContext(ctx)
+	span.SetAttributes(
+		attribute.String("homeassistant.device_id", deviceID),
+		attribute.String("homeassistant.action", cmd.Action),
+		attribute.String("request_id", reqID),
+	)
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return models.CommandResult{}, err
+	}
+
+	result, change, err := executeCommand(deviceID, cmd)
+
+	rec := CommandRecord{
+		RequestID:  reqID,
+		DeviceID:   deviceID,
+		Action:     cmd.Action,
+		Parameters: cmd.Parameters,
+		Status:     result.Status,
+		ExecutedAt: time.Now(),
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		rec.Status = "failed"
+		rec.Error = err.Error()
+	}
+	history.add(rec)
+	events.Publish(events.Default(), CommandExecuted, rec)
+	if change != nil {
+		change.RequestID = reqID
+		events.Publish(events.Default(), DeviceChanged, *change)
+	}
+
+	slog.DebugContext(ctx, "device command executed",
+		"device_id", deviceID,
+		"action", cmd.Action,
+		"status", rec.Status,
+	)
+	return result, err
+}
+
+// executeCommand performs the command against the device store. The change
+// is nil if the command left the device as it was.
+func executeCommand(deviceID string, cmd models.Command) (models.CommandResult, *DeviceChange, error) {
+	action := actionLabel(cmd.Action)
 	device, ok := mockDevices[deviceID]
 	if !ok {
-		return CommandResult{}, ErrDeviceNotFound
+		// Unknown IDs come from callers; don't let them mint new label values.
+		metrics.CommandsTotal.WithLabelValues("unknown", action, metrics.OutcomeNotFound).Inc()
+		return models.CommandResult{}, nil, ErrDeviceNotFound
 	}
 
 	if !device.Controllable {
-		return CommandResult{}, ErrDeviceNotControllable
+		metrics.CommandsTotal.WithLabelValues(deviceID, action, metrics.OutcomeNotControllable).Inc()
+		return models.CommandResult{}, nil, ErrDeviceNotControllable
 	}
 
-	return CommandResult{
+	change := applyCommand(device, cmd)
+	metrics.CommandsTotal.WithLabelValues(deviceID, action, metrics.OutcomeSuccess).Inc()
+	return models.CommandResult{
 		Status:   "success",
 		DeviceID: deviceID,
 		Action:   cmd.Action,
-	}, nil
+	}, change, nil
+}
+
+// applyCommand updates device as Home Assistant would after cmd: actions in
+// actionStates set the state, toggle flips it, and the parameters are merged
+// into the attributes (turn_on with brightness sets the brightness). It
+// returns the change, or nil if the device is unchanged.
+func applyCommand(device *models.Device, cmd models.Command) *DeviceChange {
+	devicesMu.Lock()
+	defer devicesMu.Unlock()
+	change := &DeviceChange{DeviceID: device.ID, OldState: device.State, OldAttributes: device.Attributes}
+	if state, ok := actionStates[cmd.Action]; ok {
+		device.State = state
+	} else if cmd.Action == "toggle" {
+		switch device.State {
+		case "on":
+			device.State = "off"
+		case "off":
+			device.State = "on"
+		}
+	}
+	if len(cmd.Parameters) > 0 {
+		attrs := maps.Clone(device.Attributes)
+		if attrs == nil {
+			attrs = make(map[string]interface{}, len(cmd.Parameters))
+		}
+		maps.Copy(attrs, cmd.Parameters)
+		device.Attributes = attrs
+	}
+	device.LastUpdated = time.Now()
+
+	change.NewState, change.NewAttributes, change.ChangedAt = device.State, device.Attributes, device.LastUpdated
+	if change.OldState == change.NewState && SameValue(change.OldAttributes, change.NewAttributes) {
+		return nil
+	}
+	return change
 }
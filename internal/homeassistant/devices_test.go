@@ -1,9 +1,15 @@
 package homeassistant
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	"go-github/internal/events"
+	"go-github/internal/metrics"
+	"go-github/internal/requestid"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -106,3 +112,98 @@ func TestExecuteCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteCommand_RecordsMetrics(t *testing.T) {
+	success := metrics.CommandsTotal.WithLabelValues("device-001", "turn_off", metrics.OutcomeSuccess)
+	notFound := metrics.CommandsTotal.WithLabelValues("unknown", "turn_off", metrics.OutcomeNotFound)
+	beforeSuccess, beforeNotFound := testutil.ToFloat64(success), testutil.ToFloat64(notFound)
+
+	_, _ = ExecuteCommand("device-001", Command{Action: "turn_off", Parameters: map[string]interface{}{}})
+	_, _ = ExecuteCommand("no-such-device", Command{Action: "turn_off", Parameters: map[string]interface{}{}})
+
+	assert.Equal(t, beforeSuccess+1, testutil.ToFloat64(success))
+	assert.Equal(t, beforeNotFound+1, testutil.ToFloat64(notFound))
+}
+
+func TestExecuteCommand_UnknownActionSharesSeries(t *testing.T) {
+	other := metrics.CommandsTotal.WithLabelValues("device-001", "other", metrics.OutcomeSuccess)
+	before := testutil.ToFloat64(other)
+	series := testutil.CollectAndCount(metrics.CommandsTotal)
+
+	_, _ = ExecuteCommand("device-001", Command{Action: "made-up-action-1", Parameters: map[string]interface{}{}})
+	_, _ = ExecuteCommand("device-001", Command{Action: "made-up-action-2", Parameters: map[string]interface{}{}})
+
+	assert.Equal(t, before+2, testutil.ToFloat64(other))
+	assert.Equal(t, series, testutil.CollectAndCount(metrics.CommandsTotal), "arbitrary actions should not create new series")
+}
+
+func TestExecuteCommand_UpdatesDevice(t *testing.T) {
+	resetScenes(t)
+
+	tests := []struct {
+		action string
+		params map[string]interface{}
+		state  string
+	}{
+		{"turn_on", map[string]interface{}{"brightness": 60}, "on"},
+		{"toggle", map[string]interface{}{}, "off"},
+		{"toggle", map[string]interface{}{}, "on"},
+		{"lock", map[string]interface{}{}, "locked"},
+		{"toggle", map[string]interface{}{}, "locked"},
+		{"set_color", map[string]interface{}{"color": "red"}, "locked"},
+	}
+	for _, tt := range tests {
+		_, err := ExecuteCommand("device-001", Command{Action: tt.action, Parameters: tt.params})
+		require.NoError(t, err)
+		d, _ := GetDevice("device-001")
+		assert.Equal(t, tt.state, d.State, "after %s", tt.action)
+	}
+
+	d, _ := GetDevice("device-001")
+	assert.Equal(t, map[string]interface{}{"brightness": 60, "color": "red"}, d.Attributes)
+	d.Attributes["brightness"] = 0
+	again, _ := GetDevice("device-001")
+	assert.Equal(t, 60, again.Attributes["brightness"], "GetDevice returns a copy of the attributes")
+}
+
+func TestDeviceEvents(t *testing.T) {
+	resetScenes(t)
+	_, err := ExecuteCommand("device-001", Command{Action: "turn_off", Parameters: map[string]interface{}{}})
+	require.NoError(t, err)
+
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+	changes := events.Subscribe(bus, DeviceChanged)
+	commands := events.Subscribe(bus, CommandExecuted)
+
+	ctx := requestid.NewContext(context.Background(), "change-req-1")
+	_, err = ExecuteCommandContext(ctx, "device-001", Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 40}})
+	require.NoError(t, err)
+	_, err = ExecuteCommandContext(ctx, "device-001", Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 40}})
+	require.NoError(t, err)
+	_, err = ExecuteCommand("readonly-sensor-001", Command{Action: "turn_on", Parameters: map[string]interface{}{}})
+	require.Error(t, err)
+	changes.Close()
+	commands.Close()
+
+	var got []DeviceChange
+	for c := range changes.C() {
+		got = append(got, c)
+	}
+	require.Len(t, got, 1, "commands that change nothing, or fail, change no device")
+	c := got[0]
+	assert.Equal(t, "device-001", c.DeviceID)
+	assert.Equal(t, "off", c.OldState)
+	assert.Equal(t, "on", c.NewState)
+	assert.Equal(t, 40, c.NewAttributes["brightness"])
+	assert.Equal(t, "change-req-1", c.RequestID)
+	assert.False(t, c.ChangedAt.IsZero())
+
+	var statuses []string
+	for rec := range commands.C() {
+		statuses = append(statuses, rec.DeviceID+" "+rec.Status)
+	}
+	assert.Equal(t, []string{"device-001 success", "device-001 success", "readonly-sensor-001 failed"}, statuses,
+		"every command is published")
+}
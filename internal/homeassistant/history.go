@@ -0,0 +1,65 @@
+package homeassistant
+
+import (
+	"sync"
+	"time"
+)
+
+// commandHistorySize is the number of most recent commands kept in memory.
+const commandHistorySize = 100
+
+// CommandRecord is an entry in the command history.
+type CommandRecord struct {
+	RequestID  string                 `json:"request_id,omitempty"`
+	DeviceID   string                 `json:"device_id"`
+	Action     string                 `json:"action"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Status     string                 `json:"status"`
+	Error      string                 `json:"error,omitempty"`
+	ExecutedAt time.Time              `json:"executed_at"`
+}
+
+// commandHistory is a fixed-size ring buffer of executed commands.
+type commandHistory struct {
+	mu      sync.Mutex
+	records []CommandRecord
+	next    int
+	full    bool
+}
+
+var history = &commandHistory{records: make([]CommandRecord, commandHistorySize)}
+
+// add appends a record, overwriting the oldest once the buffer is full.
+func (h *commandHistory) add(rec CommandRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records[h.next] = rec
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the records newest first.
+func (h *commandHistory) list() []CommandRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := h.next
+	if h.full {
+		n = len(h.records)
+	}
+	out := make([]CommandRecord, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, h.records[(h.next-i+len(h.records))%len(h.records)])
+	}
+	return out
+}
+
+// CommandHistory returns the most recently executed commands, newest first,
+// including failed attempts. Each record carries the request ID of the HTTP or
+// MCP request that issued it.
+func CommandHistory() []CommandRecord {
+	return history.list()
+}
@@ -0,0 +1,61 @@
+package homeassistant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommandContext_RecordsHistory(t *testing.T) {
+	ctx := requestid.NewContext(context.Background(), "history-req-1")
+	_, err := ExecuteCommandContext(ctx, "device-001", models.Command{Action: "turn_on", Parameters: map[string]interface{}{}})
+	require.NoError(t, err)
+
+	ctx = requestid.NewContext(context.Background(), "history-req-2")
+	_, err = ExecuteCommandContext(ctx, "readonly-sensor-001", models.Command{Action: "turn_on", Parameters: map[string]interface{}{}})
+	require.Error(t, err)
+
+	records := CommandHistory()
+	require.GreaterOrEqual(t, len(records), 2)
+
+	assert.Equal(t, "history-req-2", records[0].RequestID, "newest first")
+	assert.Equal(t, "failed", records[0].Status)
+	assert.NotEmpty(t, records[0].Error)
+
+	assert.Equal(t, "history-req-1", records[1].RequestID)
+	assert.Equal(t, "device-001", records[1].DeviceID)
+	assert.Equal(t, "success", records[1].Status)
+}
+
+func TestCommandHistory_RingBuffer(t *testing.T) {
+	h := &commandHistory{records: make([]CommandRecord, 3)}
+	assert.Empty(t, h.list())
+
+	for i := 1; i <= 5; i++ {
+		h.add(CommandRecord{RequestID: fmt.Sprintf("r%d", i)})
+	}
+
+	records := h.list()
+	require.Len(t, records, 3)
+	assert.Equal(t, "r5", records[0].RequestID)
+	assert.Equal(t, "r4", records[1].RequestID)
+	assert.Equal(t, "r3", records[2].RequestID)
+}
+
+func TestExecuteCommandContext_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(requestid.NewContext(context.Background(), "history-cancelled"))
+	cancel()
+
+	_, err := ExecuteCommandContext(ctx, "device-001", models.Command{Action: "turn_on", Parameters: map[string]interface{}{}})
+	require.ErrorIs(t, err, context.Canceled)
+
+	for _, rec := range CommandHistory() {
+		assert.NotEqual(t, "history-cancelled", rec.RequestID, "commands that were never sent are not recorded")
+	}
+}
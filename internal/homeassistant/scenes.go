@@ -0,0 +1,235 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-github/internal/models"
+)
+
+// Scenes are kept in memory like areas and groups. Home Assistant's own
+// scenes are stored in scenes.yaml and activated with scene.turn_on; these
+// are captured from, and restored through, the device commands instead, so
+// they work with any backend that can execute commands.
+
+// Errors returned by the scene functions.
+var (
+	ErrSceneNotFound = errors.New("scene not found")
+	ErrSceneExists   = errors.New("scene already exists")
+)
+
+// stateActions maps each device state a scene can restore to the action
+// that puts a device in it; it is the inverse of actionStates.
+var stateActions = map[string]string{
+	"on":       "turn_on",
+	"off":      "turn_off",
+	"open":     "open",
+	"closed":   "close",
+	"locked":   "lock",
+	"unlocked": "unlock",
+}
+
+// Statuses of a device in a scene activation.
+const (
+	SceneDeviceRestored  = "restored"
+	SceneDeviceUnchanged = "unchanged"
+	SceneDeviceFailed    = "failed"
+)
+
+// ValidateScene checks r and returns every invalid field, located by JSON
+// pointer.
+func ValidateScene(r *models.SceneRequest) []models.FieldError {
+	errs := validateMembers(r.ID, r.Name, r.DeviceIDs)
+	if len(r.DeviceIDs) == 0 {
+		errs = append(errs, models.FieldError{Pointer: "/device_ids", Detail: "at least one device is required"})
+	}
+	for i, deviceID := range r.DeviceIDs {
+		if d, ok := mockDevices[deviceID]; ok && !d.Controllable {
+			errs = append(errs, models.FieldError{Pointer: fmt.Sprintf("/device_ids/%d", i), Detail: "device is not controllable: " + deviceID})
+		}
+	}
+	return errs
+}
+
+// sceneStore holds the scenes.
+type sceneStore struct {
+	mu     sync.RWMutex
+	scenes map[string]models.Scene
+}
+
+var scenes = newSceneStore()
+
+func newSceneStore() *sceneStore {
+	return &sceneStore{scenes: map[string]models.Scene{}}
+}
+
+// ListScenes returns every scene, ordered by ID.
+func ListScenes() []models.Scene {
+	scenes.mu.RLock()
+	defer scenes.mu.RUnlock()
+	out := slices.Collect(maps.Values(scenes.scenes))
+	slices.SortFunc(out, func(a, b models.Scene) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// GetScene returns the scene id, or ErrSceneNotFound.
+func GetScene(id string) (models.Scene, error) {
+	scenes.mu.RLock()
+	defer scenes.mu.RUnlock()
+	s, ok := scenes.scenes[id]
+	if !ok {
+		return models.Scene{}, ErrSceneNotFound
+	}
+	return s, nil
+}
+
+// CreateScene captures the current state of the devices of r, which must be
+// valid, as a new scene. It returns ErrSceneExists if r names the ID of an
+// existing scene.
+func CreateScene(r models.SceneRequest) (models.Scene, error) {
+	scenes.mu.Lock()
+	defer scenes.mu.Unlock()
+	id := UniqueID(r.ID, r.Name, func(id string) bool { _, ok := scenes.scenes[id]; return ok })
+	if _, ok := scenes.scenes[id]; ok {
+		return models.Scene{}, ErrSceneExists
+	}
+	s := capture(id, r)
+	scenes.scenes[id] = s
+	return s, nil
+}
+
+// UpdateScene renames the scene id and recaptures it from the current state
+// of the devices of r, which must be valid; r.ID is ignored.
+func UpdateScene(id string, r models.SceneRequest) (models.Scene, error) {
+	scenes.mu.Lock()
+	defer scenes.mu.Unlock()
+	if _, ok := scenes.scenes[id]; !ok {
+		return models.Scene{}, ErrSceneNotFound
+	}
+	s := capture(id, r)
+	scenes.scenes[id] = s
+	return s, nil
+}
+
+// DeleteScene removes the scene id.
+func DeleteScene(id string) error {
+	scenes.mu.Lock()
+	defer scenes.mu.Unlock()
+	if _, ok := scenes.scenes[id]; !ok {
+		return ErrSceneNotFound
+	}
+	delete(scenes.scenes, id)
+	return nil
+}
+
+// capture returns the scene id holding the current state of the devices of r.
+func capture(id string, r models.SceneRequest) models.Scene {
+	deviceIDs := slices.Sorted(maps.Keys(deviceSet(r.DeviceIDs)))
+	s := models.Scene{
+		ID:         id,
+		Name:       strings.TrimSpace(r.Name),
+		Devices:    make([]models.SceneDevice, 0, len(deviceIDs)),
+		CapturedAt: time.Now(),
+	}
+	for _, d := range devicesByID(deviceIDs) {
+		attrs := d.Attributes
+		if attrs == nil {
+			attrs = map[string]interface{}{}
+		}
+		s.Devices = append(s.Devices, models.SceneDevice{DeviceID: d.ID, State: d.State, Attributes: attrs})
+	}
+	return s
+}
+
+// ActivateScene restores the devices of the scene id to their captured
+// state. Each device that differs from the scene gets one command, sent
+// through ExecuteCommandContext as a batch: the action that sets the
+// captured state, with the attributes that differ as parameters. Devices
+// that already match get no command. A device that cannot be restored is
+// reported in the result; it does not stop the others.
+func ActivateScene(ctx context.Context, id string) (models.SceneActivation, error) {
+	s, err := GetScene(id)
+	if err != nil {
+		return models.SceneActivation{}, err
+	}
+
+	out := models.SceneActivation{SceneID: id, Results: make([]models.SceneDeviceResult, len(s.Devices))}
+	var batch models.BatchRequest
+	var pending []int // indexes into out.Results of the batch commands
+	for i, snap := range s.Devices {
+		res := models.SceneDeviceResult{DeviceID: snap.DeviceID, Status: SceneDeviceUnchanged}
+		current, ok := GetDevice(snap.DeviceID)
+		cmd, err := restoreCommand(snap, current)
+		switch {
+		case !ok:
+			_, res.Error, res.Message = commandError(snap.DeviceID, ErrDeviceNotFound)
+			res.Status = SceneDeviceFailed
+		case err != nil:
+			res.Status, res.Error, res.Message = SceneDeviceFailed, "not_restorable", err.Error()
+		case cmd != nil:
+			res.Command = cmd
+			batch.Commands = append(batch.Commands, models.BatchCommand{DeviceID: snap.DeviceID, Action: cmd.Action, Parameters: cmd.Parameters})
+			pending = append(pending, i)
+		}
+		out.Results[i] = res
+	}
+
+	for j, item := range ExecuteBatch(ctx, batch).Results {
+		res := &out.Results[pending[j]]
+		if item.Status == http.StatusOK {
+			res.Status = SceneDeviceRestored
+			continue
+		}
+		res.Status, res.Error, res.Message = SceneDeviceFailed, item.Error, item.Message
+	}
+
+	for _, res := range out.Results {
+		switch res.Status {
+		case SceneDeviceRestored:
+			out.Restored++
+		case SceneDeviceUnchanged:
+			out.Unchanged++
+		default:
+			out.Failed++
+		}
+	}
+	return out, nil
+}
+
+// restoreCommand returns the command that brings current back to snap, or
+// nil if it already matches. current may be nil.
+func restoreCommand(snap models.SceneDevice, current *models.Device) (*models.Command, error) {
+	if current == nil {
+		return nil, nil
+	}
+	params := map[string]interface{}{}
+	for k, v := range snap.Attributes {
+		if cur, ok := current.Attributes[k]; !ok || !SameValue(cur, v) {
+			params[k] = v
+		}
+	}
+	if current.State == snap.State && len(params) == 0 {
+		return nil, nil
+	}
+	action, ok := stateActions[snap.State]
+	if !ok {
+		return nil, fmt.Errorf("no action restores state %q of device %s", snap.State, snap.DeviceID)
+	}
+	return &models.Command{Action: action, Parameters: params}, nil
+}
+
+// SameValue reports whether a and b are equal as JSON, so that an attribute
+// set from a decoded request (a float64) matches its captured int.
+func SameValue(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,133 @@
+package homeassistant
+
+import (
+	"context"
+	"testing"
+
+	"go-github/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetScenes removes the scenes and restores the device states after the
+// test.
+func resetScenes(t *testing.T) {
+	t.Helper()
+	saved := make(map[string]models.Device, len(mockDevices))
+	for id := range mockDevices {
+		d, _ := GetDevice(id)
+		saved[id] = *d
+	}
+	t.Cleanup(func() {
+		scenes = newSceneStore()
+		devicesMu.Lock()
+		defer devicesMu.Unlock()
+		for id, d := range saved {
+			mockDevices[id].State = d.State
+			mockDevices[id].Attributes = d.Attributes
+		}
+	})
+}
+
+func TestSceneRequest_Validate(t *testing.T) {
+	r := models.SceneRequest{Name: "Movie night", DeviceIDs: []string{"device-001", "readonly-sensor-001", "nope"}}
+	var details []string
+	for _, f := range ValidateScene(&r) {
+		details = append(details, f.Pointer+": "+f.Detail)
+	}
+	assert.Equal(t, []string{
+		"/device_ids/2: device not found: nope",
+		"/device_ids/1: device is not controllable: readonly-sensor-001",
+	}, details)
+
+	r = models.SceneRequest{Name: "Empty"}
+	require.Len(t, ValidateScene(&r), 1)
+	assert.Equal(t, "/device_ids", ValidateScene(&r)[0].Pointer)
+}
+
+func TestScenes(t *testing.T) {
+	resetScenes(t)
+
+	_, err := ExecuteCommand("device-001", models.Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 40}})
+	require.NoError(t, err)
+	s, err := CreateScene(models.SceneRequest{Name: "Movie night", DeviceIDs: []string{"device-001", "device-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "movie_night", s.ID)
+	require.Len(t, s.Devices, 1)
+	assert.Equal(t, models.SceneDevice{DeviceID: "device-001", State: "on", Attributes: map[string]interface{}{"brightness": 40}}, s.Devices[0])
+
+	_, err = CreateScene(models.SceneRequest{ID: "movie_night", Name: "Again", DeviceIDs: []string{"device-001"}})
+	assert.ErrorIs(t, err, ErrSceneExists)
+
+	_, err = ExecuteCommand("device-001", models.Command{Action: "turn_off", Parameters: map[string]interface{}{}})
+	require.NoError(t, err)
+	s, err = UpdateScene("movie_night", models.SceneRequest{Name: "Lights out", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Lights out", s.Name)
+	assert.Equal(t, "off", s.Devices[0].State, "updating recaptures the devices")
+
+	got, err := GetScene("movie_night")
+	require.NoError(t, err)
+	assert.Equal(t, s, got)
+	assert.Len(t, ListScenes(), 1)
+
+	require.NoError(t, DeleteScene("movie_night"))
+	assert.ErrorIs(t, DeleteScene("movie_night"), ErrSceneNotFound)
+	_, err = UpdateScene("movie_night", models.SceneRequest{Name: "x", DeviceIDs: []string{"device-001"}})
+	assert.ErrorIs(t, err, ErrSceneNotFound)
+}
+
+func TestActivateScene(t *testing.T) {
+	resetScenes(t)
+
+	_, err := ExecuteCommand("device-001", models.Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 80}})
+	require.NoError(t, err)
+	_, err = CreateScene(models.SceneRequest{ID: "bright", Name: "Bright", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+
+	result, err := ActivateScene(context.Background(), "bright")
+	require.NoError(t, err)
+	assert.Equal(t, models.SceneActivation{SceneID: "bright", Results: []models.SceneDeviceResult{{DeviceID: "device-001", Status: SceneDeviceUnchanged}}, Unchanged: 1}, result,
+		"a device that already matches gets no command")
+
+	// A decoded request sets brightness as a float64; only the state differs.
+	_, err = ExecuteCommand("device-001", models.Command{Action: "turn_off", Parameters: map[string]interface{}{"brightness": float64(80)}})
+	require.NoError(t, err)
+	result, err = ActivateScene(context.Background(), "bright")
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, SceneDeviceRestored, result.Results[0].Status)
+	assert.Equal(t, &models.Command{Action: "turn_on", Parameters: map[string]interface{}{}}, result.Results[0].Command)
+	assert.Equal(t, 1, result.Restored)
+
+	_, err = ExecuteCommand("device-001", models.Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 10}})
+	require.NoError(t, err)
+	result, err = ActivateScene(context.Background(), "bright")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 80}}, result.Results[0].Command)
+	d, _ := GetDevice("device-001")
+	assert.Equal(t, "on", d.State)
+	assert.Equal(t, 80, d.Attributes["brightness"])
+
+	_, err = ActivateScene(context.Background(), "dim")
+	assert.ErrorIs(t, err, ErrSceneNotFound)
+}
+
+func TestActivateScene_ReportsFailures(t *testing.T) {
+	resetScenes(t)
+	scenes.scenes["broken"] = models.Scene{ID: "broken", Name: "Broken", Devices: []models.SceneDevice{
+		{DeviceID: "device-001", State: "dimmed", Attributes: map[string]interface{}{}},
+		{DeviceID: "gone", State: "on", Attributes: map[string]interface{}{}},
+		{DeviceID: "readonly-sensor-001", State: "on", Attributes: map[string]interface{}{}},
+	}}
+
+	result, err := ActivateScene(context.Background(), "broken")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Failed)
+	require.Len(t, result.Results, 3)
+	assert.Equal(t, "not_restorable", result.Results[0].Error)
+	assert.Equal(t, "not_found", result.Results[1].Error)
+	assert.Equal(t, "method_not_allowed", result.Results[2].Error)
+	assert.NotNil(t, result.Results[2].Command, "the failed command is reported")
+}
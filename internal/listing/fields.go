@@ -0,0 +1,196 @@
+package listing
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// field is a struct field as it appears in JSON.
+type field struct {
+	name  string
+	index int
+	typ   reflect.Type
+}
+
+// jsonFields returns the fields encoding/json writes for struct type t, in
+// declaration order.
+func jsonFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, field{name: name, index: i, typ: f.Type})
+	}
+	return fields
+}
+
+func fieldsByName(t reflect.Type) map[string]field {
+	byName := map[string]field{}
+	for _, f := range jsonFields(t) {
+		byName[f.name] = f
+	}
+	return byName
+}
+
+// elemType returns the struct type behind a (pointer) type.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func rowValue(item any) reflect.Value {
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	return v
+}
+
+// Rows returns the items of v if it is a list: a slice of structs (or
+// pointers to structs), or a struct with exactly one such slice field, like
+// a response wrapping its items.
+func Rows(v any) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return reflect.Value{}, false
+	}
+	if isRowSlice(rv.Type()) {
+		return rv, true
+	}
+	if i, ok := rowsField(rv.Type()); ok {
+		return rv.Field(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// rowsField returns the index of the only exported row slice field of t.
+func rowsField(t reflect.Type) (int, bool) {
+	if t.Kind() != reflect.Struct {
+		return 0, false
+	}
+	index := -1
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() || !isRowSlice(t.Field(i).Type) {
+			continue
+		}
+		if index >= 0 {
+			return 0, false
+		}
+		index = i
+	}
+	return index, index >= 0
+}
+
+func isRowSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := elemType(t.Elem())
+	return elem.Kind() == reflect.Struct && elem != timeType
+}
+
+// Project returns a copy of v whose items only have the named fields, in
+// declaration order, for sparse fieldsets. v is a list as accepted by Rows;
+// other fields of a wrapping struct are kept. The copy uses struct types
+// built at run time, so it encodes like the original in every format.
+func Project(v any, names []string) (any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Pointer {
+		return nil, fmt.Errorf("listing: cannot project %T", v)
+	}
+	if isRowSlice(rv.Type()) {
+		return projectRows(rv, names)
+	}
+	i, ok := rowsField(rv.Type())
+	if !ok {
+		return nil, fmt.Errorf("listing: cannot project %T", v)
+	}
+	rows, err := projectRows(rv.Field(i), names)
+	if err != nil {
+		return nil, err
+	}
+
+	// reflect.StructOf rejects unexported fields; encoders skip them anyway.
+	var fields []reflect.StructField
+	var index []int
+	for j := 0; j < rv.NumField(); j++ {
+		f := rv.Type().Field(j)
+		if !f.IsExported() {
+			continue
+		}
+		f.Index, f.Offset = nil, 0
+		if j == i {
+			f.Type = reflect.TypeOf(rows)
+		}
+		fields = append(fields, f)
+		index = append(index, j)
+	}
+	out := reflect.New(reflect.StructOf(fields)).Elem()
+	for k, j := range index {
+		if j == i {
+			out.Field(k).Set(reflect.ValueOf(rows))
+		} else {
+			out.Field(k).Set(rv.Field(j))
+		}
+	}
+	return out.Interface(), nil
+}
+
+func projectRows(rows reflect.Value, names []string) (any, error) {
+	rowType := elemType(rows.Type().Elem())
+	byName := fieldsByName(rowType)
+	keep := map[int]bool{}
+	for _, name := range names {
+		f, ok := byName[name]
+		if !ok {
+			return nil, &ParamError{"fields", fmt.Sprintf("unknown field %q", name)}
+		}
+		keep[f.index] = true
+	}
+	var fields []reflect.StructField
+	var index []int
+	for i := 0; i < rowType.NumField(); i++ {
+		if keep[i] {
+			f := rowType.Field(i)
+			f.Index, f.Offset = nil, 0
+			fields = append(fields, f)
+			index = append(index, i)
+		}
+	}
+
+	projected := reflect.StructOf(fields)
+	out := reflect.MakeSlice(reflect.SliceOf(projected), 0, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		for row.Kind() == reflect.Pointer {
+			row = row.Elem()
+		}
+		if !row.IsValid() {
+			continue
+		}
+		p := reflect.New(projected).Elem()
+		for j, src := range index {
+			p.Field(j).Set(row.Field(src))
+		}
+		out = reflect.Append(out, p)
+	}
+	return out.Interface(), nil
+}
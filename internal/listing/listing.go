@@ -0,0 +1,113 @@
+// Package listing implements the list parameters shared by the HTTP list
+// endpoints and the MCP list resources: opaque cursor pagination (limit,
+// cursor), multi-key sorting (sort) and sparse fieldsets (fields).
+//
+// Items are addressed by their json field names. Pagination is keyset based:
+// a cursor records the sort key values of the last item of a page, so pages
+// stay consistent when items are added or removed between requests.
+package listing
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLimit is the page size when a request does not set limit.
+	DefaultLimit = 100
+	// MaxLimit is the largest accepted limit.
+	MaxLimit = 1000
+)
+
+// Params are the list parameters of a request.
+type Params struct {
+	// Limit is the maximum number of items per page.
+	Limit int
+	// Cursor is the next_cursor of the previous page, or "" for the first.
+	Cursor string
+	// Sort orders the items. Ties, and an empty Sort, are broken by the key
+	// fields passed to Paginate.
+	Sort []SortKey
+	// Fields restricts items to the named fields; empty means all.
+	Fields []string
+}
+
+// SortKey orders items by one field.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// String returns the key in sort parameter syntax: "name" or "-name".
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + k.Field
+	}
+	return k.Field
+}
+
+// ParamError reports an invalid list parameter.
+type ParamError struct {
+	Param   string
+	Message string
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Param, e.Message)
+}
+
+// ParseParams reads limit, cursor, sort and fields from query:
+//
+//	limit=50&sort=-status,name&fields=id,name&cursor=<next_cursor>
+//
+// sort is a comma-separated list of fields, each optionally prefixed with
+// "-" for descending order. Whether the fields exist is checked by Paginate.
+func ParseParams(query url.Values) (Params, error) {
+	p := Params{Limit: DefaultLimit, Cursor: query.Get("cursor")}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Params{}, &ParamError{"limit", fmt.Sprintf("%q is not a number between 1 and %d", v, MaxLimit)}
+		}
+		p.Limit = limit
+	}
+
+	if v := query.Get("sort"); v != "" {
+		seen := map[string]bool{}
+		for _, s := range strings.Split(v, ",") {
+			s = strings.TrimSpace(s)
+			key := SortKey{Field: strings.TrimPrefix(s, "-"), Desc: strings.HasPrefix(s, "-")}
+			if key.Field == "" {
+				return Params{}, &ParamError{"sort", fmt.Sprintf("empty field in %q", v)}
+			}
+			if seen[key.Field] {
+				return Params{}, &ParamError{"sort", fmt.Sprintf("field %q is listed twice", key.Field)}
+			}
+			seen[key.Field] = true
+			p.Sort = append(p.Sort, key)
+		}
+	}
+
+	if v := query.Get("fields"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f == "" {
+				return Params{}, &ParamError{"fields", fmt.Sprintf("empty field in %q", v)}
+			}
+			p.Fields = append(p.Fields, f)
+		}
+	}
+	return p, nil
+}
+
+// Has reports whether query sets any list parameter.
+func Has(query url.Values) bool {
+	for _, name := range []string{"limit", "cursor", "sort", "fields"} {
+		if query.Has(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package listing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID      string            `json:"id"`
+	Group   string            `json:"group"`
+	Size    int               `json:"size,omitempty"`
+	Enabled bool              `json:"enabled"`
+	Updated time.Time         `json:"updated"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	secret  string
+}
+
+type itemList struct {
+	Items      []item `json:"items"`
+	Count      int    `json:"count"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	hidden     bool
+}
+
+func TestParseParams(t *testing.T) {
+	p, err := ParseParams(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, Params{Limit: DefaultLimit}, p)
+
+	p, err = ParseParams(url.Values{
+		"limit":  {"25"},
+		"cursor": {"abc"},
+		"sort":   {"-group, id"},
+		"fields": {"id,group"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Params{
+		Limit:  25,
+		Cursor: "abc",
+		Sort:   []SortKey{{Field: "group", Desc: true}, {Field: "id"}},
+		Fields: []string{"id", "group"},
+	}, p)
+
+	tests := []struct {
+		query string
+		param string
+		want  string
+	}{
+		{"limit=0", "limit", `invalid limit: "0" is not a number between 1 and 1000`},
+		{"limit=1001", "limit", `invalid limit: "1001" is not a number between 1 and 1000`},
+		{"limit=ten", "limit", `invalid limit: "ten" is not a number between 1 and 1000`},
+		{"sort=id,,group", "sort", `invalid sort: empty field in "id,,group"`},
+		{"sort=-", "sort", `invalid sort: empty field in "-"`},
+		{"sort=id,-id", "sort", `invalid sort: field "id" is listed twice`},
+		{"fields=id,", "fields", `invalid fields: empty field in "id,"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			_, err = ParseParams(query)
+			var perr *ParamError
+			require.True(t, errors.As(err, &perr), "got %v", err)
+			assert.Equal(t, tt.param, perr.Param)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestHas(t *testing.T) {
+	assert.False(t, Has(url.Values{}))
+	assert.False(t, Has(url.Values{"format": {"csv"}}))
+	assert.True(t, Has(url.Values{"fields": {"id"}}))
+}
+
+func testItems() []item {
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	return []item{
+		{ID: "e", Group: "b", Size: 1, Updated: base.Add(4 * time.Hour)},
+		{ID: "a", Group: "b", Size: 3, Updated: base},
+		{ID: "d", Group: "a", Size: 2, Enabled: true, Updated: base.Add(time.Hour)},
+		{ID: "c", Group: "a", Size: 3, Updated: base.Add(3 * time.Hour)},
+		{ID: "b", Group: "c", Size: 1, Enabled: true, Updated: base.Add(2 * time.Hour)},
+	}
+}
+
+func ids(items []item) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = it.ID
+	}
+	return out
+}
+
+// collect pages through items with p and returns the IDs of every page.
+func collect(t *testing.T, p Params) [][]string {
+	t.Helper()
+	var pages [][]string
+	for {
+		page, next, err := Paginate(testItems(), p, "id")
+		require.NoError(t, err)
+		pages = append(pages, ids(page))
+		if next == "" {
+			return pages
+		}
+		p.Cursor = next
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name string
+		sort []SortKey
+		want [][]string
+	}{
+		{"by key", nil, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{"descending", []SortKey{{Field: "id", Desc: true}}, [][]string{{"e", "d"}, {"c", "b"}, {"a"}}},
+		{"multiple keys", []SortKey{{Field: "group", Desc: true}, {Field: "size"}}, [][]string{{"b", "e"}, {"a", "d"}, {"c"}}},
+		{"ties broken by key", []SortKey{{Field: "size"}}, [][]string{{"b", "e"}, {"d", "a"}, {"c"}}},
+		{"bool", []SortKey{{Field: "enabled", Desc: true}}, [][]string{{"b", "d"}, {"a", "c"}, {"e"}}},
+		{"time", []SortKey{{Field: "updated"}}, [][]string{{"a", "d"}, {"b", "c"}, {"e"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, collect(t, Params{Limit: 2, Sort: tt.sort}))
+		})
+	}
+
+	t.Run("single page", func(t *testing.T) {
+		assert.Equal(t, [][]string{{"a", "b", "c", "d", "e"}}, collect(t, Params{}))
+	})
+
+	t.Run("cursor survives changes to the list", func(t *testing.T) {
+		items := testItems()
+		_, next, err := Paginate(items, Params{Limit: 2}, "id")
+		require.NoError(t, err)
+
+		// "b" is removed and "bb" added before the next page is read.
+		items = append(testItems()[:4], item{ID: "bb"})
+		page, _, err := Paginate(items, Params{Limit: 2, Cursor: next}, "id")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bb", "c"}, ids(page))
+	})
+}
+
+func TestPaginate_Errors(t *testing.T) {
+	_, next, err := Paginate(testItems(), Params{Limit: 1, Sort: []SortKey{{Field: "size"}}}, "id")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		p    Params
+		want string
+	}{
+		{"unknown sort field", Params{Sort: []SortKey{{Field: "colour"}}}, `invalid sort: unknown field "colour"`},
+		{"unsortable field", Params{Sort: []SortKey{{Field: "labels"}}}, `invalid sort: cannot sort by "labels"`},
+		{"unexported field", Params{Sort: []SortKey{{Field: "secret"}}}, `invalid sort: unknown field "secret"`},
+		{"unknown fields entry", Params{Fields: []string{"id", "colour"}}, `invalid fields: unknown field "colour"`},
+		{"malformed cursor", Params{Cursor: "!!"}, "invalid cursor: malformed cursor"},
+		{"cursor of another type", Params{Cursor: "eyJzIjoiaWQiLCJ2IjpbMV19"}, "invalid cursor: malformed cursor"},
+		{"cursor for another sort", Params{Cursor: next}, `invalid cursor: cursor was issued for sort "size,id", not "id"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Paginate(testItems(), tt.p, "id")
+			var perr *ParamError
+			require.True(t, errors.As(err, &perr), "got %v", err)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestPaginate_Pointers(t *testing.T) {
+	items := []*item{{ID: "b"}, {ID: "a"}}
+	page, next, err := Paginate(items, Params{Limit: 1}, "id")
+	require.NoError(t, err)
+	assert.Equal(t, "a", page[0].ID)
+
+	page, _, err = Paginate(items, Params{Limit: 1, Cursor: next}, "id")
+	require.NoError(t, err)
+	assert.Equal(t, "b", page[0].ID)
+}
+
+func TestRows(t *testing.T) {
+	list := itemList{Items: testItems()}
+	tests := []struct {
+		name string
+		data any
+		rows int
+		ok   bool
+	}{
+		{"slice of structs", testItems(), 5, true},
+		{"slice of pointers", []*item{{}}, 1, true},
+		{"wrapper struct", list, 5, true},
+		{"pointer to wrapper", &list, 5, true},
+		{"single object", item{}, 0, false},
+		{"slice of times", []time.Time{{}}, 0, false},
+		{"slice of strings", []string{"a"}, 0, false},
+		{"map", map[string]string{}, 0, false},
+		{"nil", nil, 0, false},
+		{"nil pointer", (*itemList)(nil), 0, false},
+		{"two lists", struct {
+			A []item
+			B []item
+		}{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, ok := Rows(tt.data)
+			assert.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.rows, rows.Len())
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	updated := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	items := []item{{ID: "a", Group: "g", Size: 2, Updated: updated, secret: "s"}}
+
+	got, err := Project(items, []string{"size", "id"})
+	require.NoError(t, err)
+	data, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":"a","size":2}]`, string(data))
+
+	got, err = Project(&itemList{Items: items, Count: 1, NextCursor: "next", hidden: true}, []string{"updated"})
+	require.NoError(t, err)
+	data, err = json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[{"updated":"2026-03-14T10:00:00Z"}],"count":1,"next_cursor":"next"}`, string(data))
+
+	rows, ok := Rows(got)
+	require.True(t, ok, "projections are still lists")
+	assert.Equal(t, 1, rows.Len())
+
+	_, err = Project(items, []string{"colour"})
+	assert.EqualError(t, err, `invalid fields: unknown field "colour"`)
+	_, err = Project(item{}, []string{"id"})
+	assert.Error(t, err)
+	_, err = Project(nil, []string{"id"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+package listing
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// column is a field items are ordered by.
+type column struct {
+	field
+	desc bool
+}
+
+// cursor is the decoded form of a next_cursor: the sort order it was issued
+// for and the sort key values of the last item of the page.
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// Paginate sorts items in place by p.Sort and then by the key fields, and
+// returns the page p selects with the cursor of the next page, or "" if this
+// is the last page. key names the fields that together identify an item,
+// such as "id", so that the order is total. Unknown sort or fields entries
+// and cursors issued for another sort order are reported as *ParamError.
+func Paginate[T any](items []T, p Params, key ...string) ([]T, string, error) {
+	rowType := elemType(reflect.TypeFor[T]())
+	fields := fieldsByName(rowType)
+	for _, name := range p.Fields {
+		if _, ok := fields[name]; !ok {
+			return nil, "", &ParamError{"fields", fmt.Sprintf("unknown field %q", name)}
+		}
+	}
+
+	order := slices.Clone(p.Sort)
+	for _, k := range key {
+		if !slices.ContainsFunc(order, func(s SortKey) bool { return s.Field == k }) {
+			order = append(order, SortKey{Field: k})
+		}
+	}
+	cols := make([]column, len(order))
+	spec := make([]string, len(order))
+	for i, k := range order {
+		f, ok := fields[k.Field]
+		if !ok {
+			return nil, "", &ParamError{"sort", fmt.Sprintf("unknown field %q", k.Field)}
+		}
+		if !sortable(f.typ) {
+			return nil, "", &ParamError{"sort", fmt.Sprintf("cannot sort by %q", k.Field)}
+		}
+		cols[i] = column{field: f, desc: k.Desc}
+		spec[i] = k.String()
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return compareRows(rowValue(items[i]), rowValue(items[j]), cols) < 0
+	})
+
+	start := 0
+	if p.Cursor != "" {
+		after, err := decodeCursor(p.Cursor, strings.Join(spec, ","), rowType, cols)
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return compareRows(rowValue(items[i]), after, cols) > 0
+		})
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	end := min(start+limit, len(items))
+	page := items[start:end]
+	if end == len(items) || end == start {
+		return page, "", nil
+	}
+	next, err := encodeCursor(strings.Join(spec, ","), rowValue(page[len(page)-1]), cols)
+	if err != nil {
+		return nil, "", err
+	}
+	return page, next, nil
+}
+
+func encodeCursor(spec string, row reflect.Value, cols []column) (string, error) {
+	c := cursor{Sort: spec, Values: make([]json.RawMessage, len(cols))}
+	for i, col := range cols {
+		v, err := json.Marshal(row.Field(col.index).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = v
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns a row of rowType holding the values recorded in s.
+// Only the columns are set.
+func decodeCursor(s, spec string, rowType reflect.Type, cols []column) (reflect.Value, error) {
+	invalid := &ParamError{"cursor", "malformed cursor"}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return reflect.Value{}, invalid
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return reflect.Value{}, invalid
+	}
+	if c.Sort != spec {
+		return reflect.Value{}, &ParamError{"cursor", fmt.Sprintf("cursor was issued for sort %q, not %q", c.Sort, spec)}
+	}
+	if len(c.Values) != len(cols) {
+		return reflect.Value{}, invalid
+	}
+	row := reflect.New(rowType).Elem()
+	for i, col := range cols {
+		if err := json.Unmarshal(c.Values[i], row.Field(col.index).Addr().Interface()); err != nil {
+			return reflect.Value{}, invalid
+		}
+	}
+	return row, nil
+}
+
+func compareRows(a, b reflect.Value, cols []column) int {
+	for _, col := range cols {
+		c := compareValues(a.Field(col.index), b.Field(col.index))
+		if col.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareValues(a, b reflect.Value) int {
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	}
+	switch a.Kind() {
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return cmp.Compare(boolInt(a.Bool()), boolInt(b.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	}
+	return 0
+}
+
+func sortable(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
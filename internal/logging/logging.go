@@ -0,0 +1,83 @@
+// Package logging configures the process-wide slog logger: output format,
+// minimum level, and request ID tagging.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go-github/internal/requestid"
+)
+
+// Output formats accepted by Setup.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// level is shared by every handler created by Setup so that the minimum level
+// can be changed at runtime with SetLevel.
+var level = new(slog.LevelVar)
+
+// ParseLevel converts debug, info, warn or error (case-insensitive) to a
+// slog.Level. An empty string means info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("logging: unknown level %q (want debug, info, warn or error)", s)
+	}
+}
+
+// NewHandler returns a slog handler writing to w in the given format (text or
+// json; empty means text) whose records are tagged with the request ID from
+// their context.
+func NewHandler(w io.Writer, format string) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatText:
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("logging: unknown format %q (want text or json)", format)
+	}
+	return requestid.NewLogHandler(h), nil
+}
+
+// Setup installs the default slog logger writing to w with the given level
+// and format.
+func Setup(w io.Writer, levelName, format string) error {
+	lvl, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+	h, err := NewHandler(w, format)
+	if err != nil {
+		return err
+	}
+	level.Set(lvl)
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// SetLevel changes the minimum level of every handler created by NewHandler.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Level returns the current minimum level.
+func Level() slog.Level {
+	return level.Level()
+}
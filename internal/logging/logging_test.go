@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"go-github/internal/requestid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{in: "", want: slog.LevelInfo},
+		{in: "debug", want: slog.LevelDebug},
+		{in: "INFO", want: slog.LevelInfo},
+		{in: "warn", want: slog.LevelWarn},
+		{in: "warning", want: slog.LevelWarn},
+		{in: " error ", want: slog.LevelError},
+		{in: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	prev := slog.Default()
+	defer slog.SetDefault(prev)
+	defer SetLevel(slog.LevelInfo)
+
+	var buf bytes.Buffer
+	require.NoError(t, Setup(&buf, "warn", "json"))
+
+	slog.Info("hidden")
+	slog.WarnContext(requestid.NewContext(context.Background(), "setup-req"), "shown")
+
+	out := buf.String()
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, `"msg":"shown"`)
+	assert.Contains(t, out, `"request_id":"setup-req"`)
+
+	SetLevel(slog.LevelDebug)
+	assert.Equal(t, slog.LevelDebug, Level())
+	slog.Debug("now visible")
+	assert.Contains(t, buf.String(), "now visible", "SetLevel should apply to the installed handler")
+}
+
+func TestSetup_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	assert.ErrorContains(t, Setup(&buf, "loud", "json"), "unknown level")
+	assert.ErrorContains(t, Setup(&buf, "info", "xml"), "unknown format")
+}
+
+func TestNewHandler_Text(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(&buf, "")
+	require.NoError(t, err)
+
+	slog.New(h).Info("plain", "k", "v")
+	assert.Contains(t, buf.String(), "msg=plain k=v")
+}
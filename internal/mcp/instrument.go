@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-github/internal/metrics"
+	"go-github/internal/requestid"
+	"go-github/internal/tracing"
+)
+
+// instrumentTool wraps a tool handler so every call gets a request ID and is
+// traced, logged and counted by outcome. A result flagged IsError counts as an
+// error even though the handler itself returned a nil Go error.
+func instrumentTool(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		reqID := requestid.New()
+		ctx = requestid.NewContext(ctx, reqID)
+
+		ctx, span := tracing.Tracer().Start(ctx, "mcp.tool "+name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("mcp.tool.name", name),
+				attribute.String("request_id", reqID),
+			),
+		)
+		defer span.End()
+
+		result, err := next(ctx, req)
+
+		outcome := metrics.OutcomeSuccess
+		if err != nil || (result != nil && result.IsError) {
+			outcome = metrics.OutcomeError
+			span.SetStatus(codes.Error, "tool call failed")
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		metrics.MCPToolCallsTotal.WithLabelValues(name, outcome).Inc()
+		slog.InfoContext(ctx, "mcp tool call completed",
+			"tool", name,
+			"outcome", outcome,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+
+		return result, err
+	}
+}
+
+// instrumentResource wraps a resource handler so every read gets a request ID
+// and is traced, logged and counted by outcome.
+func instrumentResource(uri string, next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		start := time.Now()
+		reqID := requestid.New()
+		ctx = requestid.NewContext(ctx, reqID)
+
+		ctx, span := tracing.Tracer().Start(ctx, "mcp.resource "+uri,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("mcp.resource.uri", uri),
+				attribute.String("request_id", reqID),
+			),
+		)
+		defer span.End()
+
+		contents, err := next(ctx, req)
+
+		outcome := metrics.OutcomeSuccess
+		if err != nil {
+			outcome = metrics.OutcomeError
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		metrics.MCPResourceReadsTotal.WithLabelValues(uri, outcome).Inc()
+		slog.InfoContext(ctx, "mcp resource read completed",
+			"uri", uri,
+			"outcome", outcome,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+
+		return contents, err
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/metrics"
+	"go-github/internal/requestid"
+	"go-github/internal/tracing/tracingtest"
+)
+
+func TestInstrumentTool_CountsOutcomes(t *testing.T) {
+	handler := instrumentTool("execute_command", ExecuteCommandHandler)
+	success := metrics.MCPToolCallsTotal.WithLabelValues("execute_command", metrics.OutcomeSuccess)
+	failure := metrics.MCPToolCallsTotal.WithLabelValues("execute_command", metrics.OutcomeError)
+	beforeSuccess, beforeFailure := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+	_, err := handler(context.Background(), buildToolRequest(map[string]interface{}{
+		"device_id": "device-001",
+		"action":    "turn_on",
+	}))
+	require.NoError(t, err)
+
+	result, err := handler(context.Background(), buildToolRequest(map[string]interface{}{
+		"device_id": "bad-device-id",
+		"action":    "turn_on",
+	}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	assert.Equal(t, beforeSuccess+1, testutil.ToFloat64(success))
+	assert.Equal(t, beforeFailure+1, testutil.ToFloat64(failure), "IsError results count as errors")
+}
+
+func TestInstrumentResource_CountsReads(t *testing.T) {
+	handler := instrumentResource("homelab://services", ServicesResourceHandler)
+	counter := metrics.MCPResourceReadsTotal.WithLabelValues("homelab://services", metrics.OutcomeSuccess)
+	before := testutil.ToFloat64(counter)
+
+	_, err := handler(context.Background(), mcpgo.ReadResourceRequest{})
+	require.NoError(t, err)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestInstrumentTool_RecordsSpan(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	handler := instrumentTool("execute_command", ExecuteCommandHandler)
+	_, err := handler(context.Background(), buildToolRequest(map[string]interface{}{
+		"device_id": "device-001",
+		"action":    "turn_on",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"homeassistant.ExecuteCommand", "mcp.tool execute_command"},
+		tracingtest.SpanNames(exporter),
+		"the upstream call should be a child of the tool span")
+	spans := exporter.GetSpans()
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestInstrumentResource_RecordsSpan(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	handler := instrumentResource("homelab://cluster/services", ClusterServicesResourceHandler)
+	_, err := handler(context.Background(), mcpgo.ReadResourceRequest{})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"kubernetes.ListServices", "mcp.resource homelab://cluster/services"},
+		tracingtest.SpanNames(exporter))
+}
+
+func TestInstrumentTool_AssignsRequestID(t *testing.T) {
+	var seen string
+	handler := instrumentTool("probe", func(ctx context.Context, _ mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		seen = requestid.FromContext(ctx)
+		return mcpgo.NewToolResultText("ok"), nil
+	})
+
+	_, err := handler(context.Background(), buildToolRequest(nil))
+	require.NoError(t, err)
+	assert.True(t, requestid.Valid(seen), "tool handlers should see a generated request ID")
+
+	_, err = ExecuteCommandHandler(requestid.NewContext(context.Background(), "mcp-req-1"), buildToolRequest(map[string]interface{}{
+		"device_id": "device-001",
+		"action":    "turn_on",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "mcp-req-1", homeassistant.CommandHistory()[0].RequestID,
+		"the request ID should reach the command history")
+}
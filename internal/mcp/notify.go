@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"go-github/internal/cluster"
+	"go-github/internal/events"
+	"go-github/internal/homeassistant"
+)
+
+// notifyResourceUpdates tells the clients of s when a resource changes, by
+// sending notifications/resources/updated for device changes and cluster
+// service changes on the event bus until ctx is cancelled. It subscribes
+// before returning, so that no change published afterwards is missed.
+func notifyResourceUpdates(ctx context.Context, s *server.MCPServer) {
+	notifyUpdated(ctx, s, homeassistant.DeviceChanged, "homelab://devices")
+	notifyUpdated(ctx, s, cluster.ServiceChanged, "homelab://cluster/services")
+}
+
+// notifyUpdated subscribes to topic and sends a resource update notification
+// for uri on each of its events until ctx is cancelled. A notification only
+// says that the resource changed, so while one is pending the events after it
+// are dropped.
+func notifyUpdated[T any](ctx context.Context, s *server.MCPServer, topic events.Topic[T], uri string) {
+	sub := events.Subscribe(events.Default(), topic, events.WithName("mcp"), events.WithBuffer(1))
+	go func() {
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.C():
+				s.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+			}
+		}
+	}()
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-github/internal/cluster"
+	"go-github/internal/events"
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession is an initialized client session whose notifications are
+// read from its channel.
+type testSession chan mcpgo.JSONRPCNotification
+
+func (s testSession) Initialize()                                           {}
+func (s testSession) Initialized() bool                                     { return true }
+func (s testSession) NotificationChannel() chan<- mcpgo.JSONRPCNotification { return s }
+func (s testSession) SessionID() string                                     { return "test-session" }
+
+// TestNotifyResourceUpdates verifies that device and cluster service changes
+// on the event bus reach clients as resource update notifications.
+func TestNotifyResourceUpdates(t *testing.T) {
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := NewMCPServer()
+	session := make(testSession, 4)
+	require.NoError(t, s.RegisterSession(ctx, session))
+	notifyResourceUpdates(ctx, s)
+
+	next := func() string {
+		t.Helper()
+		select {
+		case n := <-session:
+			assert.Equal(t, mcpgo.MethodNotificationResourceUpdated, n.Method)
+			uri, _ := n.Params.AdditionalFields["uri"].(string)
+			return uri
+		case <-time.After(5 * time.Second):
+			t.Fatal("no resource update notification")
+			return ""
+		}
+	}
+	events.Publish(bus, homeassistant.DeviceChanged, homeassistant.DeviceChange{DeviceID: "device-001"})
+	assert.Equal(t, "homelab://devices", next())
+	events.Publish(bus, cluster.ServiceChanged, cluster.ServiceEvent{Type: cluster.ServiceAdded, Service: models.ServiceInfo{Name: "web"}})
+	assert.Equal(t, "homelab://cluster/services", next())
+}
@@ -3,73 +3,115 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/url"
 
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"go-github/internal/cluster"
 	"go-github/internal/health"
 	"go-github/internal/homeassistant"
+	"go-github/internal/listing"
+	"go-github/internal/models"
 	"go-github/internal/services"
 )
 
-// DevicesResourceHandler returns all smart home devices as a JSON resource.
-func DevicesResourceHandler(_ context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+// listQuery is the URI template suffix of the list resources; see
+// listContents.
+const listQuery = "{?limit,cursor,sort,fields}"
+
+// DevicesResourceHandler returns smart home devices, ordered by ID, as a JSON
+// resource.
+func DevicesResourceHandler(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 	devices := homeassistant.GetDevices()
 
 	// Collect map values into a slice for stable JSON representation.
-	deviceSlice := make([]interface{}, 0, len(devices))
+	deviceSlice := make([]*models.Device, 0, len(devices))
 	for _, d := range devices {
 		deviceSlice = append(deviceSlice, d)
 	}
 
-	data, err := json.Marshal(deviceSlice)
+	return listContents(req, "homelab://devices", deviceSlice, "id")
+}
+
+// ServicesResourceHandler returns homelab services, ordered by name, as a JSON
+// resource.
+func ServicesResourceHandler(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return listContents(req, "homelab://services", services.GetServices(), "name")
+}
+
+// ClusterServicesResourceHandler returns Kubernetes cluster services, ordered
+// by namespace and name, as a JSON resource.
+func ClusterServicesResourceHandler(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	clusterSvc := cluster.NewService()
+	clusterServices, err := clusterSvc.ListServicesContext(ctx, "")
 	if err != nil {
 		return nil, err
 	}
 
-	return []mcp.ResourceContents{
-		mcp.TextResourceContents{
-			URI:      "homelab://devices",
-			MIMEType: "application/json",
-			Text:     string(data),
-		},
-	}, nil
+	return listContents(req, "homelab://cluster/services", clusterServices, "namespace", "name")
 }
 
-// ServicesResourceHandler returns all homelab services as a JSON resource.
-func ServicesResourceHandler(_ context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	svcs := services.GetServices()
+// ScenesResourceHandler returns the saved scenes, ordered by ID, as a JSON
+// resource.
+func ScenesResourceHandler(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return listContents(req, "homelab://scenes", homeassistant.ListScenes(), "id")
+}
+
+// listPage is a page of a list resource read with list parameters.
+type listPage struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
 
-	data, err := json.Marshal(svcs)
+// listContents returns items as the contents of the list resource uri. The
+// request URI may carry the list parameters of the HTTP list endpoints
+// (homelab://devices?limit=10&sort=-state&fields=id,state). Without them the
+// resource is a JSON array of every item; with them it is a page,
+// {"items": [...], "next_cursor": "..."}, whose next_cursor is passed back as
+// cursor to read the next one.
+func listContents[T any](req mcp.ReadResourceRequest, uri string, items []T, key ...string) ([]mcp.ResourceContents, error) {
+	query := url.Values{}
+	if req.Params.URI != "" {
+		u, err := url.Parse(req.Params.URI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource URI %q: %w", req.Params.URI, err)
+		}
+		query = u.Query()
+		uri = req.Params.URI
+	}
+
+	p, err := listing.ParseParams(query)
 	if err != nil {
 		return nil, err
 	}
-
-	return []mcp.ResourceContents{
-		mcp.TextResourceContents{
-			URI:      "homelab://services",
-			MIMEType: "application/json",
-			Text:     string(data),
-		},
-	}, nil
-}
-
-// ClusterServicesResourceHandler returns Kubernetes cluster services as a JSON resource.
-func ClusterServicesResourceHandler(_ context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	clusterSvc := cluster.NewService()
-	clusterServices, err := clusterSvc.ListServices("")
+	paged := listing.Has(query)
+	if !paged {
+		p.Limit = len(items)
+	}
+	page, next, err := listing.Paginate(items, p, key...)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := json.Marshal(clusterServices)
+	var result interface{} = page
+	if len(p.Fields) > 0 {
+		if result, err = listing.Project(page, p.Fields); err != nil {
+			return nil, err
+		}
+	}
+	if paged {
+		result = listPage{Items: result, NextCursor: next}
+	}
+
+	data, err := json.Marshal(result)
 	if err != nil {
 		return nil, err
 	}
 
 	return []mcp.ResourceContents{
 		mcp.TextResourceContents{
-			URI:      "homelab://cluster/services",
+			URI:      uri,
 			MIMEType: "application/json",
 			Text:     string(data),
 		},
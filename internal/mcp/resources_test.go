@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"testing"
 
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -96,6 +99,7 @@ func TestAllResourceHandlers_TableDriven(t *testing.T) {
 		{"services", ServicesResourceHandler, "homelab://services"},
 		{"cluster/services", ClusterServicesResourceHandler, "homelab://cluster/services"},
 		{"health", HealthResourceHandler, "homelab://health"},
+		{"scenes", ScenesResourceHandler, "homelab://scenes"},
 	}
 
 	for _, tc := range tests {
@@ -146,3 +150,95 @@ func TestResourceHandlers_CancelledContext(t *testing.T) {
 		})
 	}
 }
+
+// readList reads a list resource through handler and decodes its text into v.
+func readList(t *testing.T, handler func(context.Context, mcpgo.ReadResourceRequest) ([]mcpgo.ResourceContents, error), uri string, v interface{}) mcpgo.TextResourceContents {
+	t.Helper()
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = uri
+	contents, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	tc, ok := contents[0].(mcpgo.TextResourceContents)
+	require.True(t, ok)
+	require.NoError(t, json.Unmarshal([]byte(tc.Text), v))
+	return tc
+}
+
+func TestListResources_Paging(t *testing.T) {
+	var all []map[string]interface{}
+	readList(t, DevicesResourceHandler, "homelab://devices", &all)
+	require.Greater(t, len(all), 1)
+	for i := 1; i < len(all); i++ {
+		assert.Less(t, all[i-1]["id"], all[i]["id"], "devices are ordered by id")
+	}
+
+	var got []string
+	uri := "homelab://devices?limit=1&fields=id"
+	for {
+		var page struct {
+			Items      []map[string]interface{} `json:"items"`
+			NextCursor string                   `json:"next_cursor"`
+		}
+		tc := readList(t, DevicesResourceHandler, uri, &page)
+		assert.Equal(t, uri, tc.URI)
+		assert.Len(t, page.Items, 1)
+		for _, item := range page.Items {
+			assert.Len(t, item, 1)
+			got = append(got, item["id"].(string))
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		uri = "homelab://devices?limit=1&cursor=" + page.NextCursor + "&fields=id"
+	}
+	want := make([]string, len(all))
+	for i, d := range all {
+		want[i] = d["id"].(string)
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestListResources_Sort(t *testing.T) {
+	var page struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	readList(t, ServicesResourceHandler, "homelab://services?sort=-name", &page)
+	require.NotEmpty(t, page.Items)
+	assert.Equal(t, "prometheus", page.Items[0].Name)
+}
+
+func TestListResources_InvalidParams(t *testing.T) {
+	for _, uri := range []string{
+		"homelab://cluster/services?limit=0",
+		"homelab://cluster/services?sort=colour",
+		"homelab://cluster/services?fields=colour",
+		"homelab://cluster/services?cursor=nope",
+	} {
+		req := mcpgo.ReadResourceRequest{}
+		req.Params.URI = uri
+		_, err := ClusterServicesResourceHandler(context.Background(), req)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestScenesResourceHandler(t *testing.T) {
+	_, err := homeassistant.CreateScene(models.SceneRequest{ID: "mcp_resource_scene", Name: "Resource", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = homeassistant.DeleteScene("mcp_resource_scene") })
+
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = "homelab://scenes"
+	contents, err := ScenesResourceHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	tc, ok := contents[0].(mcpgo.TextResourceContents)
+	require.True(t, ok)
+	var scenes []models.Scene
+	require.NoError(t, json.Unmarshal([]byte(tc.Text), &scenes))
+	require.NotEmpty(t, scenes)
+	assert.Equal(t, "mcp_resource_scene", scenes[0].ID)
+	assert.Equal(t, "device-001", scenes[0].Devices[0].DeviceID)
+}
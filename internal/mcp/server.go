@@ -11,6 +11,11 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+
+	"go-github/internal/config"
+	"go-github/internal/homeassistant"
+	"go-github/internal/scheduler"
+	"go-github/internal/services"
 )
 
 const (
@@ -18,9 +23,32 @@ const (
 	serverVersion = "1.0.0"
 )
 
+// Option configures NewMCPServer.
+type Option func(*options)
+
+type options struct {
+	cfg *config.Config
+}
+
+// WithConfig configures the MCP server from cfg. It installs cfg's service
+// catalog, which the MCP and HTTP surfaces share.
+func WithConfig(cfg *config.Config) Option {
+	return func(o *options) {
+		o.cfg = cfg
+	}
+}
+
 // NewMCPServer constructs and returns a fully-configured *server.MCPServer.
 // All resources, tools, and prompt stubs are registered here.
-func NewMCPServer() *server.MCPServer {
+func NewMCPServer(opts ...Option) *server.MCPServer {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cfg != nil {
+		services.SetCatalog(o.cfg.Services.Models())
+	}
+
 	s := server.NewMCPServer(
 		serverName,
 		serverVersion,
@@ -38,49 +66,90 @@ func NewMCPServer() *server.MCPServer {
 
 // Run starts the MCP stdio server and blocks until ctx is cancelled or an I/O
 // error occurs. It is designed to be launched as a goroutine alongside the HTTP
-// server.
-func Run(ctx context.Context) error {
+// server. Clients are notified when the devices or cluster services resources
+// change.
+func Run(ctx context.Context, opts ...Option) error {
 	slog.Info("mcp server started", "transport", "stdio")
 
-	mcpServer := NewMCPServer()
+	mcpServer := NewMCPServer(opts...)
+	notifyResourceUpdates(ctx, mcpServer)
 	stdioServer := server.NewStdioServer(mcpServer)
 
 	return stdioServer.Listen(ctx, os.Stdin, os.Stdout)
 }
 
-// registerResources registers the four homelab resource endpoints.
+// registerResources registers the five homelab resource endpoints, and URI
+// templates that read the list resources page by page.
 func registerResources(s *server.MCPServer) {
 	s.AddResource(
 		mcp.NewResource("homelab://devices", "Homelab Devices",
 			mcp.WithResourceDescription("All smart home devices managed by Home Assistant"),
 			mcp.WithMIMEType("application/json"),
 		),
-		DevicesResourceHandler,
+		instrumentResource("homelab://devices", DevicesResourceHandler),
 	)
 	s.AddResource(
 		mcp.NewResource("homelab://services", "Homelab Services",
 			mcp.WithResourceDescription("All homelab services (prometheus, grafana, etc.)"),
 			mcp.WithMIMEType("application/json"),
 		),
-		ServicesResourceHandler,
+		instrumentResource("homelab://services", ServicesResourceHandler),
 	)
 	s.AddResource(
 		mcp.NewResource("homelab://cluster/services", "Cluster Services",
 			mcp.WithResourceDescription("Kubernetes cluster services and their endpoints"),
 			mcp.WithMIMEType("application/json"),
 		),
-		ClusterServicesResourceHandler,
+		instrumentResource("homelab://cluster/services", ClusterServicesResourceHandler),
 	)
 	s.AddResource(
 		mcp.NewResource("homelab://health", "Health Status",
 			mcp.WithResourceDescription("Current health status and uptime of the homelab API"),
 			mcp.WithMIMEType("application/json"),
 		),
-		HealthResourceHandler,
+		instrumentResource("homelab://health", HealthResourceHandler),
+	)
+	s.AddResource(
+		mcp.NewResource("homelab://scenes", "Scenes",
+			mcp.WithResourceDescription("Saved scenes with the captured state of their devices"),
+			mcp.WithMIMEType("application/json"),
+		),
+		instrumentResource("homelab://scenes", ScenesResourceHandler),
+	)
+
+	// Query parameters must appear in template order to match.
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("homelab://devices"+listQuery, "Homelab Devices (paged)",
+			mcp.WithTemplateDescription("A page of smart home devices; pass next_cursor back as cursor for the next page"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		server.ResourceTemplateHandlerFunc(instrumentResource("homelab://devices", DevicesResourceHandler)),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("homelab://services"+listQuery, "Homelab Services (paged)",
+			mcp.WithTemplateDescription("A page of homelab services; pass next_cursor back as cursor for the next page"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		server.ResourceTemplateHandlerFunc(instrumentResource("homelab://services", ServicesResourceHandler)),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("homelab://cluster/services"+listQuery, "Cluster Services (paged)",
+			mcp.WithTemplateDescription("A page of Kubernetes cluster services; pass next_cursor back as cursor for the next page"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		server.ResourceTemplateHandlerFunc(instrumentResource("homelab://cluster/services", ClusterServicesResourceHandler)),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate("homelab://scenes"+listQuery, "Scenes (paged)",
+			mcp.WithTemplateDescription("A page of saved scenes; pass next_cursor back as cursor for the next page"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		server.ResourceTemplateHandlerFunc(instrumentResource("homelab://scenes", ScenesResourceHandler)),
 	)
 }
 
-// registerTools registers the execute_command tool.
+// registerTools registers the execute_command, execute_batch, activate_scene,
+// schedule_command, list_schedules and cancel_schedule tools.
 func registerTools(s *server.MCPServer) {
 	executeCommandTool := mcp.NewTool(
 		"execute_command",
@@ -97,7 +166,93 @@ func registerTools(s *server.MCPServer) {
 			mcp.Description("Optional parameters for the action"),
 		),
 	)
-	s.AddTool(executeCommandTool, ExecuteCommandHandler)
+	s.AddTool(executeCommandTool, instrumentTool("execute_command", ExecuteCommandHandler))
+
+	executeBatchTool := mcp.NewTool(
+		"execute_batch",
+		mcp.WithDescription("Execute several control commands on Home Assistant devices concurrently"),
+		mcp.WithArray("commands",
+			mcp.Required(),
+			mcp.Description("The commands to execute, each with a device_id, an action and optional parameters"),
+			mcp.MinItems(1),
+			mcp.MaxItems(homeassistant.MaxBatchSize),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"device_id":  map[string]any{"type": "string"},
+					"action":     map[string]any{"type": "string"},
+					"parameters": map[string]any{"type": "object"},
+				},
+				"required": []string{"device_id", "action"},
+			}),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("Stop at the first failed command and undo the commands that succeeded where possible"),
+		),
+	)
+	s.AddTool(executeBatchTool, instrumentTool("execute_batch", ExecuteBatchHandler))
+
+	activateSceneTool := mcp.NewTool(
+		"activate_scene",
+		mcp.WithDescription("Restore the devices of a scene to their captured state; the result lists the devices that were restored, unchanged or failed"),
+		mcp.WithString("scene_id",
+			mcp.Required(),
+			mcp.Description("The ID of the scene, as listed by the homelab://scenes resource"),
+		),
+	)
+	s.AddTool(activateSceneTool, instrumentTool("activate_scene", ActivateSceneHandler))
+
+	scheduleCommandTool := mcp.NewTool(
+		"schedule_command",
+		mcp.WithDescription("Schedule a command on a Home Assistant device: once at a time, once after a delay (e.g. turn off the porch light in 30 minutes), or repeatedly on a cron expression. Give exactly one of at, delay and cron"),
+		mcp.WithString("device_id",
+			mcp.Required(),
+			mcp.Description("The unique identifier of the target device"),
+		),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("The action to perform (e.g. turn_on, turn_off, set_brightness)"),
+		),
+		mcp.WithObject("parameters",
+			mcp.Description("Optional parameters for the action"),
+		),
+		mcp.WithString("at",
+			mcp.Description("Run once at this RFC 3339 time, e.g. 2026-05-01T22:30:00+02:00"),
+		),
+		mcp.WithString("delay",
+			mcp.Description("Run once after this duration, e.g. 30m or 1h30m"),
+		),
+		mcp.WithString("cron",
+			mcp.Description("Run on this five-field cron expression (minute hour day-of-month month day-of-week), e.g. 0 22 * * MON-FRI"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone of the cron expression, e.g. Europe/Berlin; defaults to the server's"),
+		),
+		mcp.WithString("missed_run_policy",
+			mcp.Description("What to do with a run missed while the server was down"),
+			mcp.Enum(scheduler.PolicyRunOnce, scheduler.PolicySkip),
+		),
+		mcp.WithString("name",
+			mcp.Description("Optional name of the schedule"),
+		),
+	)
+	s.AddTool(scheduleCommandTool, instrumentTool("schedule_command", ScheduleCommandHandler))
+
+	listSchedulesTool := mcp.NewTool(
+		"list_schedules",
+		mcp.WithDescription("List the scheduled device commands with their next run and the outcome of their last run"),
+	)
+	s.AddTool(listSchedulesTool, instrumentTool("list_schedules", ListSchedulesHandler))
+
+	cancelScheduleTool := mcp.NewTool(
+		"cancel_schedule",
+		mcp.WithDescription("Cancel a scheduled device command so that it does not run again"),
+		mcp.WithString("schedule_id",
+			mcp.Required(),
+			mcp.Description("The ID of the schedule, as returned by schedule_command or list_schedules"),
+		),
+	)
+	s.AddTool(cancelScheduleTool, instrumentTool("cancel_schedule", CancelScheduleHandler))
 }
 
 // registerPrompts registers the device_control and service_status prompt templates.
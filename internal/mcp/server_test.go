@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"go-github/internal/config"
+	"go-github/internal/services"
+
 	mcpclient "github.com/mark3labs/mcp-go/client"
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
@@ -75,8 +78,8 @@ func TestInitializeHandshake_ServerName(t *testing.T) {
 	assert.NotNil(t, result.Capabilities.Prompts)
 }
 
-// TestResourcesList_ReturnsFourResources verifies resources/list returns exactly 4 resources.
-func TestResourcesList_ReturnsFourResources(t *testing.T) {
+// TestResourcesList_ReturnsFiveResources verifies resources/list returns exactly 5 resources.
+func TestResourcesList_ReturnsFiveResources(t *testing.T) {
 	ctx := context.Background()
 	c, cleanup := newTestClient(t)
 	defer cleanup()
@@ -84,7 +87,7 @@ func TestResourcesList_ReturnsFourResources(t *testing.T) {
 	result, err := c.ListResources(ctx, mcpgo.ListResourcesRequest{})
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	assert.Len(t, result.Resources, 4)
+	assert.Len(t, result.Resources, 5)
 
 	uris := make([]string, len(result.Resources))
 	for i, r := range result.Resources {
@@ -94,6 +97,37 @@ func TestResourcesList_ReturnsFourResources(t *testing.T) {
 	assert.Contains(t, uris, "homelab://services")
 	assert.Contains(t, uris, "homelab://cluster/services")
 	assert.Contains(t, uris, "homelab://health")
+	assert.Contains(t, uris, "homelab://scenes")
+}
+
+// TestResourceTemplates_ReadPages verifies the list resources can be read
+// page by page through their URI templates.
+func TestResourceTemplates_ReadPages(t *testing.T) {
+	ctx := context.Background()
+	c, cleanup := newTestClient(t)
+	defer cleanup()
+
+	templates, err := c.ListResourceTemplates(ctx, mcpgo.ListResourceTemplatesRequest{})
+	require.NoError(t, err)
+	uris := make([]string, len(templates.ResourceTemplates))
+	for i, rt := range templates.ResourceTemplates {
+		uris[i] = rt.URITemplate.Raw()
+	}
+	assert.ElementsMatch(t, []string{
+		"homelab://devices{?limit,cursor,sort,fields}",
+		"homelab://services{?limit,cursor,sort,fields}",
+		"homelab://cluster/services{?limit,cursor,sort,fields}",
+		"homelab://scenes{?limit,cursor,sort,fields}",
+	}, uris)
+
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = "homelab://services?limit=2&sort=name"
+	result, err := c.ReadResource(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	text := result.Contents[0].(mcpgo.TextResourceContents).Text
+	assert.Contains(t, text, `"items":[{"name":"alertmanager"`)
+	assert.Contains(t, text, `"next_cursor":"`)
 }
 
 // TestToolsList_ContainsExecuteCommand verifies execute_command tool is registered.
@@ -118,6 +152,71 @@ func TestToolsList_ContainsExecuteCommand(t *testing.T) {
 	assert.Contains(t, found.InputSchema.Required, "action")
 }
 
+// TestToolsList_ContainsExecuteBatch verifies execute_batch tool is registered.
+func TestToolsList_ContainsExecuteBatch(t *testing.T) {
+	ctx := context.Background()
+	c, cleanup := newTestClient(t)
+	defer cleanup()
+
+	result, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
+	require.NoError(t, err)
+
+	var found *mcpgo.Tool
+	for i := range result.Tools {
+		if result.Tools[i].Name == "execute_batch" {
+			found = &result.Tools[i]
+			break
+		}
+	}
+	require.NotNil(t, found, "execute_batch tool should be registered")
+	assert.Equal(t, []string{"commands"}, found.InputSchema.Required)
+	assert.Contains(t, found.InputSchema.Properties, "atomic")
+}
+
+// TestToolsList_ContainsActivateScene verifies activate_scene tool is registered.
+func TestToolsList_ContainsActivateScene(t *testing.T) {
+	ctx := context.Background()
+	c, cleanup := newTestClient(t)
+	defer cleanup()
+
+	result, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
+	require.NoError(t, err)
+
+	var found *mcpgo.Tool
+	for i := range result.Tools {
+		if result.Tools[i].Name == "activate_scene" {
+			found = &result.Tools[i]
+			break
+		}
+	}
+	require.NotNil(t, found, "activate_scene tool should be registered")
+	assert.Equal(t, []string{"scene_id"}, found.InputSchema.Required)
+}
+
+// TestToolsList_ContainsScheduleTools verifies the schedule_command,
+// list_schedules and cancel_schedule tools are registered.
+func TestToolsList_ContainsScheduleTools(t *testing.T) {
+	ctx := context.Background()
+	c, cleanup := newTestClient(t)
+	defer cleanup()
+
+	result, err := c.ListTools(ctx, mcpgo.ListToolsRequest{})
+	require.NoError(t, err)
+
+	tools := map[string]mcpgo.Tool{}
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool
+	}
+	require.Contains(t, tools, "schedule_command")
+	assert.Equal(t, []string{"device_id", "action"}, tools["schedule_command"].InputSchema.Required)
+	for _, p := range []string{"at", "delay", "cron", "timezone", "missed_run_policy"} {
+		assert.Contains(t, tools["schedule_command"].InputSchema.Properties, p)
+	}
+	require.Contains(t, tools, "list_schedules")
+	require.Contains(t, tools, "cancel_schedule")
+	assert.Equal(t, []string{"schedule_id"}, tools["cancel_schedule"].InputSchema.Required)
+}
+
 // TestPromptsList_ContainsBothPrompts verifies both prompt templates are registered.
 func TestPromptsList_ContainsBothPrompts(t *testing.T) {
 	ctx := context.Background()
@@ -190,3 +289,25 @@ func TestGetPrompt_UnknownPromptReturnsError(t *testing.T) {
 	_, err := c.GetPrompt(ctx, req)
 	assert.Error(t, err, "requesting a nonexistent prompt should return an error")
 }
+
+// TestNewMCPServer_WithConfigInstallsCatalog verifies the configured service
+// catalog is what homelab://services returns.
+func TestNewMCPServer_WithConfigInstallsCatalog(t *testing.T) {
+	t.Cleanup(func() { services.SetCatalog(nil) })
+
+	cfg := config.Default()
+	cfg.Services.Catalog = []config.ServiceEntry{{Name: "jellyfin", Type: "media", Endpoint: "http://jellyfin.local:8096"}}
+	s := NewMCPServer(WithConfig(cfg))
+	require.NotNil(t, s)
+
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = "homelab://services"
+	contents, err := ServicesResourceHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+
+	tc, ok := contents[0].(mcpgo.TextResourceContents)
+	require.True(t, ok)
+	assert.Contains(t, tc.Text, "jellyfin")
+	assert.NotContains(t, tc.Text, "grafana")
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 
 	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+	"go-github/internal/scheduler"
 )
 
 // ExecuteCommandHandler handles the execute_command MCP tool call.
 // It extracts device_id and action from the request arguments, executes the
 // command via the homeassistant package, and returns a structured result.
-func ExecuteCommandHandler(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func ExecuteCommandHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
 
 	deviceID, _ := args["device_id"].(string)
@@ -38,12 +40,12 @@ func ExecuteCommandHandler(_ context.Context, req mcp.CallToolRequest) (*mcp.Cal
 		params = map[string]interface{}{}
 	}
 
-	cmd := homeassistant.Command{
+	cmd := models.Command{
 		Action:     action,
 		Parameters: params,
 	}
 
-	result, err := homeassistant.ExecuteCommand(deviceID, cmd)
+	result, err := homeassistant.ExecuteCommandContext(ctx, deviceID, cmd)
 	if err != nil {
 		switch {
 		case errors.Is(err, homeassistant.ErrDeviceNotFound):
@@ -63,3 +65,117 @@ func ExecuteCommandHandler(_ context.Context, req mcp.CallToolRequest) (*mcp.Cal
 
 	return mcp.NewToolResultText(string(data)), nil
 }
+
+// ExecuteBatchHandler handles the execute_batch MCP tool call. It runs the
+// commands of the request like POST /api/v1/homeassistant/commands:batch and
+// returns the per-command results. Failed commands do not make the call an
+// error; only an invalid batch does.
+func ExecuteBatchHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	raw, err := json.Marshal(req.GetArguments())
+	if err != nil {
+		return mcp.NewToolResultError("invalid arguments: " + err.Error()), nil
+	}
+	var batch models.BatchRequest
+	if err := json.Unmarshal(raw, &batch); err != nil {
+		return mcp.NewToolResultError("invalid arguments: " + err.Error()), nil
+	}
+	// Parameters are optional here, as they are for execute_command.
+	for i := range batch.Commands {
+		if batch.Commands[i].Parameters == nil {
+			batch.Commands[i].Parameters = map[string]interface{}{}
+		}
+	}
+	if problems := homeassistant.ValidateBatch(&batch); len(problems) > 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid batch: %s: %s", problems[0].Pointer, problems[0].Detail)), nil
+	}
+
+	data, err := json.Marshal(homeassistant.ExecuteBatch(ctx, batch))
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal result: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// ActivateSceneHandler handles the activate_scene MCP tool call. It restores
+// the devices of the scene to their captured state and returns the outcome of
+// each device, including the ones that failed, as JSON.
+func ActivateSceneHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sceneID, _ := req.GetArguments()["scene_id"].(string)
+	if sceneID == "" {
+		return mcp.NewToolResultError("scene_id is required"), nil
+	}
+
+	result, err := homeassistant.ActivateScene(ctx, sceneID)
+	if err != nil {
+		if errors.Is(err, homeassistant.ErrSceneNotFound) {
+			return mcp.NewToolResultError(fmt.Sprintf("scene not found: %s", sceneID)), nil
+		}
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal result: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// ScheduleCommandHandler handles the schedule_command MCP tool call. It
+// schedules a device command like POST /api/v1/schedules and returns the new
+// schedule as JSON.
+func ScheduleCommandHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	raw, err := json.Marshal(req.GetArguments())
+	if err != nil {
+		return mcp.NewToolResultError("invalid arguments: " + err.Error()), nil
+	}
+	var r models.ScheduleRequest
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return mcp.NewToolResultError("invalid arguments: " + err.Error()), nil
+	}
+	if problems := scheduler.ValidateSchedule(&r); len(problems) > 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid schedule: %s: %s", problems[0].Pointer, problems[0].Detail)), nil
+	}
+
+	sched, err := scheduler.Default().Create(r)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	data, err := json.Marshal(sched)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal result: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// ListSchedulesHandler handles the list_schedules MCP tool call. It returns
+// every schedule, ordered by ID, as JSON.
+func ListSchedulesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(models.ScheduleListResponse{Schedules: scheduler.Default().List()})
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal result: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// CancelScheduleHandler handles the cancel_schedule MCP tool call. It cancels
+// the schedule and returns it, as it was before it was cancelled, as JSON.
+func CancelScheduleHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	scheduleID, _ := req.GetArguments()["schedule_id"].(string)
+	if scheduleID == "" {
+		return mcp.NewToolResultError("schedule_id is required"), nil
+	}
+
+	sched, err := scheduler.Default().Cancel(scheduleID)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrNotFound) {
+			return mcp.NewToolResultError(fmt.Sprintf("schedule not found: %s", scheduleID)), nil
+		}
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	data, err := json.Marshal(sched)
+	if err != nil {
+		return mcp.NewToolResultError("failed to marshal result: " + err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -6,6 +6,10 @@ import (
 	"sync"
 	"testing"
 
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+	"go-github/internal/scheduler"
+
 	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -151,3 +155,163 @@ func TestExecuteCommandHandler_ConcurrentRace(t *testing.T) {
 		assert.NotNil(t, results[i], "goroutine %d should return a non-nil result", i)
 	}
 }
+
+func TestExecuteBatchHandler(t *testing.T) {
+	req := mcpgo.CallToolRequest{}
+	req.Params.Name = "execute_batch"
+	req.Params.Arguments = map[string]interface{}{
+		"commands": []interface{}{
+			map[string]interface{}{"device_id": "device-001", "action": "turn_on"},
+			map[string]interface{}{"device_id": "bad-device-id", "action": "turn_on", "parameters": map[string]interface{}{}},
+		},
+	}
+
+	result, err := ExecuteBatchHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, "failed commands are reported per item")
+
+	tc, ok := result.Content[0].(mcpgo.TextContent)
+	require.True(t, ok)
+	var payload struct {
+		Results []struct {
+			Status int    `json:"status"`
+			Error  string `json:"error"`
+		} `json:"results"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(tc.Text), &payload))
+	assert.Equal(t, 1, payload.Succeeded)
+	assert.Equal(t, 1, payload.Failed)
+	require.Len(t, payload.Results, 2)
+	assert.Equal(t, 200, payload.Results[0].Status)
+	assert.Equal(t, "not_found", payload.Results[1].Error)
+}
+
+func TestExecuteBatchHandler_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"no commands", map[string]interface{}{}, "invalid batch: /commands: at least one command is required"},
+		{"missing action", map[string]interface{}{
+			"commands": []interface{}{map[string]interface{}{"device_id": "device-001"}},
+		}, "invalid batch: /commands/0/action"},
+		{"wrong type", map[string]interface{}{"commands": "turn_on"}, "invalid arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcpgo.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := ExecuteBatchHandler(context.Background(), req)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			tc, ok := result.Content[0].(mcpgo.TextContent)
+			require.True(t, ok)
+			assert.Contains(t, tc.Text, tt.want)
+		})
+	}
+}
+
+func TestActivateSceneHandler(t *testing.T) {
+	_, err := homeassistant.CreateScene(models.SceneRequest{ID: "mcp_test_scene", Name: "MCP test", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = homeassistant.DeleteScene("mcp_test_scene") })
+
+	req := mcpgo.CallToolRequest{}
+	req.Params.Name = "activate_scene"
+	req.Params.Arguments = map[string]interface{}{"scene_id": "mcp_test_scene"}
+	result, err := ActivateSceneHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	tc, ok := result.Content[0].(mcpgo.TextContent)
+	require.True(t, ok)
+	var activation models.SceneActivation
+	require.NoError(t, json.Unmarshal([]byte(tc.Text), &activation))
+	assert.Equal(t, "mcp_test_scene", activation.SceneID)
+	require.Len(t, activation.Results, 1)
+	assert.Equal(t, homeassistant.SceneDeviceUnchanged, activation.Results[0].Status, "nothing changed since the capture")
+
+	for _, args := range []map[string]interface{}{{}, {"scene_id": "no_such_scene"}} {
+		req.Params.Arguments = args
+		result, err = ActivateSceneHandler(context.Background(), req)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "args %v", args)
+	}
+}
+
+func TestScheduleTools(t *testing.T) {
+	// A scheduler that is not running, so nothing is executed.
+	scheduler.SetDefault(scheduler.New())
+	t.Cleanup(func() { scheduler.SetDefault(nil) })
+
+	text := func(result *mcpgo.CallToolResult) string {
+		t.Helper()
+		tc, ok := result.Content[0].(mcpgo.TextContent)
+		require.True(t, ok)
+		return tc.Text
+	}
+
+	req := mcpgo.CallToolRequest{}
+	req.Params.Name = "schedule_command"
+	req.Params.Arguments = map[string]interface{}{
+		"device_id":  "device-001",
+		"action":     "turn_off",
+		"parameters": map[string]interface{}{"transition": 2},
+		"delay":      "30m",
+	}
+	result, err := ScheduleCommandHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	var sched models.Schedule
+	require.NoError(t, json.Unmarshal([]byte(text(result)), &sched))
+	assert.Equal(t, scheduler.KindOnce, sched.Kind)
+	assert.Equal(t, float64(2), sched.Parameters["transition"])
+
+	req.Params.Name = "list_schedules"
+	req.Params.Arguments = nil
+	result, err = ListSchedulesHandler(context.Background(), req)
+	require.NoError(t, err)
+	var list models.ScheduleListResponse
+	require.NoError(t, json.Unmarshal([]byte(text(result)), &list))
+	require.Len(t, list.Schedules, 1)
+	assert.Equal(t, sched.ID, list.Schedules[0].ID)
+
+	req.Params.Name = "cancel_schedule"
+	req.Params.Arguments = map[string]interface{}{"schedule_id": sched.ID}
+	result, err = CancelScheduleHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError, text(result))
+	assert.Empty(t, scheduler.Default().List())
+
+	result, err = CancelScheduleHandler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "schedule not found: "+sched.ID, text(result))
+}
+
+func TestScheduleCommandHandler_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"no time", map[string]interface{}{"device_id": "device-001", "action": "turn_on"}, "invalid schedule: /at: one of at, delay and cron is required"},
+		{"bad delay", map[string]interface{}{"device_id": "device-001", "action": "turn_on", "delay": "soon"}, "invalid schedule: /delay: delay must be a positive duration such as 30m or 1h30m"},
+		{"bad at", map[string]interface{}{"device_id": "device-001", "action": "turn_on", "at": "tonight"}, "invalid arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcpgo.CallToolRequest{}
+			req.Params.Name = "schedule_command"
+			req.Params.Arguments = tt.args
+			result, err := ScheduleCommandHandler(context.Background(), req)
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			tc, ok := result.Content[0].(mcpgo.TextContent)
+			require.True(t, ok)
+			assert.Contains(t, tc.Text, tt.want)
+		})
+	}
+}
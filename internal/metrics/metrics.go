@@ -0,0 +1,168 @@
+// Package metrics defines the Prometheus collectors exported by the homelab
+// API on /metrics. Collectors are registered on a dedicated Registry rather
+// than the global default so that tests and embedders get a predictable set.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "homelab"
+
+// Outcome label values shared by the domain counters.
+const (
+	OutcomeSuccess         = "success"
+	OutcomeError           = "error"
+	OutcomeNotFound        = "not_found"
+	OutcomeNotControllable = "not_controllable"
+)
+
+// Registry holds every collector exposed on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal counts completed HTTP requests.
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total HTTP requests by route template, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration observes HTTP request latency.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// CommandsTotal counts device commands by device, action and outcome.
+	CommandsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "homeassistant",
+		Name:      "commands_total",
+		Help:      "Device commands executed by device, action and outcome.",
+	}, []string{"device_id", "action", "outcome"})
+
+	// MCPToolCallsTotal counts MCP tool invocations.
+	MCPToolCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mcp",
+		Name:      "tool_calls_total",
+		Help:      "MCP tool calls by tool name and outcome.",
+	}, []string{"tool", "outcome"})
+
+	// MCPResourceReadsTotal counts MCP resource reads.
+	MCPResourceReadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mcp",
+		Name:      "resource_reads_total",
+		Help:      "MCP resource reads by resource URI and outcome.",
+	}, []string{"uri", "outcome"})
+
+	// ServiceProbesTotal counts service probe results.
+	ServiceProbesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "services",
+		Name:      "probes_total",
+		Help:      "Service probes by service name and result (up or down).",
+	}, []string{"service", "result"})
+
+	// ServiceUp reports the result of the most recent probe (1 up, 0 down).
+	ServiceUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "services",
+		Name:      "up",
+		Help:      "Whether the last probe of a service succeeded.",
+	}, []string{"service"})
+
+	// RateLimitRejectionsTotal counts requests rejected by the rate limiter.
+	RateLimitRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected with 429 by route template.",
+	}, []string{"route"})
+
+	// SchedulerRunsTotal counts runs of scheduled commands.
+	SchedulerRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "runs_total",
+		Help:      "Scheduled command runs by status (success, failed or skipped).",
+	}, []string{"status"})
+
+	// AutomationRunsTotal counts runs of automations.
+	AutomationRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "automations",
+		Name:      "runs_total",
+		Help:      "Automation runs by status (success, failed, conditions_not_met or loop_blocked).",
+	}, []string{"status"})
+
+	// EventsPublishedTotal counts events published on the event bus.
+	EventsPublishedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "published_total",
+		Help:      "Events published on the event bus by topic.",
+	}, []string{"topic"})
+
+	// EventsDroppedTotal counts events a subscriber dropped because its
+	// buffer was full.
+	EventsDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "dropped_total",
+		Help:      "Events dropped because the subscriber's buffer was full, by topic and subscriber.",
+	}, []string{"topic", "subscriber"})
+
+	// EventSubscribers is the number of subscriptions to each topic.
+	EventSubscribers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "subscribers",
+		Help:      "Subscriptions to the event bus by topic.",
+	}, []string{"topic"})
+
+	// ConfigReloadsTotal counts configuration reload attempts.
+	ConfigReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "config",
+		Name:      "reloads_total",
+		Help:      "Configuration reloads by outcome (success or error).",
+	}, []string{"outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		CommandsTotal,
+		MCPToolCallsTotal,
+		MCPResourceReadsTotal,
+		ServiceProbesTotal,
+		ServiceUp,
+		RateLimitRejectionsTotal,
+		SchedulerRunsTotal,
+		AutomationRunsTotal,
+		EventsPublishedTotal,
+		EventsDroppedTotal,
+		EventSubscribers,
+		ConfigReloadsTotal,
+	)
+}
+
+// Handler returns the HTTP handler that serves Registry in the Prometheus
+// exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
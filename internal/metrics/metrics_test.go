@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ExposesRegisteredMetrics(t *testing.T) {
+	HTTPRequestsTotal.WithLabelValues("/health", "GET", "200").Inc()
+	CommandsTotal.WithLabelValues("device-001", "turn_on", OutcomeSuccess).Inc()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `homelab_http_requests_total{method="GET",route="/health",status="200"}`)
+	assert.Contains(t, body, `homelab_homeassistant_commands_total{action="turn_on",device_id="device-001",outcome="success"}`)
+	assert.Contains(t, body, "go_goroutines", "runtime collectors should be registered")
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the header that may carry an API key instead of
+// "Authorization: Bearer <key>".
+const APIKeyHeader = "X-API-Key"
+
+// APIKey is a named credential accepted by APIKeyAuth.
+type APIKey struct {
+	Name string
+	Key  string
+}
+
+// ClientCertPrincipal returns a gin.HandlerFunc middleware that stores the
+// subject of a verified TLS client certificate under PrincipalKey, so that
+// mutual TLS clients are authenticated without an API key.
+func ClientCertPrincipal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if state := c.Request.TLS; state != nil && len(state.VerifiedChains) > 0 {
+			c.Set(PrincipalKey, state.VerifiedChains[0][0].Subject.String())
+		}
+		c.Next()
+	}
+}
+
+// APIKeyAuth returns a gin.HandlerFunc middleware that requires one of keys in
+// the Authorization (Bearer) or X-API-Key header and stores the matching key's
+// name under PrincipalKey. Requests already authenticated by a verified client
+// certificate (see ClientCertPrincipal) are let through. Requests without a
+// valid key are rejected with 401. An empty key list disables authentication.
+func APIKeyAuth(keys []APIKey) gin.HandlerFunc {
+	if len(keys) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if c.GetString(PrincipalKey) != "" {
+			c.Next()
+			return
+		}
+
+		presented := presentedKey(c.Request)
+		if presented == "" {
+			abortUnauthorized(c, "missing API key")
+			return
+		}
+
+		// Compare against every key so the response time doesn't reveal
+		// which key (if any) matched.
+		var principal string
+		for _, k := range keys {
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(k.Key)) == 1 {
+				principal = k.Name
+			}
+		}
+		if principal == "" {
+			abortUnauthorized(c, "invalid API key")
+			return
+		}
+
+		c.Set(PrincipalKey, principal)
+		c.Next()
+	}
+}
+
+// presentedKey extracts the API key from the request headers.
+func presentedKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="homelab-api"`)
+	abortWithError(c, http.StatusUnauthorized, "unauthorized", message)
+}
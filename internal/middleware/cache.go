@@ -0,0 +1,14 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// CacheControl returns a gin.HandlerFunc middleware that sends directives as
+// the Cache-Control header of a route's responses, for example "no-cache" for
+// data clients should revalidate with its ETag on every use. Error responses
+// drop the header (see handlers.JSONError).
+func CacheControl(directives string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", directives)
+		c.Next()
+	}
+}
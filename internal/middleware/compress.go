@@ -0,0 +1,299 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultCompressMinSize is the default CompressConfig.MinSize. Below about
+// a kilobyte the encoding overhead outweighs the bytes saved.
+const DefaultCompressMinSize = 1024
+
+// Content codings offered by Compress, in order of preference when the
+// client accepts several equally. For JSON, zstd is faster than gzip and
+// compresses better, and brotli compresses best at some CPU cost; see
+// research/compress_benchmark_test.go.
+const (
+	EncodingZstd   = "zstd"
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+var encodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+
+// encoder is the interface shared by the gzip, brotli and zstd writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// encoderPools reuse encoders across responses; their internal buffers are
+// the bulk of the cost of compressing a small body.
+var encoderPools = map[string]*sync.Pool{
+	EncodingZstd: {New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault),
+			zstd.WithEncoderConcurrency(1), zstd.WithLowerEncoderMem(true))
+		return encoder(enc)
+	}},
+	EncodingBrotli: {New: func() any {
+		return encoder(brotli.NewWriterLevel(nil, 4))
+	}},
+	EncodingGzip: {New: func() any {
+		enc, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return encoder(enc)
+	}},
+}
+
+// CompressConfig configures CompressWithConfig.
+type CompressConfig struct {
+	// MinSize is the smallest response body, in bytes, that is compressed.
+	// Zero means DefaultCompressMinSize.
+	MinSize int
+}
+
+// Compress returns a gin.HandlerFunc middleware that compresses responses
+// with the default settings. See CompressWithConfig.
+func Compress() gin.HandlerFunc {
+	return CompressWithConfig(CompressConfig{})
+}
+
+// CompressWithConfig returns a gin.HandlerFunc middleware that compresses
+// response bodies with zstd, brotli or gzip, whichever the Accept-Encoding
+// header ranks highest. The body is held back until it reaches MinSize, so
+// small responses, like most errors, go out as they are.
+//
+// Responses that already have a Content-Encoding, carry media that is
+// compressed already (images, archives) or stream (text/event-stream, or a
+// handler that flushes before MinSize) are not compressed. A strong ETag on
+// a compressed response becomes weak, since the bytes on the wire differ
+// from the ones it was computed from.
+func CompressWithConfig(cfg CompressConfig) gin.HandlerFunc {
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		original := c.Writer
+		w := &compressWriter{
+			ResponseWriter: original,
+			encoding:       negotiateEncoding(c.GetHeader("Accept-Encoding")),
+			minSize:        minSize,
+		}
+		c.Writer = w
+		defer func() {
+			w.close()
+			c.Writer = original
+		}()
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the offered content coding with the highest
+// q-value in an Accept-Encoding header, or "" if none is acceptable.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+	explicit := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if name == "*" {
+			wildcard = q
+		} else {
+			explicit[name] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range encodings {
+		q, ok := explicit[enc]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a response until it can tell whether
+// to compress it, then either streams it through an encoder or passes it on.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buf     []byte
+	started bool
+	enc     encoder
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		if w.encoding != "" && len(w.buf)+len(data) < w.minSize {
+			w.buf = append(w.buf, data...)
+			return len(data), nil
+		}
+		w.start(true)
+		if len(w.buf) > 0 {
+			if _, err := w.write(w.buf); err != nil {
+				return 0, err
+			}
+			w.buf = nil
+		}
+	}
+	return w.write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) write(data []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteHeaderNow sends the headers, so the body can no longer be compressed.
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.started {
+		w.start(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends what was written so far. A response flushed before it reached
+// MinSize is treated as a stream and not compressed.
+func (w *compressWriter) Flush() {
+	if !w.started {
+		w.start(false)
+		if len(w.buf) > 0 {
+			_, _ = w.ResponseWriter.Write(w.buf)
+			w.buf = nil
+		}
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Written reports whether the handler wrote anything, including a body that
+// is still held back.
+func (w *compressWriter) Written() bool {
+	return w.started || len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// start settles whether the response is compressed: if compress is true and
+// the response allows it.
+func (w *compressWriter) start(compress bool) {
+	w.started = true
+	h := w.Header()
+	if !varies(h, "Accept-Encoding") {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	if !compress || w.encoding == "" || !w.compressible() {
+		return
+	}
+
+	h.Set("Content-Encoding", w.encoding)
+	h.Del("Content-Length")
+	if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		h.Set("ETag", "W/"+etag)
+	}
+	w.enc = encoderPools[w.encoding].Get().(encoder)
+	w.enc.Reset(w.ResponseWriter)
+}
+
+// compressible reports whether the response may be compressed.
+func (w *compressWriter) compressible() bool {
+	switch status := w.Status(); {
+	case status < http.StatusOK, status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, _ := strings.Cut(h.Get("Content-Type"), ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case mediaType == "text/event-stream",
+		strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml",
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		mediaType == "application/zip",
+		mediaType == "application/gzip",
+		mediaType == "application/zstd",
+		mediaType == "application/x-brotli":
+		return false
+	}
+	return true
+}
+
+// close finishes the response: it sends a body that stayed below MinSize as
+// is, or completes the compressed stream.
+func (w *compressWriter) close() {
+	if !w.started {
+		if len(w.buf) == 0 && !w.ResponseWriter.Written() {
+			// Nothing was written, e.g. a hijacked connection; leave the
+			// response to gin.
+			return
+		}
+		w.start(false)
+		if len(w.buf) > 0 {
+			_, _ = w.ResponseWriter.Write(w.buf)
+			w.buf = nil
+		}
+		return
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(nil)
+		encoderPools[w.encoding].Put(w.enc)
+		w.enc = nil
+	}
+}
+
+// varies reports whether the Vary header lists field.
+func varies(h http.Header, field string) bool {
+	for _, v := range h.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(f), field) {
+				return true
+			}
+		}
+	}
+	return false
+}
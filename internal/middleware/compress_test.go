@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", EncodingGzip},
+		{"gzip, deflate, br", EncodingBrotli},
+		{"gzip, deflate, br, zstd", EncodingZstd},
+		{"GZIP", EncodingGzip},
+		{"zstd;q=0.5, gzip", EncodingGzip},
+		{"br;q=0.8, gzip;q=0.8", EncodingBrotli},
+		{"gzip;q=0", ""},
+		{"*", EncodingZstd},
+		{"*, zstd;q=0", EncodingBrotli},
+		{"*;q=0, gzip", EncodingGzip},
+		{"gzip;q=bogus, br", EncodingBrotli},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateEncoding(tt.header))
+		})
+	}
+}
+
+// decompress decodes body according to encoding.
+func decompress(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case EncodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		r = gr
+	case EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case EncodingZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	default:
+		return string(body)
+	}
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat(`{"id":"light.kitchen","state":"on"},`, 100)
+
+	router := gin.New()
+	router.Use(Compress())
+	router.GET("/large", func(c *gin.Context) {
+		c.Header("ETag", `"abc"`)
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+	router.GET("/chunked", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain")
+		c.Status(http.StatusOK)
+		for i := 0; i < 10; i++ {
+			_, _ = c.Writer.WriteString(large[:200])
+		}
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/events", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/event-stream", []byte(large))
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(large))
+	})
+	router.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+	router.GET("/not-modified", func(c *gin.Context) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+	})
+	router.GET("/flushed", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain")
+		_, _ = c.Writer.WriteString("first")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString(large)
+	})
+
+	request := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, encoding := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			w := request("/large", encoding)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Empty(t, w.Header().Get("Content-Length"))
+			assert.Equal(t, `W/"abc"`, w.Header().Get("ETag"))
+			assert.Less(t, w.Body.Len(), len(large))
+			assert.Equal(t, large, decompress(t, encoding, w.Body.Bytes()))
+		})
+	}
+
+	t.Run("several writes", func(t *testing.T) {
+		w := request("/chunked", "gzip")
+		assert.Equal(t, EncodingGzip, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, strings.Repeat(large[:200], 10), decompress(t, EncodingGzip, w.Body.Bytes()))
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		w := request("/large", "")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+		assert.Equal(t, large, w.Body.String())
+	})
+
+	t.Run("below the minimum size", func(t *testing.T) {
+		w := request("/small", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	})
+
+	skipped := []struct {
+		name, path string
+		code       int
+	}{
+		{"event stream", "/events", http.StatusOK},
+		{"image", "/image", http.StatusOK},
+		{"not modified", "/not-modified", http.StatusNotModified},
+	}
+	for _, tt := range skipped {
+		t.Run(tt.name, func(t *testing.T) {
+			w := request(tt.path, "gzip, br, zstd")
+			assert.Equal(t, tt.code, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			if tt.code == http.StatusOK {
+				assert.Equal(t, large, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("already encoded", func(t *testing.T) {
+		w := request("/encoded", "zstd")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+	})
+
+	t.Run("flushed before the minimum size", func(t *testing.T) {
+		w := request("/flushed", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.True(t, w.Flushed)
+		assert.Equal(t, "first"+large, w.Body.String())
+	})
+
+	t.Run("head", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodHead, "/large", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		router.ServeHTTP(w, req)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+}
+
+func TestCompressWithConfig_MinSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CompressWithConfig(CompressConfig{MinSize: 10}))
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello, compressed world")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, EncodingBrotli, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "hello, compressed world", decompress(t, EncodingBrotli, w.Body.Bytes()))
+}
@@ -1,31 +1,22 @@
 package middleware
 
 import (
-	"os"
-	"strings"
+	"go-github/internal/config"
 
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	defaultCORSOrigins = "http://localhost:3000"
-)
-
-// CORS returns a gin.HandlerFunc middleware that adds CORS headers to responses.
-// It reads allowed origins from the CORS_ORIGINS environment variable (default: http://localhost:3000).
-// Multiple origins can be specified separated by commas.
+// CORS returns a gin.HandlerFunc middleware that adds CORS headers to responses
+// for the origins in cfg.
 // Allowed methods: GET, POST, OPTIONS
 // Allowed headers: Content-Type, Authorization
-func CORS() gin.HandlerFunc {
-	// Get allowed origins from environment or use default
-	originsStr := os.Getenv("CORS_ORIGINS")
-	if originsStr == "" {
-		originsStr = defaultCORSOrigins
-	}
-
-	// Parse origins into a slice
-	allowedOrigins := parseOrigins(originsStr)
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	return CORSWithOrigins(cfg.Origins)
+}
 
+// CORSWithOrigins returns a gin.HandlerFunc middleware that adds CORS headers
+// for the given allowed origins.
+func CORSWithOrigins(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
@@ -47,19 +38,6 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
-// parseOrigins splits a comma-separated string of origins into a slice
-func parseOrigins(originsStr string) []string {
-	origins := strings.Split(originsStr, ",")
-	var result []string
-	for _, origin := range origins {
-		trimmed := strings.TrimSpace(origin)
-		if trimmed != "" {
-			result = append(result, trimmed)
-		}
-	}
-	return result
-}
-
 // isOriginAllowed checks if the given origin is in the allowed list
 func isOriginAllowed(origin string, allowedOrigins []string) bool {
 	for _, allowed := range allowedOrigins {
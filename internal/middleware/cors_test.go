@@ -3,9 +3,10 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 
+	"go-github/internal/config"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -15,7 +16,7 @@ func TestCORS(t *testing.T) {
 
 	tests := []struct {
 		name           string
-		corsOrigins    string
+		corsOrigins    []string
 		requestOrigin  string
 		requestMethod  string
 		expectedStatus int
@@ -24,7 +25,7 @@ func TestCORS(t *testing.T) {
 	}{
 		{
 			name:           "default origin allowed",
-			corsOrigins:    "",
+			corsOrigins:    nil,
 			requestOrigin:  "http://localhost:3000",
 			requestMethod:  "GET",
 			expectedStatus: 200,
@@ -33,7 +34,7 @@ func TestCORS(t *testing.T) {
 		},
 		{
 			name:           "custom origin allowed",
-			corsOrigins:    "https://example.com",
+			corsOrigins:    []string{"https://example.com"},
 			requestOrigin:  "https://example.com",
 			requestMethod:  "GET",
 			expectedStatus: 200,
@@ -42,7 +43,7 @@ func TestCORS(t *testing.T) {
 		},
 		{
 			name:           "multiple origins - first allowed",
-			corsOrigins:    "https://example.com,https://app.example.com,http://localhost:8080",
+			corsOrigins:    []string{"https://example.com", "https://app.example.com", "http://localhost:8080"},
 			requestOrigin:  "https://example.com",
 			requestMethod:  "GET",
 			expectedStatus: 200,
@@ -51,7 +52,7 @@ func TestCORS(t *testing.T) {
 		},
 		{
 			name:           "multiple origins - middle allowed",
-			corsOrigins:    "https://example.com,https://app.example.com,http://localhost:8080",
+			corsOrigins:    []string{"https://example.com", "https://app.example.com", "http://localhost:8080"},
 			requestOrigin:  "https://app.example.com",
 			requestMethod:  "POST",
 			expectedStatus: 200,
@@ -60,7 +61,7 @@ func TestCORS(t *testing.T) {
 		},
 		{
 			name:           "multiple origins - last allowed",
-			corsOrigins:    "https://example.com,https://app.example.com,http://localhost:8080",
+			corsOrigins:    []string{"https://example.com", "https://app.example.com", "http://localhost:8080"},
 			requestOrigin:  "http://localhost:8080",
 			requestMethod:  "GET",
 			expectedStatus: 200,
@@ -69,7 +70,7 @@ func TestCORS(t *testing.T) {
 		},
 		{
 			name:           "forbidden origin",
-			corsOrigins:    "https://example.com",
+			corsOrigins:    []string{"https://example.com"},
 			requestOrigin:  "https://evil.com",
 			requestMethod:  "GET",
 			expectedStatus: 200,
@@ -78,37 +79,25 @@ func TestCORS(t *testing.T) {
 		},
 		{
 			name:           "no origin header",
-			corsOrigins:    "https://example.com",
+			corsOrigins:    []string{"https://example.com"},
 			requestOrigin:  "",
 			requestMethod:  "GET",
 			expectedStatus: 200,
 			expectCORS:     false,
 			wantOrigin:     "",
 		},
-		{
-			name:           "multiple origins with spaces",
-			corsOrigins:    "https://example.com, https://app.example.com , http://localhost:8080",
-			requestOrigin:  "https://app.example.com",
-			requestMethod:  "GET",
-			expectedStatus: 200,
-			expectCORS:     true,
-			wantOrigin:     "https://app.example.com",
-		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Set environment variable
-			if tt.corsOrigins != "" {
-				os.Setenv("CORS_ORIGINS", tt.corsOrigins)
-			} else {
-				os.Unsetenv("CORS_ORIGINS")
+			cfg := config.Default().CORS
+			if tt.corsOrigins != nil {
+				cfg.Origins = tt.corsOrigins
 			}
-			defer os.Unsetenv("CORS_ORIGINS")
 
 			// Create router with CORS middleware
 			router := gin.New()
-			router.Use(CORS())
+			router.Use(CORS(cfg))
 
 			router.GET("/test", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "ok"})
@@ -148,7 +137,7 @@ func TestCORS_PreflightRequest(t *testing.T) {
 
 	tests := []struct {
 		name           string
-		corsOrigins    string
+		corsOrigins    []string
 		requestOrigin  string
 		expectCORS     bool
 		wantOrigin     string
@@ -156,7 +145,7 @@ func TestCORS_PreflightRequest(t *testing.T) {
 	}{
 		{
 			name:           "preflight with allowed origin",
-			corsOrigins:    "https://example.com",
+			corsOrigins:    []string{"https://example.com"},
 			requestOrigin:  "https://example.com",
 			expectCORS:     true,
 			wantOrigin:     "https://example.com",
@@ -164,7 +153,7 @@ func TestCORS_PreflightRequest(t *testing.T) {
 		},
 		{
 			name:           "preflight with forbidden origin",
-			corsOrigins:    "https://example.com",
+			corsOrigins:    []string{"https://example.com"},
 			requestOrigin:  "https://evil.com",
 			expectCORS:     false,
 			wantOrigin:     "",
@@ -172,7 +161,7 @@ func TestCORS_PreflightRequest(t *testing.T) {
 		},
 		{
 			name:           "preflight with default origin",
-			corsOrigins:    "",
+			corsOrigins:    nil,
 			requestOrigin:  "http://localhost:3000",
 			expectCORS:     true,
 			wantOrigin:     "http://localhost:3000",
@@ -182,17 +171,14 @@ func TestCORS_PreflightRequest(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Set environment variable
-			if tt.corsOrigins != "" {
-				os.Setenv("CORS_ORIGINS", tt.corsOrigins)
-			} else {
-				os.Unsetenv("CORS_ORIGINS")
+			cfg := config.Default().CORS
+			if tt.corsOrigins != nil {
+				cfg.Origins = tt.corsOrigins
 			}
-			defer os.Unsetenv("CORS_ORIGINS")
 
 			// Create router with CORS middleware
 			router := gin.New()
-			router.Use(CORS())
+			router.Use(CORS(cfg))
 
 			router.GET("/test", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "ok"})
@@ -228,11 +214,8 @@ func TestCORS_PreflightRequest(t *testing.T) {
 func TestCORS_CallsNext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	os.Setenv("CORS_ORIGINS", "https://example.com")
-	defer os.Unsetenv("CORS_ORIGINS")
-
 	router := gin.New()
-	router.Use(CORS())
+	router.Use(CORS(config.CORSConfig{Origins: []string{"https://example.com"}}))
 
 	handlerCalled := false
 	router.GET("/test", func(c *gin.Context) {
@@ -255,11 +238,8 @@ func TestCORS_CallsNext(t *testing.T) {
 func TestCORS_PreflightDoesNotCallNext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	os.Setenv("CORS_ORIGINS", "https://example.com")
-	defer os.Unsetenv("CORS_ORIGINS")
-
 	router := gin.New()
-	router.Use(CORS())
+	router.Use(CORS(config.CORSConfig{Origins: []string{"https://example.com"}}))
 
 	handlerCalled := false
 	router.OPTIONS("/test", func(c *gin.Context) {
@@ -283,11 +263,8 @@ func TestCORS_PreflightDoesNotCallNext(t *testing.T) {
 func TestCORS_PreflightForbiddenOriginCallsNext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	os.Setenv("CORS_ORIGINS", "https://example.com")
-	defer os.Unsetenv("CORS_ORIGINS")
-
 	router := gin.New()
-	router.Use(CORS())
+	router.Use(CORS(config.CORSConfig{Origins: []string{"https://example.com"}}))
 
 	handlerCalled := false
 	router.OPTIONS("/test", func(c *gin.Context) {
@@ -308,44 +285,25 @@ func TestCORS_PreflightForbiddenOriginCallsNext(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 }
 
-func TestParseOrigins(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected []string
-	}{
-		{
-			name:     "single origin",
-			input:    "https://example.com",
-			expected: []string{"https://example.com"},
-		},
-		{
-			name:     "multiple origins",
-			input:    "https://example.com,https://app.example.com,http://localhost:3000",
-			expected: []string{"https://example.com", "https://app.example.com", "http://localhost:3000"},
-		},
-		{
-			name:     "origins with spaces",
-			input:    "https://example.com, https://app.example.com , http://localhost:3000",
-			expected: []string{"https://example.com", "https://app.example.com", "http://localhost:3000"},
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: nil,
-		},
-		{
-			name:     "origin with trailing comma",
-			input:    "https://example.com,",
-			expected: []string{"https://example.com"},
-		},
-	}
+func TestCORSWithOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := parseOrigins(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
+	router := gin.New()
+	router.Use(CORSWithOrigins([]string{"https://config.example.com"}))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for origin, want := range map[string]string{
+		"https://config.example.com": "https://config.example.com",
+		"https://other.example.com":  "",
+	} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Header().Get("Access-Control-Allow-Origin"), origin)
 	}
 }
 
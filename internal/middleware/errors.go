@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"go-github/internal/handlers"
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError aborts the request with an error response written by
+// handlers.JSONError, so middleware errors are negotiated and logged like
+// handler errors.
+func abortWithError(c *gin.Context, code int, err, message string, fields ...models.FieldError) {
+	handlers.JSONError(c, code, err, message, fields...)
+	c.Abort()
+}
@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on replayed responses.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// DefaultIdempotencyTTL is how long responses are kept for replay.
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Errors returned by IdempotencyStore.Begin.
+var (
+	// ErrIdempotencyInFlight means a request with the key has not finished.
+	ErrIdempotencyInFlight = errors.New("request with this idempotency key is in progress")
+	// ErrIdempotencyKeyReused means the key was used for a different request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+)
+
+// replayedHeaders are the response headers stored and replayed along with the
+// status and body. Headers set by other middleware, like X-Request-ID, belong
+// to the request being answered, not the one that was stored.
+var replayedHeaders = []string{"Content-Type", "Content-Language", "Location"}
+
+// StoredResponse is a response kept for replay.
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore records requests by idempotency key. Implementations must
+// be safe for concurrent use.
+type IdempotencyStore interface {
+	// Begin claims key for a request whose body hashes to fingerprint. It
+	// returns nil if the caller should handle the request, and the stored
+	// response if an earlier request with the same key and fingerprint
+	// completed. It returns ErrIdempotencyInFlight while the key is claimed
+	// and ErrIdempotencyKeyReused if the fingerprint differs.
+	Begin(ctx context.Context, key, fingerprint string) (*StoredResponse, error)
+	// Complete stores the response to the request that claimed key.
+	Complete(ctx context.Context, key string, resp StoredResponse) error
+	// Release gives up a claim without storing a response, so the request
+	// can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// idempotencyEntry is a claimed key: in flight while resp is nil.
+type idempotencyEntry struct {
+	fingerprint string
+	resp        *StoredResponse
+	expires     time.Time
+}
+
+// MemoryIdempotencyStore is the in-memory IdempotencyStore. Keys are only
+// seen by the process that stored them.
+type MemoryIdempotencyStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	nextSweep time.Time
+}
+
+// NewIdempotencyStore returns an in-memory store that keeps responses for
+// ttl (DefaultIdempotencyTTL if ttl is not positive).
+func NewIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &MemoryIdempotencyStore{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]*idempotencyEntry{},
+	}
+}
+
+// Begin implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key, fingerprint string) (*StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		switch {
+		case e.fingerprint != fingerprint:
+			return nil, ErrIdempotencyKeyReused
+		case e.resp == nil:
+			return nil, ErrIdempotencyInFlight
+		}
+		return e.resp, nil
+	}
+	s.entries[key] = &idempotencyEntry{fingerprint: fingerprint, expires: now.Add(s.ttl)}
+	return nil, nil
+}
+
+// Complete implements IdempotencyStore. The TTL runs from completion.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, resp StoredResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok {
+		e.resp = &resp
+		e.expires = s.now().Add(s.ttl)
+	}
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep drops expired entries, at most once a minute. s.mu must be held.
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(time.Minute)
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// Idempotency returns a gin.HandlerFunc middleware that makes retries of a
+// request with the same Idempotency-Key header safe. The first response is
+// stored and replayed verbatim, with Idempotent-Replayed: true, to retries
+// with the same key and body. A key reused with a different body is answered
+// with 422, and a retry while the first request is still running with 409.
+//
+// Keys are scoped to the authenticated principal, method and path. Responses
+// with a 5xx status are not stored, so the request can be retried. Requests
+// without the header are handled as usual. If the store fails, the request
+// is handled without idempotency.
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, http.StatusBadRequest, "validation_failed",
+				fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength),
+				models.FieldError{Parameter: IdempotencyKeyHeader, Detail: fmt.Sprintf("must be at most %d characters", maxIdempotencyKeyLength)})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abortWithError(c, http.StatusRequestEntityTooLarge, "payload_too_large",
+					fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			} else {
+				abortWithError(c, http.StatusBadRequest, "bad_request", "reading request body: "+err.Error())
+			}
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		ctx := c.Request.Context()
+		scoped := c.GetString(PrincipalKey) + "\n" + c.Request.Method + "\n" + c.Request.URL.Path + "\n" + key
+		stored, err := store.Begin(ctx, scoped, fingerprint)
+		switch {
+		case errors.Is(err, ErrIdempotencyInFlight):
+			c.Header("Retry-After", "1")
+			abortWithError(c, http.StatusConflict, "idempotency_key_in_use",
+				"a request with this Idempotency-Key is still in progress")
+			return
+		case errors.Is(err, ErrIdempotencyKeyReused):
+			abortWithError(c, http.StatusUnprocessableEntity, "idempotency_key_reused",
+				"Idempotency-Key was already used with a different request body")
+			return
+		case err != nil:
+			slog.WarnContext(ctx, "idempotency store unavailable", "error", err)
+			c.Next()
+			return
+		case stored != nil:
+			for name, values := range stored.Header {
+				c.Writer.Header()[name] = values
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Status(stored.Status)
+			_, _ = c.Writer.Write(stored.Body)
+			c.Abort()
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		completed := false
+		defer func() {
+			c.Writer = w.ResponseWriter
+			if completed {
+				return
+			}
+			// The handler panicked or failed, or the response could not be
+			// stored; let the client retry.
+			if err := store.Release(context.WithoutCancel(ctx), scoped); err != nil {
+				slog.WarnContext(ctx, "releasing idempotency key", "error", err)
+			}
+		}()
+		c.Next()
+
+		if status := w.Status(); status < http.StatusInternalServerError {
+			resp := StoredResponse{Status: status, Header: http.Header{}, Body: w.body.Bytes()}
+			for _, name := range replayedHeaders {
+				if values := w.Header().Values(name); len(values) > 0 {
+					resp.Header[name] = values
+				}
+			}
+			if err := store.Complete(context.WithoutCancel(ctx), scoped, resp); err != nil {
+				slog.WarnContext(ctx, "storing idempotent response", "error", err)
+				return
+			}
+			completed = true
+		}
+	}
+}
+
+// recordingWriter copies the response body as it is written.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
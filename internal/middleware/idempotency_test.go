@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	router := gin.New()
+	router.Use(Recovery())
+	router.Use(func(c *gin.Context) {
+		if p := c.GetHeader("X-Principal"); p != "" {
+			c.Set(PrincipalKey, p)
+		}
+		c.Header("X-Request-ID", c.GetHeader("X-Test-Request"))
+		c.Next()
+	})
+	router.Use(Idempotency(NewIdempotencyStore(time.Hour)))
+	router.POST("/toggle/:id", func(c *gin.Context) {
+		n := calls.Add(1)
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "call": n})
+	})
+	router.POST("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusOK, gin.H{"done": true})
+	})
+	router.POST("/flaky", func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "try again"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+	router.POST("/panic", func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	post := func(path, key, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("without a key every request runs", func(t *testing.T) {
+		calls.Store(0)
+		post("/toggle/door", "", `{}`)
+		post("/toggle/door", "", `{}`)
+		assert.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("retry is replayed", func(t *testing.T) {
+		calls.Store(0)
+		first := post("/toggle/door", "replay", `{"action":"toggle"}`, "X-Test-Request", "first")
+		require.Equal(t, http.StatusOK, first.Code)
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+		retry := post("/toggle/door", "replay", `{"action":"toggle"}`, "X-Test-Request", "retry")
+		assert.Equal(t, http.StatusOK, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, "retry", retry.Header().Get("X-Request-ID"), "only the handler's headers are replayed")
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("key reused with another body", func(t *testing.T) {
+		post("/toggle/door", "reused", `{"action":"open"}`)
+		w := post("/toggle/door", "reused", `{"action":"close"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "idempotency_key_reused", decodeErrorResponse(t, w).Error)
+	})
+
+	t.Run("keys are scoped to principal and path", func(t *testing.T) {
+		calls.Store(0)
+		post("/toggle/door", "scoped", `{}`, "X-Principal", "alice")
+		post("/toggle/door", "scoped", `{}`, "X-Principal", "bob")
+		post("/toggle/gate", "scoped", `{}`, "X-Principal", "alice")
+		assert.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("in-flight duplicate", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- post("/slow", "slow", `{}`) }()
+
+		<-started
+		w := post("/slow", "slow", `{}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "idempotency_key_in_use", decodeErrorResponse(t, w).Error)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+		close(release)
+		assert.Equal(t, http.StatusOK, (<-done).Code)
+		w = post("/slow", "slow", `{}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		calls.Store(0)
+		assert.Equal(t, http.StatusServiceUnavailable, post("/flaky", "flaky", `{}`).Code)
+		assert.Equal(t, http.StatusCreated, post("/flaky", "flaky", `{}`).Code)
+		w := post("/flaky", "flaky", `{}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+		assert.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("panics release the key", func(t *testing.T) {
+		calls.Store(0)
+		assert.Equal(t, http.StatusInternalServerError, post("/panic", "panic", `{}`).Code)
+		assert.Equal(t, http.StatusOK, post("/panic", "panic", `{}`).Code)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		w := post("/toggle/door", strings.Repeat("k", 256), `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "validation_failed", decodeErrorResponse(t, w).Error)
+	})
+}
+
+func TestIdempotency_BodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(8))
+	router.Use(Idempotency(NewIdempotencyStore(time.Hour)))
+	router.POST("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"too":"large"}`))
+	req.ContentLength = -1
+	req.Header.Set(IdempotencyKeyHeader, "k")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	store := NewIdempotencyStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	stored, err := store.Begin(ctx, "k", "a")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	require.NoError(t, store.Complete(ctx, "k", StoredResponse{Status: http.StatusOK, Body: []byte("ok")}))
+
+	now = now.Add(59 * time.Minute)
+	stored, err = store.Begin(ctx, "k", "a")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "ok", string(stored.Body))
+
+	now = now.Add(time.Minute)
+	stored, err = store.Begin(ctx, "k", "b")
+	require.NoError(t, err, "an expired key can be used again")
+	assert.Nil(t, stored)
+
+	now = now.Add(2 * time.Hour)
+	_, err = store.Begin(ctx, "other", "a")
+	require.NoError(t, err)
+	assert.Len(t, store.entries, 1, "expired entries are swept")
+}
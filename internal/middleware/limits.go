@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit returns a gin.HandlerFunc middleware that limits request bodies to
+// maxBytes. Requests whose Content-Length exceeds the limit are rejected with
+// 413 before the handler runs; chunked bodies are cut off at the limit, and
+// reading past it fails with *http.MaxBytesError, which handlers map to 413.
+// A maxBytes of zero or less disables the limit.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	if maxBytes <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			abortWithError(c, http.StatusRequestEntityTooLarge, "payload_too_large",
+				fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// TimeoutConfig controls the handler deadlines applied by TimeoutWithConfig.
+type TimeoutConfig struct {
+	// Default is the deadline of requests to routes not listed in Routes.
+	// Zero disables the deadline.
+	Default time.Duration
+	// Routes overrides Default per route template.
+	Routes map[string]time.Duration
+}
+
+// Timeout returns a gin.HandlerFunc middleware that gives every request a
+// deadline of d. See TimeoutWithConfig.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return TimeoutWithConfig(TimeoutConfig{Default: d})
+}
+
+// TimeoutWithConfig returns a gin.HandlerFunc middleware that sets a deadline
+// on the request context, so that handlers and the calls they make are
+// cancelled when it passes. Handlers are not interrupted: they observe the
+// cancelled context and return. If the deadline passed and the handler wrote
+// nothing, the middleware responds with 504.
+func TimeoutWithConfig(cfg TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d := cfg.Default
+		if rd, ok := cfg.Routes[c.FullPath()]; ok {
+			d = rd
+		}
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			abortWithError(c, http.StatusGatewayTimeout, "gateway_timeout",
+				fmt.Sprintf("request did not complete within %s", d))
+		}
+	}
+}
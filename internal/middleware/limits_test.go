@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeErrorResponse(t *testing.T, w *httptest.ResponseRecorder) models.ErrorResponse {
+	t.Helper()
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(16))
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.String(http.StatusRequestEntityTooLarge, "read past %d", tooLarge.Limit)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("small body")))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "small body", w.Body.String())
+	})
+
+	t.Run("content length over limit is rejected before the handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 17))))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		resp := decodeErrorResponse(t, w)
+		assert.Equal(t, "payload_too_large", resp.Error)
+		assert.Equal(t, "request body exceeds 16 bytes", resp.Message)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	})
+
+	t.Run("chunked body is cut off at the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 64)))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, "read past 16", w.Body.String())
+	})
+}
+
+func TestBodyLimit_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(0))
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.String(http.StatusOK, "%d", len(body))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 1<<16))))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "65536", w.Body.String())
+}
+
+// waitForContext is a handler that waits for the request context to be
+// cancelled, writing nothing, or answers "finished" after a second.
+func waitForContext(c *gin.Context) {
+	select {
+	case <-c.Request.Context().Done():
+	case <-time.After(time.Second):
+		c.String(http.StatusOK, "finished")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(TimeoutWithConfig(TimeoutConfig{
+		Default: 20 * time.Millisecond,
+		Routes: map[string]time.Duration{
+			"/slow/:id": 0,
+		},
+	}))
+	router.GET("/wait", waitForContext)
+	router.GET("/slow/:id", waitForContext)
+	router.GET("/fast", func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		assert.True(t, ok, "request context carries the deadline")
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "started")
+		<-c.Request.Context().Done()
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/wait", nil))
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		resp := decodeErrorResponse(t, w)
+		assert.Equal(t, "gateway_timeout", resp.Error)
+		assert.Equal(t, "request did not complete within 20ms", resp.Message)
+		assert.Equal(t, http.StatusGatewayTimeout, resp.Code)
+	})
+
+	t.Run("route override disables the deadline", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow/1", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "finished", w.Body.String())
+	})
+
+	t.Run("within deadline", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("response already written", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/partial", nil))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "started", w.Body.String())
+	})
+}
+
+func TestTimeout_LogsReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := captureLogs(t)
+
+	router := gin.New()
+	router.Use(LoggerWithConfig(LoggerConfig{}))
+	router.Use(Timeout(time.Millisecond))
+	router.GET("/wait", waitForContext)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/wait", nil))
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "gateway_timeout", lines[0]["error"])
+}
@@ -1,16 +1,63 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
+	"sync/atomic"
 	"time"
 
+	"go-github/internal/models"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Logger returns a gin.HandlerFunc middleware that logs HTTP requests using slog.
-// It logs the request method, path, status code, duration in milliseconds,
-// and includes the request_id from the context if available.
+// PrincipalKey is the gin context key under which the auth layer stores the
+// authenticated principal (API key name or client certificate subject).
+const PrincipalKey = "principal"
+
+// LoggerConfig controls the access log written by LoggerWithConfig.
+type LoggerConfig struct {
+	// SampledRoutes lists route templates whose successful (status < 400)
+	// requests are sampled rather than logged every time.
+	SampledRoutes []string
+	// SampleEvery logs one of every SampleEvery successful requests to a
+	// sampled route. Values <= 1 disable sampling.
+	SampleEvery int
+	// SlowThreshold is the default duration above which a request is logged
+	// at WARN. Zero disables slow request logging.
+	SlowThreshold time.Duration
+	// RouteSlowThresholds overrides SlowThreshold per route template.
+	RouteSlowThresholds map[string]time.Duration
+}
+
+// DefaultLoggerConfig samples 1 in 100 successful /health and /metrics
+// requests (probes and scrapes would otherwise dominate the log) and warns
+// about requests slower than one second.
+func DefaultLoggerConfig() LoggerConfig {
+	return LoggerConfig{
+		SampledRoutes: []string{"/health", "/metrics"},
+		SampleEvery:   100,
+		SlowThreshold: time.Second,
+	}
+}
+
+// Logger returns a gin.HandlerFunc middleware that logs HTTP requests using
+// slog with DefaultLoggerConfig.
 func Logger() gin.HandlerFunc {
+	return LoggerWithConfig(DefaultLoggerConfig())
+}
+
+// LoggerWithConfig returns a gin.HandlerFunc middleware that logs HTTP requests
+// using slog. Each record carries the request_id, method, raw path, route
+// template, status, duration, client IP, user agent, response size, and, when
+// available, the authenticated principal, the error reason recorded by
+// handlers.JSONError, and the trace_id. Server errors are logged at ERROR and
+// slow requests at WARN.
+func LoggerWithConfig(cfg LoggerConfig) gin.HandlerFunc {
+	sampler := newRouteSampler(cfg.SampledRoutes, cfg.SampleEvery)
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		requestID, exists := c.Get("request_id")
@@ -21,12 +68,96 @@ func Logger() gin.HandlerFunc {
 		c.Next()
 
 		duration := time.Since(start)
-		slog.Info("request completed",
+		status := c.Writer.Status()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		slowThreshold := cfg.SlowThreshold
+		if d, ok := cfg.RouteSlowThresholds[route]; ok {
+			slowThreshold = d
+		}
+		slow := slowThreshold > 0 && duration > slowThreshold
+
+		lvl := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			lvl = slog.LevelError
+		case slow:
+			lvl = slog.LevelWarn
+		case status < http.StatusBadRequest && !sampler.keep(route):
+			return
+		}
+
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		attrs := []any{
 			"request_id", requestID,
 			"method", c.Request.Method,
 			"path", c.Request.URL.Path,
-			"status", c.Writer.Status(),
+			"route", route,
+			"status", status,
 			"duration_ms", duration.Milliseconds(),
-		)
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+			"response_size", size,
+		}
+		if principal := c.GetString(PrincipalKey); principal != "" {
+			attrs = append(attrs, "principal", principal)
+		}
+		if reason, message, ok := errorReason(c); ok {
+			attrs = append(attrs, "error", reason, "error_message", message)
+		}
+		if slow {
+			attrs = append(attrs, "slow", true, "slow_threshold_ms", slowThreshold.Milliseconds())
+		}
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			attrs = append(attrs, "trace_id", sc.TraceID().String())
+		}
+		// request_id is already attached explicitly, so don't log with the
+		// request context (the default handler would add it a second time).
+		slog.Log(context.Background(), lvl, "request completed", attrs...)
+	}
+}
+
+// errorReason extracts the ErrorResponse attached by handlers.JSONError.
+func errorReason(c *gin.Context) (string, string, bool) {
+	for i := len(c.Errors) - 1; i >= 0; i-- {
+		if resp, ok := c.Errors[i].Meta.(models.ErrorResponse); ok {
+			return resp.Error, resp.Message, true
+		}
+	}
+	return "", "", false
+}
+
+// routeSampler keeps one of every n requests per sampled route.
+type routeSampler struct {
+	n        uint64
+	counters map[string]*atomic.Uint64
+}
+
+func newRouteSampler(routes []string, n int) *routeSampler {
+	s := &routeSampler{counters: make(map[string]*atomic.Uint64)}
+	if n > 1 {
+		s.n = uint64(n)
+		for _, r := range routes {
+			s.counters[r] = new(atomic.Uint64)
+		}
+	}
+	return s
+}
+
+// keep reports whether a successful request to route should be logged. The
+// first request of every window is kept so that sampled routes still show up
+// promptly after startup.
+func (s *routeSampler) keep(route string) bool {
+	counter, ok := s.counters[route]
+	if !ok {
+		return true
 	}
+	return (counter.Add(1)-1)%s.n == 0
 }
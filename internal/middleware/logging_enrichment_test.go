@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-github/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs installs a JSON slog default for the duration of the test and
+// returns the buffer it writes to.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	return &buf
+}
+
+// logLines decodes one JSON object per captured log line.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestLoggerWithConfig_EnrichedFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := captureLogs(t)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(PrincipalKey, "dashboard")
+		c.Next()
+	})
+	router.Use(LoggerWithConfig(LoggerConfig{}))
+	router.GET("/devices/:id", func(c *gin.Context) {
+		resp := models.ErrorResponse{Error: "not_found", Message: "device not found: x", Code: 404}
+		_ = c.Error(assert.AnError).SetMeta(resp)
+		c.JSON(http.StatusNotFound, resp)
+	})
+
+	req := httptest.NewRequest("GET", "/devices/x", nil)
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.RemoteAddr = "192.168.1.50:4444"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 1)
+	entry := lines[0]
+	assert.Equal(t, "/devices/:id", entry["route"])
+	assert.Equal(t, "/devices/x", entry["path"])
+	assert.Equal(t, "192.168.1.50", entry["client_ip"])
+	assert.Equal(t, "curl/8.0", entry["user_agent"])
+	assert.Equal(t, float64(w.Body.Len()), entry["response_size"])
+	assert.Equal(t, "dashboard", entry["principal"])
+	assert.Equal(t, "not_found", entry["error"])
+	assert.Equal(t, "device not found: x", entry["error_message"])
+	assert.Equal(t, "INFO", entry["level"])
+}
+
+func TestLoggerWithConfig_SamplesSuccessfulRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := captureLogs(t)
+
+	router := gin.New()
+	router.Use(LoggerWithConfig(LoggerConfig{SampledRoutes: []string{"/health"}, SampleEvery: 5}))
+	router.GET("/health", func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/other", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 10; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+	}
+	assert.Len(t, logLines(t, buf), 2, "1 in 5 successful requests should be logged")
+
+	buf.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health?fail=1", nil))
+	assert.Len(t, logLines(t, buf), 1, "failures are never sampled")
+
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/other", nil))
+	}
+	assert.Len(t, logLines(t, buf), 3, "other routes are logged every time")
+}
+
+func TestLoggerWithConfig_SlowRequestsLogAtWarn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := captureLogs(t)
+
+	router := gin.New()
+	router.Use(LoggerWithConfig(LoggerConfig{
+		SlowThreshold:       time.Hour,
+		RouteSlowThresholds: map[string]time.Duration{"/slow": 5 * time.Millisecond},
+	}))
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fast", nil))
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "WARN", lines[0]["level"])
+	assert.Equal(t, true, lines[0]["slow"])
+	assert.Equal(t, float64(5), lines[0]["slow_threshold_ms"])
+	assert.Equal(t, "INFO", lines[1]["level"], "per-route threshold should not affect other routes")
+}
+
+func TestLoggerWithConfig_ServerErrorsLogAtError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := captureLogs(t)
+
+	router := gin.New()
+	router.Use(LoggerWithConfig(DefaultLoggerConfig()))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "ERROR", lines[0]["level"])
+}
+
+func TestLoggerWithConfig_UnmatchedRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := captureLogs(t)
+
+	router := gin.New()
+	router.Use(LoggerWithConfig(LoggerConfig{}))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/no-such-route", nil))
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, unmatchedRoute, lines[0]["route"])
+	assert.Equal(t, "/no-such-route", lines[0]["path"])
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"go-github/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that did not match any registered route, so
+// that scanners probing random paths cannot create unbounded label values.
+const unmatchedRoute = "unmatched"
+
+// Metrics returns a gin.HandlerFunc middleware that records a request counter
+// and latency histogram for every request, labeled by route template (e.g.
+// /api/v1/homeassistant/devices/:id/command), method and status code.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).
+			Observe(time.Since(start).Seconds())
+	}
+}
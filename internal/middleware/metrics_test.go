@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-github/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/things/:id", func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	counter := metrics.HTTPRequestsTotal.WithLabelValues("/things/:id", "GET", "202")
+	before := testutil.ToFloat64(counter)
+
+	for _, id := range []string{"a", "b", "c"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/things/"+id, nil))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	}
+
+	assert.Equal(t, before+3, testutil.ToFloat64(counter), "requests with different IDs should share one series")
+}
+
+func TestMetrics_UnmatchedRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics())
+
+	counter := metrics.HTTPRequestsTotal.WithLabelValues(unmatchedRoute, "GET", "404")
+	before := testutil.ToFloat64(counter)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/wp-admin/setup.php", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestRateLimit_CountsRejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimitWithConfig(1, 1))
+	router.GET("/limited", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	counter := metrics.RateLimitRejectionsTotal.WithLabelValues("/limited")
+	before := testutil.ToFloat64(counter)
+
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/limited", nil))
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(counter))
+}
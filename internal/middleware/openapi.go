@@ -0,0 +1,291 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-github/internal/models"
+	"go-github/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIValidationConfig configures OpenAPIValidationWithConfig.
+type OpenAPIValidationConfig struct {
+	// Document is the API description to validate against. Routes it does not
+	// describe are passed through unchecked.
+	Document *openapi.Document
+	// Requests rejects requests whose parameters or body violate the
+	// document: 400 for invalid values, 415 for a body in an undocumented
+	// media type.
+	Requests bool
+	// Responses checks response bodies against the document and logs
+	// violations. Undocumented error statuses, such as a 429 from the rate
+	// limiter, are not checked.
+	Responses bool
+	// StrictResponses replaces a response that violates the document with a
+	// 500, so that contract changes fail tests. It implies Responses.
+	StrictResponses bool
+	// SkipResponse, if set, exempts the responses to the requests it reports
+	// from response checks, such as responses the request deliberately
+	// trims below the documented schema.
+	SkipResponse func(*gin.Context) bool
+}
+
+// OpenAPIValidation rejects requests that do not match doc.
+func OpenAPIValidation(doc *openapi.Document) gin.HandlerFunc {
+	return OpenAPIValidationWithConfig(OpenAPIValidationConfig{Document: doc, Requests: true})
+}
+
+// OpenAPIValidationWithConfig validates requests and responses against an
+// OpenAPI document. Operations are matched by the gin route template, so it
+// must run on the router rather than wrap it.
+func OpenAPIValidationWithConfig(cfg OpenAPIValidationConfig) gin.HandlerFunc {
+	checkResponses := cfg.Responses || cfg.StrictResponses
+	return func(c *gin.Context) {
+		op := cfg.Document.Operation(c.Request.Method, openAPIPath(c.FullPath()))
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		if cfg.Requests && !validateRequest(c, cfg.Document, op) {
+			return
+		}
+		if !checkResponses || (cfg.SkipResponse != nil && cfg.SkipResponse(c)) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buf := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = buf
+		c.Next()
+		c.Writer = original
+
+		if !buf.written {
+			// Nothing to validate, but a status set without a body, like
+			// 204 No Content, still has to reach the client.
+			original.WriteHeader(buf.status)
+			return
+		}
+		if err := validateResponse(cfg.Document, op, buf); err != nil {
+			requestID, _ := c.Get(RequestIDKey)
+			slog.Error("response does not match the OpenAPI document",
+				"request_id", requestID,
+				"route", c.FullPath(),
+				"status", buf.status,
+				"error", err,
+			)
+			if cfg.StrictResponses {
+				abortWithError(c, http.StatusInternalServerError, "response_validation_failed", err.Error())
+				return
+			}
+		}
+		original.WriteHeader(buf.status)
+		_, _ = original.Write(buf.body.Bytes())
+	}
+}
+
+// openAPIPath converts a gin route template ("/devices/:id") to an OpenAPI
+// path template ("/devices/{id}"). Catch-all routes have no equivalent and
+// yield "".
+func openAPIPath(route string) string {
+	if strings.Contains(route, "*") {
+		return ""
+	}
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if name, ok := strings.CutPrefix(s, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// validateRequest checks the parameters and body of the request against op.
+// It aborts the request and returns false if they do not match.
+func validateRequest(c *gin.Context, doc *openapi.Document, op *openapi.Operation) bool {
+	for _, p := range op.Parameters {
+		var value string
+		var present bool
+		switch p.In {
+		case "path":
+			value = c.Param(p.Name)
+			present = value != ""
+		case "query":
+			value, present = c.GetQuery(p.Name)
+		case "header":
+			value = c.GetHeader(p.Name)
+			present = value != ""
+		}
+		if !present {
+			if p.Required {
+				abortWithError(c, http.StatusBadRequest, "validation_failed",
+					fmt.Sprintf("missing required %s parameter %q", p.In, p.Name),
+					models.FieldError{Parameter: p.Name, Detail: "is required"})
+				return false
+			}
+			continue
+		}
+		if err := doc.ValidateParameter(p, value); err != nil {
+			var fields []models.FieldError
+			var invalid *openapi.ParameterError
+			if errors.As(err, &invalid) {
+				fields = append(fields, models.FieldError{Parameter: invalid.Name, Detail: invalid.Message})
+			}
+			abortWithError(c, http.StatusBadRequest, "validation_failed", err.Error(), fields...)
+			return false
+		}
+	}
+
+	if op.RequestBody == nil {
+		return true
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abortWithError(c, http.StatusRequestEntityTooLarge, "payload_too_large",
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		} else {
+			abortWithError(c, http.StatusBadRequest, "bad_request", "reading request body: "+err.Error())
+		}
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			abortWithError(c, http.StatusBadRequest, "validation_failed", "request body is required")
+			return false
+		}
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		abortWithError(c, http.StatusUnsupportedMediaType, "unsupported_media_type",
+			fmt.Sprintf("request body must be %s, got %q", strings.Join(mediaTypes(op.RequestBody.Content), " or "), c.GetHeader("Content-Type")))
+		return false
+	}
+	if !isJSON(mediaType) {
+		return true
+	}
+	if err := doc.ValidateJSON(content.Schema, body); err != nil {
+		var invalid *openapi.ValidationError
+		if errors.As(err, &invalid) {
+			fields := make([]models.FieldError, len(invalid.Errors))
+			for i, v := range invalid.Errors {
+				fields[i] = models.FieldError{Pointer: v.Pointer, Detail: v.Message}
+			}
+			abortWithError(c, http.StatusBadRequest, "validation_failed", "invalid request body: "+err.Error(), fields...)
+		} else {
+			abortWithError(c, http.StatusBadRequest, "bad_request", "invalid request body: "+err.Error())
+		}
+		return false
+	}
+	return true
+}
+
+// validateResponse checks a buffered response against op. Undocumented
+// statuses are only an error for successful responses.
+func validateResponse(doc *openapi.Document, op *openapi.Operation, w *bufferedWriter) error {
+	resp, ok := op.Responses[strconv.Itoa(w.status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		if w.status >= 200 && w.status < 300 {
+			return fmt.Errorf("status %d is not documented", w.status)
+		}
+		return nil
+	}
+	if len(resp.Content) == 0 {
+		if w.body.Len() > 0 {
+			return fmt.Errorf("status %d is documented without a body", w.status)
+		}
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	content, ok := resp.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("content type %q is not documented for status %d", mediaType, w.status)
+	}
+	if !isJSON(mediaType) {
+		return nil
+	}
+	if err := doc.ValidateJSON(content.Schema, w.body.Bytes()); err != nil {
+		return fmt.Errorf("status %d body: %w", w.status, err)
+	}
+	return nil
+}
+
+func mediaTypes(content map[string]openapi.MediaType) []string {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bufferedWriter holds the response back so it can be validated before it
+// is sent. Headers go straight to the underlying writer's header map.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op: the response is sent once it has been validated.
+func (w *bufferedWriter) Flush() {}
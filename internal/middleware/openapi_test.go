@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-github/internal/models"
+	"go-github/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `{
+	"openapi": "3.1.0",
+	"info": {"title": "test", "version": "1"},
+	"paths": {
+		"/things/{id}": {
+			"post": {
+				"parameters": [
+					{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+					{"name": "limit", "in": "query", "schema": {"type": "integer"}}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}
+				},
+				"responses": {
+					"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}},
+					"404": {"description": "Not Found", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+				}
+			},
+			"delete": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+				"responses": {"204": {"description": "No Content"}}
+			}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Thing": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "count": {"type": "integer"}},
+				"required": ["name"]
+			},
+			"Error": {
+				"type": "object",
+				"properties": {"error": {"type": "string"}},
+				"required": ["error"]
+			}
+		}
+	}
+}`
+
+// newValidatedRouter serves POST /things/:id, which answers with status and
+// body, DELETE /things/:id, which answers 204, and GET /other, which the
+// document does not describe.
+func newValidatedRouter(t *testing.T, cfg OpenAPIValidationConfig, status int, body string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	doc, err := openapi.Load([]byte(testDocument))
+	require.NoError(t, err)
+	cfg.Document = doc
+
+	router := gin.New()
+	router.Use(OpenAPIValidationWithConfig(cfg))
+	router.POST("/things/:id", func(c *gin.Context) {
+		received, _ := io.ReadAll(c.Request.Body)
+		c.Header("X-Received", string(received))
+		c.Data(status, "application/json", []byte(body))
+	})
+	router.DELETE("/things/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/other", func(c *gin.Context) {
+		c.String(http.StatusOK, "not described")
+	})
+	return router
+}
+
+func postThing(router *gin.Engine, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOpenAPIValidation_Requests(t *testing.T) {
+	router := newValidatedRouter(t, OpenAPIValidationConfig{Requests: true}, http.StatusOK, `{"name":"a"}`)
+
+	t.Run("valid request reaches the handler with its body", func(t *testing.T) {
+		w := postThing(router, "/things/1?limit=5", "application/json; charset=utf-8", `{"name":"lamp","count":2}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"name":"lamp","count":2}`, w.Header().Get("X-Received"))
+	})
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		status      int
+		errorCode   string
+		message     string
+	}{
+		{"invalid query parameter", "/things/1?limit=many", "application/json", `{"name":"a"}`, 400, "validation_failed", `query parameter "limit": expected integer, got "many"`},
+		{"missing body", "/things/1", "application/json", ``, 400, "validation_failed", "request body is required"},
+		{"wrong media type", "/things/1", "text/plain", `{"name":"a"}`, 415, "unsupported_media_type", `request body must be application/json, got "text/plain"`},
+		{"malformed JSON", "/things/1", "application/json", `{"name":`, 400, "bad_request", "invalid request body: invalid JSON: unexpected EOF"},
+		{"missing property", "/things/1", "application/json", `{"count":1}`, 400, "validation_failed", `invalid request body: (root): missing required property "name"`},
+		{"wrong property type", "/things/1", "application/json", `{"name":"a","count":1.5}`, 400, "validation_failed", "invalid request body: /count: expected integer, got number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postThing(router, tt.target, tt.contentType, tt.body)
+			assert.Equal(t, tt.status, w.Code)
+			resp := decodeErrorResponse(t, w)
+			assert.Equal(t, tt.errorCode, resp.Error)
+			assert.Equal(t, tt.message, resp.Message)
+			assert.Empty(t, w.Header().Get("X-Received"), "the handler must not run")
+		})
+	}
+
+	t.Run("undescribed routes are not checked", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/other?limit=many", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestOpenAPIValidation_ProblemFieldErrors(t *testing.T) {
+	router := newValidatedRouter(t, OpenAPIValidationConfig{Requests: true}, http.StatusOK, `{"name":"a"}`)
+
+	send := func(target, body string) models.Problem {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/problem+json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		var p models.Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		return p
+	}
+
+	p := send("/things/1", `{"count":"one"}`)
+	assert.Equal(t, "tag:homelab-api,2026:problems/validation_failed", p.Type)
+	assert.Equal(t, []models.FieldError{
+		{Detail: `missing required property "name"`},
+		{Pointer: "/count", Detail: "expected integer, got string"},
+	}, p.Errors)
+
+	p = send("/things/1?limit=many", `{"name":"a"}`)
+	assert.Equal(t, []models.FieldError{{Parameter: "limit", Detail: `expected integer, got "many"`}}, p.Errors)
+}
+
+func TestOpenAPIValidation_Responses(t *testing.T) {
+	valid := `{"name":"a"}`
+
+	t.Run("matching response is sent unchanged", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{StrictResponses: true}, http.StatusOK, valid)
+		w := postThing(router, "/things/1", "application/json", valid)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, valid, w.Body.String())
+	})
+
+	t.Run("strict mode replaces a violating response", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{StrictResponses: true}, http.StatusOK, `{"count":"one"}`)
+		w := postThing(router, "/things/1", "application/json", valid)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		resp := decodeErrorResponse(t, w)
+		assert.Equal(t, "response_validation_failed", resp.Error)
+		assert.Equal(t, `status 200 body: (root): missing required property "name"; /count: expected integer, got string`, resp.Message)
+	})
+
+	t.Run("undocumented success status is a violation", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{StrictResponses: true}, http.StatusCreated, valid)
+		w := postThing(router, "/things/1", "application/json", valid)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "status 201 is not documented", decodeErrorResponse(t, w).Message)
+	})
+
+	t.Run("documented error status is checked", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{StrictResponses: true}, http.StatusNotFound, `{"message":"gone"}`)
+		w := postThing(router, "/things/1", "application/json", valid)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("undocumented error status is passed through", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{StrictResponses: true}, http.StatusTooManyRequests, `{"error":"Rate limit exceeded"}`)
+		w := postThing(router, "/things/1", "application/json", valid)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, `{"error":"Rate limit exceeded"}`, w.Body.String())
+	})
+
+	t.Run("skipped responses are sent unchecked", func(t *testing.T) {
+		cfg := OpenAPIValidationConfig{
+			StrictResponses: true,
+			SkipResponse:    func(c *gin.Context) bool { return c.Query("limit") == "2" },
+		}
+		router := newValidatedRouter(t, cfg, http.StatusOK, `{"count":2}`)
+		w := postThing(router, "/things/1?limit=2", "application/json", valid)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"count":2}`, w.Body.String())
+
+		w = postThing(router, "/things/1?limit=3", "application/json", valid)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("status without a body is sent", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{StrictResponses: true}, http.StatusOK, valid)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/things/1", nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("non-strict mode only logs", func(t *testing.T) {
+		router := newValidatedRouter(t, OpenAPIValidationConfig{Responses: true}, http.StatusOK, `{"count":"one"}`)
+		w := postThing(router, "/things/1", "application/json", valid)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"count":"one"}`, w.Body.String())
+	})
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/devices/{id}/command", openAPIPath("/api/v1/devices/:id/command"))
+	assert.Equal(t, "/health", openAPIPath("/health"))
+	assert.Equal(t, "", openAPIPath("/api/docs/*any"))
+}
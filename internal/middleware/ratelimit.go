@@ -1,13 +1,35 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"go-github/internal/config"
+	"go-github/internal/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
+// RateLimitResult is the outcome of a single rate limit check.
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+}
+
+// RateLimitBackend decides whether a request identified by key may proceed.
+// Implementations must be safe for concurrent use.
+type RateLimitBackend interface {
+	Allow(ctx context.Context, key string) (RateLimitResult, error)
+	// Limit returns the number of requests allowed per window and the
+	// window in minutes, which the X-RateLimit-* headers advertise.
+	Limit() (maxRequests, perMinutes int)
+}
+
 // TokenBucket represents a token bucket for rate limiting
 type TokenBucket struct {
 	tokens         float64
@@ -67,11 +89,14 @@ func (tb *TokenBucket) GetTokens() float64 {
 	return tb.tokens
 }
 
-// RateLimiter manages rate limiting for multiple IPs
+// RateLimiter manages rate limiting for multiple IPs.
+// It is the default in-memory RateLimitBackend; limits are enforced per process.
 type RateLimiter struct {
-	buckets    sync.Map // map[string]*TokenBucket
-	maxTokens  float64
-	refillRate float64
+	buckets     sync.Map // map[string]*TokenBucket
+	maxTokens   float64
+	refillRate  float64
+	maxRequests int
+	perMinutes  int
 }
 
 // NewRateLimiter creates a new rate limiter.
@@ -82,8 +107,10 @@ func NewRateLimiter(maxRequests int, perMinutes int) *RateLimiter {
 	refillRate := maxTokens / (float64(perMinutes) * 60.0) // tokens per second
 
 	return &RateLimiter{
-		maxTokens:  maxTokens,
-		refillRate: refillRate,
+		maxTokens:   maxTokens,
+		refillRate:  refillRate,
+		maxRequests: maxRequests,
+		perMinutes:  perMinutes,
 	}
 }
 
@@ -98,46 +125,133 @@ func (rl *RateLimiter) GetBucket(ip string) *TokenBucket {
 	return actual.(*TokenBucket)
 }
 
-// RateLimit returns a gin middleware that implements rate limiting.
-// Default: 500 requests per minute per IP.
-func RateLimit() gin.HandlerFunc {
-	return RateLimitWithConfig(500, 1)
+// Allow consumes one token from the bucket for key.
+func (rl *RateLimiter) Allow(_ context.Context, key string) (RateLimitResult, error) {
+	bucket := rl.GetBucket(key)
+	if !bucket.TryConsume() {
+		return RateLimitResult{Allowed: false}, nil
+	}
+	return RateLimitResult{Allowed: true, Remaining: int(bucket.GetTokens())}, nil
+}
+
+// Limit returns the limit rl was created with.
+func (rl *RateLimiter) Limit() (maxRequests, perMinutes int) {
+	return rl.maxRequests, rl.perMinutes
+}
+
+// RateLimitOptions configures RateLimitWithOptions.
+type RateLimitOptions struct {
+	// MaxRequests is the number of requests allowed per window.
+	MaxRequests int
+	// PerMinutes is the window length in minutes.
+	PerMinutes int
+	// RedisAddr selects the shared Redis backend when non-empty; otherwise
+	// limits are kept in memory.
+	RedisAddr string
+	// RedisPassword is sent with AUTH to RedisAddr when non-empty.
+	RedisPassword string
+	// Backend overrides the backend selected from the fields above.
+	Backend RateLimitBackend
+}
+
+// RateLimit returns a gin middleware that enforces cfg. Limits are shared
+// across replicas through the Redis backend when cfg.RedisAddr is set.
+func RateLimit(cfg config.RateLimitConfig) gin.HandlerFunc {
+	return RateLimitWithOptions(NewRateLimitOptions(cfg))
+}
+
+// NewRateLimitOptions returns the options that enforce cfg.
+func NewRateLimitOptions(cfg config.RateLimitConfig) RateLimitOptions {
+	return RateLimitOptions{
+		MaxRequests:   cfg.Requests,
+		PerMinutes:    cfg.PerMinutes,
+		RedisAddr:     cfg.RedisAddr,
+		RedisPassword: cfg.RedisPassword.Value(),
+	}
+}
+
+// NewRateLimitBackend returns the backend selected by opts: the shared Redis
+// backend when opts.RedisAddr is set and the in-memory limiter otherwise.
+func NewRateLimitBackend(opts RateLimitOptions) RateLimitBackend {
+	if opts.RedisAddr != "" {
+		return NewRedisRateLimiter(RedisRateLimiterConfig{
+			Addr:        opts.RedisAddr,
+			Password:    opts.RedisPassword,
+			MaxRequests: opts.MaxRequests,
+			PerMinutes:  opts.PerMinutes,
+		})
+	}
+	return NewRateLimiter(opts.MaxRequests, opts.PerMinutes)
+}
+
+// RateLimitWithOptions returns a gin middleware that enforces opts using
+// opts.Backend, or NewRateLimitBackend(opts) when it is nil.
+func RateLimitWithOptions(opts RateLimitOptions) gin.HandlerFunc {
+	backend := opts.Backend
+	if backend == nil {
+		backend = NewRateLimitBackend(opts)
+	}
+	return RateLimitWithBackend(backend)
 }
 
 // RateLimitWithConfig returns a gin middleware with custom rate limiting configuration
 func RateLimitWithConfig(maxRequests int, perMinutes int) gin.HandlerFunc {
-	limiter := NewRateLimiter(maxRequests, perMinutes)
+	return RateLimitWithBackend(NewRateLimiter(maxRequests, perMinutes))
+}
+
+// RateLimitWithBackend returns a gin middleware that enforces limits using the
+// given backend, keyed by client IP. Backend errors fail open so that an
+// unavailable shared store does not take the API down with it. The
+// X-RateLimit-* headers advertise backend's Limit; rejected requests also get
+// Retry-After: the seconds until the bucket refills by one request.
+func RateLimitWithBackend(backend RateLimitBackend) gin.HandlerFunc {
+	maxRequests, perMinutes := backend.Limit()
+	limit := strconv.Itoa(maxRequests)
+	reset := strconv.Itoa(perMinutes * 60)
+	retryAfter := "1"
+	if maxRequests > 0 {
+		retryAfter = strconv.Itoa(max(1, (perMinutes*60+maxRequests-1)/maxRequests))
+	}
 
 	return func(c *gin.Context) {
 		// Get client IP
 		clientIP := c.ClientIP()
 
-		// Get or create bucket for this IP
-		bucket := limiter.GetBucket(clientIP)
+		result, err := backend.Allow(c.Request.Context(), clientIP)
+		if err != nil {
+			requestID, _ := c.Get(RequestIDKey)
+			slog.Warn("rate limit backend error, allowing request",
+				"request_id", requestID,
+				"error", err,
+			)
+			c.Next()
+			return
+		}
 
-		// Try to consume a token
-		if !bucket.TryConsume() {
+		if !result.Allowed {
 			// Rate limit exceeded
-			c.Header("X-RateLimit-Limit", "500")
+			route := c.FullPath()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			metrics.RateLimitRejectionsTotal.WithLabelValues(route).Inc()
+			c.Header("X-RateLimit-Limit", limit)
 			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", "60")
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded",
-			})
-			c.Abort()
+			c.Header("X-RateLimit-Reset", reset)
+			c.Header("Retry-After", retryAfter)
+			abortWithError(c, http.StatusTooManyRequests, "rate_limit_exceeded", "Rate limit exceeded")
 			return
 		}
 
-		// Get current token count for headers
-		remaining := int(bucket.GetTokens())
+		remaining := result.Remaining
 		if remaining < 0 {
 			remaining = 0
 		}
 
 		// Add rate limit headers
-		c.Header("X-RateLimit-Limit", "500")
+		c.Header("X-RateLimit-Limit", limit)
 		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
-		c.Header("X-RateLimit-Reset", "60")
+		c.Header("X-RateLimit-Reset", reset)
 
 		c.Next()
 	}
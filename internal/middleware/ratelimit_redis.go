@@ -0,0 +1,317 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRedisKeyPrefix   = "homelab:ratelimit:"
+	defaultRedisDialTimeout = 2 * time.Second
+	defaultRedisIOTimeout   = time.Second
+	defaultRedisMaxIdle     = 8
+)
+
+// tokenBucketScript implements the same token bucket as TokenBucket, atomically
+// on the Redis server. The server clock is used so that replicas with skewed
+// clocks still agree on refill timing.
+//
+// KEYS[1] = bucket key
+// ARGV[1] = max tokens
+// ARGV[2] = refill rate (tokens per second)
+//
+// Returns {allowed (0|1), remaining tokens (floored)}.
+const tokenBucketScript = `
+local max_tokens = tonumber(ARGV[1])
+local refill_rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = max_tokens
+  ts = now
+end
+local elapsed = now - ts
+if elapsed < 0 then elapsed = 0 end
+tokens = math.min(max_tokens, tokens + elapsed * refill_rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('EXPIRE', KEYS[1], math.ceil(max_tokens / refill_rate) + 1)
+return {allowed, math.floor(tokens)}
+`
+
+// tokenBucketScriptSHA is the SHA1 digest used with EVALSHA.
+var tokenBucketScriptSHA = func() string {
+	sum := sha1.Sum([]byte(tokenBucketScript))
+	return hex.EncodeToString(sum[:])
+}()
+
+// RedisRateLimiterConfig configures a RedisRateLimiter.
+type RedisRateLimiterConfig struct {
+	// Addr is the host:port of a server speaking the Redis protocol.
+	Addr string
+	// Password is sent with AUTH when non-empty.
+	Password string
+	// DB is selected with SELECT when non-zero.
+	DB int
+	// KeyPrefix is prepended to every bucket key (default "homelab:ratelimit:").
+	KeyPrefix string
+	// MaxRequests is the bucket size; PerMinutes is the refill window.
+	MaxRequests int
+	PerMinutes  int
+	// DialTimeout bounds connection setup (default 2s).
+	DialTimeout time.Duration
+	// IOTimeout bounds each command round trip (default 1s).
+	IOTimeout time.Duration
+	// MaxIdleConns caps the number of pooled idle connections (default 8).
+	MaxIdleConns int
+}
+
+// RedisRateLimiter is a RateLimitBackend that stores token buckets in Redis so
+// that every replica shares the same limit. Each check is a single atomic
+// script evaluation.
+type RedisRateLimiter struct {
+	cfg        RedisRateLimiterConfig
+	maxTokens  float64
+	refillRate float64
+	idle       chan *respConn
+	dialer     net.Dialer
+}
+
+// NewRedisRateLimiter creates a Redis-backed rate limiter. Connections are
+// established lazily on first use.
+func NewRedisRateLimiter(cfg RedisRateLimiterConfig) *RedisRateLimiter {
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = defaultRedisKeyPrefix
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultRedisDialTimeout
+	}
+	if cfg.IOTimeout <= 0 {
+		cfg.IOTimeout = defaultRedisIOTimeout
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = defaultRedisMaxIdle
+	}
+
+	maxTokens := float64(cfg.MaxRequests)
+	return &RedisRateLimiter{
+		cfg:        cfg,
+		maxTokens:  maxTokens,
+		refillRate: maxTokens / (float64(cfg.PerMinutes) * 60.0),
+		idle:       make(chan *respConn, cfg.MaxIdleConns),
+		dialer:     net.Dialer{Timeout: cfg.DialTimeout},
+	}
+}
+
+// Limit returns the limit configured for r.
+func (r *RedisRateLimiter) Limit() (maxRequests, perMinutes int) {
+	return r.cfg.MaxRequests, r.cfg.PerMinutes
+}
+
+// Allow consumes one token from the shared bucket for key.
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	conn, err := r.getConn(ctx)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	args := []string{
+		"1",
+		r.cfg.KeyPrefix + key,
+		strconv.FormatFloat(r.maxTokens, 'f', -1, 64),
+		strconv.FormatFloat(r.refillRate, 'f', -1, 64),
+	}
+
+	reply, err := conn.do(ctx, r.cfg.IOTimeout, append([]string{"EVALSHA", tokenBucketScriptSHA}, args...)...)
+	var redisErr respError
+	if errors.As(err, &redisErr) && strings.HasPrefix(string(redisErr), "NOSCRIPT") {
+		// First use against this server (or after SCRIPT FLUSH): send the body.
+		reply, err = conn.do(ctx, r.cfg.IOTimeout, append([]string{"EVAL", tokenBucketScript}, args...)...)
+	}
+	if err != nil {
+		// Server-side errors leave the connection usable; I/O errors do not.
+		if errors.As(err, &redisErr) {
+			r.putConn(conn)
+		} else {
+			conn.Close()
+		}
+		return RateLimitResult{}, err
+	}
+	r.putConn(conn)
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return RateLimitResult{}, fmt.Errorf("ratelimit: unexpected script reply %v", reply)
+	}
+	allowed, ok1 := values[0].(int64)
+	remaining, ok2 := values[1].(int64)
+	if !ok1 || !ok2 {
+		return RateLimitResult{}, fmt.Errorf("ratelimit: unexpected script reply %v", reply)
+	}
+
+	return RateLimitResult{Allowed: allowed == 1, Remaining: int(remaining)}, nil
+}
+
+// Close releases all pooled connections.
+func (r *RedisRateLimiter) Close() error {
+	for {
+		select {
+		case conn := <-r.idle:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// getConn returns a pooled connection or dials a new, authenticated one.
+func (r *RedisRateLimiter) getConn(ctx context.Context) (*respConn, error) {
+	select {
+	case conn := <-r.idle:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := r.dialer.DialContext(ctx, "tcp", r.cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: dial %s: %w", r.cfg.Addr, err)
+	}
+	conn := &respConn{conn: netConn, rd: bufio.NewReader(netConn)}
+
+	if r.cfg.Password != "" {
+		if _, err := conn.do(ctx, r.cfg.IOTimeout, "AUTH", r.cfg.Password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ratelimit: auth: %w", err)
+		}
+	}
+	if r.cfg.DB != 0 {
+		if _, err := conn.do(ctx, r.cfg.IOTimeout, "SELECT", strconv.Itoa(r.cfg.DB)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ratelimit: select: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// putConn returns a healthy connection to the pool, closing it if the pool is full.
+func (r *RedisRateLimiter) putConn(conn *respConn) {
+	select {
+	case r.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// respError is an error reply ("-ERR ...") returned by the server.
+type respError string
+
+func (e respError) Error() string { return "redis: " + string(e) }
+
+// respConn is a single connection speaking RESP2. It is not safe for
+// concurrent use; the pool hands each connection to one caller at a time.
+type respConn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// Close closes the underlying network connection.
+func (rc *respConn) Close() {
+	rc.conn.Close()
+}
+
+// do sends a command and reads a single reply.
+func (rc *respConn) do(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := rc.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := rc.conn.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+
+	return readRESP(rc.rd)
+}
+
+// readRESP parses one RESP2 value. Integers are returned as int64, bulk and
+// simple strings as string, nil bulk strings as nil, and arrays as
+// []interface{}. Error replies are returned as a respError.
+func readRESP(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	prefix, body := line[0], line[1:len(line)-2]
+
+	switch prefix {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, respError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			v, err := readRESP(rd)
+			if err != nil {
+				var redisErr respError
+				if !errors.As(err, &redisErr) {
+					return nil, err
+				}
+				v = redisErr
+			}
+			values[i] = v
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", prefix)
+	}
+}
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is an in-process stand-in for a Redis server. It speaks enough
+// RESP2 to serve the rate limiter and emulates tokenBucketScript in Go.
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu      sync.Mutex
+	scripts map[string]bool
+	buckets map[string]*fakeBucket
+	evals   int
+	evalSHA int
+}
+
+type fakeBucket struct {
+	tokens float64
+	ts     time.Time
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	f := &fakeRedis{
+		ln:       ln,
+		password: password,
+		scripts:  make(map[string]bool),
+		buckets:  make(map[string]*fakeBucket),
+	}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeRedis) addr() string { return f.ln.Addr().String() }
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		v, err := readRESP(rd)
+		if err != nil {
+			return
+		}
+		raw, _ := v.([]interface{})
+		args := make([]string, len(raw))
+		for i, a := range raw {
+			args[i], _ = a.(string)
+		}
+		if len(args) == 0 {
+			fmt.Fprint(conn, "-ERR empty command\r\n")
+			continue
+		}
+
+		cmd := strings.ToUpper(args[0])
+		if !authed && cmd != "AUTH" {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+
+		switch cmd {
+		case "AUTH":
+			if len(args) == 2 && args[1] == f.password {
+				authed = true
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "SELECT", "PING":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "EVALSHA":
+			f.mu.Lock()
+			known := f.scripts[args[1]]
+			f.evalSHA++
+			f.mu.Unlock()
+			if !known {
+				fmt.Fprint(conn, "-NOSCRIPT No matching script.\r\n")
+				continue
+			}
+			fmt.Fprint(conn, f.runTokenBucket(args[3], args[4], args[5]))
+		case "EVAL":
+			f.mu.Lock()
+			f.scripts[tokenBucketScriptSHA] = args[1] == tokenBucketScript
+			f.evals++
+			f.mu.Unlock()
+			fmt.Fprint(conn, f.runTokenBucket(args[3], args[4], args[5]))
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", cmd)
+		}
+	}
+}
+
+// runTokenBucket mirrors tokenBucketScript and returns the encoded reply.
+func (f *fakeRedis) runTokenBucket(key, maxArg, rateArg string) string {
+	maxTokens, _ := strconv.ParseFloat(maxArg, 64)
+	refillRate, _ := strconv.ParseFloat(rateArg, 64)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	b, ok := f.buckets[key]
+	if !ok {
+		b = &fakeBucket{tokens: maxTokens, ts: now}
+		f.buckets[key] = b
+	}
+	b.tokens = math.Min(maxTokens, b.tokens+now.Sub(b.ts).Seconds()*refillRate)
+	b.ts = now
+
+	allowed := 0
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = 1
+	}
+	return fmt.Sprintf("*2\r\n:%d\r\n:%d\r\n", allowed, int64(math.Floor(b.tokens)))
+}
+
+func TestRedisRateLimiter_Allow(t *testing.T) {
+	srv := newFakeRedis(t, "")
+	limiter := NewRedisRateLimiter(RedisRateLimiterConfig{Addr: srv.addr(), MaxRequests: 3, PerMinutes: 1})
+	defer limiter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		res, err := limiter.Allow(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, res.Allowed, "request %d should be allowed", i+1)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+
+	res, err := limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed, "4th request should be rejected")
+
+	res, err = limiter.Allow(ctx, "10.0.0.2")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "other keys have their own bucket")
+}
+
+func TestRedisRateLimiter_SharedAcrossReplicas(t *testing.T) {
+	srv := newFakeRedis(t, "")
+	cfg := RedisRateLimiterConfig{Addr: srv.addr(), MaxRequests: 4, PerMinutes: 1}
+
+	replicas := []gin.HandlerFunc{
+		RateLimitWithBackend(NewRedisRateLimiter(cfg)),
+		RateLimitWithBackend(NewRedisRateLimiter(cfg)),
+	}
+
+	gin.SetMode(gin.TestMode)
+	routers := make([]*gin.Engine, len(replicas))
+	for i, mw := range replicas {
+		routers[i] = gin.New()
+		routers[i].Use(mw)
+		routers[i].GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	success := 0
+	for i := 0; i < 8; i++ {
+		w := httptest.NewRecorder()
+		routers[i%2].ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		if w.Code == http.StatusOK {
+			success++
+		}
+		assert.Equal(t, "4", w.Header().Get("X-RateLimit-Limit"), "the headers advertise the backend's limit")
+	}
+	assert.Equal(t, 4, success, "limit should be enforced once across all replicas")
+}
+
+func TestRedisRateLimiter_LoadsScriptOnce(t *testing.T) {
+	srv := newFakeRedis(t, "")
+	limiter := NewRedisRateLimiter(RedisRateLimiterConfig{Addr: srv.addr(), MaxRequests: 10, PerMinutes: 1})
+	defer limiter.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := limiter.Allow(context.Background(), "10.0.0.1")
+		require.NoError(t, err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Equal(t, 1, srv.evals, "script body should only be sent after NOSCRIPT")
+	assert.Equal(t, 5, srv.evalSHA)
+}
+
+func TestRedisRateLimiter_Auth(t *testing.T) {
+	srv := newFakeRedis(t, "s3cret")
+
+	good := NewRedisRateLimiter(RedisRateLimiterConfig{Addr: srv.addr(), Password: "s3cret", MaxRequests: 1, PerMinutes: 1})
+	defer good.Close()
+	res, err := good.Allow(context.Background(), "k")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	bad := NewRedisRateLimiter(RedisRateLimiterConfig{Addr: srv.addr(), Password: "wrong", MaxRequests: 1, PerMinutes: 1})
+	defer bad.Close()
+	_, err = bad.Allow(context.Background(), "k")
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestRateLimitWithBackend_FailsOpen(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close() // nothing listens here any more
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitWithBackend(NewRedisRateLimiter(RedisRateLimiterConfig{
+		Addr:        addr,
+		MaxRequests: 1,
+		PerMinutes:  1,
+		DialTimeout: 100 * time.Millisecond,
+	})))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		assert.Equal(t, http.StatusOK, w.Code, "backend outage should not reject requests")
+	}
+}
+
+func TestReadRESP(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    interface{}
+		wantErr string
+	}{
+		{name: "simple string", input: "+OK\r\n", want: "OK"},
+		{name: "integer", input: ":42\r\n", want: int64(42)},
+		{name: "bulk string", input: "$5\r\nhello\r\n", want: "hello"},
+		{name: "nil bulk", input: "$-1\r\n", want: nil},
+		{name: "array", input: "*2\r\n:1\r\n$1\r\nx\r\n", want: []interface{}{int64(1), "x"}},
+		{name: "error", input: "-ERR boom\r\n", wantErr: "ERR boom"},
+		{name: "unknown type", input: "?\r\n\r\n", wantErr: "unknown reply type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readRESP(bufio.NewReader(strings.NewReader(tt.input)))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,8 @@ import (
 	"testing"
 	"time"
 
+	"go-github/internal/config"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,9 +78,10 @@ func TestRateLimit_BlocksRequestsOverLimit(t *testing.T) {
 	req := httptest.NewRequest("GET", "/test", nil)
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusTooManyRequests, w.Code, "6th request should be rate limited")
-	assert.Equal(t, "500", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "5", w.Header().Get("X-RateLimit-Limit"))
 	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
 	assert.Equal(t, "60", w.Header().Get("X-RateLimit-Reset"))
+	assert.Equal(t, "12", w.Header().Get("Retry-After"))
 }
 
 func TestRateLimit_PerIPIsolation(t *testing.T) {
@@ -110,7 +114,7 @@ func TestRateLimit_PerIPIsolation(t *testing.T) {
 func TestRateLimit_HeaderValues(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.Use(RateLimit())
+	router.Use(RateLimit(config.Default().RateLimit))
 	router.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
@@ -230,12 +234,15 @@ func TestRateLimit_ErrorResponse(t *testing.T) {
 	router.ServeHTTP(w2, req2)
 	assert.Equal(t, http.StatusTooManyRequests, w2.Code)
 	assert.Contains(t, w2.Body.String(), "Rate limit exceeded")
+	resp := decodeErrorResponse(t, w2)
+	assert.Equal(t, "rate_limit_exceeded", resp.Error)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
 }
 
 func BenchmarkRateLimit(b *testing.B) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.Use(RateLimit())
+	router.Use(RateLimit(config.Default().RateLimit))
 	router.GET("/test", func(c *gin.Context) {
 		c.Status(http.StatusOK)
 	})
@@ -247,3 +254,56 @@ func BenchmarkRateLimit(b *testing.B) {
 		router.ServeHTTP(w, req)
 	}
 }
+
+func TestRateLimiter_Allow(t *testing.T) {
+	limiter := NewRateLimiter(2, 1)
+	ctx := context.Background()
+
+	res, err := limiter.Allow(ctx, "192.168.1.1")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, _ = limiter.Allow(ctx, "192.168.1.1")
+	assert.True(t, res.Allowed)
+
+	res, _ = limiter.Allow(ctx, "192.168.1.1")
+	assert.False(t, res.Allowed, "third request should exceed the limit")
+}
+
+func TestRateLimitWithOptions_AdvertisesConfiguredLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitWithOptions(RateLimitOptions{MaxRequests: 2, PerMinutes: 5}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	var w *httptest.ResponseRecorder
+	for i := 0; i < 3; i++ {
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.20:1234"
+		router.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "300", w.Header().Get("X-RateLimit-Reset"))
+}
+
+func TestRateLimitWithBackend_AdvertisesBackendLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitWithBackend(NewRateLimiter(100, 2)))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "100", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "120", w.Header().Get("X-RateLimit-Reset"))
+}
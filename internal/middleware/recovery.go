@@ -21,11 +21,7 @@ func Recovery() gin.HandlerFunc {
 					"stack", string(debug.Stack()),
 				)
 
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":      "Internal server error",
-					"request_id": requestID,
-				})
-				c.Abort()
+				abortWithError(c, http.StatusInternalServerError, "internal_error", "Internal server error")
 			}
 		}()
 		c.Next()
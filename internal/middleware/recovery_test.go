@@ -26,6 +26,37 @@ func TestRecovery(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	assert.Contains(t, w.Body.String(), "Internal server error")
+	resp := decodeErrorResponse(t, w)
+	assert.Equal(t, "internal_error", resp.Error)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
+
+func TestRecovery_Problem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(Recovery())
+	router.GET("/panic", func(c *gin.Context) {
+		panic("test panic")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	req.Header.Set("X-Request-ID", "req-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "tag:homelab-api,2026:problems/internal_error",
+		"title": "Internal Server Error",
+		"status": 500,
+		"detail": "Internal server error",
+		"instance": "/panic",
+		"request_id": "req-1"
+	}`, w.Body.String())
 }
 
 func TestRecoveryWithoutPanic(t *testing.T) {
@@ -1,18 +1,27 @@
 package middleware
 
 import (
+	"go-github/internal/requestid"
+
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 const RequestIDKey = "request_id"
 
-// RequestID generates a unique request ID for each request
+// RequestID assigns a request ID to each request. A valid inbound X-Request-ID
+// header (see requestid.Valid) is reused so that IDs assigned by a reverse
+// proxy correlate with our logs; otherwise a new UUID is generated. The ID is
+// stored in the gin context, in the request's context.Context, and echoed in
+// the X-Request-ID response header.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := uuid.New().String()
+		requestID := c.GetHeader(requestid.Header)
+		if !requestid.Valid(requestID) {
+			requestID = requestid.New()
+		}
 		c.Set(RequestIDKey, requestID)
-		c.Header("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), requestID))
+		c.Header(requestid.Header, requestID)
 		c.Next()
 	}
 }
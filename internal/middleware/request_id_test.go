@@ -3,8 +3,11 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"go-github/internal/requestid"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,3 +59,47 @@ func TestRequestIDIsUnique(t *testing.T) {
 
 	assert.NotEqual(t, id1, id2, "Request IDs should be unique")
 }
+
+func TestRequestIDHonorsValidInboundHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+
+	var fromCtx string
+	router.GET("/test", func(c *gin.Context) {
+		fromCtx = requestid.FromContext(c.Request.Context())
+		requestID, _ := c.Get(RequestIDKey)
+		c.String(http.StatusOK, requestID.(string))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Request-ID", "proxy-abc-123")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "proxy-abc-123", w.Body.String(), "gin context should hold the inbound ID")
+	assert.Equal(t, "proxy-abc-123", fromCtx, "request context should hold the inbound ID")
+	assert.Equal(t, "proxy-abc-123", w.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDReplacesInvalidInboundHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, bad := range []string{"has space", strings.Repeat("x", 200), "evil\"quote"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Request-ID", bad)
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get("X-Request-ID")
+		assert.NotEqual(t, bad, got)
+		assert.True(t, requestid.Valid(got), "replacement ID should be valid")
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-github/internal/tracing/tracingtest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_StartsServerSpan(t *testing.T) {
+	exporter := tracingtest.Install(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/things/:id", func(c *gin.Context) {
+		assert.True(t, trace.SpanContextFromContext(c.Request.Context()).IsValid(),
+			"handler context should carry the span")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/things/42", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /things/:id", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", 200))
+}
+
+func TestTracing_ContinuesInboundTraceparent(t *testing.T) {
+	exporter := tracingtest.Install(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentID = "00f067aa0ba902b7"
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, traceID, spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, parentID, spans[0].Parent.SpanID().String())
+	assert.True(t, spans[0].Parent.IsRemote())
+}
+
+func TestTracing_MarksServerErrors(t *testing.T) {
+	exporter := tracingtest.Install(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/boom", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestLogger_IncludesTraceID(t *testing.T) {
+	tracingtest.Install(t)
+	gin.SetMode(gin.TestMode)
+
+	var logBuf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logBuf, nil)))
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.Use(Logger())
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, logBuf.String(), `"trace_id":"`+traceID+`"`)
+}
@@ -0,0 +1,6 @@
+package models
+
+// APIInfo is the response of the API version root
+type APIInfo struct {
+	Message string `json:"message" example:"API v1"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// Area is a room or zone of the home, like a Home Assistant area. A device
+// is in at most one area.
+type Area struct {
+	// ID is the area's slug, as in Home Assistant's area registry.
+	ID   string `json:"id" example:"kitchen"`
+	Name string `json:"name" example:"Kitchen"`
+	// DeviceIDs lists the devices in the area, ordered by ID.
+	DeviceIDs []string `json:"device_ids"`
+}
+
+// Group is a user-defined set of devices. Unlike areas, a device can be in
+// any number of groups.
+type Group struct {
+	ID   string `json:"id" example:"downstairs_lights"`
+	Name string `json:"name" example:"Downstairs lights"`
+	// DeviceIDs lists the devices in the group, ordered by ID.
+	DeviceIDs []string `json:"device_ids"`
+}
+
+// AreaListResponse represents a response containing a page of areas
+type AreaListResponse struct {
+	Areas []Area `json:"areas"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// GroupListResponse represents a response containing a page of groups
+type GroupListResponse struct {
+	Groups []Group `json:"groups"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AreaRequest creates or replaces an area.
+type AreaRequest struct {
+	// ID is derived from Name if it is omitted when creating the area.
+	ID   string `json:"id,omitempty" example:"kitchen"`
+	Name string `json:"name" example:"Kitchen"`
+	// DeviceIDs are moved into the area from any area they are in.
+	DeviceIDs []string `json:"device_ids,omitempty"`
+}
+
+// GroupRequest creates or replaces a group.
+type GroupRequest struct {
+	// ID is derived from Name if it is omitted when creating the group.
+	ID        string   `json:"id,omitempty" example:"downstairs_lights"`
+	Name      string   `json:"name" example:"Downstairs lights"`
+	DeviceIDs []string `json:"device_ids,omitempty"`
+}
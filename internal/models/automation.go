@@ -0,0 +1,217 @@
+package models
+
+import "time"
+
+// Automation is an if-this-then-that rule: when one of its triggers fires and
+// all of its conditions hold, its actions run in order.
+type Automation struct {
+	ID          string `json:"id" example:"porch_light_at_dusk"`
+	Name        string `json:"name" example:"Porch light at dusk"`
+	Description string `json:"description,omitempty"`
+	// Enabled rules are evaluated; disabled ones are kept but never run.
+	Enabled    bool                  `json:"enabled"`
+	Triggers   []AutomationTrigger   `json:"triggers"`
+	Conditions []AutomationCondition `json:"conditions"`
+	Actions    []AutomationAction    `json:"actions"`
+	// Source is api for rules created through the API and file for rules
+	// loaded from the automations file, which cannot be changed through the
+	// API.
+	Source string `json:"source" example:"api"`
+	// Runs counts the runs of the rule, including those whose conditions did
+	// not hold.
+	Runs      int        `json:"runs"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// AutomationTrigger is an event that runs an automation. Type says which of
+// the other fields apply.
+type AutomationTrigger struct {
+	// Type is device_state, service_status, alert or time.
+	Type string `json:"type" example:"device_state"`
+
+	// DeviceID, From and To match device_state triggers: the state of the
+	// device changing from From to To. An empty From or To matches any
+	// state; with both empty, any change of the device's state or attributes
+	// fires.
+	DeviceID string `json:"device_id,omitempty" example:"device-001"`
+	From     string `json:"from,omitempty" example:"off"`
+	To       string `json:"to,omitempty" example:"on"`
+
+	// Service and Status match service_status triggers: a probed service
+	// going up or down. An empty Service matches every service and an empty
+	// Status both directions.
+	Service string `json:"service,omitempty" example:"grafana"`
+	Status  string `json:"status,omitempty" example:"down"`
+
+	// AlertName and Labels match alert triggers: a firing alert received on
+	// POST /api/v1/automations/alerts, whose labels include Labels.
+	AlertName string            `json:"alert_name,omitempty" example:"HighTemperature"`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	// Cron and Timezone fire time triggers, as for schedules.
+	Cron     string `json:"cron,omitempty" example:"0 22 * * *"`
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+}
+
+// AutomationCondition compares the state, or an attribute, of a device with
+// a value when an automation is triggered.
+type AutomationCondition struct {
+	DeviceID string `json:"device_id" example:"readonly-sensor-001"`
+	// Attribute is compared instead of the state when it is given.
+	Attribute string `json:"attribute,omitempty" example:"brightness"`
+	// Operator is eq, ne, gt, gte, lt or lte. eq and ne compare numbers as
+	// numbers and anything else as JSON; the others only compare numbers,
+	// including numeric states such as "72".
+	Operator string      `json:"operator" example:"gt"`
+	Value    interface{} `json:"value"`
+}
+
+// AutomationAction is a step of an automation. Type says which of the other
+// fields apply.
+type AutomationAction struct {
+	// Type is command, webhook or notify.
+	Type string `json:"type" example:"command"`
+
+	// DeviceID, Action and Parameters are the device command of a command
+	// action.
+	DeviceID   string                 `json:"device_id,omitempty" example:"device-001"`
+	Action     string                 `json:"action,omitempty" example:"turn_on"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// URL receives a webhook action: a JSON POST of the automation ID, the
+	// run ID and the event.
+	URL string `json:"url,omitempty" example:"https://hooks.example.com/homelab"`
+
+	// Title and Message are the notification of a notify action.
+	Title   string `json:"title,omitempty" example:"Porch light"`
+	Message string `json:"message,omitempty" example:"The porch light was turned on"`
+}
+
+// AutomationEvent is what triggered an automation run. Type says which of the
+// other fields apply, as for AutomationTrigger.
+type AutomationEvent struct {
+	// Type is device_state, service_status, alert or time, or manual for a
+	// dry run without an event.
+	Type string `json:"type" example:"device_state"`
+
+	DeviceID   string                 `json:"device_id,omitempty" example:"device-001"`
+	From       string                 `json:"from,omitempty" example:"off"`
+	To         string                 `json:"to,omitempty" example:"on"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	Service string `json:"service,omitempty" example:"grafana"`
+	Status  string `json:"status,omitempty" example:"down"`
+
+	AlertName string            `json:"alert_name,omitempty" example:"HighTemperature"`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	Time time.Time `json:"time"`
+	// RequestID is the request ID of the command that changed the device.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// AutomationRun is the execution trace of one run of an automation.
+type AutomationRun struct {
+	// ID is also the request ID of the commands and webhooks of the run, so
+	// it finds them in the command history and the logs.
+	ID           string `json:"id" example:"0192f3c4-5e6f-7a8b-9c0d-1e2f3a4b5c6d"`
+	AutomationID string `json:"automation_id" example:"porch_light_at_dusk"`
+	// DryRun runs evaluate the triggers and conditions but run no action.
+	DryRun bool            `json:"dry_run"`
+	Event  AutomationEvent `json:"event"`
+	// Depth is the position of the run in a chain of automations triggered
+	// by the device changes of each other's commands, starting at 1.
+	Depth int `json:"depth" example:"1"`
+	// Status is success, failed (an action failed), conditions_not_met or
+	// loop_blocked (the chain was too deep). Dry runs end in not_triggered,
+	// if the event matches no trigger, conditions_not_met or would_run.
+	Status     string                      `json:"status" example:"success"`
+	Error      string                      `json:"error,omitempty"`
+	Conditions []AutomationConditionResult `json:"conditions"`
+	Actions    []AutomationActionResult    `json:"actions"`
+	StartedAt  time.Time                   `json:"started_at"`
+	FinishedAt time.Time                   `json:"finished_at"`
+}
+
+// AutomationConditionResult is the evaluation of one condition in a run.
+type AutomationConditionResult struct {
+	Index int `json:"index"`
+	// Actual is the state or attribute the condition compared.
+	Actual interface{} `json:"actual,omitempty"`
+	Passed bool        `json:"passed"`
+	Error  string      `json:"error,omitempty" example:"device not found: device-404"`
+}
+
+// AutomationActionResult is the outcome of one action in a run.
+type AutomationActionResult struct {
+	Index int    `json:"index"`
+	Type  string `json:"type" example:"command"`
+	// Status is success, failed, skipped (an earlier action failed) or, in a
+	// dry run, dry_run.
+	Status string `json:"status" example:"success"`
+	Error  string `json:"error,omitempty" example:"device is not controllable"`
+}
+
+// AutomationListResponse represents a response containing a page of automations
+type AutomationListResponse struct {
+	Automations []Automation `json:"automations"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AutomationRunListResponse represents a response containing a page of the
+// runs of an automation
+type AutomationRunListResponse struct {
+	Runs []AutomationRun `json:"runs"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AutomationRequest creates or replaces an automation. The automations file
+// holds a list of them under "automations".
+type AutomationRequest struct {
+	// ID is derived from Name if it is omitted when creating the automation.
+	ID          string `json:"id,omitempty" example:"porch_light_at_dusk"`
+	Name        string `json:"name" example:"Porch light at dusk"`
+	Description string `json:"description,omitempty"`
+	// Enabled defaults to true.
+	Enabled    *bool                 `json:"enabled,omitempty"`
+	Triggers   []AutomationTrigger   `json:"triggers"`
+	Conditions []AutomationCondition `json:"conditions,omitempty"`
+	Actions    []AutomationAction    `json:"actions"`
+}
+
+// DryRunRequest evaluates an automation against an event without running its
+// actions.
+type DryRunRequest struct {
+	// Event is checked against the triggers. Without it, the triggers are
+	// not checked and only the conditions are evaluated.
+	Event *AutomationEvent `json:"event,omitempty"`
+}
+
+// AlertWebhook is the payload of an Alertmanager webhook notification.
+type AlertWebhook struct {
+	Version string `json:"version,omitempty" example:"4"`
+	// Status is firing if any alert of the group is firing.
+	Status string  `json:"status" example:"firing"`
+	Alerts []Alert `json:"alerts"`
+}
+
+// Alert is one alert of an AlertWebhook.
+type Alert struct {
+	// Status is firing or resolved; only firing alerts trigger automations.
+	Status      string            `json:"status" example:"firing"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+}
+
+// AlertsResult reports how many alerts of a webhook were firing and passed
+// on to the automations.
+type AlertsResult struct {
+	Firing int `json:"firing"`
+}
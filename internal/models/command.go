@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Command represents a device control command for HomeAssistant
+type Command struct {
+	Action     string                 `json:"action"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// Validate checks if the Command has valid data
+func (c *Command) Validate() error {
+	if strings.TrimSpace(c.Action) == "" {
+		return errors.New("action is required")
+	}
+
+	if c.Parameters == nil {
+		return errors.New("parameters is required")
+	}
+
+	return nil
+}
+
+// CommandResult represents the result of executing a command on a device.
+type CommandResult struct {
+	Status   string `json:"status"`
+	DeviceID string `json:"device_id"`
+	Action   string `json:"action"`
+}
+
+// BatchCommand is one command of a batch.
+type BatchCommand struct {
+	DeviceID   string                 `json:"device_id" example:"device-001"`
+	Action     string                 `json:"action" example:"turn_off"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// Command returns the device command of c.
+func (c BatchCommand) Command() Command {
+	return Command{Action: c.Action, Parameters: c.Parameters}
+}
+
+// BatchRequest is a list of commands to execute together.
+type BatchRequest struct {
+	Commands []BatchCommand `json:"commands"`
+	// Atomic stops the batch at the first failed command and restores the
+	// devices of the commands that succeeded to their previous state, where
+	// an action sets that state.
+	Atomic bool `json:"atomic"`
+}
+
+// BatchItemResult is the outcome of one command of a batch.
+type BatchItemResult struct {
+	// Index is the position of the command in the request.
+	Index    int    `json:"index"`
+	DeviceID string `json:"device_id"`
+	Action   string `json:"action"`
+	// Status is the HTTP status the command would have got on its own, or
+	// 424 Failed Dependency if it was skipped because an atomic batch failed.
+	Status  int            `json:"status" example:"200"`
+	Result  *CommandResult `json:"result,omitempty"`
+	Error   string         `json:"error,omitempty" example:"not_found"`
+	Message string         `json:"message,omitempty" example:"device not found: device-404"`
+	// RolledBack is set on commands of a failed atomic batch that were undone.
+	RolledBack bool `json:"rolled_back,omitempty"`
+	// RollbackError explains why a command of a failed atomic batch is still
+	// applied.
+	RollbackError string `json:"rollback_error,omitempty"`
+}
+
+// BatchResult is the outcome of a batch, with one result per command in
+// request order.
+type BatchResult struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	// Skipped counts commands not executed because an atomic batch failed.
+	Skipped int `json:"skipped"`
+	// RolledBack is set when an atomic batch failed and was rolled back.
+	RolledBack bool `json:"rolled_back"`
+}
@@ -11,4 +11,16 @@ type Device struct {
 	Attributes   map[string]interface{} `json:"attributes"`
 	LastUpdated  time.Time              `json:"last_updated"`
 	Controllable bool                   `json:"controllable"`
+	// AreaID is the area the device is in; it is omitted if it is in none.
+	AreaID string `json:"area_id,omitempty"`
+}
+
+// DeviceListResponse represents a response containing a page of devices
+type DeviceListResponse struct {
+	Devices []Device `json:"devices"`
+	// Count is the number of devices in this page.
+	Count int `json:"count"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -6,3 +6,40 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 	Code    int    `json:"code"`
 }
+
+// ProblemContentType is the media type of Problem responses.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix is prepended to an ErrorResponse's error code to form
+// the type URI of the equivalent Problem, e.g.
+// "tag:homelab-api,2026:problems/not_found". Tag URIs (RFC 4151) identify
+// problem types without promising a page to dereference.
+const ProblemTypePrefix = "tag:homelab-api,2026:problems/"
+
+// Problem is an RFC 9457 problem details response, sent instead of an
+// ErrorResponse to clients that ask for application/problem+json.
+type Problem struct {
+	// Type identifies the kind of problem; see ProblemTypePrefix.
+	Type string `json:"type"`
+	// Title is the HTTP status text.
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail describes this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request that failed.
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists the individual inputs that failed validation.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is one invalid input of a request that failed validation. It
+// names either a value in the request body, by JSON pointer, or a request
+// parameter.
+type FieldError struct {
+	Detail string `json:"detail"`
+	// Pointer is the JSON pointer (RFC 6901) of the value in the request
+	// body; "" is the whole body.
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
@@ -6,3 +6,8 @@ type HealthStatus struct {
 	Uptime     string            `json:"uptime"`
 	Components map[string]string `json:"components"`
 }
+
+// HealthResponse is the response of the /health endpoint
+type HealthResponse struct {
+	Status string `json:"status" example:"ok"`
+}
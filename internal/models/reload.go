@@ -0,0 +1,10 @@
+package models
+
+// ReloadResult lists the settings that changed in a reload.
+type ReloadResult struct {
+	// Applied are the changed settings that took effect immediately.
+	Applied []string `json:"applied"`
+	// RestartRequired are the changed settings that only take effect after a
+	// restart. They are kept at their running values until then.
+	RestartRequired []string `json:"restart_required"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Scene is a named snapshot of the state and attributes of some devices,
+// like a Home Assistant scene. Activating it restores the snapshot.
+type Scene struct {
+	ID   string `json:"id" example:"movie_night"`
+	Name string `json:"name" example:"Movie night"`
+	// Devices holds the captured state of each device, ordered by device ID.
+	Devices []SceneDevice `json:"devices"`
+	// CapturedAt is when the device states were captured.
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// SceneDevice is the captured state of one device of a scene.
+type SceneDevice struct {
+	DeviceID   string                 `json:"device_id" example:"device-001"`
+	State      string                 `json:"state" example:"on"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// SceneListResponse represents a response containing a page of scenes
+type SceneListResponse struct {
+	Scenes []Scene `json:"scenes"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// SceneRequest creates a scene, or recaptures one, from the current state of
+// its devices.
+type SceneRequest struct {
+	// ID is derived from Name if it is omitted when creating the scene.
+	ID   string `json:"id,omitempty" example:"movie_night"`
+	Name string `json:"name" example:"Movie night"`
+	// DeviceIDs are the devices whose state is captured; they must be
+	// controllable, or the scene could not restore them.
+	DeviceIDs []string `json:"device_ids" example:"device-001"`
+}
+
+// SceneActivation is the outcome of activating a scene, with one result per
+// device of the scene, ordered by device ID.
+type SceneActivation struct {
+	SceneID   string              `json:"scene_id" example:"movie_night"`
+	Results   []SceneDeviceResult `json:"results"`
+	Restored  int                 `json:"restored"`
+	Unchanged int                 `json:"unchanged"`
+	Failed    int                 `json:"failed"`
+}
+
+// SceneDeviceResult is the outcome of restoring one device of a scene.
+type SceneDeviceResult struct {
+	DeviceID string `json:"device_id" example:"device-001"`
+	// Status is restored, unchanged (the device already matched the scene)
+	// or failed.
+	Status string `json:"status" example:"restored"`
+	// Command is the command sent to restore the device. It is omitted when
+	// the device was unchanged.
+	Command *Command `json:"command,omitempty"`
+	Error   string   `json:"error,omitempty" example:"not_found"`
+	Message string   `json:"message,omitempty" example:"device not found: device-404"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Schedule is a device command that runs at a set time or on a cron
+// expression.
+type Schedule struct {
+	ID   string `json:"id" example:"0192f3c4-5e6f-7a8b-9c0d-1e2f3a4b5c6d"`
+	Name string `json:"name,omitempty" example:"Porch light off"`
+
+	DeviceID   string                 `json:"device_id" example:"device-001"`
+	Action     string                 `json:"action" example:"turn_off"`
+	Parameters map[string]interface{} `json:"parameters"`
+
+	// Kind is once, for a schedule created with at or delay, or cron.
+	Kind string `json:"kind" example:"cron"`
+	// Cron is the cron expression of a cron schedule.
+	Cron string `json:"cron,omitempty" example:"30 22 * * 1-5"`
+	// Timezone is the IANA time zone the cron expression is evaluated in.
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+	// MissedRunPolicy is what happens to a run that was missed, e.g. because
+	// the server was down: skip it, or run_once as soon as possible.
+	MissedRunPolicy string `json:"missed_run_policy" example:"run_once"`
+
+	// State is scheduled while runs are pending and completed once a one-time
+	// schedule has run.
+	State string `json:"state" example:"scheduled"`
+	// NextRun is omitted once the schedule is completed.
+	NextRun *time.Time `json:"next_run,omitempty"`
+	// LastRun is the outcome of the most recent run.
+	LastRun *ScheduleRun `json:"last_run,omitempty"`
+	// Runs counts the runs that executed the command.
+	Runs      int       `json:"runs"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduleRun is the outcome of one run of a schedule.
+type ScheduleRun struct {
+	// ScheduledFor is when the run was due.
+	ScheduledFor time.Time `json:"scheduled_for"`
+	// StartedAt is when it started; it is later than ScheduledFor for a
+	// missed run that was run late.
+	StartedAt time.Time `json:"started_at"`
+	// Status is success, failed, or skipped for a missed run that the
+	// policy skipped.
+	Status string `json:"status" example:"success"`
+	Error  string `json:"error,omitempty" example:"device not found"`
+	// RequestID identifies the command in the command history.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ScheduleListResponse represents a response containing a page of schedules
+type ScheduleListResponse struct {
+	Schedules []Schedule `json:"schedules"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ScheduleRequest creates a schedule. Exactly one of At, Delay and Cron says
+// when the command runs.
+type ScheduleRequest struct {
+	Name       string                 `json:"name,omitempty" example:"Porch light off"`
+	DeviceID   string                 `json:"device_id" example:"device-001"`
+	Action     string                 `json:"action" example:"turn_off"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// At runs the command once at this time (RFC 3339).
+	At *time.Time `json:"at,omitempty"`
+	// Delay runs the command once after this Go duration, e.g. 30m or 1h30m.
+	Delay string `json:"delay,omitempty" example:"30m"`
+	// Cron runs the command on a five-field cron expression, e.g. "0 22 * * *".
+	Cron string `json:"cron,omitempty" example:"0 22 * * *"`
+	// Timezone is the IANA time zone of Cron; the server's time zone is used
+	// if it is omitted.
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+	// MissedRunPolicy is skip or run_once (the default).
+	MissedRunPolicy string `json:"missed_run_policy,omitempty" example:"run_once"`
+}
@@ -11,4 +11,15 @@ type Service struct {
 // ServicesResponse represents the response for listing services
 type ServicesResponse struct {
 	Services []Service `json:"services"`
+	// NextCursor is passed as cursor to fetch the next page; it is omitted
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ServiceInfo represents information about a Kubernetes cluster service
+type ServiceInfo struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Status    string   `json:"status"`
+	Endpoints []string `json:"endpoints"`
 }
@@ -0,0 +1,380 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// swagger is the subset of a Swagger 2.0 document that swag generates.
+type swagger struct {
+	Info struct {
+		Title          string   `json:"title"`
+		Description    string   `json:"description"`
+		TermsOfService string   `json:"termsOfService"`
+		Contact        *Contact `json:"contact"`
+		License        *License `json:"license"`
+		Version        string   `json:"version"`
+	} `json:"info"`
+	BasePath    string                                 `json:"basePath"`
+	Paths       map[string]map[string]swaggerOperation `json:"paths"`
+	Definitions map[string]swaggerDefinition           `json:"definitions"`
+}
+
+type swaggerOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description"`
+	Tags        []string                   `json:"tags"`
+	Consumes    []string                   `json:"consumes"`
+	Produces    []string                   `json:"produces"`
+	Parameters  []swaggerParameter         `json:"parameters"`
+	Responses   map[string]swaggerResponse `json:"responses"`
+}
+
+type swaggerParameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Type        string  `json:"type"`
+	Format      string  `json:"format"`
+	Schema      *Schema `json:"schema"`
+}
+
+type swaggerResponse struct {
+	Description string                   `json:"description"`
+	Schema      *Schema                  `json:"schema"`
+	Headers     map[string]swaggerHeader `json:"headers"`
+}
+
+type swaggerHeader struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type swaggerDefinition struct {
+	Properties map[string]struct {
+		Description string `json:"description"`
+		Example     any    `json:"example"`
+	} `json:"properties"`
+}
+
+// Alternate documents a second media type for every response whose body is
+// the Swagger definition Of. swag cannot describe per-response media types,
+// so bodies the server negotiates separately, such as
+// application/problem+json errors, are added during conversion.
+type Alternate struct {
+	// Of is the definition name of the documented body, e.g.
+	// "models.ErrorResponse".
+	Of        string
+	MediaType string
+	// Definition names the schema of the alternate body. It becomes a
+	// component schema; its Go type must be registered like any other.
+	Definition string
+}
+
+// FromSwagger converts the Swagger 2.0 document generated by swag into an
+// OpenAPI 3.1 document. types maps every definition name (e.g.
+// "models.Device") to the Go type it documents; the component schema is
+// reflected from that type, with descriptions and examples taken from the
+// Swagger definition. The result is indented JSON.
+func FromSwagger(data []byte, types map[string]reflect.Type, alternates ...Alternate) ([]byte, error) {
+	var src swagger
+	if err := json.Unmarshal(data, &src); err != nil {
+		return nil, fmt.Errorf("openapi: parse swagger document: %w", err)
+	}
+
+	doc := Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:          src.Info.Title,
+			Description:    src.Info.Description,
+			TermsOfService: src.Info.TermsOfService,
+			Contact:        src.Info.Contact,
+			License:        src.Info.License,
+			Version:        src.Info.Version,
+		},
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+	if src.BasePath != "" {
+		doc.Servers = []Server{{URL: src.BasePath}}
+	}
+
+	definitions := src.Definitions
+	if len(alternates) > 0 {
+		definitions = maps.Clone(definitions)
+		for _, alt := range alternates {
+			if _, ok := definitions[alt.Definition]; !ok {
+				definitions[alt.Definition] = swaggerDefinition{}
+			}
+		}
+	}
+
+	names := map[reflect.Type]string{}
+	for name := range definitions {
+		t, ok := types[name]
+		if !ok {
+			return nil, fmt.Errorf("openapi: no Go type registered for definition %q", name)
+		}
+		names[t] = name
+	}
+	for name, def := range definitions {
+		s, err := reflectSchema(types[name], names, true)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: definition %q: %w", name, err)
+		}
+		for prop, p := range def.Properties {
+			if target := s.Properties[prop]; target != nil {
+				target.Description = p.Description
+				if p.Example != nil {
+					target.Examples = []any{p.Example}
+				}
+			}
+		}
+		doc.Components.Schemas[name] = s
+	}
+
+	for path, ops := range src.Paths {
+		item := PathItem{}
+		for method, op := range ops {
+			converted, err := convertOperation(op, alternates)
+			if err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			item[method] = converted
+		}
+		doc.Paths[path] = item
+	}
+
+	out, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func convertOperation(op swaggerOperation, alternates []Alternate) (*Operation, error) {
+	out := &Operation{
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	consumes := firstOr(op.Consumes, "application/json")
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "body":
+			out.RequestBody = &RequestBody{
+				Description: p.Description,
+				Required:    p.Required,
+				Content:     map[string]MediaType{consumes: {Schema: convertSchema(p.Schema)}},
+			}
+		case "path", "query", "header":
+			out.Parameters = append(out.Parameters, Parameter{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.Required || p.In == "path",
+				Schema:      &Schema{Type: Types{p.Type}, Format: p.Format},
+			})
+		default:
+			return nil, fmt.Errorf("parameter %q: unsupported location %q", p.Name, p.In)
+		}
+	}
+
+	for code, r := range op.Responses {
+		resp := &Response{Description: r.Description}
+		for name, h := range r.Headers {
+			if resp.Headers == nil {
+				resp.Headers = map[string]Header{}
+			}
+			resp.Headers[name] = Header{Description: h.Description, Schema: &Schema{Type: Types{h.Type}}}
+		}
+		if r.Schema != nil {
+			resp.Content = responseContent(convertSchema(r.Schema), produces, alternates)
+		}
+		out.Responses[code] = resp
+	}
+	return out, nil
+}
+
+// responseContent describes a response body in each produced media type.
+// Text types such as text/csv carry a rendering of the body rather than its
+// structure, so they are documented as strings. Bodies with alternates are
+// only produced in the first media type and the alternates: error bodies,
+// for example, are not negotiated like the operation's results. An event
+// stream answers errors in JSON.
+func responseContent(s *Schema, produces []string, alternates []Alternate) map[string]MediaType {
+	content := map[string]MediaType{}
+	for _, alt := range alternates {
+		if s.Ref == schemaRefPrefix+alt.Of {
+			content[alt.MediaType] = MediaType{Schema: &Schema{Ref: schemaRefPrefix + alt.Definition}}
+			produces = produces[:1]
+			if produces[0] == "text/event-stream" {
+				produces = []string{"application/json"}
+			}
+		}
+	}
+	for _, mediaType := range produces {
+		if strings.HasPrefix(mediaType, "text/") {
+			content[mediaType] = MediaType{Schema: &Schema{Type: Types{"string"}}}
+			continue
+		}
+		content[mediaType] = MediaType{Schema: s}
+	}
+	return content
+}
+
+// convertSchema rewrites the definition references of a Swagger schema.
+func convertSchema(s *Schema) *Schema {
+	walk(s, func(s *Schema) {
+		if name, ok := strings.CutPrefix(s.Ref, "#/definitions/"); ok {
+			s.Ref = schemaRefPrefix + name
+		}
+	})
+	return s
+}
+
+func firstOr(values []string, fallback string) string {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return fallback
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// reflectSchema returns the schema of the JSON encoding of t. Types in names
+// other than the top-level one become references. Fields without omitempty
+// are required; slices, maps and pointers are nullable because encoding/json
+// writes nil ones as null.
+func reflectSchema(t reflect.Type, names map[reflect.Type]string, top bool) (*Schema, error) {
+	if name, ok := names[t]; ok && !top {
+		return &Schema{Ref: schemaRefPrefix + name}, nil
+	}
+	if t == timeType {
+		return &Schema{Type: Types{"string"}, Format: "date-time"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}, nil
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Pointer:
+		s, err := reflectSchema(t.Elem(), names, false)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(s), nil
+	case reflect.Slice, reflect.Array:
+		items, err := reflectSchema(t.Elem(), names, false)
+		if err != nil {
+			return nil, err
+		}
+		s := &Schema{Type: Types{"array"}, Items: items}
+		if t.Kind() == reflect.Slice {
+			s = nullable(s)
+		}
+		return s, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := reflectSchema(t.Elem(), names, false)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(&Schema{Type: Types{"object"}, AdditionalProperties: values}), nil
+	case reflect.Struct:
+		s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+		if err := reflectFields(s, t, names); err != nil {
+			return nil, err
+		}
+		sort.Strings(s.Required)
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// reflectFields adds the JSON fields of struct type t to s, flattening
+// embedded structs the way encoding/json does.
+func reflectFields(s *Schema, t reflect.Type, names map[reflect.Type]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := reflectFields(s, ft, names); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs, err := reflectSchema(f.Type, names, false)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		s.Properties[name] = fs
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+func hasOption(opts, name string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable allows null in addition to the types of s. A reference is
+// wrapped because siblings of $ref cannot widen it.
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	}
+	if len(s.Type) == 0 {
+		return s // already accepts null
+	}
+	if !s.Type.Has("null") {
+		s.Type = append(s.Type, "null")
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+// Command openapigen converts the swag output in api/swagger.json into the
+// OpenAPI 3.1 document api/openapi.json.
+//
+// Every Swagger definition must have its Go type listed in types; the
+// component schemas are reflected from those types. alternates adds the
+// response media types swag cannot describe.
+//
+// Usage (from api, via go generate):
+//
+//	go run ../internal/openapi/internal/openapigen -in swagger.json -out openapi.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+
+	"go-github/internal/models"
+	"go-github/internal/openapi"
+)
+
+// types maps Swagger definition names to the Go types they document.
+var types = map[string]reflect.Type{
+	"models.APIInfo":                   reflect.TypeFor[models.APIInfo](),
+	"models.Alert":                     reflect.TypeFor[models.Alert](),
+	"models.AlertWebhook":              reflect.TypeFor[models.AlertWebhook](),
+	"models.AlertsResult":              reflect.TypeFor[models.AlertsResult](),
+	"models.Area":                      reflect.TypeFor[models.Area](),
+	"models.AreaListResponse":          reflect.TypeFor[models.AreaListResponse](),
+	"models.AreaRequest":               reflect.TypeFor[models.AreaRequest](),
+	"models.Automation":                reflect.TypeFor[models.Automation](),
+	"models.AutomationAction":          reflect.TypeFor[models.AutomationAction](),
+	"models.AutomationActionResult":    reflect.TypeFor[models.AutomationActionResult](),
+	"models.AutomationCondition":       reflect.TypeFor[models.AutomationCondition](),
+	"models.AutomationConditionResult": reflect.TypeFor[models.AutomationConditionResult](),
+	"models.AutomationEvent":           reflect.TypeFor[models.AutomationEvent](),
+	"models.AutomationListResponse":    reflect.TypeFor[models.AutomationListResponse](),
+	"models.AutomationRequest":         reflect.TypeFor[models.AutomationRequest](),
+	"models.AutomationRun":             reflect.TypeFor[models.AutomationRun](),
+	"models.AutomationRunListResponse": reflect.TypeFor[models.AutomationRunListResponse](),
+	"models.AutomationTrigger":         reflect.TypeFor[models.AutomationTrigger](),
+	"models.BatchCommand":              reflect.TypeFor[models.BatchCommand](),
+	"models.BatchItemResult":           reflect.TypeFor[models.BatchItemResult](),
+	"models.BatchRequest":              reflect.TypeFor[models.BatchRequest](),
+	"models.BatchResult":               reflect.TypeFor[models.BatchResult](),
+	"models.Command":                   reflect.TypeFor[models.Command](),
+	"models.CommandResult":             reflect.TypeFor[models.CommandResult](),
+	"models.Device":                    reflect.TypeFor[models.Device](),
+	"models.DeviceListResponse":        reflect.TypeFor[models.DeviceListResponse](),
+	"models.DryRunRequest":             reflect.TypeFor[models.DryRunRequest](),
+	"models.ErrorResponse":             reflect.TypeFor[models.ErrorResponse](),
+	"models.Group":                     reflect.TypeFor[models.Group](),
+	"models.GroupListResponse":         reflect.TypeFor[models.GroupListResponse](),
+	"models.GroupRequest":              reflect.TypeFor[models.GroupRequest](),
+	"models.HealthResponse":            reflect.TypeFor[models.HealthResponse](),
+	"models.Problem":                   reflect.TypeFor[models.Problem](),
+	"models.ReloadResult":              reflect.TypeFor[models.ReloadResult](),
+	"models.Scene":                     reflect.TypeFor[models.Scene](),
+	"models.SceneActivation":           reflect.TypeFor[models.SceneActivation](),
+	"models.SceneDevice":               reflect.TypeFor[models.SceneDevice](),
+	"models.SceneDeviceResult":         reflect.TypeFor[models.SceneDeviceResult](),
+	"models.SceneListResponse":         reflect.TypeFor[models.SceneListResponse](),
+	"models.SceneRequest":              reflect.TypeFor[models.SceneRequest](),
+	"models.Schedule":                  reflect.TypeFor[models.Schedule](),
+	"models.ScheduleListResponse":      reflect.TypeFor[models.ScheduleListResponse](),
+	"models.ScheduleRequest":           reflect.TypeFor[models.ScheduleRequest](),
+	"models.ScheduleRun":               reflect.TypeFor[models.ScheduleRun](),
+	"models.Service":                   reflect.TypeFor[models.Service](),
+	"models.ServiceInfo":               reflect.TypeFor[models.ServiceInfo](),
+	"models.ServicesResponse":          reflect.TypeFor[models.ServicesResponse](),
+}
+
+// alternates documents the application/problem+json form of every error
+// response, which handlers.JSONError sends to clients that ask for it.
+var alternates = []openapi.Alternate{
+	{Of: "models.ErrorResponse", MediaType: models.ProblemContentType, Definition: "models.Problem"},
+}
+
+func main() {
+	in := flag.String("in", "swagger.json", "Swagger 2.0 document generated by swag")
+	out := flag.String("out", "openapi.json", "OpenAPI 3.1 document to write")
+	flag.Parse()
+
+	if err := run(*in, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "openapigen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string) error {
+	src, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	doc, err := openapi.FromSwagger(src, types, alternates...)
+	if err != nil {
+		return err
+	}
+	if _, err := openapi.Load(doc); err != nil {
+		return err
+	}
+	return os.WriteFile(out, doc, 0o644)
+}
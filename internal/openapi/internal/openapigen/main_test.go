@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go-github/internal/openapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerated_UpToDate fails when api/swagger.json or the documented Go
+// types changed without running go generate ./api.
+func TestGenerated_UpToDate(t *testing.T) {
+	src, err := os.ReadFile("../../../../api/swagger.json")
+	require.NoError(t, err)
+	want, err := openapi.FromSwagger(src, types, alternates...)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../../../api/openapi.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "api/openapi.json is out of date; run go generate ./api")
+
+	_, err = openapi.Load(got)
+	assert.NoError(t, err)
+}
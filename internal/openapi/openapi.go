@@ -0,0 +1,247 @@
+// Package openapi holds the OpenAPI 3.1 description of the HTTP API and
+// validates requests and responses against it.
+//
+// api/openapi.json is generated from the swag output (api/swagger.json): the
+// operations are converted as documented, and the component schemas are
+// reflected from the Go types the handlers actually encode, so that required
+// and nullable fields match what the server sends. Run go generate ./api after
+// regenerating the Swagger documentation.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+// Document is the subset of an OpenAPI 3.1 document the API uses.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the document metadata.
+type Info struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	TermsOfService string   `json:"termsOfService,omitempty"`
+	Contact        *Contact `json:"contact,omitempty"`
+	License        *License `json:"license,omitempty"`
+	Version        string   `json:"version"`
+}
+
+// Contact is the API contact information.
+type Contact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// License is the API license.
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Server is a base URL the API is served under.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+// Operation is a single API operation.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describes a response for one status code.
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType holds the schema of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is the subset of JSON Schema 2020-12 used by the API. A zero Schema
+// accepts any value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+}
+
+// Types is the JSON Schema "type" keyword: a single type name or a list of
+// them, e.g. ["array", "null"].
+type Types []string
+
+// MarshalJSON encodes a single type as a string.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON accepts a string or a list of strings.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings: %w", err)
+	}
+	*t = many
+	return nil
+}
+
+// Has reports whether name is one of the types.
+func (t Types) Has(name string) bool {
+	for _, n := range t {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaRefPrefix prefixes references to component schemas.
+const schemaRefPrefix = "#/components/schemas/"
+
+// Load parses an OpenAPI 3.1 document and checks that every schema
+// reference resolves.
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: parse document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		return nil, fmt.Errorf("openapi: unsupported version %q, want 3.1", doc.OpenAPI)
+	}
+
+	var errs []string
+	check := func(where string, s *Schema) {
+		walk(s, func(s *Schema) {
+			if s.Ref == "" {
+				return
+			}
+			if _, err := doc.resolve(s.Ref); err != nil {
+				errs = append(errs, where+": "+err.Error())
+			}
+		})
+	}
+	for name, s := range doc.Components.Schemas {
+		check(schemaRefPrefix+name, s)
+	}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			where := strings.ToUpper(method) + " " + path
+			for _, p := range op.Parameters {
+				check(where, p.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					check(where, mt.Schema)
+				}
+			}
+			for _, r := range op.Responses {
+				for _, mt := range r.Content {
+					check(where, mt.Schema)
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("openapi: %s", strings.Join(errs, "; "))
+	}
+	return &doc, nil
+}
+
+// Operation returns the operation for method (e.g. "GET") and an OpenAPI
+// path template (e.g. "/devices/{id}"), or nil if the document has none.
+func (d *Document) Operation(method, path string) *Operation {
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+// resolve returns the component schema ref points to.
+func (d *Document) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, schemaRefPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	s, ok := d.Components.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved reference %q", ref)
+	}
+	return s, nil
+}
+
+// walk calls fn for s and every schema nested in it.
+func walk(s *Schema, fn func(*Schema)) {
+	if s == nil {
+		return
+	}
+	fn(s)
+	for _, p := range s.Properties {
+		walk(p, fn)
+	}
+	walk(s.Items, fn)
+	walk(s.AdditionalProperties, fn)
+	for _, alt := range s.AnyOf {
+		walk(alt, fn)
+	}
+}
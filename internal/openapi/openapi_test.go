@@ -0,0 +1,317 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"invalid JSON", `{`, "parse document"},
+		{"swagger 2.0", `{"swagger": "2.0"}`, `unsupported version ""`},
+		{"openapi 3.0", `{"openapi": "3.0.3"}`, `unsupported version "3.0.3"`},
+		{
+			"unresolved reference",
+			`{"openapi": "3.1.0", "components": {"schemas": {"A": {"items": {"$ref": "#/components/schemas/B"}}}}}`,
+			`unresolved reference "#/components/schemas/B"`,
+		},
+		{
+			"external reference",
+			`{"openapi": "3.1.0", "paths": {"/x": {"get": {"responses": {"200": {"description": "OK",
+				"content": {"application/json": {"schema": {"$ref": "other.json#/A"}}}}}}}}}`,
+			`GET /x: unsupported reference "other.json#/A"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load([]byte(tt.doc))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestTypes_JSON(t *testing.T) {
+	data, err := json.Marshal(&Schema{Type: Types{"string"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"string"}`, string(data))
+
+	data, err = json.Marshal(&Schema{Type: Types{"array", "null"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":["array","null"]}`, string(data))
+
+	var s Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"type":["object","null"]}`), &s))
+	assert.Equal(t, Types{"object", "null"}, s.Type)
+	assert.Error(t, json.Unmarshal([]byte(`{"type":1}`), &s))
+}
+
+func TestValidateJSON(t *testing.T) {
+	doc, err := Load([]byte(`{
+		"openapi": "3.1.0",
+		"components": {"schemas": {
+			"Device": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "string"},
+					"updated": {"type": "string", "format": "date-time"},
+					"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+					"attributes": {"type": "object", "additionalProperties": {"type": "number"}},
+					"parent": {"anyOf": [{"$ref": "#/components/schemas/Device"}, {"type": "null"}]}
+				},
+				"required": ["id"]
+			}
+		}}
+	}`))
+	require.NoError(t, err)
+	device := &Schema{Ref: "#/components/schemas/Device"}
+
+	tests := []struct {
+		name string
+		data string
+		want []Violation
+	}{
+		{"minimal", `{"id":"a"}`, nil},
+		{"full", `{"id":"a","updated":"2026-03-14T10:00:00Z","tags":["x"],"attributes":{"w":1.5},"parent":{"id":"b"}}`, nil},
+		{"nullable", `{"id":"a","tags":null,"parent":null}`, nil},
+		{"unknown properties are allowed", `{"id":"a","extra":true}`, nil},
+		{"wrong root type", `[]`, []Violation{{"", "expected object, got array"}}},
+		{"missing required", `{}`, []Violation{{"", `missing required property "id"`}}},
+		{"bad date-time", `{"id":"a","updated":"yesterday"}`, []Violation{{"/updated", `"yesterday" is not an RFC 3339 date-time`}}},
+		{"bad item", `{"id":"a","tags":["x",1]}`, []Violation{{"/tags/1", "expected string, got integer"}}},
+		{"bad additional property", `{"id":"a","attributes":{"a/b":"wide"}}`, []Violation{{"/attributes/a~1b", "expected number, got string"}}},
+		{"bad nested reference", `{"id":"a","parent":{"id":2}}`, []Violation{{"/parent", "object matches none of the allowed schemas"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := doc.ValidateJSON(device, []byte(tt.data))
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), "got %v", err)
+			assert.Equal(t, tt.want, verr.Errors)
+		})
+	}
+
+	t.Run("error message", func(t *testing.T) {
+		err := doc.ValidateJSON(device, []byte(`{"tags":[1,2,3,4,5,6,7,8,9,10,11,12]}`))
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr))
+		assert.Len(t, verr.Errors, maxErrors)
+		assert.Equal(t, 3, verr.More)
+		assert.True(t, strings.HasPrefix(err.Error(), `(root): missing required property "id"; /tags/0: expected string, got integer;`), err.Error())
+		assert.True(t, strings.HasSuffix(err.Error(), "; and 3 more"), err.Error())
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		err := doc.ValidateJSON(device, []byte(`{"id":"a"} {}`))
+		var verr *ValidationError
+		assert.False(t, errors.As(err, &verr))
+		assert.ErrorContains(t, err, "invalid JSON")
+	})
+}
+
+func TestValidateJSON_Numbers(t *testing.T) {
+	doc := &Document{}
+	integer := &Schema{Type: Types{"integer"}}
+	number := &Schema{Type: Types{"number"}}
+
+	assert.NoError(t, doc.ValidateJSON(integer, []byte(`3`)))
+	assert.NoError(t, doc.ValidateJSON(integer, []byte(`3.0`)), "3.0 is an integer in JSON Schema")
+	assert.Error(t, doc.ValidateJSON(integer, []byte(`3.5`)))
+	assert.NoError(t, doc.ValidateJSON(number, []byte(`3`)))
+	assert.NoError(t, doc.ValidateJSON(number, []byte(`3.5`)))
+	assert.Error(t, doc.ValidateJSON(number, []byte(`"3"`)))
+}
+
+func TestValidateParameter(t *testing.T) {
+	doc := &Document{}
+	limit := Parameter{Name: "limit", In: "query", Schema: &Schema{Type: Types{"integer"}}}
+	verbose := Parameter{Name: "verbose", In: "query", Schema: &Schema{Type: Types{"boolean"}}}
+	name := Parameter{Name: "name", In: "query", Schema: &Schema{Type: Types{"string"}}}
+
+	assert.NoError(t, doc.ValidateParameter(limit, "10"))
+	err := doc.ValidateParameter(limit, "ten")
+	assert.EqualError(t, err, `query parameter "limit": expected integer, got "ten"`)
+	var perr *ParameterError
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, &ParameterError{In: "query", Name: "limit", Message: `expected integer, got "ten"`}, perr)
+	assert.NoError(t, doc.ValidateParameter(verbose, "true"))
+	assert.Error(t, doc.ValidateParameter(verbose, "yes please"))
+	assert.NoError(t, doc.ValidateParameter(name, "anything"))
+}
+
+type embedded struct {
+	Shared string `json:"shared"`
+}
+
+type reflected struct {
+	embedded
+	Name     string            `json:"name" example:"lamp"`
+	Note     string            `json:"note,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Parent   *reflected        `json:"parent,omitempty"`
+	Children []reflected       `json:"children,omitempty"`
+	Updated  time.Time         `json:"updated"`
+	Extra    interface{}       `json:"extra"`
+	Ratio    float64           `json:"ratio"`
+	Internal string            `json:"-"`
+}
+
+func TestFromSwagger(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test API", "version": "1.0"},
+		"basePath": "/",
+		"paths": {
+			"/things/{id}": {
+				"post": {
+					"operationId": "updateThing",
+					"consumes": ["application/json"],
+					"produces": ["application/json"],
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "type": "string"},
+						{"name": "dry_run", "in": "query", "type": "boolean"},
+						{"name": "thing", "in": "body", "required": true, "schema": {"$ref": "#/definitions/test.Thing"}}
+					],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"schema": {"type": "array", "items": {"$ref": "#/definitions/test.Thing"}},
+							"headers": {"ETag": {"type": "string", "description": "Entity tag"}}
+						},
+						"204": {"description": "No Content"}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"test.Thing": {"properties": {"name": {"description": "Name of the thing", "example": "lamp"}}}
+		}
+	}`
+	types := map[string]reflect.Type{"test.Thing": reflect.TypeFor[reflected]()}
+
+	out, err := FromSwagger([]byte(swagger), types)
+	require.NoError(t, err)
+	doc, err := Load(out)
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Equal(t, "Test API", doc.Info.Title)
+	assert.Equal(t, []Server{{URL: "/"}}, doc.Servers)
+
+	op := doc.Operation("POST", "/things/{id}")
+	require.NotNil(t, op)
+	assert.Equal(t, "updateThing", op.OperationID)
+	assert.Equal(t, []Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: Types{"string"}}},
+		{Name: "dry_run", In: "query", Schema: &Schema{Type: Types{"boolean"}}},
+	}, op.Parameters)
+	require.NotNil(t, op.RequestBody)
+	assert.True(t, op.RequestBody.Required)
+	assert.Equal(t, "#/components/schemas/test.Thing", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/test.Thing", op.Responses["200"].Content["application/json"].Schema.Items.Ref)
+	assert.Empty(t, op.Responses["204"].Content)
+	assert.Equal(t, map[string]Header{"ETag": {Description: "Entity tag", Schema: &Schema{Type: Types{"string"}}}}, op.Responses["200"].Headers)
+	assert.Empty(t, op.Responses["204"].Headers)
+
+	thing := doc.Components.Schemas["test.Thing"]
+	require.NotNil(t, thing)
+	assert.Equal(t, []string{"extra", "name", "ratio", "shared", "tags", "updated"}, thing.Required)
+	assert.NotContains(t, thing.Properties, "Internal")
+	assert.Equal(t, "Name of the thing", thing.Properties["name"].Description)
+	assert.Equal(t, []any{"lamp"}, thing.Properties["name"].Examples)
+	assert.Equal(t, Types{"array", "null"}, thing.Properties["tags"].Type)
+	assert.Equal(t, Types{"object", "null"}, thing.Properties["labels"].Type)
+	assert.Equal(t, "date-time", thing.Properties["updated"].Format)
+	assert.Equal(t, &Schema{}, thing.Properties["extra"])
+	assert.Equal(t, Types{"number"}, thing.Properties["ratio"].Type)
+	assert.Equal(t, "#/components/schemas/test.Thing", thing.Properties["children"].Items.Ref)
+	assert.Equal(t, []*Schema{{Ref: "#/components/schemas/test.Thing"}, {Type: Types{"null"}}}, thing.Properties["parent"].AnyOf)
+
+	// The generated schema accepts what encoding/json produces.
+	encoded, err := json.Marshal(reflected{Name: "lamp", Children: []reflected{{Name: "bulb"}}})
+	require.NoError(t, err)
+	assert.NoError(t, doc.ValidateJSON(thing, encoded))
+}
+
+func TestFromSwagger_Errors(t *testing.T) {
+	_, err := FromSwagger([]byte(`{"definitions": {"test.Missing": {}}}`), nil)
+	assert.ErrorContains(t, err, `no Go type registered for definition "test.Missing"`)
+
+	_, err = FromSwagger([]byte(`{"definitions": {"test.Chan": {}}}`), map[string]reflect.Type{"test.Chan": reflect.TypeFor[chan int]()})
+	assert.ErrorContains(t, err, "unsupported type chan int")
+
+	_, err = FromSwagger([]byte(`{"paths": {"/x": {"post": {"parameters": [{"name": "f", "in": "formData"}]}}}}`), nil)
+	assert.ErrorContains(t, err, `POST /x: parameter "f": unsupported location "formData"`)
+}
+
+type problem struct {
+	Title  string   `json:"title"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func TestFromSwagger_MediaTypes(t *testing.T) {
+	swagger := `{
+		"paths": {"/x": {"get": {"produces": ["application/json", "text/csv", "application/yaml"], "responses": {
+			"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/test.Error"}}},
+			"404": {"description": "Not Found", "schema": {"$ref": "#/definitions/test.Error"}}
+		}}}},
+		"definitions": {"test.Error": {}}
+	}`
+	types := map[string]reflect.Type{
+		"test.Error":   reflect.TypeFor[embedded](),
+		"test.Problem": reflect.TypeFor[problem](),
+	}
+
+	out, err := FromSwagger([]byte(swagger), types, Alternate{Of: "test.Error", MediaType: "application/problem+json", Definition: "test.Problem"})
+	require.NoError(t, err)
+	doc, err := Load(out)
+	require.NoError(t, err)
+
+	op := doc.Operation("GET", "/x")
+	require.NotNil(t, op)
+	assert.Equal(t, map[string]MediaType{
+		"application/json":         {Schema: &Schema{Ref: "#/components/schemas/test.Error"}},
+		"application/problem+json": {Schema: &Schema{Ref: "#/components/schemas/test.Problem"}},
+	}, op.Responses["404"].Content)
+	list := &Schema{Type: Types{"array"}, Items: &Schema{Ref: "#/components/schemas/test.Error"}}
+	assert.Equal(t, map[string]MediaType{
+		"application/json": {Schema: list},
+		"application/yaml": {Schema: list},
+		"text/csv":         {Schema: &Schema{Type: Types{"string"}}},
+	}, op.Responses["200"].Content)
+	require.Contains(t, doc.Components.Schemas, "test.Problem")
+	assert.Equal(t, []string{"title"}, doc.Components.Schemas["test.Problem"].Required)
+
+	stream := strings.Replace(swagger, `"application/json", "text/csv", "application/yaml"`, `"text/event-stream"`, 1)
+	out, err = FromSwagger([]byte(stream), types, Alternate{Of: "test.Error", MediaType: "application/problem+json", Definition: "test.Problem"})
+	require.NoError(t, err)
+	doc, err = Load(out)
+	require.NoError(t, err)
+	op = doc.Operation("GET", "/x")
+	require.NotNil(t, op)
+	assert.Equal(t, map[string]MediaType{
+		"application/json":         {Schema: &Schema{Ref: "#/components/schemas/test.Error"}},
+		"application/problem+json": {Schema: &Schema{Ref: "#/components/schemas/test.Problem"}},
+	}, op.Responses["404"].Content)
+	assert.Equal(t, map[string]MediaType{
+		"text/event-stream": {Schema: &Schema{Type: Types{"string"}}},
+	}, op.Responses["200"].Content)
+
+	_, err = FromSwagger([]byte(swagger), types, Alternate{Of: "test.Error", MediaType: "text/plain", Definition: "test.Text"})
+	assert.ErrorContains(t, err, `no Go type registered for definition "test.Text"`)
+}
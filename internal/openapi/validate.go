@@ -0,0 +1,253 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrors bounds the number of problems a ValidationError reports.
+const maxErrors = 10
+
+// ValidationError lists the ways a value violates a schema. Its message
+// prefixes each violation with the JSON pointer of the offending value, or
+// "(root)", e.g. "/parameters/brightness: expected number, got string".
+type ValidationError struct {
+	Errors []Violation
+	// More counts the violations left out of Errors.
+	More int
+}
+
+// Violation is a single way in which a value violates a schema.
+type Violation struct {
+	// Pointer is the JSON pointer (RFC 6901) of the offending value; "" is
+	// the whole value.
+	Pointer string
+	Message string
+}
+
+func (v Violation) String() string {
+	return location(v.Pointer) + ": " + v.Message
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors)+1)
+	for _, v := range e.Errors {
+		msgs = append(msgs, v.String())
+	}
+	if e.More > 0 {
+		msgs = append(msgs, fmt.Sprintf("and %d more", e.More))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ParameterError is returned by ValidateParameter for a value that does not
+// match the parameter's schema.
+type ParameterError struct {
+	In      string
+	Name    string
+	Message string
+}
+
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("%s parameter %q: %s", e.In, e.Name, e.Message)
+}
+
+// ValidateJSON checks that data is a single JSON value matching s. It
+// returns a *ValidationError for schema violations and a plain error for
+// malformed JSON.
+func (d *Document) ValidateJSON(s *Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+	}
+	return d.ValidateValue(s, v)
+}
+
+// ValidateValue checks that v, a value decoded by encoding/json (numbers as
+// json.Number or float64), matches s.
+func (d *Document) ValidateValue(s *Schema, v any) error {
+	var errs []Violation
+	d.validate(s, v, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) > maxErrors {
+		return &ValidationError{Errors: errs[:maxErrors], More: len(errs) - maxErrors}
+	}
+	return &ValidationError{Errors: errs}
+}
+
+// ValidateParameter checks a path, query or header parameter value, given
+// as a string, against p's schema. Mismatches are reported as a
+// *ParameterError.
+func (d *Document) ValidateParameter(p Parameter, value string) error {
+	s := p.Schema
+	for s != nil && s.Ref != "" {
+		var err error
+		if s, err = d.resolve(s.Ref); err != nil {
+			return err
+		}
+	}
+	if s == nil || len(s.Type) == 0 || s.Type.Has("string") {
+		return nil
+	}
+	var v any = value
+	switch {
+	case s.Type.Has("integer"), s.Type.Has("number"):
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			v = json.Number(value)
+		}
+	case s.Type.Has("boolean"):
+		if b, err := strconv.ParseBool(value); err == nil {
+			v = b
+		}
+	}
+	if !matchesType(s.Type, v) {
+		return &ParameterError{In: p.In, Name: p.Name, Message: fmt.Sprintf("expected %s, got %q", strings.Join(s.Type, " or "), value)}
+	}
+	return nil
+}
+
+func (d *Document) validate(s *Schema, v any, ptr string, errs *[]Violation) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		target, err := d.resolve(s.Ref)
+		if err != nil {
+			*errs = append(*errs, Violation{ptr, err.Error()})
+			return
+		}
+		d.validate(target, v, ptr, errs)
+		return
+	}
+
+	if len(s.AnyOf) > 0 {
+		for _, alt := range s.AnyOf {
+			var altErrs []Violation
+			d.validate(alt, v, ptr, &altErrs)
+			if len(altErrs) == 0 {
+				return
+			}
+		}
+		*errs = append(*errs, Violation{ptr, typeOf(v) + " matches none of the allowed schemas"})
+		return
+	}
+
+	if len(s.Type) > 0 && !matchesType(s.Type, v) {
+		*errs = append(*errs, Violation{ptr, fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), typeOf(v))})
+		return
+	}
+
+	switch v := v.(type) {
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				*errs = append(*errs, Violation{ptr, fmt.Sprintf("%q is not an RFC 3339 date-time", v)})
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, Violation{ptr, fmt.Sprintf("missing required property %q", name)})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := ptr + "/" + escapePointer(k)
+			if p, ok := s.Properties[k]; ok {
+				d.validate(p, v[k], child, errs)
+			} else if s.AdditionalProperties != nil {
+				d.validate(s.AdditionalProperties, v[k], child, errs)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			d.validate(s.Items, item, ptr+"/"+strconv.Itoa(i), errs)
+		}
+	}
+}
+
+// matchesType reports whether v is an instance of one of types.
+func matchesType(types Types, v any) bool {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if isInteger(v) {
+				return true
+			}
+		case "number":
+			if typeOf(v) == "integer" || typeOf(v) == "number" {
+				return true
+			}
+		default:
+			if typeOf(v) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isInteger(v any) bool {
+	switch n := v.(type) {
+	case json.Number:
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == float64(int64(f))
+	case float64:
+		return n == float64(int64(n))
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type name of a decoded JSON value.
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number, float64:
+		if isInteger(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// location names the value at JSON pointer ptr in error messages.
+func location(ptr string) string {
+	if ptr == "" {
+		return "(root)"
+	}
+	return ptr
+}
+
+// escapePointer escapes a JSON pointer reference token (RFC 6901).
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,111 @@
+// Package requestid carries a per-request correlation ID through
+// context.Context so that it reaches log records, command history and
+// outbound calls, not just the HTTP layer.
+package requestid
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header used to receive and forward request IDs.
+const Header = "X-Request-ID"
+
+// MaxLength is the longest inbound request ID that is accepted.
+const MaxLength = 128
+
+// LogKey is the slog attribute key used for request IDs.
+const LogKey = "request_id"
+
+type contextKey struct{}
+
+// New generates a fresh request ID.
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether id is acceptable as an inbound request ID: non-empty,
+// at most MaxLength bytes, and limited to letters, digits and "-_.:". The
+// restriction keeps IDs safe to echo in headers and log lines.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Transport wraps base so that outbound requests carry the request ID from
+// their context in the X-Request-ID header. A header already set by the
+// caller is left alone. A nil base uses http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		id := FromContext(req.Context())
+		if id == "" || req.Header.Get(Header) != "" {
+			return base.RoundTrip(req)
+		}
+		// RoundTrippers must not modify the caller's request.
+		req = req.Clone(req.Context())
+		req.Header.Set(Header, id)
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// NewLogHandler wraps next so that records logged with a context carrying a
+// request ID (slog.InfoContext and friends) get a request_id attribute.
+func NewLogHandler(next slog.Handler) slog.Handler {
+	return &logHandler{next: next}
+}
+
+type logHandler struct {
+	next slog.Handler
+}
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,92 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "uuid", id: "3f2b8c1e-9a4d-4e2f-8b1a-2c3d4e5f6a7b", want: true},
+		{name: "proxy style", id: "req_01HZX:edge-1.lan", want: true},
+		{name: "empty", id: "", want: false},
+		{name: "too long", id: strings.Repeat("a", MaxLength+1), want: false},
+		{name: "max length", id: strings.Repeat("a", MaxLength), want: true},
+		{name: "space", id: "abc def", want: false},
+		{name: "header injection", id: "abc\r\nSet-Cookie: x=1", want: false},
+		{name: "log injection quote", id: `abc"level=ERROR`, want: false},
+		{name: "non-ascii", id: "abcé", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Valid(tt.id))
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+
+	ctx := NewContext(context.Background(), "abc-123")
+	assert.Equal(t, "abc-123", FromContext(ctx))
+}
+
+func TestNew(t *testing.T) {
+	id := New()
+	assert.True(t, Valid(id))
+	assert.NotEqual(t, id, New())
+}
+
+func TestTransport(t *testing.T) {
+	var got string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(Header)
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: Transport(nil)}
+
+	req, _ := http.NewRequestWithContext(NewContext(context.Background(), "forward-me"), http.MethodGet, upstream.URL, nil)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "forward-me", got)
+	assert.Empty(t, req.Header.Get(Header), "caller's request must not be modified")
+
+	req, _ = http.NewRequestWithContext(NewContext(context.Background(), "from-ctx"), http.MethodGet, upstream.URL, nil)
+	req.Header.Set(Header, "explicit")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "explicit", got, "an explicit header wins")
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.InfoContext(NewContext(context.Background(), "log-id-1"), "with id")
+	assert.Contains(t, buf.String(), `"request_id":"log-id-1"`)
+
+	buf.Reset()
+	logger.Info("without id")
+	assert.NotContains(t, buf.String(), "request_id")
+
+	buf.Reset()
+	logger.With("component", "x").InfoContext(NewContext(context.Background(), "log-id-2"), "derived")
+	assert.Contains(t, buf.String(), `"request_id":"log-id-2"`)
+	assert.Contains(t, buf.String(), `"component":"x"`)
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * *", "expected 5 fields (minute hour day-of-month month day-of-week), got 4"},
+		{"60 * * * *", "minute: 60 is out of range 0-59"},
+		{"* 24 * * *", "hour: 24 is out of range 0-23"},
+		{"* * 0 * *", "day of month: 0 is out of range 1-31"},
+		{"* * * foo *", `month: invalid value "foo"`},
+		{"* * * * 8", "day of week: 8 is out of range 0-7"},
+		{"*/0 * * * *", `minute: invalid step "0"`},
+		{"30-10 * * * *", `minute: range "30-10" is reversed`},
+		{"@sometimes", `unknown descriptor "@sometimes"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr, time.UTC)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, err.Error())
+		})
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	from := time.Date(2026, time.March, 4, 10, 17, 30, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 22 * * *", time.Date(2026, 3, 4, 22, 0, 0, 0, time.UTC)},
+		{"0 8 * * MON-FRI", time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * 7", time.Date(2026, 3, 8, 8, 0, 0, 0, time.UTC)},
+		{"5/20 9-17/4 * * *", time.Date(2026, 3, 4, 13, 5, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 1st or any Friday.
+		{"0 0 1 * FRI", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 FEB *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron(tt.expr, time.UTC)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Next(from))
+		})
+	}
+}
+
+func TestCron_Next_Never(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *", time.UTC)
+	require.NoError(t, err)
+	assert.True(t, c.Next(time.Now()).IsZero())
+}
+
+func TestCron_Next_TimeZone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	c, err := ParseCron("0 22 * * *", ny)
+	require.NoError(t, err)
+
+	next := c.Next(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 7, 2, 2, 0, 0, 0, time.UTC), next.UTC())
+	assert.Equal(t, 22, next.Hour())
+}
+
+func TestCron_Next_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2:30 does not exist on 8 March 2026, when clocks go from 2:00 to 3:00.
+	c, err := ParseCron("30 2 * * *", ny)
+	require.NoError(t, err)
+	next := c.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, ny))
+	assert.Equal(t, time.Date(2026, 3, 9, 2, 30, 0, 0, ny), next)
+
+	// 1:30 happens twice on 1 November 2026, when clocks go from 2:00 back to 1:00.
+	c, err = ParseCron("30 1 * * *", ny)
+	require.NoError(t, err)
+	first := c.Next(time.Date(2026, 11, 1, 0, 0, 0, 0, ny))
+	second := c.Next(first)
+	assert.Equal(t, time.Hour, second.Sub(first))
+	assert.Equal(t, 1, second.Hour())
+}
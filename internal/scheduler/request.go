@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"strings"
+	"time"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/models"
+)
+
+// ValidateSchedule checks r and returns every invalid field, located by JSON
+// pointer.
+func ValidateSchedule(r *models.ScheduleRequest) []models.FieldError {
+	var errs []models.FieldError
+	add := func(pointer, detail string) {
+		errs = append(errs, models.FieldError{Pointer: pointer, Detail: detail})
+	}
+
+	switch d, ok := homeassistant.GetDevice(r.DeviceID); {
+	case strings.TrimSpace(r.DeviceID) == "":
+		add("/device_id", "device_id is required")
+	case !ok:
+		add("/device_id", "device not found: "+r.DeviceID)
+	case !d.Controllable:
+		add("/device_id", "device is not controllable: "+r.DeviceID)
+	}
+	if strings.TrimSpace(r.Action) == "" {
+		add("/action", "action is required")
+	}
+
+	var when []string
+	if r.At != nil {
+		when = append(when, "at")
+		if !r.At.After(time.Now()) {
+			add("/at", "at must be in the future")
+		}
+	}
+	if r.Delay != "" {
+		when = append(when, "delay")
+		if d, err := time.ParseDuration(r.Delay); err != nil || d <= 0 {
+			add("/delay", "delay must be a positive duration such as 30m or 1h30m")
+		}
+	}
+	if r.Cron != "" {
+		when = append(when, "cron")
+		if loc, err := LoadLocation(r.Timezone); err != nil {
+			add("/timezone", "unknown time zone: "+r.Timezone)
+		} else if c, err := ParseCron(r.Cron, loc); err != nil {
+			add("/cron", "invalid cron expression: "+err.Error())
+		} else if c.Next(time.Now()).IsZero() {
+			add("/cron", "the cron expression never matches")
+		}
+	} else if r.Timezone != "" {
+		add("/timezone", "timezone only applies to cron schedules; give at with an offset instead")
+	}
+	switch {
+	case len(when) == 0:
+		add("/at", "one of at, delay and cron is required")
+	case len(when) > 1:
+		add("/"+when[1], "only one of at, delay and cron may be given")
+	}
+
+	switch r.MissedRunPolicy {
+	case "", PolicySkip, PolicyRunOnce:
+	default:
+		add("/missed_run_policy", "missed_run_policy must be skip or run_once")
+	}
+	return errs
+}
+
+// LoadLocation returns the time zone name, or time.Local if name is empty.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
@@ -0,0 +1,482 @@
+// Package scheduler runs device commands at a set time, after a delay or on a
+// cron expression. Scheduled commands are executed through
+// homeassistant.ExecuteCommandContext, so they land in the command history
+// like any other command, and schedules can be persisted to a JSON file so
+// they survive a restart.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-github/internal/homeassistant"
+	"go-github/internal/metrics"
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+	"go-github/internal/tracing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Kinds of schedule.
+const (
+	KindOnce = "once"
+	KindCron = "cron"
+)
+
+// Missed-run policies. A run is missed when it starts more than the grace
+// period after it was due, e.g. because the server was down.
+const (
+	PolicySkip    = "skip"
+	PolicyRunOnce = "run_once"
+)
+
+// States of a schedule.
+const (
+	StateScheduled = "scheduled"
+	StateCompleted = "completed"
+)
+
+// Statuses of a schedule run.
+const (
+	RunSuccess = "success"
+	RunFailed  = "failed"
+	RunSkipped = "skipped"
+)
+
+// defaultMissedRunGrace is how late a run may start before it counts as
+// missed, unless WithMissedRunGrace says otherwise.
+const defaultMissedRunGrace = time.Minute
+
+// ErrNotFound is returned for an unknown schedule ID.
+var ErrNotFound = errors.New("schedule not found")
+
+// executeFunc executes a device command; it is
+// homeassistant.ExecuteCommandContext outside tests.
+type executeFunc func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error)
+
+// Scheduler holds the schedules and runs them when they are due.
+type Scheduler struct {
+	file    string
+	grace   time.Duration
+	now     func() time.Time
+	execute executeFunc
+
+	mu        sync.Mutex
+	schedules map[string]*entry
+
+	// wake interrupts Run's wait when the schedules change.
+	wake chan struct{}
+}
+
+// entry is a schedule with its parsed cron expression, if any.
+type entry struct {
+	models.Schedule
+	cron *Cron
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithFile persists the schedules to path. Without it, schedules are kept in
+// memory only.
+func WithFile(path string) Option {
+	return func(s *Scheduler) { s.file = path }
+}
+
+// WithMissedRunGrace sets how late a run may start before the missed-run
+// policy applies to it. Zero keeps the default of one minute.
+func WithMissedRunGrace(d time.Duration) Option {
+	return func(s *Scheduler) {
+		if d > 0 {
+			s.grace = d
+		}
+	}
+}
+
+// New creates a Scheduler with no schedules; call Load to read persisted
+// ones and Run to start running them.
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{
+		grace:     defaultMissedRunGrace,
+		now:       time.Now,
+		execute:   homeassistant.ExecuteCommandContext,
+		schedules: map[string]*entry{},
+		wake:      make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var (
+	defaultMu sync.Mutex
+	defaultS  *Scheduler
+)
+
+// Default returns the scheduler used by the HTTP and MCP handlers. Until
+// SetDefault is called it is an in-memory scheduler that is not running.
+func Default() *Scheduler {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultS == nil {
+		defaultS = New()
+	}
+	return defaultS
+}
+
+// SetDefault replaces the scheduler returned by Default.
+func SetDefault(s *Scheduler) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultS = s
+}
+
+// fileContents is the layout of the schedules file.
+type fileContents struct {
+	Schedules []models.Schedule `json:"schedules"`
+}
+
+// Load reads the schedules from the file given by WithFile, replacing those
+// in memory. A missing file is not an error. Runs missed while the server was
+// down are handled by the missed-run policy once Run starts.
+func (s *Scheduler) Load() error {
+	if s.file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read schedules: %w", err)
+	}
+	var contents fileContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return fmt.Errorf("parse schedules %s: %w", s.file, err)
+	}
+
+	loaded := make(map[string]*entry, len(contents.Schedules))
+	for _, sched := range contents.Schedules {
+		e := &entry{Schedule: sched}
+		if sched.Kind == KindCron {
+			loc, err := LoadLocation(sched.Timezone)
+			if err != nil {
+				return fmt.Errorf("schedule %s: unknown time zone %q", sched.ID, sched.Timezone)
+			}
+			if e.cron, err = ParseCron(sched.Cron, loc); err != nil {
+				return fmt.Errorf("schedule %s: %w", sched.ID, err)
+			}
+		}
+		loaded[sched.ID] = e
+	}
+
+	s.mu.Lock()
+	s.schedules = loaded
+	s.mu.Unlock()
+	s.notify()
+	return nil
+}
+
+// Run runs schedules as they become due until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	slog.Info("scheduler started", "schedules", len(s.List()), "file", s.file)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		s.runDue(ctx)
+
+		wait := time.Hour
+		if next, ok := s.nextRun(); ok {
+			wait = max(next.Sub(s.now()), 0)
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// notify wakes Run to recompute its wait.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// nextRun returns the earliest next run of the pending schedules.
+func (s *Scheduler) nextRun() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, e := range s.schedules {
+		if e.NextRun != nil && (next.IsZero() || e.NextRun.Before(next)) {
+			next = *e.NextRun
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// runDue runs every schedule that is due, earliest first.
+func (s *Scheduler) runDue(ctx context.Context) {
+	now := s.now()
+	s.mu.Lock()
+	var due []string
+	for id, e := range s.schedules {
+		if e.NextRun != nil && !e.NextRun.After(now) {
+			due = append(due, id)
+		}
+	}
+	slices.SortFunc(due, func(a, b string) int {
+		return s.schedules[a].NextRun.Compare(*s.schedules[b].NextRun)
+	})
+	s.mu.Unlock()
+
+	for _, id := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		s.run(ctx, id)
+	}
+}
+
+// run executes one due run of the schedule id, or skips it if it was missed
+// and the policy says so, and then moves the schedule to its next run.
+func (s *Scheduler) run(ctx context.Context, id string) {
+	s.mu.Lock()
+	e, ok := s.schedules[id]
+	if !ok || e.NextRun == nil {
+		s.mu.Unlock()
+		return
+	}
+	sched := e.Schedule
+	s.mu.Unlock()
+
+	start := s.now()
+	run := models.ScheduleRun{ScheduledFor: *sched.NextRun, StartedAt: start}
+	missed := start.Sub(run.ScheduledFor) > s.grace
+
+	if missed && sched.MissedRunPolicy == PolicySkip {
+		run.Status = RunSkipped
+		slog.WarnContext(ctx, "scheduled run missed, skipping",
+			"schedule_id", id, "device_id", sched.DeviceID, "scheduled_for", run.ScheduledFor)
+	} else {
+		run.RequestID = requestid.New()
+		runCtx := requestid.NewContext(ctx, run.RequestID)
+		runCtx, span := tracing.Tracer().Start(runCtx, "scheduler.run")
+		span.SetAttributes(
+			attribute.String("scheduler.schedule_id", id),
+			attribute.Bool("scheduler.missed", missed),
+			attribute.String("request_id", run.RequestID),
+		)
+		cmd := models.Command{Action: sched.Action, Parameters: maps.Clone(sched.Parameters)}
+		if _, err := s.execute(runCtx, sched.DeviceID, cmd); err != nil {
+			run.Status, run.Error = RunFailed, err.Error()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			run.Status = RunSuccess
+		}
+		span.End()
+		slog.InfoContext(runCtx, "scheduled command executed",
+			"schedule_id", id, "device_id", sched.DeviceID, "action", sched.Action,
+			"status", run.Status, "missed", missed)
+	}
+	metrics.SchedulerRunsTotal.WithLabelValues(run.Status).Inc()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok = s.schedules[id]
+	if !ok {
+		// Cancelled while running.
+		return
+	}
+	e.LastRun = &run
+	if run.Status != RunSkipped {
+		e.Runs++
+	}
+	e.NextRun = nil
+	if e.cron != nil {
+		// One late run stands for every occurrence that was missed.
+		if next := e.cron.Next(s.now()); !next.IsZero() {
+			e.NextRun = &next
+		}
+	}
+	if e.NextRun == nil {
+		e.State = StateCompleted
+	}
+	if err := s.saveLocked(); err != nil {
+		slog.ErrorContext(ctx, "saving schedules failed", "error", err)
+	}
+}
+
+// Create adds a schedule for r, which must be valid.
+func (s *Scheduler) Create(r models.ScheduleRequest) (models.Schedule, error) {
+	now := s.now()
+	id, err := uuid.NewV7()
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	params := r.Parameters
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	policy := r.MissedRunPolicy
+	if policy == "" {
+		policy = PolicyRunOnce
+	}
+	e := &entry{Schedule: models.Schedule{
+		ID:              id.String(),
+		Name:            strings.TrimSpace(r.Name),
+		DeviceID:        r.DeviceID,
+		Action:          r.Action,
+		Parameters:      params,
+		Kind:            KindOnce,
+		MissedRunPolicy: policy,
+		State:           StateScheduled,
+		CreatedAt:       now,
+	}}
+
+	var next time.Time
+	switch {
+	case r.At != nil:
+		next = *r.At
+	case r.Delay != "":
+		d, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return models.Schedule{}, err
+		}
+		next = now.Add(d)
+	default:
+		loc, err := LoadLocation(r.Timezone)
+		if err != nil {
+			return models.Schedule{}, err
+		}
+		if e.cron, err = ParseCron(r.Cron, loc); err != nil {
+			return models.Schedule{}, err
+		}
+		e.Kind, e.Cron, e.Timezone = KindCron, e.cron.String(), r.Timezone
+		next = e.cron.Next(now)
+	}
+	e.NextRun = &next
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules[e.ID] = e
+	if err := s.saveLocked(); err != nil {
+		delete(s.schedules, e.ID)
+		return models.Schedule{}, err
+	}
+	s.notify()
+	return e.copy(), nil
+}
+
+// List returns every schedule, ordered by ID, which is the order they were
+// created in.
+func (s *Scheduler) List() []models.Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]models.Schedule, 0, len(s.schedules))
+	for _, e := range s.schedules {
+		out = append(out, e.copy())
+	}
+	slices.SortFunc(out, func(a, b models.Schedule) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// Get returns the schedule id, or ErrNotFound.
+func (s *Scheduler) Get(id string) (models.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.schedules[id]
+	if !ok {
+		return models.Schedule{}, ErrNotFound
+	}
+	return e.copy(), nil
+}
+
+// Cancel removes the schedule id, so that it does not run again, and returns
+// it. A run in progress is not interrupted.
+func (s *Scheduler) Cancel(id string) (models.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.schedules[id]
+	if !ok {
+		return models.Schedule{}, ErrNotFound
+	}
+	delete(s.schedules, id)
+	if err := s.saveLocked(); err != nil {
+		s.schedules[id] = e
+		return models.Schedule{}, err
+	}
+	s.notify()
+	return e.copy(), nil
+}
+
+// copy returns the schedule of e, sharing nothing with it.
+func (e *entry) copy() models.Schedule {
+	out := e.Schedule
+	out.Parameters = maps.Clone(e.Parameters)
+	if e.NextRun != nil {
+		next := *e.NextRun
+		out.NextRun = &next
+	}
+	if e.LastRun != nil {
+		last := *e.LastRun
+		out.LastRun = &last
+	}
+	return out
+}
+
+// saveLocked writes the schedules to the file, if there is one, replacing
+// it atomically. s.mu must be held.
+func (s *Scheduler) saveLocked() error {
+	if s.file == "" {
+		return nil
+	}
+	contents := fileContents{Schedules: make([]models.Schedule, 0, len(s.schedules))}
+	for _, e := range s.schedules {
+		contents.Schedules = append(contents.Schedules, e.Schedule)
+	}
+	slices.SortFunc(contents.Schedules, func(a, b models.Schedule) int { return strings.Compare(a.ID, b.ID) })
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.file), filepath.Base(s.file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save schedules: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save schedules: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save schedules: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.file); err != nil {
+		return fmt.Errorf("save schedules: %w", err)
+	}
+	return nil
+}
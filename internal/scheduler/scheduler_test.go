@@ -0,0 +1,319 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// call is a command executed by a test scheduler.
+type call struct {
+	deviceID  string
+	action    string
+	requestID string
+}
+
+// testScheduler returns a scheduler whose clock is *now and which records
+// the commands it executes instead of sending them.
+func testScheduler(t *testing.T, now *time.Time, opts ...Option) (*Scheduler, *[]call) {
+	t.Helper()
+	s := New(opts...)
+	s.now = func() time.Time { return *now }
+	var calls []call
+	s.execute = func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+		calls = append(calls, call{deviceID, cmd.Action, requestid.FromContext(ctx)})
+		if deviceID == "broken" {
+			return models.CommandResult{}, errors.New("device not found")
+		}
+		return models.CommandResult{Status: "success", DeviceID: deviceID, Action: cmd.Action}, nil
+	}
+	return s, &calls
+}
+
+func TestScheduleRequest_Validate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name string
+		req  models.ScheduleRequest
+		want []string
+	}{
+		{"delay", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_off", Delay: "30m"}, nil},
+		{"at", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_off", At: &future}, nil},
+		{"cron", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "0 7 * * MON-FRI", Timezone: "Europe/Berlin", MissedRunPolicy: PolicySkip}, nil},
+		{"missing", models.ScheduleRequest{}, []string{
+			"/device_id: device_id is required",
+			"/action: action is required",
+			"/at: one of at, delay and cron is required",
+		}},
+		{"device", models.ScheduleRequest{DeviceID: "readonly-sensor-001", Action: "turn_on", Delay: "1m"}, []string{
+			"/device_id: device is not controllable: readonly-sensor-001",
+		}},
+		{"unknown device", models.ScheduleRequest{DeviceID: "nope", Action: "turn_on", Delay: "1m"}, []string{
+			"/device_id: device not found: nope",
+		}},
+		{"two times", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: "1m", Cron: "* * * * *"}, []string{
+			"/cron: only one of at, delay and cron may be given",
+		}},
+		{"past", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", At: &past}, []string{
+			"/at: at must be in the future",
+		}},
+		{"negative delay", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: "-5m"}, []string{
+			"/delay: delay must be a positive duration such as 30m or 1h30m",
+		}},
+		{"bad cron", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "61 * * * *"}, []string{
+			"/cron: invalid cron expression: minute: 61 is out of range 0-59",
+		}},
+		{"never", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "0 0 31 4 *"}, []string{
+			"/cron: the cron expression never matches",
+		}},
+		{"timezone", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "* * * * *", Timezone: "Mars/Olympus"}, []string{
+			"/timezone: unknown time zone: Mars/Olympus",
+		}},
+		{"timezone without cron", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: "1m", Timezone: "UTC"}, []string{
+			"/timezone: timezone only applies to cron schedules; give at with an offset instead",
+		}},
+		{"policy", models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: "1m", MissedRunPolicy: "always"}, []string{
+			"/missed_run_policy: missed_run_policy must be skip or run_once",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range ValidateSchedule(&tt.req) {
+				got = append(got, f.Pointer+": "+f.Detail)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheduler_Once(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	s, calls := testScheduler(t, &now)
+
+	sched, err := s.Create(models.ScheduleRequest{Name: "Porch light", DeviceID: "device-001", Action: "turn_off", Delay: "30m"})
+	require.NoError(t, err)
+	assert.Equal(t, KindOnce, sched.Kind)
+	assert.Equal(t, StateScheduled, sched.State)
+	assert.Equal(t, PolicyRunOnce, sched.MissedRunPolicy)
+	assert.Equal(t, map[string]interface{}{}, sched.Parameters)
+	require.NotNil(t, sched.NextRun)
+	assert.Equal(t, now.Add(30*time.Minute), *sched.NextRun)
+
+	now = now.Add(29 * time.Minute)
+	s.runDue(context.Background())
+	assert.Empty(t, *calls)
+
+	now = now.Add(time.Minute)
+	s.runDue(context.Background())
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "device-001", (*calls)[0].deviceID)
+	assert.Equal(t, "turn_off", (*calls)[0].action)
+	assert.NotEmpty(t, (*calls)[0].requestID)
+
+	got, err := s.Get(sched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCompleted, got.State)
+	assert.Nil(t, got.NextRun)
+	assert.Equal(t, 1, got.Runs)
+	require.NotNil(t, got.LastRun)
+	assert.Equal(t, RunSuccess, got.LastRun.Status)
+	assert.Equal(t, (*calls)[0].requestID, got.LastRun.RequestID)
+
+	s.runDue(context.Background())
+	assert.Len(t, *calls, 1, "a completed schedule does not run again")
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	s, calls := testScheduler(t, &now)
+
+	sched, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "0 * * * *", Timezone: "UTC"})
+	require.NoError(t, err)
+	assert.Equal(t, KindCron, sched.Kind)
+	assert.Equal(t, "0 * * * *", sched.Cron)
+	assert.Equal(t, time.Date(2026, 5, 1, 21, 0, 0, 0, time.UTC), *sched.NextRun)
+
+	now = time.Date(2026, 5, 1, 21, 0, 5, 0, time.UTC)
+	s.runDue(context.Background())
+	require.Len(t, *calls, 1)
+
+	got, err := s.Get(sched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateScheduled, got.State)
+	assert.Equal(t, time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC), *got.NextRun)
+	assert.Equal(t, 1, got.Runs)
+}
+
+func TestScheduler_FailedRun(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	s, _ := testScheduler(t, &now)
+
+	// Validation happens before Create; a device can still disappear later.
+	sched, err := s.Create(models.ScheduleRequest{DeviceID: "broken", Action: "turn_on", Delay: "1m"})
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	s.runDue(context.Background())
+
+	got, err := s.Get(sched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCompleted, got.State)
+	assert.Equal(t, 1, got.Runs)
+	assert.Equal(t, RunFailed, got.LastRun.Status)
+	assert.Equal(t, "device not found", got.LastRun.Error)
+}
+
+func TestScheduler_MissedRuns(t *testing.T) {
+	start := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		policy    string
+		late      time.Duration
+		wantCalls int
+		wantRun   string
+	}{
+		{PolicyRunOnce, 30 * time.Second, 1, RunSuccess},
+		{PolicySkip, 30 * time.Second, 1, RunSuccess},
+		{PolicyRunOnce, 3 * time.Hour, 1, RunSuccess},
+		{PolicySkip, 3 * time.Hour, 0, RunSkipped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy+"/"+tt.late.String(), func(t *testing.T) {
+			now := start
+			s, calls := testScheduler(t, &now)
+			sched, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "0 * * * *", Timezone: "UTC", MissedRunPolicy: tt.policy})
+			require.NoError(t, err)
+
+			// Three hourly runs are due, but only one runs (or is skipped).
+			now = sched.NextRun.Add(tt.late)
+			s.runDue(context.Background())
+			assert.Len(t, *calls, tt.wantCalls)
+
+			got, err := s.Get(sched.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRun, got.LastRun.Status)
+			assert.Equal(t, *sched.NextRun, got.LastRun.ScheduledFor)
+			assert.Equal(t, now, got.LastRun.StartedAt)
+			assert.Equal(t, tt.wantCalls, got.Runs)
+			assert.True(t, got.NextRun.After(now))
+		})
+	}
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	s, calls := testScheduler(t, &now)
+
+	sched, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_off", Delay: "10m"})
+	require.NoError(t, err)
+	cancelled, err := s.Cancel(sched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sched.ID, cancelled.ID)
+
+	now = now.Add(time.Hour)
+	s.runDue(context.Background())
+	assert.Empty(t, *calls)
+
+	_, err = s.Get(sched.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Cancel(sched.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestScheduler_List(t *testing.T) {
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	s, _ := testScheduler(t, &now)
+
+	var ids []string
+	for _, delay := range []string{"3h", "1h", "2h"} {
+		sched, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: delay})
+		require.NoError(t, err)
+		ids = append(ids, sched.ID)
+	}
+	var got []string
+	for _, sched := range s.List() {
+		got = append(got, sched.ID)
+	}
+	assert.Equal(t, ids, got, "schedules are listed in the order they were created")
+}
+
+func TestScheduler_Persistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schedules.json")
+	now := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	s, _ := testScheduler(t, &now, WithFile(file))
+	require.NoError(t, s.Load(), "a missing file is not an error")
+
+	once, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_off", Delay: "30m", Parameters: map[string]interface{}{"transition": 5}})
+	require.NoError(t, err)
+	cron, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Cron: "0 7 * * *", Timezone: "Europe/Berlin", MissedRunPolicy: PolicySkip})
+	require.NoError(t, err)
+	gone, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: "1h"})
+	require.NoError(t, err)
+	_, err = s.Cancel(gone.ID)
+	require.NoError(t, err)
+
+	// After a restart, the schedules are back and the missed once run runs.
+	now = now.Add(2 * time.Hour)
+	restarted, calls := testScheduler(t, &now, WithFile(file))
+	require.NoError(t, restarted.Load())
+	list := restarted.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, once.ID, list[0].ID)
+	assert.Equal(t, float64(5), list[0].Parameters["transition"])
+	assert.Equal(t, cron.ID, list[1].ID)
+	assert.Equal(t, "Europe/Berlin", list[1].Timezone)
+
+	restarted.runDue(context.Background())
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "turn_off", (*calls)[0].action)
+
+	again, _ := testScheduler(t, &now, WithFile(file))
+	require.NoError(t, again.Load())
+	got, err := again.Get(once.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCompleted, got.State)
+	assert.Equal(t, RunSuccess, got.LastRun.Status)
+}
+
+func TestScheduler_LoadErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schedules.json")
+	require.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+	assert.ErrorContains(t, New(WithFile(file)).Load(), "parse schedules")
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"schedules":[{"id":"a","kind":"cron","cron":"* *"}]}`), 0o600))
+	assert.EqualError(t, New(WithFile(file)).Load(), "schedule a: expected 5 fields (minute hour day-of-month month day-of-week), got 2")
+}
+
+func TestScheduler_Run(t *testing.T) {
+	s := New()
+	done := make(chan string, 1)
+	s.execute = func(ctx context.Context, deviceID string, cmd models.Command) (models.CommandResult, error) {
+		done <- deviceID
+		return models.CommandResult{Status: "success"}, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Run(ctx) }()
+
+	// The schedule is created while Run waits with nothing scheduled.
+	_, err := s.Create(models.ScheduleRequest{DeviceID: "device-001", Action: "turn_on", Delay: "10ms"})
+	require.NoError(t, err)
+	select {
+	case id := <-done:
+		assert.Equal(t, "device-001", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled command did not run")
+	}
+
+	cancel()
+	assert.NoError(t, <-stopped)
+}
@@ -1,65 +1,451 @@
 package server
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"go-github/api"
+	"go-github/internal/config"
 	"go-github/internal/handlers"
+	"go-github/internal/metrics"
 	"go-github/internal/middleware"
+	"go-github/internal/models"
+	"go-github/internal/openapi"
+	"go-github/internal/services"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Cache-Control directives of the read endpoints. Responses carry an ETag
+// (see handlers.Respond), so revalidating live data costs a 304 without a
+// body. Responses may depend on the API key, so shared caches must not
+// store them.
+var (
+	cacheRevalidate = middleware.CacheControl("private, no-cache")
+	cacheStatic     = middleware.CacheControl("private, max-age=3600")
+	cacheNever      = middleware.CacheControl("no-store")
+)
+
+// eventsRoute streams server-sent events for as long as the client listens.
+const eventsRoute = "/api/v1/events"
+
 // Server represents the HTTP server
 type Server struct {
-	router     *gin.Engine
-	httpServer *http.Server
-	mu         sync.RWMutex
+	router      *gin.Engine
+	httpServer  *http.Server
+	adminRouter *gin.Engine
+	adminServer *http.Server
+	adminPort   string
+	accessLog   middleware.LoggerConfig
+	cfg         *config.Config
+	reloader    *config.Reloader
+	mu          sync.RWMutex
+
+	// draining is set by GracefulShutdown; requests arriving afterwards on
+	// kept-alive connections are answered with 503.
+	draining atomic.Bool
+	// streams is cancelled by GracefulShutdown to end the event streams,
+	// which would otherwise hold the shutdown until its deadline.
+	streams    context.Context
+	endStreams context.CancelFunc
+
+	// Middleware rebuilt by ApplyConfig when the configuration is reloaded.
+	cors      swappable
+	auth      swappable
+	rateLimit swappable
+
+	liveMu           sync.Mutex
+	rateLimitOpts    middleware.RateLimitOptions
+	rateLimitBackend middleware.RateLimitBackend
+}
+
+// swappable is a gin middleware slot whose handler can be replaced while
+// requests are in flight.
+type swappable struct {
+	h atomic.Pointer[gin.HandlerFunc]
+}
+
+func (sw *swappable) set(h gin.HandlerFunc) { sw.h.Store(&h) }
+
+func (sw *swappable) handle(c *gin.Context) { (*sw.h.Load())(c) }
+
+// Option configures optional Server behaviour.
+type Option func(*Server)
+
+// WithConfig configures the server from cfg: the admin (metrics) port, CORS
+// origins, rate limits and API keys. It also installs cfg's service catalog, which the
+// HTTP and MCP surfaces share. Without WithConfig the server uses
+// config.Default().
+func WithConfig(cfg *config.Config) Option {
+	return func(s *Server) {
+		s.cfg = cfg
+		if cfg.Server.MetricsPort != "" {
+			s.adminPort = cfg.Server.MetricsPort
+		}
+		services.SetCatalog(cfg.Services.Models())
+	}
+}
+
+// WithReloader serves POST /admin/reload, which reloads the configuration
+// through r and reports what changed. The endpoint is registered on the admin
+// router when an admin port is configured. Otherwise it is registered on the
+// public router, behind API key authentication, only when API keys are
+// configured, and not served at all without them. Callers wire r.OnReload to
+// ApplyConfig.
+func WithReloader(r *config.Reloader) Option {
+	return func(s *Server) {
+		s.reloader = r
+	}
+}
+
+// WithAdminPort serves /metrics on a separate listener bound to port instead
+// of the public router. The admin listener also serves /health, over plain
+// HTTP, for local health checks. Use RunAdmin to start it.
+func WithAdminPort(port string) Option {
+	return func(s *Server) {
+		s.adminPort = port
+	}
+}
+
+// WithAccessLog overrides the access log sampling and slow request thresholds
+// (default: middleware.DefaultLoggerConfig).
+func WithAccessLog(cfg middleware.LoggerConfig) Option {
+	return func(s *Server) {
+		s.accessLog = cfg
+	}
 }
 
 // New creates a new server instance with middleware chain
-func New() *Server {
+func New(opts ...Option) *Server {
+	s := &Server{accessLog: middleware.DefaultLoggerConfig()}
+	s.streams, s.endStreams = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.cfg == nil {
+		s.cfg = config.Default()
+	}
+	s.applyMiddleware(s.cfg)
+
 	router := gin.New()
 	router.Use(middleware.RequestID())
-	router.Use(middleware.Logger())
+	router.Use(middleware.ClientCertPrincipal())
+	router.Use(middleware.Tracing())
+	router.Use(middleware.LoggerWithConfig(s.accessLog))
+	router.Use(middleware.Metrics())
 	router.Use(middleware.Recovery())
-	router.Use(middleware.CORS())
+	router.Use(s.cors.handle)
+	router.Use(s.rejectWhileDraining)
+	if s.cfg.Server.Compression.Enabled {
+		router.Use(middleware.CompressWithConfig(middleware.CompressConfig{MinSize: s.cfg.Server.Compression.MinBytes}))
+	}
+	router.Use(middleware.BodyLimit(s.cfg.Server.MaxBodyBytes))
+	router.Use(middleware.TimeoutWithConfig(handlerTimeouts(s.cfg.Server.Timeouts)))
+	if validation, ok := openAPIValidation(s.cfg.Server.Validation); ok {
+		router.Use(middleware.OpenAPIValidationWithConfig(validation))
+	}
 
 	// Swagger documentation
 	router.GET("/api/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/api/openapi.json", cacheStatic, openAPIHandler)
 
 	// Health endpoint
-	router.GET("/health", healthHandler)
+	router.GET("/health", cacheNever, healthHandler)
+
+	// Prometheus metrics — registered outside /api/v1 so scrapes are never rate limited
+	if s.adminPort == "" {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	} else {
+		s.adminRouter = gin.New()
+		s.adminRouter.Use(middleware.Recovery())
+		s.adminRouter.GET("/metrics", gin.WrapH(metrics.Handler()))
+		s.adminRouter.GET("/health", cacheNever, healthHandler)
+	}
+
+	if s.reloader != nil {
+		switch {
+		case s.adminRouter != nil:
+			s.adminRouter.POST("/admin/reload", s.reloadHandler)
+		case len(s.cfg.Auth.APIKeys) > 0:
+			router.POST("/admin/reload", s.auth.handle, s.reloadHandler)
+		default:
+			slog.Warn("not serving /admin/reload: it needs an admin port or API keys to be configured")
+		}
+	}
+
+	// Retried POSTs with the same Idempotency-Key are answered from here
+	idempotent := middleware.Idempotency(middleware.NewIdempotencyStore(s.cfg.Server.IdempotencyTTL.Duration))
 
 	// API v1 routes group — rate limiting applied here only
 	v1 := router.Group("/api/v1")
-	v1.Use(middleware.RateLimit())
+	v1.Use(s.rateLimit.handle)
+	v1.Use(s.auth.handle)
 	{
 		// Placeholder for API routes
-		v1.GET("", apiRootHandler)
-		v1.GET("/services", handlers.ListServicesHandler)
+		v1.GET("", cacheStatic, apiRootHandler)
+		v1.GET("/services", cacheRevalidate, handlers.ListServicesHandler)
 
 		// Cluster services endpoint
-		v1.GET("/cluster/services", handlers.ListClusterServicesHandler)
+		v1.GET("/cluster/services", cacheRevalidate, handlers.ListClusterServicesHandler)
+
+		// Server-sent events from the event bus
+		v1.GET("/events", s.untilShutdown, handlers.StreamEventsHandler)
 
 		// HomeAssistant device endpoints
-		v1.POST("/homeassistant/devices/:id/command", handlers.ExecuteCommandHandler)
+		v1.GET("/homeassistant/devices", cacheRevalidate, handlers.DeviceListHandler)
+		v1.GET("/homeassistant/devices/:id", cacheRevalidate, handlers.GetDeviceHandler)
+		v1.POST("/homeassistant/devices/:id/command", idempotent, handlers.ExecuteCommandHandler)
+		v1.POST("/homeassistant/commands\\:batch", idempotent, handlers.ExecuteBatchHandler)
+
+		// Areas and groups of devices
+		v1.GET("/homeassistant/areas", cacheRevalidate, handlers.ListAreasHandler)
+		v1.POST("/homeassistant/areas", handlers.CreateAreaHandler)
+		v1.GET("/homeassistant/areas/:id", cacheRevalidate, handlers.GetAreaHandler)
+		v1.PUT("/homeassistant/areas/:id", handlers.UpdateAreaHandler)
+		v1.DELETE("/homeassistant/areas/:id", handlers.DeleteAreaHandler)
+		v1.GET("/homeassistant/areas/:id/devices", cacheRevalidate, handlers.ListAreaDevicesHandler)
+		v1.PUT("/homeassistant/areas/:id/devices/:device_id", handlers.AddAreaDeviceHandler)
+		v1.DELETE("/homeassistant/areas/:id/devices/:device_id", handlers.RemoveAreaDeviceHandler)
+		v1.POST("/homeassistant/areas/:id/command", idempotent, handlers.ExecuteAreaCommandHandler)
+		v1.GET("/homeassistant/groups", cacheRevalidate, handlers.ListGroupsHandler)
+		v1.POST("/homeassistant/groups", handlers.CreateGroupHandler)
+		v1.GET("/homeassistant/groups/:id", cacheRevalidate, handlers.GetGroupHandler)
+		v1.PUT("/homeassistant/groups/:id", handlers.UpdateGroupHandler)
+		v1.DELETE("/homeassistant/groups/:id", handlers.DeleteGroupHandler)
+		v1.GET("/homeassistant/groups/:id/devices", cacheRevalidate, handlers.ListGroupDevicesHandler)
+		v1.PUT("/homeassistant/groups/:id/devices/:device_id", handlers.AddGroupDeviceHandler)
+		v1.DELETE("/homeassistant/groups/:id/devices/:device_id", handlers.RemoveGroupDeviceHandler)
+		v1.POST("/homeassistant/groups/:id/command", idempotent, handlers.ExecuteGroupCommandHandler)
+
+		// Scenes: captured device states that can be restored
+		v1.GET("/scenes", cacheRevalidate, handlers.ListScenesHandler)
+		v1.POST("/scenes", handlers.CreateSceneHandler)
+		v1.GET("/scenes/:id", cacheRevalidate, handlers.GetSceneHandler)
+		v1.PUT("/scenes/:id", handlers.UpdateSceneHandler)
+		v1.DELETE("/scenes/:id", handlers.DeleteSceneHandler)
+		v1.POST("/scenes/:id/activate", idempotent, handlers.ActivateSceneHandler)
+
+		// Schedules: device commands that run later or on a cron expression
+		v1.GET("/schedules", cacheRevalidate, handlers.ListSchedulesHandler)
+		v1.POST("/schedules", idempotent, handlers.CreateScheduleHandler)
+		v1.GET("/schedules/:id", cacheRevalidate, handlers.GetScheduleHandler)
+		v1.DELETE("/schedules/:id", handlers.CancelScheduleHandler)
+
+		// Automations: rules that run actions when devices, services or alerts change
+		v1.GET("/automations", cacheRevalidate, handlers.ListAutomationsHandler)
+		v1.POST("/automations", handlers.CreateAutomationHandler)
+		v1.POST("/automations/alerts", handlers.AutomationAlertsHandler)
+		v1.GET("/automations/:id", cacheRevalidate, handlers.GetAutomationHandler)
+		v1.PUT("/automations/:id", handlers.UpdateAutomationHandler)
+		v1.DELETE("/automations/:id", handlers.DeleteAutomationHandler)
+		v1.GET("/automations/:id/runs", cacheRevalidate, handlers.ListAutomationRunsHandler)
+		v1.POST("/automations/:id/dry-run", handlers.DryRunAutomationHandler)
+	}
+
+	router.NoRoute(noRouteHandler)
+	if s.adminRouter != nil {
+		s.adminRouter.NoRoute(noRouteHandler)
+	}
+
+	s.router = router
+	return s
+}
+
+// ApplyConfig applies the live settings of cfg: CORS origins, rate limits,
+// API keys and the service catalog. The rate limiter, and with it the
+// per-client buckets, is only rebuilt when its settings changed. It is safe to
+// call while the server is handling requests.
+func (s *Server) ApplyConfig(cfg *config.Config) {
+	s.applyMiddleware(cfg)
+	services.SetCatalog(cfg.Services.Models())
+}
+
+// applyMiddleware rebuilds the swappable middleware from cfg.
+func (s *Server) applyMiddleware(cfg *config.Config) {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+
+	s.cors.set(middleware.CORS(cfg.CORS))
+
+	keys := make([]middleware.APIKey, len(cfg.Auth.APIKeys))
+	for i, k := range cfg.Auth.APIKeys {
+		keys[i] = middleware.APIKey{Name: k.Name, Key: k.Key.Value()}
+	}
+	s.auth.set(middleware.APIKeyAuth(keys))
+
+	opts := middleware.NewRateLimitOptions(cfg.RateLimit)
+	if s.rateLimitBackend == nil || opts != s.rateLimitOpts {
+		old := s.rateLimitBackend
+		s.rateLimitOpts = opts
+		s.rateLimitBackend = middleware.NewRateLimitBackend(opts)
+		opts.Backend = s.rateLimitBackend
+		s.rateLimit.set(middleware.RateLimitWithOptions(opts))
+		if closer, ok := old.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				slog.Warn("closing previous rate limit backend", "error", err)
+			}
+		}
+	}
+}
+
+// apiDocument is the embedded OpenAPI document. Tests load it, so an error
+// here is a build defect rather than a runtime condition.
+var apiDocument = sync.OnceValue(func() *openapi.Document {
+	doc, err := openapi.Load(api.OpenAPI)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+})
+
+// openAPIValidation returns the validation middleware settings for cfg, and
+// false if nothing is validated. In gin's test mode responses are always
+// validated, and violations become 500s, so that handler changes that break
+// the documented contract fail tests. Sparse fieldset responses (?fields=)
+// leave out required properties by design and are not checked.
+func openAPIValidation(cfg config.ValidationConfig) (middleware.OpenAPIValidationConfig, bool) {
+	v := middleware.OpenAPIValidationConfig{
+		Requests:        cfg.Requests,
+		Responses:       cfg.Responses,
+		StrictResponses: gin.Mode() == gin.TestMode,
+		SkipResponse: func(c *gin.Context) bool {
+			return c.Query("fields") != "" || c.FullPath() == eventsRoute
+		},
+	}
+	if !v.Requests && !v.Responses && !v.StrictResponses {
+		return v, false
 	}
+	v.Document = apiDocument()
+	return v, true
+}
 
-	return &Server{router: router}
+// openAPIHandler serves the OpenAPI 3.1 document.
+func openAPIHandler(c *gin.Context) {
+	handlers.Data(c, http.StatusOK, "application/json", api.OpenAPI)
 }
 
-// Run starts the HTTP server on the specified port
+// handlerTimeouts converts the configured handler deadlines for the timeout
+// middleware. The event stream has no deadline unless one is configured.
+func handlerTimeouts(t config.TimeoutsConfig) middleware.TimeoutConfig {
+	cfg := middleware.TimeoutConfig{
+		Default: t.Handler.Duration,
+		Routes:  map[string]time.Duration{eventsRoute: 0},
+	}
+	for route, d := range t.Routes {
+		cfg.Routes[route] = d.Duration
+	}
+	return cfg
+}
+
+// untilShutdown cancels the request context when GracefulShutdown begins,
+// for streams that would otherwise not end on their own.
+func (s *Server) untilShutdown(c *gin.Context) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	stop := context.AfterFunc(s.streams, cancel)
+	defer stop()
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
+// rejectWhileDraining answers 503 once shutdown has begun, so that clients
+// reusing a kept-alive connection retry elsewhere instead of racing the
+// connection being closed.
+func (s *Server) rejectWhileDraining(c *gin.Context) {
+	if !s.draining.Load() {
+		c.Next()
+		return
+	}
+	c.Header("Connection", "close")
+	c.Header("Retry-After", "1")
+	handlers.ServiceUnavailable(c, "server is shutting down")
+	c.Abort()
+}
+
+// newHTTPServer returns an http.Server for handler on port with the
+// configured connection timeouts.
+func (s *Server) newHTTPServer(port string, handler http.Handler) *http.Server {
+	t := s.cfg.Server.Timeouts
+	return &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: t.ReadHeader.Duration,
+		ReadTimeout:       t.Read.Duration,
+		WriteTimeout:      t.Write.Duration,
+		IdleTimeout:       t.Idle.Duration,
+	}
+}
+
+// reloadHandler godoc
+// @Summary Reload configuration
+// @ID reloadConfig
+// @Description Reload the configuration file and apply the settings that can change at runtime. Settings that need a restart are reported but not applied. On error the previous configuration stays in effect.
+// @Tags admin
+// @Produce json,application/yaml,application/msgpack
+// @Success 200 {object} models.ReloadResult
+// @Failure 406 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/reload [post]
+func (s *Server) reloadHandler(c *gin.Context) {
+	result, err := s.reloader.Reload()
+	if err != nil {
+		handlers.JSONError(c, http.StatusUnprocessableEntity, "invalid_config", err.Error())
+		return
+	}
+	handlers.Respond(c, http.StatusOK, result)
+}
+
+// Run starts the HTTP server on the specified port with the configured
+// connection timeouts. It serves HTTPS when TLS is configured, picking up
+// rotated certificate files without a restart.
 func (s *Server) Run(port string) error {
+	httpServer := s.newHTTPServer(port, s.router)
+
+	tlsCfg := s.cfg.Server.TLS
+	if tlsCfg.Enabled() {
+		certs, err := newCertReloader(tlsCfg)
+		if err != nil {
+			return err
+		}
+		httpServer.TLSConfig = certs.TLSConfig()
+	}
+
 	s.mu.Lock()
-	s.httpServer = &http.Server{
-		Addr:    ":" + port,
-		Handler: s.router,
+	s.httpServer = httpServer
+	s.mu.Unlock()
+
+	if httpServer.TLSConfig != nil {
+		return httpServer.ListenAndServeTLS("", "")
 	}
+	return httpServer.ListenAndServe()
+}
+
+// RunAdmin starts the admin HTTP server configured with WithAdminPort. It
+// returns nil immediately when no admin port is configured.
+func (s *Server) RunAdmin() error {
+	if s.adminRouter == nil {
+		return nil
+	}
+	s.mu.Lock()
+	s.adminServer = s.newHTTPServer(s.adminPort, s.adminRouter)
 	s.mu.Unlock()
-	return s.httpServer.ListenAndServe()
+	if err := s.adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// AdminRouter returns the admin gin router, or nil when metrics are served on
+// the public router (useful for testing)
+func (s *Server) AdminRouter() *gin.Engine {
+	return s.adminRouter
 }
 
 // Router returns the gin router (useful for testing)
@@ -69,28 +455,34 @@ func (s *Server) Router() *gin.Engine {
 
 // healthHandler godoc
 // @Summary Health check
+// @ID health
 // @Description Get the health status of the API
 // @Tags health
 // @Accept json
-// @Produce json
-// @Success 200 {object} map[string]string
+// @Produce json,application/yaml,application/msgpack
+// @Success 200 {object} models.HealthResponse
+// @Failure 406 {object} models.ErrorResponse
 // @Router /health [get]
 func healthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-	})
+	handlers.Respond(c, http.StatusOK, models.HealthResponse{Status: "ok"})
+}
+
+// noRouteHandler answers requests for unknown paths with a JSON 404 instead
+// of gin's plain-text default.
+func noRouteHandler(c *gin.Context) {
+	handlers.NotFound(c, "no route for "+c.Request.Method+" "+c.Request.URL.Path)
 }
 
 // apiRootHandler godoc
 // @Summary API root
+// @ID getAPIInfo
 // @Description Get API version information
 // @Tags api
 // @Accept json
-// @Produce json
-// @Success 200 {object} map[string]string
+// @Produce json,application/yaml,application/msgpack
+// @Success 200 {object} models.APIInfo
+// @Failure 406 {object} models.ErrorResponse
 // @Router /api/v1 [get]
 func apiRootHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"message": "API v1",
-	})
+	handlers.Respond(c, http.StatusOK, models.APIInfo{Message: "API v1"})
 }
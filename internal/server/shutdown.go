@@ -1,14 +1,28 @@
 package server
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
-// GracefulShutdown gracefully shuts down the server
+// GracefulShutdown gracefully shuts down the server. Requests that arrive
+// while in-flight requests are being drained are answered with 503. The
+// public listener is drained first so that the admin /health keeps answering
+// until it is done; both are shut down even if one of them fails. Event
+// streams are ended first, since they would not finish on their own.
 func (s *Server) GracefulShutdown(ctx context.Context) error {
+	s.draining.Store(true)
+	s.endStreams()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if s.httpServer == nil {
-		return nil
+	var errs []error
+	if s.httpServer != nil {
+		errs = append(errs, s.httpServer.Shutdown(ctx))
+	}
+	if s.adminServer != nil {
+		errs = append(errs, s.adminServer.Shutdown(ctx))
 	}
-	return s.httpServer.Shutdown(ctx)
+	return errors.Join(errs...)
 }
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go-github/internal/config"
+)
+
+// certCheckInterval bounds how often the certificate files are checked for
+// rotation. Checks happen lazily during TLS handshakes.
+const certCheckInterval = 10 * time.Second
+
+// tlsVersions maps config names to TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// certReloader serves the certificate, key and client CA bundle from disk and
+// re-reads them when their contents change, so that certificates rotated by
+// cert-manager are picked up without a restart. A failed reload keeps the
+// previous certificate.
+type certReloader struct {
+	cfg  config.TLSConfig
+	base *tls.Config
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	contents  [][]byte
+	checked   time.Time
+	now       func() time.Time
+}
+
+// newCertReloader loads the files named by cfg and returns a reloader whose
+// TLSConfig reflects cfg's minimum version, cipher suites and client auth.
+func newCertReloader(cfg config.TLSConfig) (*certReloader, error) {
+	r := &certReloader{cfg: cfg, now: time.Now}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	base := &tls.Config{
+		MinVersion: tlsVersions[cfg.MinVersion],
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if base.MinVersion == 0 {
+		base.MinVersion = tls.VersionTLS12
+	}
+	for _, name := range cfg.CipherSuites {
+		id, ok := cipherSuiteID(name)
+		if !ok {
+			return nil, fmt.Errorf("server: unknown TLS cipher suite %q", name)
+		}
+		base.CipherSuites = append(base.CipherSuites, id)
+	}
+	if cfg.ClientCAFile != "" {
+		base.ClientAuth = tls.RequireAndVerifyClientCert
+		if cfg.ClientAuth == config.ClientAuthOptional {
+			base.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+	r.base = base
+	return r, nil
+}
+
+// TLSConfig returns the configuration to install on the http.Server. Every
+// handshake gets the current certificate and client CA pool.
+func (r *certReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.base.MinVersion,
+		NextProtos: r.base.NextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := r.current()
+			cfg := r.base.Clone()
+			cfg.Certificates = []tls.Certificate{*cert}
+			cfg.ClientCAs = clientCAs
+			return cfg, nil
+		},
+	}
+}
+
+// current returns the certificate and client CA pool, reloading them first
+// if the files changed since the last check.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := r.now(); now.Sub(r.checked) >= certCheckInterval {
+		r.checked = now
+		if err := r.reloadIfChangedLocked(); err != nil {
+			slog.Error("tls certificate reload failed, keeping previous certificate", "error", err)
+		}
+	}
+	return r.cert, r.clientCAs
+}
+
+// load reads the files unconditionally.
+func (r *certReloader) load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checked = r.now()
+	return r.reloadIfChangedLocked()
+}
+
+// reloadIfChangedLocked re-parses the files when any of them changed.
+func (r *certReloader) reloadIfChangedLocked() error {
+	paths := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		paths = append(paths, r.cfg.ClientCAFile)
+	}
+
+	contents := make([][]byte, len(paths))
+	changed := r.cert == nil
+	for i, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("server: read %s: %w", p, err)
+		}
+		contents[i] = data
+		if r.contents == nil || !bytes.Equal(data, r.contents[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(contents[0], contents[1])
+	if err != nil {
+		return fmt.Errorf("server: load key pair %s, %s: %w", paths[0], paths[1], err)
+	}
+	var clientCAs *x509.CertPool
+	if len(contents) > 2 {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(contents[2]) {
+			return fmt.Errorf("server: %s contains no PEM certificates", paths[2])
+		}
+	}
+
+	if r.cert != nil {
+		slog.Info("tls certificate reloaded", "cert_file", r.cfg.CertFile)
+	}
+	r.cert, r.clientCAs, r.contents = &cert, clientCAs, contents
+	return nil
+}
+
+// cipherSuiteID looks up a secure TLS 1.2 cipher suite by its standard name.
+// TLS 1.3 suites are not configurable in Go.
+func cipherSuiteID(name string) (uint16, bool) {
+	for _, cs := range tls.CipherSuites() {
+		if cs.Name == name && slices.Contains(cs.SupportedVersions, tls.VersionTLS12) {
+			return cs.ID, true
+		}
+	}
+	return 0, false
+}
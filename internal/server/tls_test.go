@@ -0,0 +1,265 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-github/internal/config"
+	"go-github/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues certificates for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "homelab test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for cn, usable as a server
+// certificate for 127.0.0.1 and as a client certificate.
+func (ca *testCA) issue(t *testing.T, cn string, serial int64) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"homelab"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeTLSFiles writes the server certificate, key and CA bundle to a temp
+// directory and returns a TLSConfig pointing at them.
+func writeTLSFiles(t *testing.T, ca *testCA, certPEM, keyPEM []byte) config.TLSConfig {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	require.NoError(t, os.WriteFile(cfg.CertFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(cfg.KeyFile, keyPEM, 0o600))
+	require.NoError(t, os.WriteFile(cfg.ClientCAFile, ca.pem, 0o600))
+	return cfg
+}
+
+// startTLSServer serves a server built from tlsCfg with an extra /whoami
+// route that reports the principal.
+func startTLSServer(t *testing.T, tlsCfg config.TLSConfig) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Server.TLS = tlsCfg
+	srv := New(WithConfig(cfg))
+	srv.Router().GET("/whoami", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(middleware.PrincipalKey))
+	})
+
+	certs, err := newCertReloader(tlsCfg)
+	require.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(srv.Router())
+	ts.TLS = certs.TLSConfig()
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func tlsClient(ca *testCA, clientCert *tls.Certificate) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	tlsCfg := &tls.Config{RootCAs: roots}
+	if clientCert != nil {
+		tlsCfg.Certificates = []tls.Certificate{*clientCert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+}
+
+func TestServeTLS_ServesHTTPS(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "homelab-api", 2)
+	tlsCfg := writeTLSFiles(t, ca, certPEM, keyPEM)
+	tlsCfg.ClientCAFile = ""
+
+	ts := startTLSServer(t, tlsCfg)
+
+	resp, err := tlsClient(ca, nil).Get(ts.URL + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, uint16(tls.VersionTLS13), resp.TLS.Version)
+}
+
+func TestServeTLS_MutualTLSPrincipal(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "homelab-api", 2)
+	ts := startTLSServer(t, writeTLSFiles(t, ca, certPEM, keyPEM))
+
+	_, err := tlsClient(ca, nil).Get(ts.URL + "/health")
+	assert.Error(t, err, "client certificates are required by default")
+
+	clientPEM, clientKey := ca.issue(t, "homelab-ci", 3)
+	clientCert, err := tls.X509KeyPair(clientPEM, clientKey)
+	require.NoError(t, err)
+
+	resp, err := tlsClient(ca, &clientCert).Get(ts.URL + "/whoami")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "CN=homelab-ci,O=homelab", string(body[:n]))
+}
+
+func TestServeTLS_OptionalClientAuth(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "homelab-api", 2)
+	tlsCfg := writeTLSFiles(t, ca, certPEM, keyPEM)
+	tlsCfg.ClientAuth = config.ClientAuthOptional
+
+	ts := startTLSServer(t, tlsCfg)
+
+	resp, err := tlsClient(ca, nil).Get(ts.URL + "/whoami")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestCertReloader_PicksUpRotatedCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "homelab-api", 2)
+	tlsCfg := writeTLSFiles(t, ca, certPEM, keyPEM)
+
+	r, err := newCertReloader(tlsCfg)
+	require.NoError(t, err)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	cert, _ := r.current()
+	assert.Equal(t, int64(2), cert.Leaf.SerialNumber.Int64())
+
+	// Rotate the files the way cert-manager does.
+	newCert, newKey := ca.issue(t, "homelab-api", 4)
+	require.NoError(t, os.WriteFile(tlsCfg.CertFile, newCert, 0o600))
+	require.NoError(t, os.WriteFile(tlsCfg.KeyFile, newKey, 0o600))
+
+	cert, _ = r.current()
+	assert.Equal(t, int64(2), cert.Leaf.SerialNumber.Int64(), "files are not re-checked before certCheckInterval")
+
+	now = now.Add(certCheckInterval)
+	cert, _ = r.current()
+	assert.Equal(t, int64(4), cert.Leaf.SerialNumber.Int64())
+
+	// A half-written rotation (new cert, old key) keeps the previous pair.
+	rotated, _ := ca.issue(t, "homelab-api", 5)
+	require.NoError(t, os.WriteFile(tlsCfg.CertFile, rotated, 0o600))
+	now = now.Add(certCheckInterval)
+	cert, _ = r.current()
+	assert.Equal(t, int64(4), cert.Leaf.SerialNumber.Int64())
+}
+
+func TestCertReloader_Options(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "homelab-api", 2)
+	tlsCfg := writeTLSFiles(t, ca, certPEM, keyPEM)
+	tlsCfg.MinVersion = "1.3"
+	tlsCfg.ClientAuth = config.ClientAuthOptional
+	tlsCfg.CipherSuites = []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}
+
+	r, err := newCertReloader(tlsCfg)
+	require.NoError(t, err)
+
+	got, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), got.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, got.CipherSuites)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, got.ClientAuth)
+	assert.NotNil(t, got.ClientCAs)
+	assert.Len(t, got.Certificates, 1)
+}
+
+func TestCertReloader_TLS13CipherSuite(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "homelab-api", 2)
+	tlsCfg := writeTLSFiles(t, ca, certPEM, keyPEM)
+	tlsCfg.CipherSuites = []string{"TLS_AES_128_GCM_SHA256"}
+
+	_, err := newCertReloader(tlsCfg)
+	assert.ErrorContains(t, err, `unknown TLS cipher suite "TLS_AES_128_GCM_SHA256"`)
+}
+
+func TestCertReloader_InvalidFiles(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, _ := ca.issue(t, "homelab-api", 2)
+	_, otherKey := ca.issue(t, "other", 3)
+
+	_, err := newCertReloader(writeTLSFiles(t, ca, certPEM, otherKey))
+	assert.ErrorContains(t, err, "load key pair")
+
+	tlsCfg := writeTLSFiles(t, ca, certPEM, otherKey)
+	require.NoError(t, os.WriteFile(tlsCfg.ClientCAFile, []byte("not pem"), 0o600))
+	_, err = newCertReloader(tlsCfg)
+	assert.Error(t, err)
+}
+
+func TestRun_TLSLoadError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.Server.TLS = config.TLSConfig{
+		CertFile: filepath.Join(t.TempDir(), "missing.crt"),
+		KeyFile:  filepath.Join(t.TempDir(), "missing.key"),
+	}
+
+	err := New(WithConfig(cfg)).Run("0")
+	assert.ErrorContains(t, err, "missing.crt", "Run fails fast instead of listening without a certificate")
+}
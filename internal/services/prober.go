@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/metrics"
+	"go-github/internal/models"
+	"go-github/internal/requestid"
+	"go-github/internal/tracing"
+)
+
+// defaultProbeTimeout bounds a single endpoint probe.
+const defaultProbeTimeout = 5 * time.Second
+
+// ProbeResult is the outcome of probing a single service endpoint.
+type ProbeResult struct {
+	Service    string        `json:"service"`
+	Up         bool          `json:"up"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Latency    time.Duration `json:"latency"`
+	Error      string        `json:"error,omitempty"`
+	CheckedAt  time.Time     `json:"checked_at"`
+}
+
+// Prober periodically sends an HTTP GET to every service endpoint and records
+// whether it answered. Any HTTP response below 500 counts as up; connection
+// errors, timeouts and 5xx responses count as down.
+type Prober struct {
+	client   *http.Client
+	interval time.Duration
+	list     func() []models.Service
+
+	mu      sync.RWMutex
+	results map[string]ProbeResult
+}
+
+// NewProber creates a Prober that checks GetServices every interval. A nil
+// client uses an http.Client with a 5 second timeout whose requests carry the
+// trace context and request ID.
+func NewProber(client *http.Client, interval time.Duration) *Prober {
+	if client == nil {
+		client = &http.Client{
+			Timeout:   defaultProbeTimeout,
+			Transport: tracing.Transport(requestid.Transport(nil)),
+		}
+	}
+	return &Prober{
+		client:   client,
+		interval: interval,
+		list:     GetServices,
+		results:  make(map[string]ProbeResult),
+	}
+}
+
+// Run probes immediately and then every interval until ctx is cancelled.
+func (p *Prober) Run(ctx context.Context) error {
+	slog.Info("service prober started", "interval", p.interval.String())
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.ProbeAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeAll probes every service concurrently and returns the results. All
+// probes of one round share a request ID so they can be correlated upstream.
+func (p *Prober) ProbeAll(ctx context.Context) []ProbeResult {
+	if requestid.FromContext(ctx) == "" {
+		ctx = requestid.NewContext(ctx, requestid.New())
+	}
+	svcs := p.list()
+	results := make([]ProbeResult, len(svcs))
+
+	var wg sync.WaitGroup
+	for i, svc := range svcs {
+		wg.Add(1)
+		go func(i int, svc models.Service) {
+			defer wg.Done()
+			results[i] = p.probe(ctx, svc)
+		}(i, svc)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Results returns the most recent probe result for each service.
+func (p *Prober) Results() map[string]ProbeResult {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make(map[string]ProbeResult, len(p.results))
+	for k, v := range p.results {
+		out[k] = v
+	}
+	return out
+}
+
+// probe checks a single service and records the result.
+func (p *Prober) probe(ctx context.Context, svc models.Service) ProbeResult {
+	start := time.Now()
+	result := ProbeResult{Service: svc.Name, CheckedAt: start}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, svc.Endpoint, nil)
+	if err == nil {
+		var resp *http.Response
+		resp, err = p.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			result.StatusCode = resp.StatusCode
+			result.Up = resp.StatusCode < http.StatusInternalServerError
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.Latency = time.Since(start)
+
+	p.record(result)
+	return result
+}
+
+// record stores the result, updates the probe metrics and reports a change
+// of the service between up and down on StatusChanged.
+func (p *Prober) record(result ProbeResult) {
+	p.mu.Lock()
+	prev, seen := p.results[result.Service]
+	p.results[result.Service] = result
+	p.mu.Unlock()
+
+	if seen && prev.Up != result.Up {
+		events.Publish(events.Default(), StatusChanged, StatusChange{Previous: prev, Current: result})
+	}
+
+	label, up := "down", 0.0
+	if result.Up {
+		label, up = "up", 1.0
+	}
+	metrics.ServiceProbesTotal.WithLabelValues(result.Service, label).Inc()
+	metrics.ServiceUp.WithLabelValues(result.Service).Set(up)
+}
+
+// StatusChanged is the topic on events.Default() that carries every probed
+// service going up or down. The first probe of a service is not a change.
+var StatusChanged = events.NewTopic[StatusChange]("services.status_changed")
+
+// StatusChange is a service going up or down between two probes.
+type StatusChange struct {
+	Previous ProbeResult `json:"previous"`
+	Current  ProbeResult `json:"current"`
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-github/internal/events"
+	"go-github/internal/metrics"
+	"go-github/internal/models"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProber_ProbeAll(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	p := NewProber(nil, time.Minute)
+	p.list = func() []models.Service {
+		return []models.Service{
+			{Name: "probe-healthy", Endpoint: healthy.URL},
+			{Name: "probe-failing", Endpoint: failing.URL},
+			{Name: "probe-unreachable", Endpoint: "http://127.0.0.1:1"},
+		}
+	}
+
+	results := p.ProbeAll(context.Background())
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].Up)
+	assert.Equal(t, http.StatusOK, results[0].StatusCode)
+	assert.False(t, results[1].Up)
+	assert.Equal(t, http.StatusServiceUnavailable, results[1].StatusCode)
+	assert.False(t, results[2].Up)
+	assert.NotEmpty(t, results[2].Error)
+
+	stored := p.Results()
+	assert.Len(t, stored, 3)
+	assert.True(t, stored["probe-healthy"].Up)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ServiceUp.WithLabelValues("probe-healthy")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.ServiceUp.WithLabelValues("probe-failing")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ServiceProbesTotal.WithLabelValues("probe-unreachable", "down")))
+}
+
+func TestProber_RunStopsOnCancel(t *testing.T) {
+	p := NewProber(nil, 10*time.Millisecond)
+	p.list = func() []models.Service { return nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestProber_StatusChanged(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	p := NewProber(nil, time.Minute)
+	p.list = func() []models.Service { return []models.Service{{Name: "probe-flapping", Endpoint: ts.URL}} }
+
+	bus := events.New()
+	events.SetDefault(bus)
+	t.Cleanup(func() { events.SetDefault(nil) })
+	sub := events.Subscribe(bus, StatusChanged)
+
+	p.ProbeAll(context.Background())
+	p.ProbeAll(context.Background())
+	healthy.Store(false)
+	p.ProbeAll(context.Background())
+	p.ProbeAll(context.Background())
+	sub.Close()
+
+	var changes []StatusChange
+	for c := range sub.C() {
+		changes = append(changes, c)
+	}
+	require.Len(t, changes, 1, "only the transition from up to down is a change")
+	assert.Equal(t, "probe-flapping", changes[0].Current.Service)
+	assert.True(t, changes[0].Previous.Up)
+	assert.False(t, changes[0].Current.Up)
+	assert.Equal(t, http.StatusBadGateway, changes[0].Current.StatusCode)
+}
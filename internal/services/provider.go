@@ -1,11 +1,42 @@
 // Package services provides shared service data for the homelab API.
 package services
 
-import "go-github/internal/models"
+import (
+	"sync/atomic"
 
-// GetServices returns the list of mock homelab services.
+	"go-github/internal/models"
+)
+
+// catalog holds the service list configured with SetCatalog; nil means the
+// built-in mock list.
+var catalog atomic.Pointer[[]models.Service]
+
+// GetServices returns the homelab service catalog: the list installed with
+// SetCatalog, or the built-in mock services when none is configured.
 // Both the HTTP handler and the MCP server consume this shared data.
 func GetServices() []models.Service {
+	if svcs := catalog.Load(); svcs != nil {
+		out := make([]models.Service, len(*svcs))
+		copy(out, *svcs)
+		return out
+	}
+	return defaultServices()
+}
+
+// SetCatalog replaces the service catalog. An empty list restores the
+// built-in mock services.
+func SetCatalog(svcs []models.Service) {
+	if len(svcs) == 0 {
+		catalog.Store(nil)
+		return
+	}
+	cp := make([]models.Service, len(svcs))
+	copy(cp, svcs)
+	catalog.Store(&cp)
+}
+
+// defaultServices returns the built-in list of mock homelab services.
+func defaultServices() []models.Service {
 	return []models.Service{
 		{
 			Name:     "homeassistant",
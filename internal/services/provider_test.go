@@ -3,6 +3,8 @@ package services
 import (
 	"testing"
 
+	"go-github/internal/models"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,3 +43,19 @@ func TestGetServices_AllHaveNonEmptyNames(t *testing.T) {
 		assert.NotEmpty(t, svc.Name, "service should have a non-empty Name")
 	}
 }
+
+func TestSetCatalog(t *testing.T) {
+	t.Cleanup(func() { SetCatalog(nil) })
+
+	custom := []models.Service{{Name: "jellyfin", Type: "media", Status: "running", Endpoint: "http://jellyfin.local:8096"}}
+	SetCatalog(custom)
+
+	got := GetServices()
+	assert.Equal(t, custom, got)
+
+	got[0].Name = "mutated"
+	assert.Equal(t, "jellyfin", GetServices()[0].Name, "callers must not be able to mutate the catalog")
+
+	SetCatalog(nil)
+	assert.Equal(t, defaultServices(), GetServices(), "an empty catalog restores the built-in list")
+}
@@ -0,0 +1,122 @@
+// Package tracing configures OpenTelemetry distributed tracing for the homelab
+// API. Spans are exported over OTLP/HTTP when an endpoint is configured; with
+// no endpoint the global no-op tracer provider stays in place, so
+// instrumentation costs almost nothing. W3C trace context is always
+// propagated so that upstream trace IDs survive even when export is disabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by this module.
+const instrumentationName = "go-github"
+
+// defaultServiceName is reported as service.name when Config leaves it empty.
+const defaultServiceName = "homelab-api"
+
+// Config controls trace export.
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector URL, e.g. http://otel-collector:4318.
+	// Tracing export is disabled when empty.
+	Endpoint string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	// SampleRatio is the fraction of new root traces to sample (0 < r <= 1).
+	// Zero means sample everything. Child spans follow their parent's decision.
+	SampleRatio float64
+}
+
+// Setup installs the W3C propagator and, if cfg.Endpoint is set, an OTLP/HTTP
+// exporting tracer provider. The returned function flushes and stops the
+// exporter; it is safe to call when export is disabled.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOptions(cfg.Endpoint)...)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: create otlp exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: build resource: %w", err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// exporterOptions translates an endpoint URL into otlptracehttp options.
+// A bare collector URL such as http://otel-collector:4318 gets the standard
+// /v1/traces path; an explicit path is used as given. Plain http URLs
+// disable TLS.
+func exporterOptions(endpoint string) []otlptracehttp.Option {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(endpoint)}
+	if u, err := url.Parse(endpoint); err == nil && strings.Trim(u.Path, "/") == "" {
+		opts = append(opts, otlptracehttp.WithURLPath("/v1/traces"))
+	}
+	return opts
+}
+
+// Tracer returns the module tracer from the current global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartClientSpan starts a span for a call to an upstream system such as Home
+// Assistant, Kubernetes or Prometheus.
+func StartClientSpan(ctx context.Context, system, operation string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, system+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.PeerService(system)),
+	)
+}
+
+// Transport wraps base so that every outbound HTTP request gets a client span
+// and carries the current trace context in its traceparent header. A nil base
+// uses http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go-github/internal/tracing/tracingtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup_NoEndpointIsNoop(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent",
+		"W3C trace context should be propagated even without an exporter")
+}
+
+func TestSetup_ExportsOverOTLPHTTP(t *testing.T) {
+	var received atomic.Int32
+	var path atomic.Value
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path.Store(r.URL.Path)
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	shutdown, err := Setup(context.Background(), Config{Endpoint: collector.URL, ServiceName: "test"})
+	require.NoError(t, err)
+
+	_, span := Tracer().Start(context.Background(), "exported")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Equal(t, int32(1), received.Load(), "shutdown should flush the batch to the collector")
+	assert.Equal(t, "/v1/traces", path.Load())
+}
+
+func TestStartClientSpan(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	_, child := StartClientSpan(ctx, "homeassistant", "ExecuteCommand")
+	child.End()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "homeassistant.ExecuteCommand", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestTransport_InjectsTraceparent(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	var traceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer upstream.Close()
+
+	ctx, span := Tracer().Start(context.Background(), "caller")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	span.End()
+
+	require.NotEmpty(t, traceparent)
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Len(t, exporter.GetSpans(), 2, "transport should record a client span")
+}
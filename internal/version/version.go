@@ -0,0 +1,71 @@
+// Package version reports the build version of the homelab API binary.
+// Release builds set the variables with -ldflags, for example:
+//
+//	go build -ldflags "-X go-github/internal/version.Version=v1.2.0 \
+//	  -X go-github/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X go-github/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+//
+// Without them, Commit and Date fall back to the VCS information the Go
+// toolchain embeds in the binary.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build information, overridden with -ldflags -X at build time.
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes the running binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+					if len(info.Commit) > 12 {
+						info.Commit = info.Commit[:12]
+					}
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+// String formats i on one line, e.g.
+// "homelab-api v1.2.0 (commit 1a2b3c4, built 2026-01-02T03:04:05Z, go1.25.0)".
+func (i Info) String() string {
+	return fmt.Sprintf("homelab-api %s (commit %s, built %s, %s)", i.Version, i.Commit, i.Date, i.GoVersion)
+}
@@ -0,0 +1,31 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_LdflagsOverride(t *testing.T) {
+	prevVersion, prevCommit, prevDate := Version, Commit, Date
+	t.Cleanup(func() { Version, Commit, Date = prevVersion, prevCommit, prevDate })
+
+	Version, Commit, Date = "v1.2.0", "1a2b3c4", "2026-01-02T03:04:05Z"
+
+	info := Get()
+	assert.Equal(t, Info{
+		Version:   "v1.2.0",
+		Commit:    "1a2b3c4",
+		Date:      "2026-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+	}, info)
+	assert.Equal(t, "homelab-api v1.2.0 (commit 1a2b3c4, built 2026-01-02T03:04:05Z, "+runtime.Version()+")", info.String())
+}
+
+func TestGet_Defaults(t *testing.T) {
+	info := Get()
+	assert.Equal(t, "dev", info.Version)
+	assert.NotEmpty(t, info.Commit, "falls back to VCS info or \"unknown\"")
+	assert.NotEmpty(t, info.Date)
+}
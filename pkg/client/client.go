@@ -0,0 +1,234 @@
+// Package client is a typed Go client for the Home Lab API.
+//
+// The endpoint methods (ListDevices, ExecuteCommand, ...) and the model type
+// aliases are generated from api/swagger.json by internal/clientgen; run
+// go generate ./pkg/client after changing the API's swag annotations.
+//
+//	c, err := client.New("http://homelab.local:8080", client.WithAPIKey(key))
+//	if err != nil {
+//		return err
+//	}
+//	devices, err := c.ListDevices(ctx, nil)
+//
+// Requests rejected by the rate limiter (HTTP 429) are retried with backoff,
+// waiting as long as the Retry-After and X-RateLimit-* headers ask. Non-2xx
+// responses are returned as *APIError.
+package client
+
+//go:generate go run ./internal/clientgen -spec ../../api/swagger.json -out endpoints_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// endpoint is the method and Swagger path template of a generated method.
+type endpoint struct {
+	Method string
+	Path   string
+}
+
+// RetryPolicy controls how requests rejected with HTTP 429 are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; zero
+	// disables retrying.
+	MaxRetries int
+	// BaseDelay is the first backoff delay when the server does not say how
+	// long to wait. It doubles on every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps every wait, including ones requested by the server.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used unless WithRetryPolicy is given:
+// 3 retries, starting at 500ms and waiting at most 30s at a time.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// Client calls the Home Lab API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	userAgent  string
+	retry      RetryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, e.g. one with a TLS
+// configuration for mutual TLS. The default is http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithAPIKey sends key as a bearer token with every request.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithRetryPolicy sets how rate-limited requests are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// New returns a client for the API at baseURL, e.g. "https://homelab:8080".
+// The admin endpoints (ReloadConfig) are served on the admin port when one is
+// configured; use a separate client for it.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		userAgent:  "homelab-api-client",
+		retry:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// do sends the request with the extra headers in header, retrying
+// rate-limited attempts, and decodes a 2xx JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encode %s %s request: %w", method, path, err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, u, header, payload)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.retry.MaxRetries {
+			if err := sleep(ctx, c.retryDelay(resp.Header, attempt)); err != nil {
+				return err
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return newAPIError(resp, data)
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("decode %s %s response: %w", method, path, err)
+		}
+		return nil
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, u string, header http.Header, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return c.httpClient.Do(req)
+}
+
+// retryDelay is how long to wait before retrying a 429 response. Retry-After
+// wins; otherwise, when the limit is exhausted, the refill time of one request
+// (X-RateLimit-Reset seconds per X-RateLimit-Limit requests) is the floor for
+// exponential backoff. Up to 10% jitter spreads out concurrent callers.
+func (c *Client) retryDelay(h http.Header, attempt int) time.Duration {
+	delay := c.retry.BaseDelay << attempt
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil && s >= 0 {
+		delay = time.Duration(s) * time.Second
+	} else if t, err := http.ParseTime(h.Get("Retry-After")); err == nil {
+		delay = time.Until(t)
+	} else if h.Get("X-RateLimit-Remaining") == "0" {
+		reset, errR := strconv.Atoi(h.Get("X-RateLimit-Reset"))
+		limit, errL := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+		if errR == nil && errL == nil && limit > 0 {
+			delay = max(delay, time.Duration(reset)*time.Second/time.Duration(limit))
+		}
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	delay += time.Duration(rand.Int64N(int64(delay)/10 + 1))
+	if c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay {
+		delay = c.retry.MaxDelay
+	}
+	return delay
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,417 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-github/internal/config"
+	"go-github/internal/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIKey = "client-test-key-0123456789"
+
+// newTestServer serves the real router with API key authentication and a
+// reloader, so that every endpoint is registered.
+func newTestServer(t *testing.T) *server.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.Auth.APIKeys = []config.APIKey{{Name: "client", Key: config.Secret(testAPIKey)}}
+	reloader := config.NewReloader(cfg, func() (*config.Config, error) { return cfg, nil })
+	return server.New(server.WithConfig(cfg), server.WithReloader(reloader))
+}
+
+func newTestClient(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	ts := httptest.NewServer(newTestServer(t).Router())
+	t.Cleanup(ts.Close)
+
+	c, err := New(ts.URL, append([]Option{WithAPIKey(testAPIKey)}, opts...)...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, u := range []string{"", "homelab:8080", "ftp://homelab", "http://[::1"} {
+		_, err := New(u)
+		assert.Error(t, err, u)
+	}
+}
+
+// TestEndpoints_CoverRouter fails when a route is added to server.New without
+// regenerating the client (swag init, then go generate ./pkg/client). The
+// event stream is left to SSE clients.
+func TestEndpoints_CoverRouter(t *testing.T) {
+	var want []string
+	for _, r := range newTestServer(t).Router().Routes() {
+		if r.Path == "/metrics" || r.Path == "/api/openapi.json" || r.Path == "/api/v1/events" || strings.HasPrefix(r.Path, "/api/docs/") {
+			continue
+		}
+		want = append(want, r.Method+" "+strings.ReplaceAll(r.Path, `\:`, ":"))
+	}
+
+	var got []string
+	for _, e := range endpoints {
+		path := e.Path
+		for strings.Contains(path, "{") {
+			start, end := strings.Index(path, "{"), strings.Index(path, "}")
+			path = path[:start] + ":" + path[start+1:end] + path[end+1:]
+		}
+		got = append(got, e.Method+" "+path)
+	}
+	assert.ElementsMatch(t, want, got)
+}
+
+func TestClient_Devices(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	list, err := c.ListDevices(ctx, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Devices)
+	assert.Equal(t, len(list.Devices), list.Count)
+
+	first, err := c.ListDevices(ctx, &ListDevicesOptions{Limit: 1, Sort: "-id", Fields: "id"})
+	require.NoError(t, err)
+	require.Len(t, first.Devices, 1)
+	assert.Equal(t, list.Devices[len(list.Devices)-1].ID, first.Devices[0].ID)
+	assert.Empty(t, first.Devices[0].Name)
+	second, err := c.ListDevices(ctx, &ListDevicesOptions{Limit: 1, Sort: "-id", Cursor: first.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, list.Devices[len(list.Devices)-2].ID, second.Devices[0].ID)
+
+	device, err := c.GetDevice(ctx, list.Devices[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, list.Devices[0].ID, device.ID)
+
+	result, err := c.ExecuteCommand(ctx, "device-001", Command{Action: "turn_on", Parameters: map[string]interface{}{"brightness": 80}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "device-001", result.DeviceID)
+	assert.Equal(t, "turn_on", result.Action)
+}
+
+func TestClient_ExecuteCommand_IdempotencyKey(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	opts := &ExecuteCommandOptions{IdempotencyKey: "client-test-1"}
+
+	first, err := c.ExecuteCommand(ctx, "device-001", Command{Action: "turn_off", Parameters: map[string]interface{}{}}, opts)
+	require.NoError(t, err)
+	retry, err := c.ExecuteCommand(ctx, "device-001", Command{Action: "turn_off", Parameters: map[string]interface{}{}}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, first, retry)
+
+	_, err = c.ExecuteCommand(ctx, "device-001", Command{Action: "turn_on", Parameters: map[string]interface{}{}}, opts)
+	assert.True(t, IsStatus(err, http.StatusUnprocessableEntity), "got %v", err)
+}
+
+func TestClient_ExecuteBatch(t *testing.T) {
+	c := newTestClient(t)
+
+	result, err := c.ExecuteBatch(context.Background(), BatchRequest{Commands: []BatchCommand{
+		{DeviceID: "device-001", Action: "turn_off", Parameters: map[string]interface{}{}},
+		{DeviceID: "readonly-sensor-001", Action: "turn_on", Parameters: map[string]interface{}{}},
+	}}, nil)
+	require.NoError(t, err, "207 Multi-Status is a success")
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, http.StatusMethodNotAllowed, result.Results[1].Status)
+
+	_, err = c.ExecuteBatch(context.Background(), BatchRequest{}, nil)
+	assert.True(t, IsStatus(err, http.StatusBadRequest), "got %v", err)
+}
+
+func TestClient_Areas(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	area, err := c.CreateArea(ctx, AreaRequest{Name: "Client Test Porch", DeviceIDs: []string{"readonly-sensor-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "client_test_porch", area.ID)
+
+	devices, err := c.ListAreaDevices(ctx, area.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, devices.Devices, 1)
+	assert.Equal(t, area.ID, devices.Devices[0].AreaID)
+
+	area, err = c.RemoveAreaDevice(ctx, area.ID, "readonly-sensor-001")
+	require.NoError(t, err)
+	assert.Empty(t, area.DeviceIDs)
+
+	require.NoError(t, c.DeleteArea(ctx, area.ID))
+	_, err = c.GetArea(ctx, area.ID)
+	assert.True(t, IsNotFound(err), "got %v", err)
+}
+
+func TestClient_Scenes(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	scene, err := c.CreateScene(ctx, SceneRequest{Name: "Client Test Scene", DeviceIDs: []string{"device-001"}})
+	require.NoError(t, err)
+	assert.Equal(t, "client_test_scene", scene.ID)
+	require.Len(t, scene.Devices, 1)
+
+	activation, err := c.ActivateScene(ctx, scene.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, scene.ID, activation.SceneID)
+	assert.Zero(t, activation.Failed)
+
+	require.NoError(t, c.DeleteScene(ctx, scene.ID))
+	_, err = c.GetScene(ctx, scene.ID)
+	assert.True(t, IsNotFound(err), "got %v", err)
+}
+
+func TestClient_Schedules(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	sched, err := c.CreateSchedule(ctx, ScheduleRequest{DeviceID: "device-001", Action: "turn_off", Cron: "0 23 * * *", Timezone: "UTC"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "cron", sched.Kind)
+	require.NotNil(t, sched.NextRun)
+	assert.Equal(t, 23, sched.NextRun.UTC().Hour())
+
+	list, err := c.ListSchedules(ctx, nil)
+	require.NoError(t, err)
+	var ids []string
+	for _, s := range list.Schedules {
+		ids = append(ids, s.ID)
+	}
+	assert.Contains(t, ids, sched.ID)
+
+	require.NoError(t, c.CancelSchedule(ctx, sched.ID))
+	_, err = c.GetSchedule(ctx, sched.ID)
+	assert.True(t, IsNotFound(err), "got %v", err)
+}
+
+func TestClient_Automations(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	a, err := c.CreateAutomation(ctx, AutomationRequest{
+		Name:     "Client porch light",
+		Triggers: []AutomationTrigger{{Type: "time", Cron: "0 22 * * *", Timezone: "UTC"}},
+		Actions:  []AutomationAction{{Type: "command", DeviceID: "device-001", Action: "turn_on"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "client_porch_light", a.ID)
+	assert.True(t, a.Enabled)
+
+	run, err := c.DryRunAutomation(ctx, a.ID, DryRunRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "would_run", run.Status)
+
+	res, err := c.ReceiveAutomationAlerts(ctx, AlertWebhook{Status: "firing", Alerts: []Alert{
+		{Status: "firing", Labels: map[string]string{"alertname": "ClientTest"}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Firing)
+
+	runs, err := c.ListAutomationRuns(ctx, a.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, runs.Runs)
+
+	require.NoError(t, c.DeleteAutomation(ctx, a.ID))
+	_, err = c.GetAutomation(ctx, a.ID)
+	assert.True(t, IsNotFound(err), "got %v", err)
+}
+
+func TestClient_GetDevice_NotFound(t *testing.T) {
+	c := newTestClient(t)
+
+	_, err := c.GetDevice(context.Background(), "no-such-device")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, http.StatusNotFound, apiErr.Response.Code)
+	assert.NotEmpty(t, apiErr.Response.Error)
+	assert.NotEmpty(t, apiErr.RequestID)
+}
+
+func TestClient_ExecuteCommand_ReadOnlyDevice(t *testing.T) {
+	c := newTestClient(t)
+
+	_, err := c.ExecuteCommand(context.Background(), "readonly-sensor-001", Command{Action: "turn_on", Parameters: map[string]interface{}{}}, nil)
+	assert.True(t, IsStatus(err, http.StatusMethodNotAllowed), "got %v", err)
+}
+
+func TestClient_Services(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	services, err := c.ListServices(ctx, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, services.Services)
+
+	all, err := c.ListClusterServices(ctx, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	namespace := all[0].Namespace
+	filtered, err := c.ListClusterServices(ctx, &ListClusterServicesOptions{Namespace: namespace})
+	require.NoError(t, err)
+	require.NotEmpty(t, filtered)
+	for _, s := range filtered {
+		assert.Equal(t, namespace, s.Namespace)
+	}
+}
+
+func TestClient_HealthAndInfo(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	health, err := c.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", health.Status)
+
+	info, err := c.GetAPIInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "API v1", info.Message)
+}
+
+func TestClient_ReloadConfig(t *testing.T) {
+	c := newTestClient(t)
+
+	result, err := c.ReloadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.RestartRequired)
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	c := newTestClient(t, WithAPIKey("wrong-key"))
+
+	_, err := c.ListDevices(context.Background(), nil)
+	assert.True(t, IsStatus(err, http.StatusUnauthorized), "got %v", err)
+}
+
+// rateLimited responds 429 with headers for the first n requests and then
+// with body.
+func rateLimited(n int32, header http.Header, body string) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"Rate limit exceeded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	return ts, &calls
+}
+
+func TestClient_RetriesRateLimited(t *testing.T) {
+	ts, calls := rateLimited(2, http.Header{"Retry-After": {"0"}}, `{"status":"ok"}`)
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	require.NoError(t, err)
+	health, err := c.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ok", health.Status)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_RetriesExhausted(t *testing.T) {
+	ts, calls := rateLimited(10, http.Header{"Retry-After": {"0"}}, `{}`)
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithRetryPolicy(RetryPolicy{MaxRetries: 2}))
+	require.NoError(t, err)
+	_, err = c.Health(context.Background())
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr), "got %v", err)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, "Rate limit exceeded", apiErr.Response.Message)
+	assert.Equal(t, "Rate limit exceeded (HTTP 429)", apiErr.Error())
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_RetryRespectsContext(t *testing.T) {
+	ts, calls := rateLimited(10, http.Header{"Retry-After": {"60"}}, `{}`)
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.Health(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RetryDelay(t *testing.T) {
+	c, err := New("http://homelab", WithRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 10 * time.Second}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		attempt  int
+		min, max time.Duration
+	}{
+		{"backoff", http.Header{}, 0, 100 * time.Millisecond, 110 * time.Millisecond},
+		{"backoff doubles", http.Header{}, 2, 400 * time.Millisecond, 440 * time.Millisecond},
+		{"retry after", http.Header{"Retry-After": {"3"}}, 0, 3 * time.Second, 3300 * time.Millisecond},
+		{"capped", http.Header{"Retry-After": {"120"}}, 0, 10 * time.Second, 10 * time.Second},
+		{
+			"rate limit refill",
+			http.Header{"X-Ratelimit-Limit": {"30"}, "X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"60"}},
+			0, 2 * time.Second, 2200 * time.Millisecond,
+		},
+		{
+			"remaining requests",
+			http.Header{"X-Ratelimit-Limit": {"30"}, "X-Ratelimit-Remaining": {"5"}, "X-Ratelimit-Reset": {"60"}},
+			0, 100 * time.Millisecond, 110 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := c.retryDelay(tt.header, tt.attempt)
+			assert.GreaterOrEqual(t, d, tt.min)
+			assert.LessOrEqual(t, d, tt.max)
+		})
+	}
+}
+
+func TestClient_RetriesAgainstRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.RateLimit.Requests = 1
+	cfg.RateLimit.PerMinutes = 1
+	ts := httptest.NewServer(server.New(server.WithConfig(cfg)).Router())
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithRetryPolicy(RetryPolicy{}))
+	require.NoError(t, err)
+	_, err = c.ListServices(context.Background(), nil)
+	require.NoError(t, err)
+	_, err = c.ListServices(context.Background(), nil)
+	require.True(t, IsStatus(err, http.StatusTooManyRequests), "got %v", err)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Rate limit exceeded", apiErr.Response.Message)
+}
@@ -0,0 +1,1244 @@
+// Code generated by clientgen from api/swagger.json. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go-github/internal/models"
+)
+
+// APIInfo is models.APIInfo.
+type APIInfo = models.APIInfo
+
+// Alert is models.Alert.
+type Alert = models.Alert
+
+// AlertWebhook is models.AlertWebhook.
+type AlertWebhook = models.AlertWebhook
+
+// AlertsResult is models.AlertsResult.
+type AlertsResult = models.AlertsResult
+
+// Area is models.Area.
+type Area = models.Area
+
+// AreaListResponse is models.AreaListResponse.
+type AreaListResponse = models.AreaListResponse
+
+// AreaRequest is models.AreaRequest.
+type AreaRequest = models.AreaRequest
+
+// Automation is models.Automation.
+type Automation = models.Automation
+
+// AutomationAction is models.AutomationAction.
+type AutomationAction = models.AutomationAction
+
+// AutomationActionResult is models.AutomationActionResult.
+type AutomationActionResult = models.AutomationActionResult
+
+// AutomationCondition is models.AutomationCondition.
+type AutomationCondition = models.AutomationCondition
+
+// AutomationConditionResult is models.AutomationConditionResult.
+type AutomationConditionResult = models.AutomationConditionResult
+
+// AutomationEvent is models.AutomationEvent.
+type AutomationEvent = models.AutomationEvent
+
+// AutomationListResponse is models.AutomationListResponse.
+type AutomationListResponse = models.AutomationListResponse
+
+// AutomationRequest is models.AutomationRequest.
+type AutomationRequest = models.AutomationRequest
+
+// AutomationRun is models.AutomationRun.
+type AutomationRun = models.AutomationRun
+
+// AutomationRunListResponse is models.AutomationRunListResponse.
+type AutomationRunListResponse = models.AutomationRunListResponse
+
+// AutomationTrigger is models.AutomationTrigger.
+type AutomationTrigger = models.AutomationTrigger
+
+// BatchCommand is models.BatchCommand.
+type BatchCommand = models.BatchCommand
+
+// BatchItemResult is models.BatchItemResult.
+type BatchItemResult = models.BatchItemResult
+
+// BatchRequest is models.BatchRequest.
+type BatchRequest = models.BatchRequest
+
+// BatchResult is models.BatchResult.
+type BatchResult = models.BatchResult
+
+// Command is models.Command.
+type Command = models.Command
+
+// CommandResult is models.CommandResult.
+type CommandResult = models.CommandResult
+
+// Device is models.Device.
+type Device = models.Device
+
+// DeviceListResponse is models.DeviceListResponse.
+type DeviceListResponse = models.DeviceListResponse
+
+// DryRunRequest is models.DryRunRequest.
+type DryRunRequest = models.DryRunRequest
+
+// ErrorResponse is models.ErrorResponse.
+type ErrorResponse = models.ErrorResponse
+
+// Group is models.Group.
+type Group = models.Group
+
+// GroupListResponse is models.GroupListResponse.
+type GroupListResponse = models.GroupListResponse
+
+// GroupRequest is models.GroupRequest.
+type GroupRequest = models.GroupRequest
+
+// HealthResponse is models.HealthResponse.
+type HealthResponse = models.HealthResponse
+
+// ReloadResult is models.ReloadResult.
+type ReloadResult = models.ReloadResult
+
+// Scene is models.Scene.
+type Scene = models.Scene
+
+// SceneActivation is models.SceneActivation.
+type SceneActivation = models.SceneActivation
+
+// SceneDevice is models.SceneDevice.
+type SceneDevice = models.SceneDevice
+
+// SceneDeviceResult is models.SceneDeviceResult.
+type SceneDeviceResult = models.SceneDeviceResult
+
+// SceneListResponse is models.SceneListResponse.
+type SceneListResponse = models.SceneListResponse
+
+// SceneRequest is models.SceneRequest.
+type SceneRequest = models.SceneRequest
+
+// Schedule is models.Schedule.
+type Schedule = models.Schedule
+
+// ScheduleListResponse is models.ScheduleListResponse.
+type ScheduleListResponse = models.ScheduleListResponse
+
+// ScheduleRequest is models.ScheduleRequest.
+type ScheduleRequest = models.ScheduleRequest
+
+// ScheduleRun is models.ScheduleRun.
+type ScheduleRun = models.ScheduleRun
+
+// Service is models.Service.
+type Service = models.Service
+
+// ServiceInfo is models.ServiceInfo.
+type ServiceInfo = models.ServiceInfo
+
+// ServicesResponse is models.ServicesResponse.
+type ServicesResponse = models.ServicesResponse
+
+// endpoints lists the method and path of every generated method.
+var endpoints = []endpoint{
+	{http.MethodPost, "/api/v1/scenes/{id}/activate"},
+	{http.MethodPut, "/api/v1/homeassistant/areas/{id}/devices/{device_id}"},
+	{http.MethodPut, "/api/v1/homeassistant/groups/{id}/devices/{device_id}"},
+	{http.MethodDelete, "/api/v1/schedules/{id}"},
+	{http.MethodPost, "/api/v1/homeassistant/areas"},
+	{http.MethodPost, "/api/v1/automations"},
+	{http.MethodPost, "/api/v1/homeassistant/groups"},
+	{http.MethodPost, "/api/v1/scenes"},
+	{http.MethodPost, "/api/v1/schedules"},
+	{http.MethodDelete, "/api/v1/homeassistant/areas/{id}"},
+	{http.MethodDelete, "/api/v1/automations/{id}"},
+	{http.MethodDelete, "/api/v1/homeassistant/groups/{id}"},
+	{http.MethodDelete, "/api/v1/scenes/{id}"},
+	{http.MethodPost, "/api/v1/automations/{id}/dry-run"},
+	{http.MethodPost, "/api/v1/homeassistant/areas/{id}/command"},
+	{http.MethodPost, "/api/v1/homeassistant/commands:batch"},
+	{http.MethodPost, "/api/v1/homeassistant/devices/{id}/command"},
+	{http.MethodPost, "/api/v1/homeassistant/groups/{id}/command"},
+	{http.MethodGet, "/api/v1"},
+	{http.MethodGet, "/api/v1/homeassistant/areas/{id}"},
+	{http.MethodGet, "/api/v1/automations/{id}"},
+	{http.MethodGet, "/api/v1/homeassistant/devices/{id}"},
+	{http.MethodGet, "/api/v1/homeassistant/groups/{id}"},
+	{http.MethodGet, "/api/v1/scenes/{id}"},
+	{http.MethodGet, "/api/v1/schedules/{id}"},
+	{http.MethodGet, "/health"},
+	{http.MethodGet, "/api/v1/homeassistant/areas/{id}/devices"},
+	{http.MethodGet, "/api/v1/homeassistant/areas"},
+	{http.MethodGet, "/api/v1/automations/{id}/runs"},
+	{http.MethodGet, "/api/v1/automations"},
+	{http.MethodGet, "/api/v1/cluster/services"},
+	{http.MethodGet, "/api/v1/homeassistant/devices"},
+	{http.MethodGet, "/api/v1/homeassistant/groups/{id}/devices"},
+	{http.MethodGet, "/api/v1/homeassistant/groups"},
+	{http.MethodGet, "/api/v1/scenes"},
+	{http.MethodGet, "/api/v1/schedules"},
+	{http.MethodGet, "/api/v1/services"},
+	{http.MethodPost, "/api/v1/automations/alerts"},
+	{http.MethodPost, "/admin/reload"},
+	{http.MethodDelete, "/api/v1/homeassistant/areas/{id}/devices/{device_id}"},
+	{http.MethodDelete, "/api/v1/homeassistant/groups/{id}/devices/{device_id}"},
+	{http.MethodPut, "/api/v1/homeassistant/areas/{id}"},
+	{http.MethodPut, "/api/v1/automations/{id}"},
+	{http.MethodPut, "/api/v1/homeassistant/groups/{id}"},
+	{http.MethodPut, "/api/v1/scenes/{id}"},
+}
+
+// ActivateSceneOptions are the optional parameters of ActivateScene.
+type ActivateSceneOptions struct {
+	// IdempotencyKey sets the "Idempotency-Key" header.
+	// Unique key of this activation; retries with the same key are not executed again.
+	IdempotencyKey string
+}
+
+func (o *ActivateSceneOptions) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+	if o.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", o.IdempotencyKey)
+	}
+	return header
+}
+
+// ActivateScene calls POST /api/v1/scenes/{id}/activate.
+//
+// Restores the devices of a scene to their captured state. Each device that
+// differs gets one command; devices that already match get none. The response
+// is 200 if every device was restored or unchanged and 207 if any failed.
+func (c *Client) ActivateScene(ctx context.Context, id string, opts *ActivateSceneOptions) (*SceneActivation, error) {
+	var out SceneActivation
+	if err := c.do(ctx, http.MethodPost, "/api/v1/scenes/"+url.PathEscape(id)+"/activate", nil, opts.header(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddAreaDevice calls PUT /api/v1/homeassistant/areas/{id}/devices/{device_id}.
+//
+// Moves a device into an area, taking it out of the area it was in.
+func (c *Client) AddAreaDevice(ctx context.Context, id string, deviceID string) (*Area, error) {
+	var out Area
+	if err := c.do(ctx, http.MethodPut, "/api/v1/homeassistant/areas/"+url.PathEscape(id)+"/devices/"+url.PathEscape(deviceID), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddGroupDevice calls PUT /api/v1/homeassistant/groups/{id}/devices/{device_id}.
+//
+// Adds a device to a group. Adding a device that is already in the group
+// succeeds.
+func (c *Client) AddGroupDevice(ctx context.Context, id string, deviceID string) (*Group, error) {
+	var out Group
+	if err := c.do(ctx, http.MethodPut, "/api/v1/homeassistant/groups/"+url.PathEscape(id)+"/devices/"+url.PathEscape(deviceID), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelSchedule calls DELETE /api/v1/schedules/{id}.
+//
+// Cancels a schedule so that it does not run again. A run in progress is not
+// interrupted. Completed schedules can be deleted the same way.
+func (c *Client) CancelSchedule(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/schedules/"+url.PathEscape(id), nil, nil, nil, nil)
+}
+
+// CreateArea calls POST /api/v1/homeassistant/areas.
+//
+// Creates an area and moves the listed devices into it. Without an id, the
+// area's ID is derived from its name, as Home Assistant does.
+func (c *Client) CreateArea(ctx context.Context, area AreaRequest) (*Area, error) {
+	var out Area
+	if err := c.do(ctx, http.MethodPost, "/api/v1/homeassistant/areas", nil, nil, area, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateAutomation calls POST /api/v1/automations.
+//
+// Creates an automation: when one of its triggers fires (a device state
+// change, a service going up or down, a firing alert or a cron expression) and
+// all of its conditions on device state hold, its actions (device commands,
+// webhooks and notifications) run in order. Without an id, the automation's ID
+// is derived from its name.
+func (c *Client) CreateAutomation(ctx context.Context, automation AutomationRequest) (*Automation, error) {
+	var out Automation
+	if err := c.do(ctx, http.MethodPost, "/api/v1/automations", nil, nil, automation, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateGroup calls POST /api/v1/homeassistant/groups.
+//
+// Creates a group of devices. Without an id, the group's ID is derived from
+// its name.
+func (c *Client) CreateGroup(ctx context.Context, group GroupRequest) (*Group, error) {
+	var out Group
+	if err := c.do(ctx, http.MethodPost, "/api/v1/homeassistant/groups", nil, nil, group, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateScene calls POST /api/v1/scenes.
+//
+// Captures the current state and attributes of the listed devices as a scene.
+// The devices must be controllable. Without an id, the scene's ID is derived
+// from its name.
+func (c *Client) CreateScene(ctx context.Context, scene SceneRequest) (*Scene, error) {
+	var out Scene
+	if err := c.do(ctx, http.MethodPost, "/api/v1/scenes", nil, nil, scene, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateScheduleOptions are the optional parameters of CreateSchedule.
+type CreateScheduleOptions struct {
+	// IdempotencyKey sets the "Idempotency-Key" header.
+	// Unique key of this request; retries with the same key do not create another schedule.
+	IdempotencyKey string
+}
+
+func (o *CreateScheduleOptions) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+	if o.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", o.IdempotencyKey)
+	}
+	return header
+}
+
+// CreateSchedule calls POST /api/v1/schedules.
+//
+// Schedules a device command to run once at a time (at) or after a delay
+// (delay), or repeatedly on a cron expression (cron) evaluated in timezone.
+// Exactly one of at, delay and cron must be given. Runs go through the same
+// path as POST /api/v1/devices/{id}/command and appear in the command history.
+// A run that starts more than the configured grace period late, e.g. because
+// the server was down, is skipped or run once according to missed_run_policy.
+func (c *Client) CreateSchedule(ctx context.Context, schedule ScheduleRequest, opts *CreateScheduleOptions) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodPost, "/api/v1/schedules", nil, opts.header(), schedule, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteArea calls DELETE /api/v1/homeassistant/areas/{id}.
+//
+// Deletes an area. Its devices are in no area afterwards.
+func (c *Client) DeleteArea(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/homeassistant/areas/"+url.PathEscape(id), nil, nil, nil, nil)
+}
+
+// DeleteAutomation calls DELETE /api/v1/automations/{id}.
+//
+// Deletes an automation created through the API, with its runs. Automations
+// from the automations file cannot be deleted.
+func (c *Client) DeleteAutomation(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/automations/"+url.PathEscape(id), nil, nil, nil, nil)
+}
+
+// DeleteGroup calls DELETE /api/v1/homeassistant/groups/{id}.
+//
+// Deletes a group. Its devices are not affected.
+func (c *Client) DeleteGroup(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/homeassistant/groups/"+url.PathEscape(id), nil, nil, nil, nil)
+}
+
+// DeleteScene calls DELETE /api/v1/scenes/{id}.
+//
+// Deletes a scene. Its devices are not affected.
+func (c *Client) DeleteScene(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/scenes/"+url.PathEscape(id), nil, nil, nil, nil)
+}
+
+// DryRunAutomation calls POST /api/v1/automations/{id}/dry-run.
+//
+// Evaluates an automation as if event had happened: whether the event matches
+// one of its triggers and whether its conditions hold on the current device
+// state. No action runs and the run is not recorded. Without an event, only
+// the conditions are evaluated.
+func (c *Client) DryRunAutomation(ctx context.Context, id string, request DryRunRequest) (*AutomationRun, error) {
+	var out AutomationRun
+	if err := c.do(ctx, http.MethodPost, "/api/v1/automations/"+url.PathEscape(id)+"/dry-run", nil, nil, request, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExecuteAreaCommandOptions are the optional parameters of ExecuteAreaCommand.
+type ExecuteAreaCommandOptions struct {
+	// IdempotencyKey sets the "Idempotency-Key" header.
+	// Unique key of this command; retries with the same key are not executed again.
+	IdempotencyKey string
+}
+
+func (o *ExecuteAreaCommandOptions) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+	if o.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", o.IdempotencyKey)
+	}
+	return header
+}
+
+// ExecuteAreaCommand calls POST /api/v1/homeassistant/areas/{id}/command.
+//
+// Executes a command on every controllable device in an area, as a batch, and
+// returns the result of each. Devices that are not controllable are left out.
+// The response is 200 if every command succeeded and 207 otherwise.
+func (c *Client) ExecuteAreaCommand(ctx context.Context, id string, command Command, opts *ExecuteAreaCommandOptions) (*BatchResult, error) {
+	var out BatchResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/homeassistant/areas/"+url.PathEscape(id)+"/command", nil, opts.header(), command, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExecuteBatchOptions are the optional parameters of ExecuteBatch.
+type ExecuteBatchOptions struct {
+	// IdempotencyKey sets the "Idempotency-Key" header.
+	// Unique key of this batch; retries with the same key are not executed again.
+	IdempotencyKey string
+}
+
+func (o *ExecuteBatchOptions) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+	if o.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", o.IdempotencyKey)
+	}
+	return header
+}
+
+// ExecuteBatch calls POST /api/v1/homeassistant/commands:batch.
+//
+// Executes up to 100 device commands concurrently and returns the result of
+// each, with the status code it would have got on its own. The response is 200
+// if every command succeeded and 207 otherwise. In atomic mode the first
+// failure skips the commands that have not started (424) and restores the
+// devices of the ones that succeeded to the state and attributes they had
+// before, where an action sets that state.
+func (c *Client) ExecuteBatch(ctx context.Context, batch BatchRequest, opts *ExecuteBatchOptions) (*BatchResult, error) {
+	var out BatchResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/homeassistant/commands:batch", nil, opts.header(), batch, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExecuteCommandOptions are the optional parameters of ExecuteCommand.
+type ExecuteCommandOptions struct {
+	// IdempotencyKey sets the "Idempotency-Key" header.
+	// Unique key of this command; retries with the same key are not executed again.
+	IdempotencyKey string
+}
+
+func (o *ExecuteCommandOptions) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+	if o.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", o.IdempotencyKey)
+	}
+	return header
+}
+
+// ExecuteCommand calls POST /api/v1/homeassistant/devices/{id}/command.
+//
+// Execute a control command on a HomeAssistant device. With an
+// Idempotency-Key, retries with the same key and body get the first response
+// again instead of repeating the command.
+func (c *Client) ExecuteCommand(ctx context.Context, id string, command Command, opts *ExecuteCommandOptions) (*CommandResult, error) {
+	var out CommandResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/homeassistant/devices/"+url.PathEscape(id)+"/command", nil, opts.header(), command, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExecuteGroupCommandOptions are the optional parameters of ExecuteGroupCommand.
+type ExecuteGroupCommandOptions struct {
+	// IdempotencyKey sets the "Idempotency-Key" header.
+	// Unique key of this command; retries with the same key are not executed again.
+	IdempotencyKey string
+}
+
+func (o *ExecuteGroupCommandOptions) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+	if o.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", o.IdempotencyKey)
+	}
+	return header
+}
+
+// ExecuteGroupCommand calls POST /api/v1/homeassistant/groups/{id}/command.
+//
+// Executes a command on every controllable device in a group, as a batch, and
+// returns the result of each. Devices that are not controllable are left out.
+// The response is 200 if every command succeeded and 207 otherwise.
+func (c *Client) ExecuteGroupCommand(ctx context.Context, id string, command Command, opts *ExecuteGroupCommandOptions) (*BatchResult, error) {
+	var out BatchResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/homeassistant/groups/"+url.PathEscape(id)+"/command", nil, opts.header(), command, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAPIInfo calls GET /api/v1.
+//
+// Get API version information.
+func (c *Client) GetAPIInfo(ctx context.Context) (*APIInfo, error) {
+	var out APIInfo
+	if err := c.do(ctx, http.MethodGet, "/api/v1", nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetArea calls GET /api/v1/homeassistant/areas/{id}.
+//
+// Returns an area with the IDs of its devices.
+func (c *Client) GetArea(ctx context.Context, id string) (*Area, error) {
+	var out Area
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/areas/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAutomation calls GET /api/v1/automations/{id}.
+//
+// Returns an automation with the number and time of its runs.
+func (c *Client) GetAutomation(ctx context.Context, id string) (*Automation, error) {
+	var out Automation
+	if err := c.do(ctx, http.MethodGet, "/api/v1/automations/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDevice calls GET /api/v1/homeassistant/devices/{id}.
+//
+// Returns a single HomeAssistant device with its state and attributes.
+func (c *Client) GetDevice(ctx context.Context, id string) (*Device, error) {
+	var out Device
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/devices/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGroup calls GET /api/v1/homeassistant/groups/{id}.
+//
+// Returns a group with the IDs of its devices.
+func (c *Client) GetGroup(ctx context.Context, id string) (*Group, error) {
+	var out Group
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/groups/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetScene calls GET /api/v1/scenes/{id}.
+//
+// Returns a scene with the captured state of its devices.
+func (c *Client) GetScene(ctx context.Context, id string) (*Scene, error) {
+	var out Scene
+	if err := c.do(ctx, http.MethodGet, "/api/v1/scenes/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSchedule calls GET /api/v1/schedules/{id}.
+//
+// Returns a scheduled command with its next run and the outcome of its last
+// run.
+func (c *Client) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodGet, "/api/v1/schedules/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Health calls GET /health.
+//
+// Get the health status of the API.
+func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
+	var out HealthResponse
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAreaDevicesOptions are the optional query parameters of ListAreaDevices.
+type ListAreaDevicesOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListAreaDevicesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListAreaDevices calls GET /api/v1/homeassistant/areas/{id}/devices.
+//
+// Returns a page of the devices in an area, ordered by ID unless sort is
+// given.
+func (c *Client) ListAreaDevices(ctx context.Context, id string, opts *ListAreaDevicesOptions) (*DeviceListResponse, error) {
+	var out DeviceListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/areas/"+url.PathEscape(id)+"/devices", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAreasOptions are the optional query parameters of ListAreas.
+type ListAreasOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListAreasOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListAreas calls GET /api/v1/homeassistant/areas.
+//
+// Returns a page of the areas of the home, ordered by ID unless sort is given.
+func (c *Client) ListAreas(ctx context.Context, opts *ListAreasOptions) (*AreaListResponse, error) {
+	var out AreaListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/areas", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAutomationRunsOptions are the optional query parameters of ListAutomationRuns.
+type ListAutomationRunsOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListAutomationRunsOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListAutomationRuns calls GET /api/v1/automations/{id}/runs.
+//
+// Returns a page of the latest runs of an automation, oldest first (run IDs
+// are ordered by time) unless sort is given, each with the event that
+// triggered it and the outcome of its conditions and actions. The last 50 runs
+// are kept.
+func (c *Client) ListAutomationRuns(ctx context.Context, id string, opts *ListAutomationRunsOptions) (*AutomationRunListResponse, error) {
+	var out AutomationRunListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/automations/"+url.PathEscape(id)+"/runs", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAutomationsOptions are the optional query parameters of ListAutomations.
+type ListAutomationsOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListAutomationsOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListAutomations calls GET /api/v1/automations.
+//
+// Returns a page of the automations, from the API and the automations file,
+// ordered by ID unless sort is given.
+func (c *Client) ListAutomations(ctx context.Context, opts *ListAutomationsOptions) (*AutomationListResponse, error) {
+	var out AutomationListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/automations", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusterServicesOptions are the optional query parameters of ListClusterServices.
+type ListClusterServicesOptions struct {
+	// Name sets the "name" query parameter.
+	// Filter services by name (case-insensitive substring match).
+	Name string
+	// Namespace sets the "namespace" query parameter.
+	// Only return services in this namespace (exact match).
+	Namespace string
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListClusterServicesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Name != "" {
+		query.Set("name", o.Name)
+	}
+	if o.Namespace != "" {
+		query.Set("namespace", o.Namespace)
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListClusterServices calls GET /api/v1/cluster/services.
+//
+// Returns a page of Kubernetes cluster services, optionally filtered by name
+// and namespace. Services are ordered by namespace and name unless sort is
+// given; the next page is linked in the Link header.
+func (c *Client) ListClusterServices(ctx context.Context, opts *ListClusterServicesOptions) ([]ServiceInfo, error) {
+	var out []ServiceInfo
+	if err := c.do(ctx, http.MethodGet, "/api/v1/cluster/services", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDevicesOptions are the optional query parameters of ListDevices.
+type ListDevicesOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListDevicesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListDevices calls GET /api/v1/homeassistant/devices.
+//
+// Returns a page of HomeAssistant devices, ordered by ID unless sort is given.
+func (c *Client) ListDevices(ctx context.Context, opts *ListDevicesOptions) (*DeviceListResponse, error) {
+	var out DeviceListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/devices", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListGroupDevicesOptions are the optional query parameters of ListGroupDevices.
+type ListGroupDevicesOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListGroupDevicesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListGroupDevices calls GET /api/v1/homeassistant/groups/{id}/devices.
+//
+// Returns a page of the devices in a group, ordered by ID unless sort is
+// given.
+func (c *Client) ListGroupDevices(ctx context.Context, id string, opts *ListGroupDevicesOptions) (*DeviceListResponse, error) {
+	var out DeviceListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/groups/"+url.PathEscape(id)+"/devices", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListGroupsOptions are the optional query parameters of ListGroups.
+type ListGroupsOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListGroupsOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListGroups calls GET /api/v1/homeassistant/groups.
+//
+// Returns a page of the device groups, ordered by ID unless sort is given.
+func (c *Client) ListGroups(ctx context.Context, opts *ListGroupsOptions) (*GroupListResponse, error) {
+	var out GroupListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/homeassistant/groups", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListScenesOptions are the optional query parameters of ListScenes.
+type ListScenesOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListScenesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListScenes calls GET /api/v1/scenes.
+//
+// Returns a page of the scenes, ordered by ID unless sort is given.
+func (c *Client) ListScenes(ctx context.Context, opts *ListScenesOptions) (*SceneListResponse, error) {
+	var out SceneListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/scenes", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListSchedulesOptions are the optional query parameters of ListSchedules.
+type ListSchedulesOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListSchedulesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListSchedules calls GET /api/v1/schedules.
+//
+// Returns a page of the scheduled commands, including completed one-time
+// schedules, ordered by ID (creation order) unless sort is given.
+func (c *Client) ListSchedules(ctx context.Context, opts *ListSchedulesOptions) (*ScheduleListResponse, error) {
+	var out ScheduleListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/schedules", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListServicesOptions are the optional query parameters of ListServices.
+type ListServicesOptions struct {
+	// Limit sets the "limit" query parameter.
+	// Maximum number of items to return.
+	Limit int
+	// Cursor sets the "cursor" query parameter.
+	// next_cursor of the previous page.
+	Cursor string
+	// Sort sets the "sort" query parameter.
+	// Comma-separated fields to sort by; prefix a field with - for descending order.
+	Sort string
+	// Fields sets the "fields" query parameter.
+	// Comma-separated fields to include in each item.
+	Fields string
+}
+
+func (o *ListServicesOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Limit != 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Fields != "" {
+		query.Set("fields", o.Fields)
+	}
+	return query
+}
+
+// ListServices calls GET /api/v1/services.
+//
+// Returns a page of the services in the homelab, ordered by name unless sort
+// is given.
+func (c *Client) ListServices(ctx context.Context, opts *ListServicesOptions) (*ServicesResponse, error) {
+	var out ServicesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/services", opts.values(), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReceiveAutomationAlerts calls POST /api/v1/automations/alerts.
+//
+// Receives an Alertmanager webhook notification. Each firing alert triggers
+// the automations with an alert trigger on its alertname label whose labels it
+// includes; resolved alerts are ignored. The automations run after the
+// response.
+func (c *Client) ReceiveAutomationAlerts(ctx context.Context, alerts AlertWebhook) (*AlertsResult, error) {
+	var out AlertsResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/automations/alerts", nil, nil, alerts, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReloadConfig calls POST /admin/reload.
+//
+// Reload the configuration file and apply the settings that can change at
+// runtime. Settings that need a restart are reported but not applied. On error
+// the previous configuration stays in effect.
+func (c *Client) ReloadConfig(ctx context.Context) (*ReloadResult, error) {
+	var out ReloadResult
+	if err := c.do(ctx, http.MethodPost, "/admin/reload", nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveAreaDevice calls DELETE /api/v1/homeassistant/areas/{id}/devices/{device_id}.
+//
+// Takes a device out of an area, leaving it in no area. Removing a device that
+// is not in the area succeeds.
+func (c *Client) RemoveAreaDevice(ctx context.Context, id string, deviceID string) (*Area, error) {
+	var out Area
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/homeassistant/areas/"+url.PathEscape(id)+"/devices/"+url.PathEscape(deviceID), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveGroupDevice calls DELETE /api/v1/homeassistant/groups/{id}/devices/{device_id}.
+//
+// Removes a device from a group. Removing a device that is not in the group
+// succeeds.
+func (c *Client) RemoveGroupDevice(ctx context.Context, id string, deviceID string) (*Group, error) {
+	var out Group
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/homeassistant/groups/"+url.PathEscape(id)+"/devices/"+url.PathEscape(deviceID), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateArea calls PUT /api/v1/homeassistant/areas/{id}.
+//
+// Renames an area and replaces its devices. Devices that leave the area are in
+// no area afterwards.
+func (c *Client) UpdateArea(ctx context.Context, id string, area AreaRequest) (*Area, error) {
+	var out Area
+	if err := c.do(ctx, http.MethodPut, "/api/v1/homeassistant/areas/"+url.PathEscape(id), nil, nil, area, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAutomation calls PUT /api/v1/automations/{id}.
+//
+// Replaces the triggers, conditions and actions of an automation created
+// through the API; its runs are kept. Automations from the automations file
+// cannot be changed.
+func (c *Client) UpdateAutomation(ctx context.Context, id string, automation AutomationRequest) (*Automation, error) {
+	var out Automation
+	if err := c.do(ctx, http.MethodPut, "/api/v1/automations/"+url.PathEscape(id), nil, nil, automation, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateGroup calls PUT /api/v1/homeassistant/groups/{id}.
+//
+// Renames a group and replaces its devices.
+func (c *Client) UpdateGroup(ctx context.Context, id string, group GroupRequest) (*Group, error) {
+	var out Group
+	if err := c.do(ctx, http.MethodPut, "/api/v1/homeassistant/groups/"+url.PathEscape(id), nil, nil, group, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateScene calls PUT /api/v1/scenes/{id}.
+//
+// Renames a scene and captures it again from the current state of the listed
+// devices.
+func (c *Client) UpdateScene(ctx context.Context, id string, scene SceneRequest) (*Scene, error) {
+	var out Scene
+	if err := c.do(ctx, http.MethodPut, "/api/v1/scenes/"+url.PathEscape(id), nil, nil, scene, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is a non-2xx response from the API. Responses in the
+// models.ErrorResponse shape are decoded into Response; for anything else
+// Response.Message holds the response body, or the status text if it was
+// empty.
+type APIError struct {
+	StatusCode int
+	Response   ErrorResponse
+	// RequestID is the X-Request-ID of the failed request, if the server
+	// sent one.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	if e.Response.Error == "" {
+		return fmt.Sprintf("%s (HTTP %d)", e.Response.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("%s (%s, HTTP %d)", e.Response.Message, e.Response.Error, e.StatusCode)
+}
+
+// IsStatus reports whether err is, or wraps, an *APIError with the given
+// HTTP status code.
+func IsStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+// IsNotFound reports whether err is an HTTP 404 *APIError.
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+	if json.Unmarshal(body, &e.Response) == nil && (e.Response.Message != "" || e.Response.Error != "") {
+		if e.Response.Message == "" {
+			// Bodies like {"error": "Rate limit exceeded"}, as sent by older
+			// servers, only carry error.
+			e.Response.Message, e.Response.Error = e.Response.Error, ""
+		}
+		if e.Response.Code == 0 {
+			e.Response.Code = resp.StatusCode
+		}
+		return e
+	}
+	e.Response = ErrorResponse{Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	if e.Response.Message == "" {
+		e.Response.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
@@ -0,0 +1,517 @@
+// Command clientgen generates the typed endpoint methods of package client
+// from the Swagger 2.0 document in api/swagger.json.
+//
+// Every operation needs an operationId (the @ID swag annotation); it becomes
+// the method name. Path parameters become string arguments, named in
+// lowerCamelCase, a body parameter a typed argument, and query parameters (strings or integers) and string
+// header parameters an optional <Method>Options struct, where the zero value
+// leaves a parameter unset. The method returns the schema of the 200 or 201
+// response, or only an error if the operation answers 204 No Content.
+// Operations that stream server-sent events are left out.
+// Definitions become aliases of the server's own model types, which must all
+// be declared in internal/models.
+//
+// Usage (from pkg/client, via go generate):
+//
+//	go run ./internal/clientgen -spec ../../api/swagger.json -out endpoints_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// modelsPackage declares every type the client exposes. It imports only the
+// standard library, so that importing the client does not pull in the server
+// and its dependencies; definitions from any other package are refused.
+const modelsPackage = "go-github/internal/models"
+
+func main() {
+	specPath := flag.String("spec", "../../api/swagger.json", "Swagger 2.0 document to generate from")
+	outPath := flag.String("out", "endpoints_gen.go", "file to write")
+	flag.Parse()
+
+	spec, err := os.ReadFile(*specPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+	src, err := generate(spec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*outPath, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+}
+
+// document is the subset of Swagger 2.0 the generator understands.
+type document struct {
+	Paths       map[string]map[string]operation `json:"paths"`
+	Definitions map[string]json.RawMessage      `json:"definitions"`
+}
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Produces    []string            `json:"produces"`
+	Parameters  []parameter         `json:"parameters"`
+	Responses   map[string]response `json:"responses"`
+}
+
+// streams reports whether op only produces server-sent events, which a
+// method returning once the response is read cannot consume.
+func (op operation) streams() bool {
+	return len(op.Produces) == 1 && op.Produces[0] == "text/event-stream"
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Type        string  `json:"type"`
+	Description string  `json:"description"`
+	Schema      *schema `json:"schema"`
+}
+
+type response struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string  `json:"$ref"`
+	Type                 string  `json:"type"`
+	Items                *schema `json:"items"`
+	AdditionalProperties *schema `json:"additionalProperties"`
+}
+
+// alias is a generated type alias for a swagger definition.
+type alias struct {
+	Name   string
+	Target string
+}
+
+// queryParam is a field of a generated options struct: a query parameter,
+// or a header if Header is set.
+type queryParam struct {
+	Field  string
+	Name   string
+	Doc    string
+	Int    bool
+	Header bool
+}
+
+// method is a generated endpoint method.
+type method struct {
+	Name       string
+	HTTPMethod string
+	Path       string
+	Doc        []string
+	PathArgs   []string
+	PathExpr   string
+	BodyArg    string
+	BodyType   string
+	Query      []queryParam
+	Headers    []queryParam
+	// Result is the Go type of the response, "" for 204 No Content.
+	Result  string
+	Pointer bool
+}
+
+// MethodConst is the net/http constant for the HTTP method.
+func (m method) MethodConst() string {
+	return "http.Method" + m.HTTPMethod[:1] + strings.ToLower(m.HTTPMethod[1:])
+}
+
+// HasOptions reports whether the method takes an options struct.
+func (m method) HasOptions() bool {
+	return len(m.Query) > 0 || len(m.Headers) > 0
+}
+
+// Options are the fields of the options struct: the query parameters, then
+// the headers.
+func (m method) Options() []queryParam {
+	return append(append([]queryParam(nil), m.Query...), m.Headers...)
+}
+
+// Params is the parameter list of the method after ctx.
+func (m method) Params() string {
+	var params []string
+	for _, a := range m.PathArgs {
+		params = append(params, a+" string")
+	}
+	if m.BodyArg != "" {
+		params = append(params, m.BodyArg+" "+m.BodyType)
+	}
+	if m.HasOptions() {
+		params = append(params, "opts *"+m.Name+"Options")
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(params, ", ")
+}
+
+// generate returns the formatted Go source for the operations in spec.
+func generate(spec []byte) ([]byte, error) {
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+
+	imports := map[string]bool{}
+	var aliases []alias
+	for name := range doc.Definitions {
+		pkg, typ, ok := strings.Cut(name, ".")
+		if !ok || pkg != "models" {
+			return nil, fmt.Errorf("definition %q: the client only exposes types of %s", name, modelsPackage)
+		}
+		imports[modelsPackage] = true
+		aliases = append(aliases, alias{Name: typ, Target: name})
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+	for i := 1; i < len(aliases); i++ {
+		if aliases[i].Name == aliases[i-1].Name {
+			return nil, fmt.Errorf("definitions %q and %q have the same type name", aliases[i-1].Target, aliases[i].Target)
+		}
+	}
+
+	var methods []method
+	for path, ops := range doc.Paths {
+		for httpMethod, op := range ops {
+			if op.streams() {
+				continue
+			}
+			m, err := newMethod(strings.ToUpper(httpMethod), path, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(httpMethod), path, err)
+			}
+			methods = append(methods, m)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	for i := 1; i < len(methods); i++ {
+		if methods[i].Name == methods[i-1].Name {
+			return nil, fmt.Errorf("operationId %q is used twice", methods[i].Name)
+		}
+	}
+
+	importList := make([]string, 0, len(imports))
+	for p := range imports {
+		importList = append(importList, p)
+	}
+	sort.Strings(importList)
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Imports      []string
+		Aliases      []alias
+		Methods      []method
+		NeedsURL     bool
+		NeedsStrconv bool
+	}{importList, aliases, methods, needsURL(methods), needsStrconv(methods)})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+func newMethod(httpMethod, path string, op operation) (method, error) {
+	if op.OperationID == "" {
+		return method{}, fmt.Errorf("missing operationId (add an @ID annotation)")
+	}
+	m := method{
+		Name:       exported(op.OperationID),
+		HTTPMethod: httpMethod,
+		Path:       path,
+	}
+
+	m.Doc = append(m.Doc, fmt.Sprintf("%s calls %s %s.", m.Name, httpMethod, path))
+	if op.Description != "" {
+		m.Doc = append(m.Doc, "")
+		m.Doc = append(m.Doc, wrap(sentence(op.Description), 76)...)
+	}
+
+	pathArgs := map[string]bool{}
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			m.PathArgs = append(m.PathArgs, unexported(p.Name))
+			pathArgs[p.Name] = true
+		case "body":
+			if p.Schema == nil {
+				return method{}, fmt.Errorf("body parameter %q has no schema", p.Name)
+			}
+			typ, err := goType(p.Schema)
+			if err != nil {
+				return method{}, err
+			}
+			m.BodyArg, m.BodyType = p.Name, typ
+		case "query":
+			if p.Type != "string" && p.Type != "integer" {
+				return method{}, fmt.Errorf("query parameter %q: unsupported type %q", p.Name, p.Type)
+			}
+			m.Query = append(m.Query, queryParam{Field: exported(p.Name), Name: p.Name, Doc: p.Description, Int: p.Type == "integer"})
+		case "header":
+			if p.Type != "string" {
+				return method{}, fmt.Errorf("header parameter %q: unsupported type %q", p.Name, p.Type)
+			}
+			m.Headers = append(m.Headers, queryParam{Field: exported(p.Name), Name: p.Name, Doc: p.Description, Header: true})
+		default:
+			return method{}, fmt.Errorf("parameter %q: unsupported location %q", p.Name, p.In)
+		}
+	}
+
+	// Build the path expression, escaping each path parameter.
+	var parts []string
+	rest := path
+	for rest != "" {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest))
+			break
+		}
+		end := strings.Index(rest, "}")
+		if end < open {
+			return method{}, fmt.Errorf("malformed path")
+		}
+		name := rest[open+1 : end]
+		if !pathArgs[name] {
+			return method{}, fmt.Errorf("path parameter %q is not declared", name)
+		}
+		if open > 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest[:open]))
+		}
+		parts = append(parts, "url.PathEscape("+unexported(name)+")")
+		rest = rest[end+1:]
+	}
+	m.PathExpr = strings.Join(parts, " + ")
+
+	for _, code := range []string{"200", "201", "202"} {
+		if ok, found := op.Responses[code]; found && ok.Schema != nil {
+			result, err := goType(ok.Schema)
+			if err != nil {
+				return method{}, err
+			}
+			m.Result = result
+			m.Pointer = ok.Schema.Ref != ""
+			return m, nil
+		}
+	}
+	if _, found := op.Responses["204"]; found {
+		return m, nil
+	}
+	return method{}, fmt.Errorf("no 200, 201 or 202 response schema and no 204 response")
+}
+
+// goType returns the Go type of s in package client.
+func goType(s *schema) (string, error) {
+	switch {
+	case s.Ref != "":
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		_, typ, ok := strings.Cut(name, ".")
+		if !ok {
+			return "", fmt.Errorf("unsupported reference %q", s.Ref)
+		}
+		return typ, nil
+	case s.Type == "array" && s.Items != nil:
+		elem, err := goType(s.Items)
+		return "[]" + elem, err
+	case s.Type == "object" && s.AdditionalProperties != nil:
+		elem, err := goType(s.AdditionalProperties)
+		return "map[string]" + elem, err
+	case s.Type == "string":
+		return "string", nil
+	case s.Type == "integer":
+		return "int", nil
+	case s.Type == "boolean":
+		return "bool", nil
+	}
+	return "", fmt.Errorf("unsupported schema %+v", *s)
+}
+
+// needsStrconv reports whether the generated code uses package strconv.
+func needsStrconv(methods []method) bool {
+	for _, m := range methods {
+		for _, q := range m.Query {
+			if q.Int {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// needsURL reports whether the generated code uses package net/url.
+func needsURL(methods []method) bool {
+	for _, m := range methods {
+		if len(m.Query) > 0 || len(m.PathArgs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// exported upper-cases the first letter of name, keeping common initialisms
+// ("api", "id", "url") in upper case.
+func exported(name string) string {
+	for _, initialism := range []string{"api", "id", "url"} {
+		if strings.HasPrefix(name, initialism) && (len(name) == len(initialism) || unicode.IsUpper(rune(name[len(initialism)]))) {
+			return strings.ToUpper(initialism) + name[len(initialism):]
+		}
+	}
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' }) {
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// unexported returns name in lowerCamelCase, for use as a Go identifier:
+// "device_id" becomes "deviceID".
+func unexported(name string) string {
+	first, rest, _ := strings.Cut(name, "_")
+	if rest == "" {
+		return first
+	}
+	return first + exported(strings.ReplaceAll(rest, "_", "-"))
+}
+
+// wrap splits s into lines of at most width bytes at word boundaries.
+func wrap(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
+
+// sentence returns s ending with a full stop.
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
+
+var tmpl = template.Must(template.New("client").Parse(`// Code generated by clientgen from api/swagger.json. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+{{- if .NeedsURL}}
+	"net/url"
+{{- end}}
+{{- if .NeedsStrconv}}
+	"strconv"
+{{- end}}
+{{range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+{{range .Aliases -}}
+// {{.Name}} is {{.Target}}.
+type {{.Name}} = {{.Target}}
+
+{{end -}}
+
+// endpoints lists the method and path of every generated method.
+var endpoints = []endpoint{
+{{- range .Methods}}
+	{ {{.MethodConst}}, "{{.Path}}"},
+{{- end}}
+}
+{{range .Methods}}
+{{- if .HasOptions}}
+// {{.Name}}Options are the optional {{if not .Headers}}query {{end}}parameters of {{.Name}}.
+type {{.Name}}Options struct {
+{{- range .Options}}
+	// {{.Field}} sets the "{{.Name}}" {{if .Header}}header{{else}}query parameter{{end}}.
+{{- if .Doc}}
+	// {{.Doc}}.
+{{- end}}
+	{{.Field}} {{if .Int}}int{{else}}string{{end}}
+{{- end}}
+}
+
+{{- if .Query}}
+
+func (o *{{.Name}}Options) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+{{- range .Query}}
+{{- if .Int}}
+	if o.{{.Field}} != 0 {
+		query.Set("{{.Name}}", strconv.Itoa(o.{{.Field}}))
+	}
+{{- else}}
+	if o.{{.Field}} != "" {
+		query.Set("{{.Name}}", o.{{.Field}})
+	}
+{{- end}}
+{{- end}}
+	return query
+}
+{{- end}}
+{{- if .Headers}}
+
+func (o *{{.Name}}Options) header() http.Header {
+	header := http.Header{}
+	if o == nil {
+		return header
+	}
+{{- range .Headers}}
+	if o.{{.Field}} != "" {
+		header.Set("{{.Name}}", o.{{.Field}})
+	}
+{{- end}}
+	return header
+}
+{{- end}}
+{{end}}
+{{range .Doc}}//{{if .}} {{.}}{{end}}
+{{end -}}
+{{if not .Result -}}
+func (c *Client) {{.Name}}(ctx context.Context{{.Params}}) error {
+	return c.do(ctx, {{.MethodConst}}, {{.PathExpr}}, {{if .Query}}opts.values(){{else}}nil{{end}}, {{if .Headers}}opts.header(){{else}}nil{{end}}, {{if .BodyArg}}{{.BodyArg}}{{else}}nil{{end}}, nil)
+}
+{{- else -}}
+func (c *Client) {{.Name}}(ctx context.Context{{.Params}}) ({{if .Pointer}}*{{end}}{{.Result}}, error) {
+	var out {{.Result}}
+	if err := c.do(ctx, {{.MethodConst}}, {{.PathExpr}}, {{if .Query}}opts.values(){{else}}nil{{end}}, {{if .Headers}}opts.header(){{else}}nil{{end}}, {{if .BodyArg}}{{.BodyArg}}{{else}}nil{{end}}, &out); err != nil {
+		return nil, err
+	}
+	return {{if .Pointer}}&{{end}}out, nil
+}
+{{- end}}
+{{end}}`))
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerated_UpToDate fails when api/swagger.json changed without running
+// go generate ./pkg/client.
+func TestGenerated_UpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../../../api/swagger.json")
+	require.NoError(t, err)
+	want, err := generate(spec)
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../endpoints_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "pkg/client is out of date; run go generate ./pkg/client")
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"invalid JSON", `{`},
+		{"missing operationId", `{"paths": {"/x": {"get": {"responses": {"200": {"schema": {"type": "string"}}}}}}}`},
+		{"unknown package", `{"definitions": {"other.Thing": {}}}`},
+		{"server package", `{"definitions": {"homeassistant.Command": {}}}`},
+		{"undeclared path parameter", `{"paths": {"/x/{id}": {"get": {"operationId": "getX", "responses": {"200": {"schema": {"type": "string"}}}}}}}`},
+		{"no success schema", `{"paths": {"/x": {"get": {"operationId": "getX", "responses": {"200": {}}}}}}`},
+		{
+			"duplicate operationId",
+			`{"paths": {
+				"/a": {"get": {"operationId": "getX", "responses": {"200": {"schema": {"type": "string"}}}}},
+				"/b": {"get": {"operationId": "getX", "responses": {"200": {"schema": {"type": "string"}}}}}
+			}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generate([]byte(tt.spec))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExported(t *testing.T) {
+	assert.Equal(t, "ListDevices", exported("listDevices"))
+	assert.Equal(t, "GetAPIInfo", exported("getAPIInfo"))
+	assert.Equal(t, "APIKeys", exported("apiKeys"))
+	assert.Equal(t, "Apiary", exported("apiary"))
+	assert.Equal(t, "ID", exported("id"))
+	assert.Equal(t, "IdempotencyKey", exported("Idempotency-Key"))
+}
+
+func TestUnexported(t *testing.T) {
+	assert.Equal(t, "id", unexported("id"))
+	assert.Equal(t, "deviceID", unexported("device_id"))
+	assert.Equal(t, "lastSeenAt", unexported("last_seen_at"))
+}
+
+func TestGenerate_NoContent(t *testing.T) {
+	src, err := generate([]byte(`{"paths": {"/x/{x_id}": {"delete": {
+		"operationId": "deleteX",
+		"parameters": [{"name": "x_id", "in": "path", "type": "string"}],
+		"responses": {"204": {}}
+	}}}}`))
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "func (c *Client) DeleteX(ctx context.Context, xID string) error {")
+	assert.Contains(t, string(src), `"/x/"+url.PathEscape(xID)`)
+}
@@ -8,9 +8,20 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-github/internal/config"
 	"go-github/internal/middleware"
 )
 
+// loadCORSConfig loads the CORS settings the server would use, so that these
+// tests cover CORS_ORIGINS through config.Load.
+func loadCORSConfig(t *testing.T) config.CORSConfig {
+	t.Helper()
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	return cfg.CORS
+}
+
 func TestCORS_Integration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -19,7 +30,7 @@ func TestCORS_Integration(t *testing.T) {
 		defer os.Unsetenv("CORS_ORIGINS")
 
 		router := gin.New()
-		router.Use(middleware.CORS())
+		router.Use(middleware.CORS(loadCORSConfig(t)))
 		router.GET("/api/test", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "ok"})
 		})
@@ -41,7 +52,7 @@ func TestCORS_Integration(t *testing.T) {
 		defer os.Unsetenv("CORS_ORIGINS")
 
 		router := gin.New()
-		router.Use(middleware.CORS())
+		router.Use(middleware.CORS(loadCORSConfig(t)))
 		router.GET("/api/test", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "ok"})
 		})
@@ -63,7 +74,7 @@ func TestCORS_Integration(t *testing.T) {
 		defer os.Unsetenv("CORS_ORIGINS")
 
 		router := gin.New()
-		router.Use(middleware.CORS())
+		router.Use(middleware.CORS(loadCORSConfig(t)))
 		router.GET("/api/test", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "ok"})
 		})
@@ -92,7 +103,7 @@ func TestCORS_Integration(t *testing.T) {
 		defer os.Unsetenv("CORS_ORIGINS")
 
 		router := gin.New()
-		router.Use(middleware.CORS())
+		router.Use(middleware.CORS(loadCORSConfig(t)))
 		router.POST("/api/test", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "ok"})
 		})
@@ -116,7 +127,7 @@ func TestCORS_Integration(t *testing.T) {
 		defer os.Unsetenv("CORS_ORIGINS")
 
 		router := gin.New()
-		router.Use(middleware.CORS())
+		router.Use(middleware.CORS(loadCORSConfig(t)))
 		router.GET("/api/test", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "ok"})
 		})